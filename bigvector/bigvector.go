@@ -0,0 +1,207 @@
+// Package bigvector provides a Vector of arbitrary-precision *big.Float
+// elements for computations that need more accuracy than float64, e.g. the
+// residuals in iterative refinement of linear solves.
+//
+// Every Vector has a precision (in mantissa bits, 53 is float64 precision):
+// all elements and all results are rounded to that precision (with the
+// default big.ToNearestEven rounding mode).
+//
+// Important details:
+//
+// (*) Like vector.Vector, the mutating methods (Add, Subtract, Scale, Set,
+// SetPrec) operate in-place and return the Vector to allow chaining. Call
+// Clone() first when an independent copy is needed.
+//
+// (*) Operations on two vectors use the precision of the receiver.
+package bigvector
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Vector is a vector of *big.Float elements with a fixed precision.
+type Vector struct {
+	elements []*big.Float
+	prec     uint
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns a Vector with the specified
+// precision (in bits) and elements.
+func New(prec uint, elements ...float64) *Vector {
+	v := &Vector{elements: make([]*big.Float, len(elements)), prec: prec}
+	for i, element := range elements {
+		v.elements[i] = new(big.Float).SetPrec(prec).SetFloat64(element)
+	}
+	return v
+}
+
+// NewFromStrings is a constructor function that parses the elements with
+// full precision, e.g. "0.1" is not rounded to float64 first. Returns an
+// error if an element cannot be parsed.
+func NewFromStrings(prec uint, elements ...string) (*Vector, error) {
+	v := &Vector{elements: make([]*big.Float, len(elements)), prec: prec}
+	for i, element := range elements {
+		x, _, err := big.ParseFloat(element, 10, prec, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("invalid element %q: %w", element, err)
+		}
+		v.elements[i] = x
+	}
+	return v, nil
+}
+
+// NewFromVector is a constructor function that converts a vector.Vector to a
+// Vector with the specified precision.
+func NewFromVector[T wbmath.SignedNumber](prec uint, v vector.Vector[T]) *Vector {
+	return New(prec, v.CloneAsFloat64()...)
+}
+
+// ============================================================================
+// Accessors
+// ============================================================================
+
+// Len returns the number of elements.
+func (v *Vector) Len() int {
+	return len(v.elements)
+}
+
+// Prec returns the precision in bits.
+func (v *Vector) Prec() uint {
+	return v.prec
+}
+
+// At returns a copy of element i. Returns an error if i is out of range.
+func (v *Vector) At(i int) (*big.Float, error) {
+	if i < 0 || i >= len(v.elements) {
+		return nil, fmt.Errorf("index %d out of range for vector of length %d", i, len(v.elements))
+	}
+	return v.newFloat().Set(v.elements[i]), nil
+}
+
+// Set sets element i to x, rounded to the precision of the Vector. Returns
+// an error if i is out of range.
+func (v *Vector) Set(i int, x *big.Float) (*Vector, error) {
+	if i < 0 || i >= len(v.elements) {
+		return nil, fmt.Errorf("index %d out of range for vector of length %d", i, len(v.elements))
+	}
+	v.elements[i].Set(x)
+	return v, nil
+}
+
+// SetPrec changes the precision of the Vector, rounding the elements when the
+// precision is lowered.
+func (v *Vector) SetPrec(prec uint) *Vector {
+	v.prec = prec
+	for _, element := range v.elements {
+		element.SetPrec(prec)
+	}
+	return v
+}
+
+// Clone returns an independent copy of the Vector.
+func (v *Vector) Clone() *Vector {
+	clone := &Vector{elements: make([]*big.Float, len(v.elements)), prec: v.prec}
+	for i, element := range v.elements {
+		clone.elements[i] = v.newFloat().Set(element)
+	}
+	return clone
+}
+
+// Float64s returns the elements rounded to float64 as a vector.Vector.
+func (v *Vector) Float64s() vector.Vector[float64] {
+	result := vector.NewFromValue(0.0, len(v.elements))
+	for i, element := range v.elements {
+		result[i], _ = element.Float64()
+	}
+	return result
+}
+
+// String implements the fmt.Stringer interface, e.g. "[0.1 0.2]". The
+// elements are formatted with the shortest representation that is exact at
+// the precision of the Vector.
+func (v *Vector) String() string {
+	parts := make([]string, len(v.elements))
+	for i, element := range v.elements {
+		parts[i] = element.Text('g', -1)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add adds the elements of other to the elements of v in-place. Returns an
+// error if the lengths differ.
+func (v *Vector) Add(other *Vector) (*Vector, error) {
+	if len(v.elements) != len(other.elements) {
+		return nil, errors.New("vectors must have the same length")
+	}
+	for i, element := range v.elements {
+		element.Add(element, other.elements[i])
+	}
+	return v, nil
+}
+
+// Subtract subtracts the elements of other from the elements of v in-place.
+// Returns an error if the lengths differ.
+func (v *Vector) Subtract(other *Vector) (*Vector, error) {
+	if len(v.elements) != len(other.elements) {
+		return nil, errors.New("vectors must have the same length")
+	}
+	for i, element := range v.elements {
+		element.Sub(element, other.elements[i])
+	}
+	return v, nil
+}
+
+// Scale multiplies every element by factor in-place.
+func (v *Vector) Scale(factor *big.Float) *Vector {
+	for _, element := range v.elements {
+		element.Mul(element, factor)
+	}
+	return v
+}
+
+// Sum returns the sum of the elements.
+func (v *Vector) Sum() *big.Float {
+	sum := v.newFloat()
+	for _, element := range v.elements {
+		sum.Add(sum, element)
+	}
+	return sum
+}
+
+// Dot returns the dot product of v and other. Returns an error if the
+// lengths differ.
+func (v *Vector) Dot(other *Vector) (*big.Float, error) {
+	if len(v.elements) != len(other.elements) {
+		return nil, errors.New("vectors must have the same length")
+	}
+	sum, product := v.newFloat(), v.newFloat()
+	for i, element := range v.elements {
+		sum.Add(sum, product.Mul(element, other.elements[i]))
+	}
+	return sum, nil
+}
+
+// Norm returns the Euclidean norm (magnitude) of the Vector.
+func (v *Vector) Norm() *big.Float {
+	squares, _ := v.Dot(v)
+	return squares.Sqrt(squares)
+}
+
+// newFloat returns a zero big.Float with the precision of the Vector.
+func (v *Vector) newFloat() *big.Float {
+	return new(big.Float).SetPrec(v.prec)
+}
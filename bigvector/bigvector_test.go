@@ -0,0 +1,62 @@
+package bigvector
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+func TestPrecision(t *testing.T) {
+	// 1e20 + 1 - 1e20 loses the 1 in float64 but not with 128 bits.
+	v := New(128, 1e20, 1, -1e20)
+	if got := v.Sum(); got.Cmp(big.NewFloat(1)) != 0 {
+		t.Fatalf("Sum() = %v; want 1", got)
+	}
+	low := v.Clone().SetPrec(53)
+	if got := low.Sum(); got.Sign() != 0 {
+		t.Fatalf("Sum() at 53 bits = %v; want 0", got)
+	}
+	tenths, err := NewFromStrings(200, "0.1", "0.2")
+	if err != nil {
+		t.Fatalf("NewFromStrings returned error: %v", err)
+	}
+	want, _, _ := big.ParseFloat("0.3", 10, 200, big.ToNearestEven)
+	if got := tenths.Sum(); got.Cmp(want) != 0 {
+		t.Fatalf("0.1 + 0.2 = %v; want 0.3 at 200 bits", got)
+	}
+	if _, err := NewFromStrings(64, "x"); err == nil {
+		t.Fatalf("NewFromStrings(x) should return error")
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := NewFromVector(100, vector.New(3, 4))
+	b := New(100, 1, 2)
+	if dot, _ := a.Dot(b); dot.Cmp(big.NewFloat(11)) != 0 {
+		t.Fatalf("Dot() = %v; want 11", dot)
+	}
+	if norm := a.Norm(); norm.Cmp(big.NewFloat(5)) != 0 {
+		t.Fatalf("Norm() = %v; want 5", norm)
+	}
+	sum, _ := a.Clone().Add(b)
+	sum.Scale(big.NewFloat(2))
+	if sum.String() != "[8 12]" {
+		t.Fatalf("2(a + b) = %v; want [8 12]", sum)
+	}
+	difference, _ := a.Clone().Subtract(b)
+	if got := difference.Float64s(); got[0] != 2 || got[1] != 2 {
+		t.Fatalf("a - b = %v; want [2 2]", got)
+	}
+	if a.String() != "[3 4]" {
+		t.Fatalf("a was modified: %v", a)
+	}
+	if _, err := a.Add(New(100, 1)); err == nil {
+		t.Fatalf("Add with different lengths should return error")
+	}
+	x, _ := a.At(1)
+	x.SetInt64(0)
+	if y, _ := a.At(1); y.Cmp(big.NewFloat(4)) != 0 {
+		t.Fatalf("At returned a reference instead of a copy")
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bogersw/wbmath/expr"
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// value is a scalar or a vector. Every value has a float64 approximation;
+// exact is nil when the value has no exact rational form (e.g. sqrt(2)).
+type value struct {
+	approx   vector.Vector[float64]
+	exact    []*fraction.Fraction
+	isVector bool
+}
+
+// calculator holds the state of a REPL session.
+type calculator struct {
+	exactMode bool
+	variables map[string]value
+}
+
+// reductions are the functions that turn vectors into scalars. They are
+// handled by the calculator before the expression is parsed.
+var reductions = map[string]int{"sum": 1, "len": 1, "norm": 1, "dot": 2}
+
+func newCalculator(exactMode bool) *calculator {
+	return &calculator{exactMode: exactMode, variables: map[string]value{}}
+}
+
+// Execute runs one line of input and returns the text to print. Lines are
+// commands (":help"), assignments ("x = 1/3") or expressions. The result of
+// the last expression is stored in the variable ans.
+func (c *calculator) Execute(line string) (string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(line, ":") {
+		return c.command(line)
+	}
+	name := "ans"
+	if before, after, found := strings.Cut(line, "="); found {
+		name, line = strings.TrimSpace(before), after
+		if !isIdentifier(name) || strings.HasPrefix(name, "_") {
+			return "", fmt.Errorf("invalid variable name %q", name)
+		}
+	}
+	result, err := c.evaluate(line)
+	if err != nil {
+		return "", err
+	}
+	c.variables[name] = result
+	if name == "ans" {
+		return c.format(result), nil
+	}
+	return name + " = " + c.format(result), nil
+}
+
+func (c *calculator) command(line string) (string, error) {
+	switch line {
+	case ":help":
+		return help, nil
+	case ":exact":
+		c.exactMode = true
+		return "exact mode", nil
+	case ":float":
+		c.exactMode = false
+		return "float mode", nil
+	case ":vars":
+		names := make([]string, 0, len(c.variables))
+		for name := range c.variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		lines := make([]string, len(names))
+		for i, name := range names {
+			lines[i] = name + " = " + c.format(c.variables[name])
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+	return "", fmt.Errorf("unknown command %q (try :help)", line)
+}
+
+// evaluate evaluates an expression that may contain vector literals and
+// reductions. Both are replaced by temporary variables first; the remaining
+// expression is evaluated element-wise, with scalars broadcast to vectors.
+func (c *calculator) evaluate(source string) (value, error) {
+	temporaries := map[string]value{}
+	source, err := c.substitute(source, temporaries)
+	if err != nil {
+		return value{}, err
+	}
+	e, err := expr.Parse(source)
+	if err != nil {
+		return value{}, err
+	}
+	operands := map[string]value{}
+	length, isVector := 1, false
+	for _, name := range e.Variables() {
+		operand, ok := temporaries[name]
+		if !ok {
+			operand, ok = c.variables[name]
+		}
+		if !ok {
+			continue // Left to the backend: constants like pi.
+		}
+		operands[name] = operand
+		if operand.isVector {
+			if isVector && len(operand.approx) != length {
+				return value{}, errors.New("vectors must have the same length")
+			}
+			length, isVector = len(operand.approx), true
+		}
+	}
+	result := value{approx: make(vector.Vector[float64], length), exact: make([]*fraction.Fraction, length), isVector: isVector}
+	var exactErr error
+	for i := 0; i < length; i++ {
+		floats := map[string]float64{}
+		fractions := map[string]*fraction.Fraction{}
+		for name, operand := range operands {
+			j := 0
+			if operand.isVector {
+				j = i
+			}
+			floats[name] = operand.approx[j]
+			if operand.exact != nil {
+				fractions[name] = operand.exact[j]
+			}
+		}
+		if result.approx[i], err = e.Eval(floats); err != nil {
+			return value{}, err
+		}
+		if result.exact != nil {
+			if result.exact[i], exactErr = e.EvalFraction(fractions); exactErr != nil {
+				result.exact = nil
+			}
+		}
+		// In exact mode an exact error (like a division by zero) is more
+		// useful than an infinite or NaN approximation.
+		if c.exactMode && result.exact == nil && (math.IsInf(result.approx[i], 0) || math.IsNaN(result.approx[i])) {
+			return value{}, exactErr
+		}
+	}
+	return result, nil
+}
+
+// substitute replaces vector literals and reduction calls in the source by
+// temporary variables and returns the rewritten source.
+func (c *calculator) substitute(source string, temporaries map[string]value) (string, error) {
+	var sb strings.Builder
+	temporary := func(v value) {
+		name := "_" + strconv.Itoa(len(temporaries))
+		temporaries[name] = v
+		sb.WriteString(" " + name + " ")
+	}
+	for i := 0; i < len(source); {
+		switch {
+		case source[i] == '[':
+			end, err := closing(source, i)
+			if err != nil {
+				return "", err
+			}
+			v, err := c.vectorLiteral(source[i+1 : end])
+			if err != nil {
+				return "", err
+			}
+			temporary(v)
+			i = end + 1
+		case unicode.IsLetter(rune(source[i])) || source[i] == '_':
+			start := i
+			for i < len(source) && (isIdentifier(source[i:i+1]) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			name := source[start:i]
+			open := i
+			for open < len(source) && source[open] == ' ' {
+				open++
+			}
+			arity, isReduction := reductions[name]
+			if !isReduction || open == len(source) || source[open] != '(' {
+				sb.WriteString(name)
+				continue
+			}
+			end, err := closing(source, open)
+			if err != nil {
+				return "", err
+			}
+			arguments := split(source[open+1 : end])
+			if len(arguments) != arity {
+				return "", fmt.Errorf("%s expects %d argument(s), got %d", name, arity, len(arguments))
+			}
+			v, err := c.reduce(name, arguments)
+			if err != nil {
+				return "", err
+			}
+			temporary(v)
+			i = end + 1
+		default:
+			sb.WriteByte(source[i])
+			i++
+		}
+	}
+	return sb.String(), nil
+}
+
+func (c *calculator) vectorLiteral(source string) (value, error) {
+	elements := split(source)
+	v := value{approx: make(vector.Vector[float64], len(elements)), exact: make([]*fraction.Fraction, len(elements)), isVector: true}
+	for i, element := range elements {
+		scalar, err := c.evaluate(element)
+		if err != nil {
+			return value{}, err
+		}
+		if scalar.isVector {
+			return value{}, errors.New("vectors cannot be nested")
+		}
+		v.approx[i] = scalar.approx[0]
+		if v.exact != nil && scalar.exact != nil {
+			v.exact[i] = scalar.exact[0]
+		} else {
+			v.exact = nil
+		}
+	}
+	return v, nil
+}
+
+// reduce evaluates the arguments as vectors and applies the reduction.
+func (c *calculator) reduce(name string, arguments []string) (value, error) {
+	operands := make([]value, len(arguments))
+	for i, argument := range arguments {
+		operand, err := c.evaluate(argument)
+		if err != nil {
+			return value{}, err
+		}
+		operands[i] = operand
+	}
+	u := operands[0]
+	result := value{approx: vector.New(0.0), exact: []*fraction.Fraction{fraction.MustNew(0, 1)}}
+	switch name {
+	case "len":
+		result.approx[0] = float64(len(u.approx))
+		result.exact[0] = fraction.MustNew(len(u.approx), 1)
+		return result, nil
+	case "sum":
+		result.approx[0] = u.approx.Sum()
+		for _, element := range u.exact {
+			result.exact[0].Add(element).Simplify()
+		}
+	case "norm":
+		result.approx[0] = u.approx.Magnitude()
+		for _, element := range u.exact {
+			result.exact[0].Add(element.Clone().Multiply(element)).Simplify()
+		}
+		if u.exact != nil {
+			root, err := expr.FractionBackend{}.Call("sqrt", result.exact)
+			result.exact = []*fraction.Fraction{root}
+			if err != nil {
+				result.exact = nil
+			}
+		}
+	case "dot":
+		v := operands[1]
+		dot, err := u.approx.DotProduct(v.approx)
+		if err != nil {
+			return value{}, err
+		}
+		result.approx[0] = dot
+		if v.exact == nil {
+			u.exact = nil
+		}
+		for i, element := range u.exact {
+			result.exact[0].Add(element.Clone().Multiply(v.exact[i])).Simplify()
+		}
+	}
+	if u.exact == nil {
+		result.exact = nil
+	}
+	return result, nil
+}
+
+// format returns the value as text: exact when in exact mode and an exact
+// value exists, otherwise as float (prefixed with ≈ in exact mode).
+func (c *calculator) format(v value) string {
+	elements := make([]string, len(v.approx))
+	for i, x := range v.approx {
+		switch {
+		case c.exactMode && v.exact != nil:
+			elements[i] = v.exact[i].String()
+		default:
+			elements[i] = strconv.FormatFloat(x, 'g', -1, 64)
+		}
+	}
+	text := "[" + strings.Join(elements, ", ") + "]"
+	if !v.isVector {
+		text = elements[0]
+	}
+	if c.exactMode && v.exact == nil {
+		return "≈ " + text
+	}
+	return text
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// closing returns the index of the bracket that closes the one at `open`.
+func closing(source string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(source); i++ {
+		switch source[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced %q at position %d", source[open], open)
+}
+
+// split splits the source at commas that are not nested in brackets.
+func split(source string) []string {
+	if strings.TrimSpace(source) == "" {
+		return nil
+	}
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(source); i++ {
+		switch source[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, source[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, source[start:])
+}
+
+func isIdentifier(name string) bool {
+	for i, c := range name {
+		if !(unicode.IsLetter(c) || c == '_' || (i > 0 && unicode.IsDigit(c))) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+const help = `Enter an expression to evaluate it, or assign it with name = expression.
+  operators   + - * / ^ and parentheses
+  functions   sqrt, abs, pow, sin, cos, exp, ln, ... (see package expr)
+  vectors     [1, 2, 3]; arithmetic is element-wise, scalars are broadcast
+  reductions  sum(v), len(v), norm(v), dot(u, v)
+  ans         the result of the last expression
+Commands:
+  :exact      show exact fractions (inexact results are prefixed with ≈)
+  :float      show floating-point numbers
+  :vars       list variables
+  :help       show this help
+  :quit       exit`
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestExecute(t *testing.T) {
+	calc := newCalculator(true)
+	tests := []struct {
+		input, want string
+	}{
+		{"1/3 + 1/6", "1/2"},
+		{"v = [1/2, 1/3, 1/6]", "v = [1/2, 1/3, 1/6]"},
+		{"sum(v) + 2*v", "[2, 1 2/3, 1 1/3]"},
+		{"dot(v, [6, 6, 6]) - len(v)", "3"},
+		{"norm([3, 4])", "5"},
+		{"sqrt(2)", "≈ 1.4142135623730951"},
+		{"ans^2", "≈ 2.0000000000000004"},
+		{":float", "float mode"},
+		{"x = 0.1 + 0.2", "x = 0.30000000000000004"},
+		{":exact", "exact mode"},
+		{"x", "3/10"},
+	}
+	for _, test := range tests {
+		got, err := calc.Execute(test.input)
+		if err != nil || got != test.want {
+			t.Fatalf("Execute(%q) = %q, %v; want %q", test.input, got, err, test.want)
+		}
+	}
+	for _, input := range []string{"1/0", "[1, 2] + [1, 2, 3]", "dot(v)", "_x = 1", "[[1], 2]", ":nope"} {
+		if _, err := calc.Execute(input); err == nil {
+			t.Fatalf("Execute(%q) should return error", input)
+		}
+	}
+}
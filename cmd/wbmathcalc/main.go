@@ -0,0 +1,64 @@
+// Wbmathcalc is an interactive calculator built on the wbmath packages expr,
+// fraction and vector. It evaluates expressions with floating-point numbers
+// or, in exact mode, with fractions, and supports variables and vector
+// literals.
+//
+// Usage:
+//
+//	wbmathcalc [-exact]
+//
+// Example session:
+//
+//	> :exact
+//	exact mode
+//	> v = [1/2, 1/3, 1/6]
+//	v = [1/2, 1/3, 1/6]
+//	> sum(v) + 2*v
+//	[2, 1 2/3, 1 1/3]
+//
+// Type :help for an overview of the syntax and the available commands.
+// Input that is not a terminal (e.g. a file or a pipe) is processed without
+// prompts, which makes the calculator usable in scripts.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	exact := flag.Bool("exact", false, "start in exact (fraction) mode")
+	flag.Parse()
+
+	calc := newCalculator(*exact)
+	info, err := os.Stdin.Stat()
+	interactive := err == nil && info.Mode()&os.ModeCharDevice != 0
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		if interactive {
+			fmt.Print("> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == ":quit" || line == ":q" {
+			return
+		}
+		output, err := calc.Execute(line)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			continue
+		}
+		if output != "" {
+			fmt.Println(output)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
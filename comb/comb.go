@@ -0,0 +1,277 @@
+// Package comb provides iterators over combinatorial structures of a slice
+// (permutations, combinations, subsets, multiset permutations and Cartesian
+// products) together with functions that count them.
+//
+// Important details:
+//
+// (*) All iterators yield in lexicographic order with respect to the order of
+// the input slice (for MultisetPermutations: with respect to sorted order).
+//
+// (*) To keep allocations low, every iterator reuses a single output slice:
+// the yielded slice is only valid until the next iteration. Use slices.Clone
+// to keep a result.
+//
+// (*) The input slices are never modified.
+//
+// (*) The counting functions return int and will overflow for large inputs
+// (e.g. CountPermutations(21, 21) on 64-bit platforms).
+package comb
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/bogersw/wbmath"
+)
+
+// ============================================================================
+// Iterators
+// ============================================================================
+
+// Permutations returns an iterator over all orderings of `items` (n!
+// permutations). Equal elements are treated as distinct: use
+// MultisetPermutations to skip duplicates.
+func Permutations[T any](items []T) iter.Seq[[]T] {
+	return PermutationsK(items, len(items))
+}
+
+// PermutationsK returns an iterator over all ordered selections of k elements
+// of `items` (n!/(n-k)! permutations). Yields nothing if k is negative or
+// larger than the number of items.
+func PermutationsK[T any](items []T, k int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(items)
+		if k < 0 || k > n {
+			return
+		}
+		// indices holds a permutation of 0..n-1: the first k entries are the
+		// current selection, the remaining entries are kept sorted descending
+		// so the next-permutation step skips the unused tails.
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		out := make([]T, k)
+		for {
+			for i := 0; i < k; i++ {
+				out[i] = items[indices[i]]
+			}
+			if !yield(out) {
+				return
+			}
+			slices.Reverse(indices[k:])
+			if !nextPermutation(indices) {
+				return
+			}
+		}
+	}
+}
+
+// Combinations returns an iterator over all subsets of k elements of `items`
+// (n choose k combinations), each in the original order of `items`. Yields
+// nothing if k is negative or larger than the number of items.
+func Combinations[T any](items []T, k int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(items)
+		if k < 0 || k > n {
+			return
+		}
+		indices := make([]int, k)
+		for i := range indices {
+			indices[i] = i
+		}
+		out := make([]T, k)
+		for {
+			for i, index := range indices {
+				out[i] = items[index]
+			}
+			if !yield(out) {
+				return
+			}
+			// Find the rightmost index that can still be incremented.
+			i := k - 1
+			for i >= 0 && indices[i] == n-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[j-1] + 1
+			}
+		}
+	}
+}
+
+// Subsets returns an iterator over all 2^n subsets of `items`, ordered by size
+// first (starting with the empty subset) and lexicographically within a size.
+func Subsets[T any](items []T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for k := 0; k <= len(items); k++ {
+			for subset := range Combinations(items, k) {
+				if !yield(subset) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MultisetPermutations returns an iterator over all distinct orderings of
+// `items`, in lexicographic order. For example [1 1 2] yields [1 1 2],
+// [1 2 1] and [2 1 1].
+func MultisetPermutations[T cmp.Ordered](items []T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		out := slices.Clone(items)
+		slices.Sort(out)
+		for {
+			if !yield(out) {
+				return
+			}
+			if !nextPermutation(out) {
+				return
+			}
+		}
+	}
+}
+
+// CartesianProduct returns an iterator over all tuples with one element of
+// each of the specified sets, varying the last set fastest. Yields nothing if
+// no sets are specified or if any set is empty.
+func CartesianProduct[T any](sets ...[]T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if len(sets) == 0 {
+			return
+		}
+		for _, set := range sets {
+			if len(set) == 0 {
+				return
+			}
+		}
+		indices := make([]int, len(sets))
+		out := make([]T, len(sets))
+		for {
+			for i, index := range indices {
+				out[i] = sets[i][index]
+			}
+			if !yield(out) {
+				return
+			}
+			// Increment the indices like an odometer.
+			i := len(sets) - 1
+			for i >= 0 {
+				indices[i]++
+				if indices[i] < len(sets[i]) {
+					break
+				}
+				indices[i] = 0
+				i--
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
+
+// ============================================================================
+// Counting functions
+// ============================================================================
+
+// Factorial returns n! (and 1 for n <= 0).
+func Factorial(n int) int {
+	result := 1
+	for i := 2; i <= n; i++ {
+		result *= i
+	}
+	return result
+}
+
+// CountPermutations returns the number of ordered selections of k elements
+// out of n: n!/(n-k)!. Returns 0 if k is negative or larger than n.
+func CountPermutations(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := 1
+	for i := n - k + 1; i <= n; i++ {
+		result *= i
+	}
+	return result
+}
+
+// CountCombinations returns the binomial coefficient "n choose k": the number
+// of subsets of k elements out of n. Returns 0 if k is negative or larger
+// than n.
+func CountCombinations(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	result := 1
+	for i := 1; i <= k; i++ {
+		// Divide by the gcd first to postpone overflow: result*(n-k+i) is
+		// always divisible by i.
+		factor := n - k + i
+		gcd := wbmath.Gcd(result, i)
+		result = (result / gcd) * (factor / (i / gcd))
+	}
+	return result
+}
+
+// CountSubsets returns the number of subsets of a set with n elements: 2^n.
+func CountSubsets(n int) int {
+	return wbmath.PowInt(2, uint(n))
+}
+
+// CountMultisetPermutations returns the number of distinct orderings of a
+// multiset with the specified multiplicities: (sum counts)! / (c1! c2! ...).
+func CountMultisetPermutations(counts ...int) int {
+	result := 1
+	total := 0
+	for _, count := range counts {
+		total += count
+		result *= CountCombinations(total, count)
+	}
+	return result
+}
+
+// CountCartesianProduct returns the number of tuples in the Cartesian product
+// of sets with the specified sizes.
+func CountCartesianProduct(sizes ...int) int {
+	if len(sizes) == 0 {
+		return 0
+	}
+	result := 1
+	for _, size := range sizes {
+		result *= size
+	}
+	return result
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// nextPermutation rearranges values into the next lexicographic permutation.
+// Returns false (and leaves values unchanged) if values is the last one.
+func nextPermutation[T cmp.Ordered](values []T) bool {
+	i := len(values) - 2
+	for i >= 0 && values[i] >= values[i+1] {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	j := len(values) - 1
+	for values[j] <= values[i] {
+		j--
+	}
+	values[i], values[j] = values[j], values[i]
+	slices.Reverse(values[i+1:])
+	return true
+}
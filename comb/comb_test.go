@@ -0,0 +1,87 @@
+package comb
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func collect[T any](seq func(func([]T) bool)) [][]T {
+	var result [][]T
+	for value := range seq {
+		result = append(result, slices.Clone(value))
+	}
+	return result
+}
+
+func TestPermutations(t *testing.T) {
+	got := collect(Permutations([]string{"a", "b", "c"}))
+	want := [][]string{{"a", "b", "c"}, {"a", "c", "b"}, {"b", "a", "c"},
+		{"b", "c", "a"}, {"c", "a", "b"}, {"c", "b", "a"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Permutations = %v; want %v", got, want)
+	}
+	got2 := collect(PermutationsK([]int{1, 2, 3}, 2))
+	want2 := [][]int{{1, 2}, {1, 3}, {2, 1}, {2, 3}, {3, 1}, {3, 2}}
+	if !reflect.DeepEqual(got2, want2) {
+		t.Fatalf("PermutationsK = %v; want %v", got2, want2)
+	}
+	if n := len(collect(PermutationsK(make([]int, 6), 3))); n != CountPermutations(6, 3) {
+		t.Fatalf("PermutationsK(6, 3) yielded %d; want %d", n, CountPermutations(6, 3))
+	}
+}
+
+func TestCombinationsAndSubsets(t *testing.T) {
+	got := collect(Combinations([]int{1, 2, 3, 4}, 2))
+	want := [][]int{{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Combinations = %v; want %v", got, want)
+	}
+	if got := collect(Combinations([]int{1}, 2)); len(got) != 0 {
+		t.Fatalf("Combinations(k > n) = %v; want none", got)
+	}
+	subsets := collect(Subsets([]int{1, 2, 3}))
+	if len(subsets) != CountSubsets(3) || len(subsets[0]) != 0 {
+		t.Fatalf("Subsets = %v; want 8 subsets starting with []", subsets)
+	}
+}
+
+func TestMultisetPermutations(t *testing.T) {
+	got := collect(MultisetPermutations([]int{2, 1, 1}))
+	want := [][]int{{1, 1, 2}, {1, 2, 1}, {2, 1, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MultisetPermutations = %v; want %v", got, want)
+	}
+	if n := len(collect(MultisetPermutations([]rune("MISSISSIPPI")))); n != CountMultisetPermutations(1, 4, 4, 2) {
+		t.Fatalf("MultisetPermutations(MISSISSIPPI) yielded %d; want 34650", n)
+	}
+}
+
+func TestCartesianProduct(t *testing.T) {
+	got := collect(CartesianProduct([]int{1, 2}, []int{3}, []int{4, 5}))
+	want := [][]int{{1, 3, 4}, {1, 3, 5}, {2, 3, 4}, {2, 3, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CartesianProduct = %v; want %v", got, want)
+	}
+	if got := collect(CartesianProduct([]int{1}, []int{})); len(got) != 0 {
+		t.Fatalf("CartesianProduct with empty set = %v; want none", got)
+	}
+}
+
+func TestCounting(t *testing.T) {
+	if got := Factorial(10); got != 3628800 {
+		t.Fatalf("Factorial(10) = %d; want 3628800", got)
+	}
+	if got := CountCombinations(52, 5); got != 2598960 {
+		t.Fatalf("CountCombinations(52, 5) = %d; want 2598960", got)
+	}
+	if got := CountCombinations(62, 31); got != 465428353255261088 {
+		t.Fatalf("CountCombinations(62, 31) = %d; want 465428353255261088", got)
+	}
+	if got := CountMultisetPermutations(1, 4, 4, 2); got != 34650 {
+		t.Fatalf("CountMultisetPermutations = %d; want 34650", got)
+	}
+	if got := CountCartesianProduct(2, 3, 4); got != 24 {
+		t.Fatalf("CountCartesianProduct = %d; want 24", got)
+	}
+}
@@ -0,0 +1,389 @@
+// Package contfrac provides a ContinuedFraction type for simple continued
+// fractions [a0; a1, a2, ...], both finite (rational numbers) and eventually
+// periodic (quadratic irrationals like sqrt(2) = [1; (2)]).
+//
+// The convergents of a continued fraction are the best rational
+// approximations of its value and are returned as *fraction.Fraction.
+//
+// Important details:
+//
+// (*) All terms after the first must be positive. The first term can be any
+// integer (it is the floor of the value).
+//
+// (*) Convergents are computed with int arithmetic: iteration stops as soon
+// as the next numerator or denominator would overflow, so an "infinite"
+// continued fraction yields a finite number of convergents in practice.
+package contfrac
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/bits"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// ContinuedFraction represents the simple continued fraction with the terms
+// of prefix, followed by the terms of period repeated indefinitely (if
+// period is not empty).
+type ContinuedFraction struct {
+	prefix []int
+	period []int
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the finite continued fraction
+// with the specified terms. Returns an error if no terms are specified or if
+// any term after the first is not positive.
+func New(terms ...int) (*ContinuedFraction, error) {
+	return NewPeriodic(terms, nil)
+}
+
+// NewPeriodic is a constructor function that returns the continued fraction
+// with the specified prefix terms followed by the periodic terms repeated
+// indefinitely. Returns an error if there are no terms at all or if any term
+// after the first is not positive.
+func NewPeriodic(prefix []int, period []int) (*ContinuedFraction, error) {
+	if len(prefix)+len(period) == 0 {
+		return nil, errors.New("a continued fraction needs at least one term")
+	}
+	for i, term := range append(append([]int{}, prefix...), period...) {
+		if i > 0 && term <= 0 {
+			return nil, errors.New("all terms after the first must be positive")
+		}
+	}
+	return &ContinuedFraction{
+			prefix: append([]int{}, prefix...),
+			period: append([]int{}, period...)},
+		nil
+}
+
+// NewFromFraction is a constructor function that returns the (finite)
+// continued fraction expansion of the specified Fraction, computed with the
+// Euclidean algorithm. Returns an error if the Fraction is nil.
+func NewFromFraction(f *fraction.Fraction) (*ContinuedFraction, error) {
+	if f == nil {
+		return nil, fraction.ErrNilFraction
+	}
+	numerator, _ := f.Numerator()
+	denominator, _ := f.Denominator()
+	return &ContinuedFraction{prefix: euclid(numerator, denominator)}, nil
+}
+
+// Sqrt is a constructor function that returns the continued fraction of the
+// square root of n. For perfect squares the result is finite, otherwise it is
+// periodic, e.g. Sqrt(2) = [1; (2)] and Sqrt(7) = [2; (1, 1, 1, 4)]. Returns
+// an error if n is negative.
+func Sqrt(n int) (*ContinuedFraction, error) {
+	return NewQuadratic(0, 1, n)
+}
+
+// NewQuadratic is a constructor function that returns the continued fraction
+// of the quadratic irrational (p + sqrt(d)) / q. The expansion is eventually
+// periodic (Lagrange's theorem). Returns an error if q is zero or d is
+// negative.
+func NewQuadratic(p, q, d int) (*ContinuedFraction, error) {
+	if q == 0 {
+		return nil, errors.New("division by zero")
+	}
+	if d < 0 {
+		return nil, errors.New("the square root of a negative number is not real")
+	}
+	s := isqrt(d)
+	if s*s == d {
+		// Rational value: (p + s) / q
+		return &ContinuedFraction{prefix: euclid(p+s, q)}, nil
+	}
+	// The iteration requires q | (d - p^2): if needed, multiply numerator and
+	// denominator by |q| (which keeps the value unchanged).
+	if (d-p*p)%q != 0 {
+		absQ := wbmath.Abs(q)
+		p, d, q = p*absQ, d*q*q, q*absQ
+		s = isqrt(d)
+	}
+	// Standard iteration on the state (p, q): a = floor((p + sqrt(d)) / q),
+	// p' = a*q - p, q' = (d - p'^2) / q. The state repeats exactly when the
+	// terms start repeating.
+	type state struct{ p, q int }
+	seen := make(map[state]int)
+	var terms []int
+	for {
+		current := state{p, q}
+		if start, ok := seen[current]; ok {
+			return &ContinuedFraction{prefix: terms[:start], period: terms[start:]}, nil
+		}
+		seen[current] = len(terms)
+		var a int
+		if q > 0 {
+			a = floorDiv(p+s, q)
+		} else {
+			a = floorDiv(p+s+1, q)
+		}
+		terms = append(terms, a)
+		p = a*q - p
+		q = (d - p*p) / q
+	}
+}
+
+// MustNew is a constructor identical to New but which panics if an error
+// occurs.
+func MustNew(terms ...int) *ContinuedFraction {
+	cf, err := New(terms...)
+	if err != nil {
+		panic(err)
+	}
+	return cf
+}
+
+// ============================================================================
+// Terms
+// ============================================================================
+
+// IsFinite checks if the continued fraction has a finite number of terms
+// (i.e. it represents a rational number).
+func (cf *ContinuedFraction) IsFinite() bool {
+	return len(cf.period) == 0
+}
+
+// Len returns the number of terms of a finite continued fraction, or -1 if
+// the continued fraction is periodic.
+func (cf *ContinuedFraction) Len() int {
+	if !cf.IsFinite() {
+		return -1
+	}
+	return len(cf.prefix)
+}
+
+// Prefix returns a copy of the non-repeating terms.
+func (cf *ContinuedFraction) Prefix() []int {
+	return append([]int{}, cf.prefix...)
+}
+
+// Period returns a copy of the repeating terms (empty for a finite
+// continued fraction).
+func (cf *ContinuedFraction) Period() []int {
+	return append([]int{}, cf.period...)
+}
+
+// Term returns the i-th term (starting at 0) and a boolean value that
+// indicates if the term exists.
+func (cf *ContinuedFraction) Term(i int) (int, bool) {
+	if i < 0 {
+		return 0, false
+	}
+	if i < len(cf.prefix) {
+		return cf.prefix[i], true
+	}
+	if len(cf.period) == 0 {
+		return 0, false
+	}
+	return cf.period[(i-len(cf.prefix))%len(cf.period)], true
+}
+
+// Terms returns an iterator over all terms (infinite if periodic).
+func (cf *ContinuedFraction) Terms() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			term, ok := cf.Term(i)
+			if !ok || !yield(term) {
+				return
+			}
+		}
+	}
+}
+
+// String implements the fmt.Stringer interface and returns the continued
+// fraction in the usual notation, with the period in parentheses, e.g.
+// "[1; 2, 2]" or "[2; (1, 1, 1, 4)]".
+func (cf *ContinuedFraction) String() string {
+	if cf == nil {
+		return "NaN"
+	}
+	var parts []string
+	for _, term := range cf.prefix {
+		parts = append(parts, fmt.Sprintf("%d", term))
+	}
+	if len(cf.period) > 0 {
+		period := make([]string, len(cf.period))
+		for i, term := range cf.period {
+			period[i] = fmt.Sprintf("%d", term)
+		}
+		parts = append(parts, "("+strings.Join(period, ", ")+")")
+	}
+	if len(parts) == 1 {
+		return "[" + parts[0] + "]"
+	}
+	return "[" + parts[0] + "; " + strings.Join(parts[1:], ", ") + "]"
+}
+
+// ============================================================================
+// Convergents and approximation
+// ============================================================================
+
+// Convergents returns an iterator over the convergents h_n/k_n of the
+// continued fraction. Iteration stops after the last term of a finite
+// continued fraction, or when the next convergent would overflow int.
+func (cf *ContinuedFraction) Convergents() iter.Seq[*fraction.Fraction] {
+	return func(yield func(*fraction.Fraction) bool) {
+		for h, k := range cf.convergents() {
+			if !yield(fraction.MustNew(h, k)) {
+				return
+			}
+		}
+	}
+}
+
+// Convergent returns the n-th convergent (starting at 0). Returns an error if
+// the convergent doesn't exist or can't be represented without overflow.
+func (cf *ContinuedFraction) Convergent(n int) (*fraction.Fraction, error) {
+	i := 0
+	for h, k := range cf.convergents() {
+		if i == n {
+			return fraction.MustNew(h, k), nil
+		}
+		i++
+	}
+	return nil, fmt.Errorf("convergent %d is not available", n)
+}
+
+// Evaluate returns the value of the continued fraction as a float, using the
+// last convergent that can be represented without overflow.
+func (cf *ContinuedFraction) Evaluate() float64 {
+	if cf == nil {
+		return math.NaN()
+	}
+	value := math.NaN()
+	for h, k := range cf.convergents() {
+		value = float64(h) / float64(k)
+		if k > 1<<40 {
+			// Far beyond float64 precision already
+			break
+		}
+	}
+	return value
+}
+
+// BestApproximation returns the fraction closest to the value of the
+// continued fraction among all fractions with a denominator of at most
+// maxDenominator. The result is either a convergent or a semiconvergent.
+// Returns an error if maxDenominator is smaller than 1.
+func (cf *ContinuedFraction) BestApproximation(maxDenominator int) (*fraction.Fraction, error) {
+	if maxDenominator < 1 {
+		return nil, errors.New("maximum denominator must be at least 1")
+	}
+	// (h1, k1) is the last convergent within the bound, (h0, k0) the one
+	// before (starting with the formal convergent 1/0).
+	h0, k0, h1, k1 := 1, 0, 0, 1
+	n := 0
+	for h, k := range cf.convergents() {
+		if k > maxDenominator {
+			break
+		}
+		h0, k0, h1, k1 = h1, k1, h, k
+		n++
+	}
+	a, ok := cf.Term(n)
+	if !ok || n == 0 {
+		// All convergents are within the bound (finite continued fraction).
+		return fraction.MustNew(h1, k1), nil
+	}
+	// Largest semiconvergent (h0 + j*h1) / (k0 + j*k1) within the bound.
+	j := (maxDenominator - k0) / k1
+	if 2*j < a {
+		return fraction.MustNew(h1, k1), nil
+	}
+	semi := fraction.MustNew(h0+j*h1, k0+j*k1)
+	if 2*j > a {
+		return semi, nil
+	}
+	// Tie: compare the distances to the value.
+	value := cf.Evaluate()
+	if math.Abs(semi.Evaluate()-value) < math.Abs(float64(h1)/float64(k1)-value) {
+		return semi, nil
+	}
+	return fraction.MustNew(h1, k1), nil
+}
+
+// convergents returns an iterator over the numerators and denominators of the
+// convergents, stopping before an overflow.
+func (cf *ContinuedFraction) convergents() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		h0, k0, h1, k1 := 0, 1, 1, 0
+		for term := range cf.Terms() {
+			h, ok1 := mulAdd(term, h1, h0)
+			k, ok2 := mulAdd(term, k1, k0)
+			if !ok1 || !ok2 {
+				return
+			}
+			if !yield(h, k) {
+				return
+			}
+			h0, k0, h1, k1 = h1, k1, h, k
+		}
+	}
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// euclid returns the continued fraction terms of numerator/denominator.
+func euclid(numerator, denominator int) []int {
+	if denominator < 0 {
+		numerator, denominator = -numerator, -denominator
+	}
+	var terms []int
+	for denominator != 0 {
+		a := floorDiv(numerator, denominator)
+		terms = append(terms, a)
+		numerator, denominator = denominator, numerator-a*denominator
+	}
+	return terms
+}
+
+// floorDiv returns floor(a / b).
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// isqrt returns floor(sqrt(n)) for n >= 0.
+func isqrt(n int) int {
+	r := int(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
+
+// mulAdd returns a*b + c and a boolean value that indicates if the result
+// fits in an int.
+func mulAdd(a, b, c int) (int, bool) {
+	negative := (a < 0) != (b < 0)
+	hi, lo := bits.Mul64(uint64(wbmath.Abs(a)), uint64(wbmath.Abs(b)))
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	product := int(lo)
+	if negative {
+		product = -product
+	}
+	sum := product + c
+	if (c > 0 && sum < product) || (c < 0 && sum > product) {
+		return 0, false
+	}
+	return sum, true
+}
@@ -0,0 +1,90 @@
+package contfrac
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func TestConstructors(t *testing.T) {
+	cases := []struct {
+		name string
+		cf   *ContinuedFraction
+		want string
+	}{
+		{"New", MustNew(1, 2, 2), "[1; 2, 2]"},
+		{"Sqrt(2)", must(Sqrt(2)), "[1; (2)]"},
+		{"Sqrt(7)", must(Sqrt(7)), "[2; (1, 1, 1, 4)]"},
+		{"Sqrt(16)", must(Sqrt(16)), "[4]"},
+		{"golden ratio", must(NewQuadratic(1, 2, 5)), "[(1)]"},
+		{"NewFromFraction", must(NewFromFraction(fraction.MustNew(-415, 93))), "[-5; 1, 1, 6, 7]"},
+	}
+	for _, c := range cases {
+		if got := c.cf.String(); got != c.want {
+			t.Fatalf("%s = %q; want %q", c.name, got, c.want)
+		}
+	}
+	if _, err := New(1, 0); err == nil {
+		t.Fatalf("New with a zero term should return error")
+	}
+	if _, err := Sqrt(-1); err == nil {
+		t.Fatalf("Sqrt(-1) should return error")
+	}
+}
+
+func TestConvergents(t *testing.T) {
+	var got []string
+	for c := range must(Sqrt(2)).Convergents() {
+		got = append(got, c.AsIntegerRatio())
+		if len(got) == 5 {
+			break
+		}
+	}
+	if want := []string{"1/1", "3/2", "7/5", "17/12", "41/29"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Convergents = %v; want %v", got, want)
+	}
+	c, err := MustNew(3, 7, 15, 1, 292).Convergent(3)
+	if err != nil || c.AsIntegerRatio() != "355/113" {
+		t.Fatalf("Convergent(3) = %v, %v; want 355/113", c, err)
+	}
+	if _, err := MustNew(1, 2).Convergent(5); err == nil {
+		t.Fatalf("Convergent beyond the last term should return error")
+	}
+	if v := must(Sqrt(2)).Evaluate(); math.Abs(v-math.Sqrt2) > 1e-15 {
+		t.Fatalf("Evaluate = %v; want %v", v, math.Sqrt2)
+	}
+}
+
+func TestBestApproximation(t *testing.T) {
+	pi := MustNew(3, 7, 15, 1, 292, 1, 1, 1, 2, 1, 3)
+	cases := []struct {
+		maxDenominator int
+		want           string
+	}{
+		{1, "3/1"},
+		{10, "22/7"},
+		{100, "311/99"},
+		{1000, "355/113"},
+	}
+	for _, c := range cases {
+		got, err := pi.BestApproximation(c.maxDenominator)
+		if err != nil || got.AsIntegerRatio() != c.want {
+			t.Fatalf("BestApproximation(%d) = %v, %v; want %s", c.maxDenominator, got, err, c.want)
+		}
+	}
+	if got, _ := must(Sqrt(2)).BestApproximation(10); got.AsIntegerRatio() != "7/5" {
+		t.Fatalf("BestApproximation of sqrt(2) = %v; want 7/5", got)
+	}
+	if _, err := pi.BestApproximation(0); err == nil {
+		t.Fatalf("BestApproximation(0) should return error")
+	}
+}
+
+func must(cf *ContinuedFraction, err error) *ContinuedFraction {
+	if err != nil {
+		panic(err)
+	}
+	return cf
+}
@@ -0,0 +1,487 @@
+// Package decimal provides a fixed-point Decimal type: an int64 mantissa with
+// a decimal scale, representing mantissa * 10^-scale. Contrary to float64,
+// values like 0.1 are represented exactly, which gives the decimal semantics
+// expected in currency-adjacent code.
+//
+// Important details:
+//
+// (*) Decimal is an immutable value type: methods return a new Decimal.
+//
+// (*) The scale is limited to 0..MaxScale (18), so 10^scale fits in an int64
+// and every Decimal converts losslessly to a Fraction.
+//
+// (*) Addition, subtraction and multiplication are exact. They return an error
+// instead of silently overflowing when the result doesn't fit. Division (and
+// any other operation that has to drop digits) takes an explicit scale and
+// RoundingMode.
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// MaxScale is the largest supported number of decimal places.
+const MaxScale = 18
+
+// RoundingMode determines how digits are dropped when rounding.
+type RoundingMode int
+
+const (
+	// HalfUp rounds to the nearest value, ties away from zero (1.5 -> 2,
+	// -1.5 -> -2). This is the rounding taught in school.
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to the nearest value, ties to the even neighbour
+	// (1.5 -> 2, 2.5 -> 2). Also known as banker's rounding.
+	HalfEven
+	// HalfDown rounds to the nearest value, ties towards zero.
+	HalfDown
+	// Down truncates towards zero.
+	Down
+	// Up rounds away from zero.
+	Up
+	// Floor rounds towards negative infinity.
+	Floor
+	// Ceiling rounds towards positive infinity.
+	Ceiling
+)
+
+// Decimal represents the value mantissa * 10^-scale.
+type Decimal struct {
+	mantissa int64
+	scale    int
+}
+
+// pow10 holds the powers of ten up to 10^MaxScale.
+var pow10 = func() [MaxScale + 1]int64 {
+	var powers [MaxScale + 1]int64
+	powers[0] = 1
+	for i := 1; i <= MaxScale; i++ {
+		powers[i] = powers[i-1] * 10
+	}
+	return powers
+}()
+
+var errOverflow = errors.New("decimal overflow")
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the Decimal mantissa *
+// 10^-scale, e.g. New(1234, 2) is 12.34. Returns an error if the scale is not
+// in the range 0..MaxScale.
+func New(mantissa int64, scale int) (Decimal, error) {
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("scale must be between 0 and %d", MaxScale)
+	}
+	return Decimal{mantissa: mantissa, scale: scale}, nil
+}
+
+// MustNew is a constructor identical to New but which panics if an error
+// occurs.
+func MustNew(mantissa int64, scale int) Decimal {
+	d, err := New(mantissa, scale)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewFromInt is a constructor function that returns the Decimal for an
+// integer value (scale 0).
+func NewFromInt(value int64) Decimal {
+	return Decimal{mantissa: value}
+}
+
+// decimalPattern matches an optional sign, digits with an optional decimal
+// point and an optional exponent.
+var decimalPattern = regexp.MustCompile(`^([+\-]?)(\d*)(?:\.(\d*))?(?:[eE]([+\-]?\d+))?$`)
+
+// Parse is a constructor function that parses strings like "12.34", "-0.5",
+// ".25" or "1.5e3". The scale of the result is the number of decimal places
+// (after applying the exponent), so "1.50" has scale 2. Returns an error if
+// the string is invalid, if the value doesn't fit or if more than MaxScale
+// decimal places are needed.
+func Parse(s string) (Decimal, error) {
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil || match[2]+match[3] == "" {
+		return Decimal{}, errors.New("invalid decimal format")
+	}
+	digits := match[2] + match[3]
+	scale := len(match[3])
+	if match[4] != "" {
+		exponent, err := strconv.Atoi(match[4])
+		if err != nil {
+			return Decimal{}, errors.New("invalid decimal format")
+		}
+		// A result has at most 19 integer digits and MaxScale decimal places,
+		// so other exponents are rejected before 10^exponent is computed.
+		switch {
+		case exponent < -(len(digits) + MaxScale):
+			return Decimal{}, fmt.Errorf("more than %d decimal places", MaxScale)
+		case exponent > len(digits)+19 && strings.Trim(digits, "0") != "":
+			return Decimal{}, errOverflow
+		case exponent > len(match[3]) && strings.Trim(digits, "0") == "":
+			// Zero stays zero, whatever the exponent.
+			exponent = len(match[3])
+		}
+		scale -= exponent
+	}
+	mantissa, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, errors.New("invalid decimal format")
+	}
+	if scale < 0 {
+		mantissa.Mul(mantissa, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+	if scale > MaxScale {
+		return Decimal{}, fmt.Errorf("more than %d decimal places", MaxScale)
+	}
+	if match[1] == "-" {
+		mantissa.Neg(mantissa)
+	}
+	if !mantissa.IsInt64() {
+		return Decimal{}, errOverflow
+	}
+	return Decimal{mantissa: mantissa.Int64(), scale: scale}, nil
+}
+
+// MustParse is a constructor identical to Parse but which panics if an error
+// occurs.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewFromFraction is a constructor function that converts a Fraction to a
+// Decimal with the specified scale, rounding with the specified mode. Returns
+// an error if the Fraction is nil, if the scale is invalid or if the result
+// doesn't fit.
+func NewFromFraction(f *fraction.Fraction, scale int, mode RoundingMode) (Decimal, error) {
+	if f == nil {
+		return Decimal{}, fraction.ErrNilFraction
+	}
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("scale must be between 0 and %d", MaxScale)
+	}
+	numerator, _ := f.Numerator()
+	denominator, _ := f.Denominator()
+	num := new(big.Int).Mul(big.NewInt(int64(numerator)), big.NewInt(pow10[scale]))
+	return fromQuotient(num, big.NewInt(int64(denominator)), scale, mode)
+}
+
+// NewFromFractionExact is a constructor function that converts a Fraction to
+// a Decimal without loss, using the smallest possible scale. Returns an error
+// if the Fraction is nil or has no finite decimal expansion (like 1/3), or if
+// the result doesn't fit.
+func NewFromFractionExact(f *fraction.Fraction) (Decimal, error) {
+	if f == nil {
+		return Decimal{}, fraction.ErrNilFraction
+	}
+	numerator, _ := f.Numerator()
+	denominator, _ := f.Denominator()
+	if denominator == 0 {
+		return Decimal{}, fraction.ErrDivisionByZero
+	}
+	// A fraction in lowest terms has a finite decimal expansion if and only if
+	// its denominator has no prime factors other than 2 and 5.
+	var twos, fives int
+	rest := denominator / wbmath.Gcd(wbmath.Abs(numerator), denominator)
+	for rest%2 == 0 {
+		rest /= 2
+		twos++
+	}
+	for rest%5 == 0 {
+		rest /= 5
+		fives++
+	}
+	if rest != 1 {
+		return Decimal{}, errors.New("fraction has no finite decimal expansion")
+	}
+	scale := max(twos, fives)
+	if scale > MaxScale {
+		return Decimal{}, fmt.Errorf("more than %d decimal places", MaxScale)
+	}
+	return NewFromFraction(f, scale, Down)
+}
+
+// ============================================================================
+// Accessors and conversion
+// ============================================================================
+
+// Mantissa returns the unscaled value of the Decimal.
+func (d Decimal) Mantissa() int64 {
+	return d.mantissa
+}
+
+// Scale returns the number of decimal places of the Decimal.
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the Decimal.
+func (d Decimal) Sign() int {
+	switch {
+	case d.mantissa < 0:
+		return -1
+	case d.mantissa > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero checks if the Decimal equals zero.
+func (d Decimal) IsZero() bool {
+	return d.mantissa == 0
+}
+
+// Fraction returns the Decimal as a simplified Fraction. The conversion is
+// lossless.
+func (d Decimal) Fraction() *fraction.Fraction {
+	return fraction.MustNew(int(d.mantissa), int(pow10[d.scale])).Simplify()
+}
+
+// Float64 returns the nearest float64 value of the Decimal.
+func (d Decimal) Float64() float64 {
+	value, _ := new(big.Rat).SetFrac(big.NewInt(d.mantissa), big.NewInt(pow10[d.scale])).Float64()
+	return value
+}
+
+// String implements the fmt.Stringer interface and returns the Decimal in
+// plain notation with exactly Scale decimal places, e.g. "-12.30".
+func (d Decimal) String() string {
+	digits := strconv.FormatUint(absUint(d.mantissa), 10)
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+	result := digits
+	if d.scale > 0 {
+		result = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.mantissa < 0 {
+		return "-" + result
+	}
+	return result
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns d + other. The scale of the result is the larger of both
+// scales. Returns an error on overflow.
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	a, b, err := align(d, other)
+	if err != nil {
+		return Decimal{}, err
+	}
+	sum := a.mantissa + b.mantissa
+	// Overflow if both operands have the same sign and the sum doesn't.
+	if (a.mantissa >= 0) == (b.mantissa >= 0) && (sum >= 0) != (a.mantissa >= 0) {
+		return Decimal{}, errOverflow
+	}
+	return Decimal{mantissa: sum, scale: a.scale}, nil
+}
+
+// Subtract returns d - other. The scale of the result is the larger of both
+// scales. Returns an error on overflow.
+func (d Decimal) Subtract(other Decimal) (Decimal, error) {
+	a, b, err := align(d, other)
+	if err != nil {
+		return Decimal{}, err
+	}
+	difference := a.mantissa - b.mantissa
+	// Overflow if the operands have different signs and the difference
+	// doesn't have the sign of the first one.
+	if (a.mantissa >= 0) != (b.mantissa >= 0) && (difference >= 0) != (a.mantissa >= 0) {
+		return Decimal{}, errOverflow
+	}
+	return Decimal{mantissa: difference, scale: a.scale}, nil
+}
+
+// Multiply returns d * other. The scale of the result is the sum of both
+// scales. Returns an error on overflow or if the scale exceeds MaxScale (use
+// MultiplyRound in that case).
+func (d Decimal) Multiply(other Decimal) (Decimal, error) {
+	if d.scale+other.scale > MaxScale {
+		return Decimal{}, fmt.Errorf("more than %d decimal places", MaxScale)
+	}
+	product := new(big.Int).Mul(big.NewInt(d.mantissa), big.NewInt(other.mantissa))
+	if !product.IsInt64() {
+		return Decimal{}, errOverflow
+	}
+	return Decimal{mantissa: product.Int64(), scale: d.scale + other.scale}, nil
+}
+
+// MultiplyRound returns d * other rounded to the specified scale with the
+// specified mode. Returns an error on overflow or if the scale is invalid.
+func (d Decimal) MultiplyRound(other Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("scale must be between 0 and %d", MaxScale)
+	}
+	// d*other = (ma*mb) / 10^(sa+sb); rescaled: (ma*mb*10^scale) / 10^(sa+sb)
+	num := new(big.Int).Mul(big.NewInt(d.mantissa), big.NewInt(other.mantissa))
+	num.Mul(num, big.NewInt(pow10[scale]))
+	den := new(big.Int).Mul(big.NewInt(pow10[d.scale]), big.NewInt(pow10[other.scale]))
+	return fromQuotient(num, den, scale, mode)
+}
+
+// Divide returns d / other rounded to the specified scale with the specified
+// mode. Returns fraction.ErrDivisionByZero if other is zero, or an error on
+// overflow or if the scale is invalid.
+func (d Decimal) Divide(other Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	if other.mantissa == 0 {
+		return Decimal{}, fraction.ErrDivisionByZero
+	}
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("scale must be between 0 and %d", MaxScale)
+	}
+	// (ma / 10^sa) / (mb / 10^sb) * 10^scale = ma * 10^(sb+scale) / (mb * 10^sa)
+	num := new(big.Int).Mul(big.NewInt(d.mantissa), big.NewInt(pow10[other.scale]))
+	num.Mul(num, big.NewInt(pow10[scale]))
+	den := new(big.Int).Mul(big.NewInt(other.mantissa), big.NewInt(pow10[d.scale]))
+	return fromQuotient(num, den, scale, mode)
+}
+
+// Negate returns -d. Note that the negation of the smallest mantissa
+// (math.MinInt64) overflows and returns d unchanged.
+func (d Decimal) Negate() Decimal {
+	if d.mantissa == math.MinInt64 {
+		return d
+	}
+	return Decimal{mantissa: -d.mantissa, scale: d.scale}
+}
+
+// Abs returns the absolute value of d (see Negate for the single exception).
+func (d Decimal) Abs() Decimal {
+	if d.mantissa < 0 {
+		return d.Negate()
+	}
+	return d
+}
+
+// Round returns d rounded to the specified number of decimal places with the
+// specified mode. If d already has fewer decimal places it is returned
+// unchanged (use Rescale to add trailing zeros).
+func (d Decimal) Round(scale int, mode RoundingMode) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	if scale >= d.scale {
+		return d
+	}
+	result, _ := fromQuotient(big.NewInt(d.mantissa), big.NewInt(pow10[d.scale-scale]), scale, mode)
+	return result
+}
+
+// Rescale returns d with exactly the specified number of decimal places:
+// trailing zeros are added or digits are rounded away with the specified
+// mode. Returns an error on overflow or if the scale is invalid.
+func (d Decimal) Rescale(scale int, mode RoundingMode) (Decimal, error) {
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("scale must be between 0 and %d", MaxScale)
+	}
+	if scale <= d.scale {
+		return d.Round(scale, mode), nil
+	}
+	product := new(big.Int).Mul(big.NewInt(d.mantissa), big.NewInt(pow10[scale-d.scale]))
+	if !product.IsInt64() {
+		return Decimal{}, errOverflow
+	}
+	return Decimal{mantissa: product.Int64(), scale: scale}, nil
+}
+
+// Compare returns -1 if d < other, 0 if d == other and 1 if d > other. The
+// scales don't matter: 1.5 equals 1.50.
+func (d Decimal) Compare(other Decimal) int {
+	a := new(big.Int).Mul(big.NewInt(d.mantissa), big.NewInt(pow10[other.scale]))
+	b := new(big.Int).Mul(big.NewInt(other.mantissa), big.NewInt(pow10[d.scale]))
+	return a.Cmp(b)
+}
+
+// Equals checks if d and other have the same value (regardless of scale).
+func (d Decimal) Equals(other Decimal) bool {
+	return d.Compare(other) == 0
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// align returns a and b rescaled to the larger of both scales.
+func align(a, b Decimal) (Decimal, Decimal, error) {
+	var err error
+	if a.scale < b.scale {
+		a, err = a.Rescale(b.scale, Down)
+	} else if b.scale < a.scale {
+		b, err = b.Rescale(a.scale, Down)
+	}
+	return a, b, err
+}
+
+// fromQuotient returns the Decimal with the specified scale whose mantissa is
+// num / den rounded to an integer with the specified mode.
+func fromQuotient(num, den *big.Int, scale int, mode RoundingMode) (Decimal, error) {
+	if den.Sign() < 0 {
+		num = new(big.Int).Neg(num)
+		den = new(big.Int).Neg(den)
+	}
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	if remainder.Sign() != 0 {
+		negative := num.Sign() < 0
+		// Compare 2*|remainder| with den to find out where the value lies
+		// between the truncated quotient and the next value away from zero.
+		half := new(big.Int).Abs(remainder)
+		half.Lsh(half, 1)
+		cmp := half.Cmp(den)
+		awayFromZero := false
+		switch mode {
+		case HalfUp:
+			awayFromZero = cmp >= 0
+		case HalfDown:
+			awayFromZero = cmp > 0
+		case HalfEven:
+			awayFromZero = cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1)
+		case Down:
+			awayFromZero = false
+		case Up:
+			awayFromZero = true
+		case Floor:
+			awayFromZero = negative
+		case Ceiling:
+			awayFromZero = !negative
+		}
+		if awayFromZero {
+			if negative {
+				quotient.Sub(quotient, big.NewInt(1))
+			} else {
+				quotient.Add(quotient, big.NewInt(1))
+			}
+		}
+	}
+	if !quotient.IsInt64() {
+		return Decimal{}, errOverflow
+	}
+	return Decimal{mantissa: quotient.Int64(), scale: scale}, nil
+}
+
+// absUint returns |value| as an uint64 (also for math.MinInt64).
+func absUint(value int64) uint64 {
+	if value < 0 {
+		return uint64(-(value + 1)) + 1
+	}
+	return uint64(value)
+}
@@ -0,0 +1,124 @@
+package decimal
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func TestParseAndString(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"12.34", "12.34"},
+		{"-0.5", "-0.5"},
+		{".25", "0.25"},
+		{"1.50", "1.50"},
+		{"1.5e3", "1500"},
+		{"25e-4", "0.0025"},
+		{"+7", "7"},
+		{"0e100000000", "0"},
+		{"0.00e-5", "0.0000000"},
+	}
+	for _, c := range cases {
+		d, err := Parse(c.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", c.input, err)
+		}
+		if got := d.String(); got != c.want {
+			t.Fatalf("Parse(%q).String() = %q; want %q", c.input, got, c.want)
+		}
+	}
+	for _, input := range []string{"", ".", "1.2.3", "abc", "99999999999999999999", "1e-19",
+		"1e100000000", "1e-100000000", "1e9223372036854775807", "1e-9223372036854775808", "1e99999999999999999999"} {
+		if _, err := Parse(input); err == nil {
+			t.Fatalf("Parse(%q) should return error", input)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("0.1")
+	b := MustParse("0.2")
+	if sum, _ := a.Add(b); !sum.Equals(MustParse("0.3")) {
+		t.Fatalf("0.1 + 0.2 = %v; want 0.3", sum)
+	}
+	if diff, _ := MustParse("1.00").Subtract(MustParse("0.005")); diff.String() != "0.995" {
+		t.Fatalf("1.00 - 0.005 = %v; want 0.995", diff)
+	}
+	if product, _ := MustParse("1.5").Multiply(MustParse("-2.25")); product.String() != "-3.375" {
+		t.Fatalf("1.5 * -2.25 = %v; want -3.375", product)
+	}
+	if quotient, _ := MustParse("10").Divide(MustParse("3"), 4, HalfUp); quotient.String() != "3.3333" {
+		t.Fatalf("10 / 3 = %v; want 3.3333", quotient)
+	}
+	if _, err := a.Divide(NewFromInt(0), 2, HalfUp); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("Divide by zero error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := MustNew(9223372036854775807, 0).Add(NewFromInt(1)); err == nil {
+		t.Fatalf("Add overflow should return error")
+	}
+	// -1 - MinInt64 = MaxInt64 fits, although -MinInt64 doesn't.
+	if diff, err := NewFromInt(-1).Subtract(NewFromInt(math.MinInt64)); err != nil || diff.String() != "9223372036854775807" {
+		t.Fatalf("-1 - MinInt64 = %v, %v; want 9223372036854775807", diff, err)
+	}
+	if _, err := NewFromInt(0).Subtract(NewFromInt(math.MinInt64)); err == nil {
+		t.Fatalf("0 - MinInt64 should return error")
+	}
+	if _, err := NewFromInt(math.MinInt64).Subtract(NewFromInt(1)); err == nil {
+		t.Fatalf("MinInt64 - 1 should return error")
+	}
+	if MustParse("1.5").Compare(MustParse("1.50")) != 0 || MustParse("-2").Compare(MustParse("1")) != -1 {
+		t.Fatalf("Compare mismatch")
+	}
+}
+
+func TestRounding(t *testing.T) {
+	cases := []struct {
+		value string
+		mode  RoundingMode
+		want  string
+	}{
+		{"2.5", HalfUp, "3"}, {"-2.5", HalfUp, "-3"},
+		{"2.5", HalfEven, "2"}, {"3.5", HalfEven, "4"},
+		{"2.5", HalfDown, "2"}, {"2.51", HalfDown, "3"},
+		{"2.9", Down, "2"}, {"-2.9", Down, "-2"},
+		{"2.1", Up, "3"}, {"-2.1", Up, "-3"},
+		{"-2.1", Floor, "-3"}, {"2.1", Floor, "2"},
+		{"-2.9", Ceiling, "-2"}, {"2.1", Ceiling, "3"},
+	}
+	for _, c := range cases {
+		if got := MustParse(c.value).Round(0, c.mode).String(); got != c.want {
+			t.Fatalf("Round(%s, mode %d) = %s; want %s", c.value, c.mode, got, c.want)
+		}
+	}
+	if got, _ := MustParse("1.5").Rescale(3, HalfUp); got.String() != "1.500" {
+		t.Fatalf("Rescale(3) = %v; want 1.500", got)
+	}
+}
+
+func TestFractionConversion(t *testing.T) {
+	if f := MustParse("-1.25").Fraction(); f.AsIntegerRatio() != "-5/4" {
+		t.Fatalf("Fraction() = %v; want -5/4", f.AsIntegerRatio())
+	}
+	d, err := NewFromFractionExact(fraction.MustNew(3, 40))
+	if err != nil || d.String() != "0.075" {
+		t.Fatalf("NewFromFractionExact(3/40) = %v, %v; want 0.075", d, err)
+	}
+	if _, err := NewFromFractionExact(fraction.MustNew(1, 3)); err == nil {
+		t.Fatalf("NewFromFractionExact(1/3) should return error")
+	}
+	d, err = NewFromFraction(fraction.MustNew(-2, 3), 2, HalfEven)
+	if err != nil || d.String() != "-0.67" {
+		t.Fatalf("NewFromFraction(-2/3, 2) = %v, %v; want -0.67", d, err)
+	}
+	// Round trip
+	original := MustParse("123.456")
+	back, _ := NewFromFractionExact(original.Fraction())
+	if !back.Equals(original) {
+		t.Fatalf("round trip = %v; want %v", back, original)
+	}
+}
@@ -0,0 +1,407 @@
+// Package dist provides probability distributions that generate random
+// variates directly as vectors. Every distribution exposes its mean and
+// variance and a Sample method that draws n variates from a caller-supplied
+// rand.Source, so simulations are reproducible: the same seed always gives
+// the same Vector.
+//
+// Continuous distributions: Uniform, Normal, Exponential and Gamma.
+// Discrete distributions (which also expose SampleInt): Bernoulli, Binomial,
+// Geometric and Poisson.
+//
+// Important details:
+//
+// (*) Distributions are value types created with NewXxx constructors that
+// validate the parameters and return an error for invalid ones.
+//
+// (*) Sources come from math/rand/v2, e.g. rand.NewPCG(1, 2). A source is
+// not safe for concurrent use; use one source per goroutine.
+package dist
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Distribution is implemented by every distribution in this package.
+type Distribution interface {
+	Mean() float64
+	Variance() float64
+	Sample(n int, src rand.Source) vector.Vector[float64]
+}
+
+// Discrete is implemented by distributions with integer support.
+type Discrete interface {
+	Distribution
+	SampleInt(n int, src rand.Source) vector.Vector[int]
+}
+
+// ============================================================================
+// Continuous distributions
+// ============================================================================
+
+// Uniform is the continuous uniform distribution on [min, max).
+type Uniform struct {
+	min, max float64
+}
+
+// NewUniform is a constructor function that returns the uniform distribution
+// on [min, max). Returns an error if min >= max.
+func NewUniform(min, max float64) (Uniform, error) {
+	if !(min < max) {
+		return Uniform{}, errors.New("min must be smaller than max")
+	}
+	return Uniform{min: min, max: max}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Uniform) Mean() float64 { return (d.min + d.max) / 2 }
+
+// Variance returns the variance of the distribution.
+func (d Uniform) Variance() float64 { return (d.max - d.min) * (d.max - d.min) / 12 }
+
+// Sample draws n variates using the specified source.
+func (d Uniform) Sample(n int, src rand.Source) vector.Vector[float64] {
+	rng := rand.New(src)
+	return sample(n, func() float64 { return d.min + (d.max-d.min)*rng.Float64() })
+}
+
+// Normal is the normal (Gaussian) distribution.
+type Normal struct {
+	mu, sigma float64
+}
+
+// NewNormal is a constructor function that returns the normal distribution
+// with mean mu and standard deviation sigma. Returns an error if sigma is not
+// positive.
+func NewNormal(mu, sigma float64) (Normal, error) {
+	if !(sigma > 0) {
+		return Normal{}, errors.New("sigma must be positive")
+	}
+	return Normal{mu: mu, sigma: sigma}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Normal) Mean() float64 { return d.mu }
+
+// Variance returns the variance of the distribution.
+func (d Normal) Variance() float64 { return d.sigma * d.sigma }
+
+// Sample draws n variates using the specified source.
+func (d Normal) Sample(n int, src rand.Source) vector.Vector[float64] {
+	rng := rand.New(src)
+	return sample(n, func() float64 { return d.mu + d.sigma*rng.NormFloat64() })
+}
+
+// Exponential is the exponential distribution with the specified rate.
+type Exponential struct {
+	rate float64
+}
+
+// NewExponential is a constructor function that returns the exponential
+// distribution with the specified rate. Returns an error if the rate is not
+// positive.
+func NewExponential(rate float64) (Exponential, error) {
+	if !(rate > 0) {
+		return Exponential{}, errors.New("rate must be positive")
+	}
+	return Exponential{rate: rate}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Exponential) Mean() float64 { return 1 / d.rate }
+
+// Variance returns the variance of the distribution.
+func (d Exponential) Variance() float64 { return 1 / (d.rate * d.rate) }
+
+// Sample draws n variates using the specified source.
+func (d Exponential) Sample(n int, src rand.Source) vector.Vector[float64] {
+	rng := rand.New(src)
+	return sample(n, func() float64 { return rng.ExpFloat64() / d.rate })
+}
+
+// Gamma is the gamma distribution with the specified shape and rate.
+type Gamma struct {
+	shape, rate float64
+}
+
+// NewGamma is a constructor function that returns the gamma distribution
+// with the specified shape and rate. Returns an error if either is not
+// positive.
+func NewGamma(shape, rate float64) (Gamma, error) {
+	if !(shape > 0) || !(rate > 0) {
+		return Gamma{}, errors.New("shape and rate must be positive")
+	}
+	return Gamma{shape: shape, rate: rate}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Gamma) Mean() float64 { return d.shape / d.rate }
+
+// Variance returns the variance of the distribution.
+func (d Gamma) Variance() float64 { return d.shape / (d.rate * d.rate) }
+
+// Sample draws n variates using the specified source (Marsaglia-Tsang).
+func (d Gamma) Sample(n int, src rand.Source) vector.Vector[float64] {
+	rng := rand.New(src)
+	return sample(n, func() float64 { return gamma(rng, d.shape) / d.rate })
+}
+
+// ============================================================================
+// Discrete distributions
+// ============================================================================
+
+// Bernoulli is the Bernoulli distribution: 1 with probability p, 0 otherwise.
+type Bernoulli struct {
+	p float64
+}
+
+// NewBernoulli is a constructor function that returns the Bernoulli
+// distribution with success probability p. Returns an error if p is not in
+// [0, 1].
+func NewBernoulli(p float64) (Bernoulli, error) {
+	if !(p >= 0 && p <= 1) {
+		return Bernoulli{}, errors.New("p must be in [0, 1]")
+	}
+	return Bernoulli{p: p}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Bernoulli) Mean() float64 { return d.p }
+
+// Variance returns the variance of the distribution.
+func (d Bernoulli) Variance() float64 { return d.p * (1 - d.p) }
+
+// Sample draws n variates using the specified source.
+func (d Bernoulli) Sample(n int, src rand.Source) vector.Vector[float64] {
+	return d.SampleInt(n, src).CloneAsFloat64()
+}
+
+// SampleInt draws n variates using the specified source.
+func (d Bernoulli) SampleInt(n int, src rand.Source) vector.Vector[int] {
+	rng := rand.New(src)
+	return sampleInt(n, func() int {
+		if rng.Float64() < d.p {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Binomial is the distribution of the number of successes in n independent
+// trials with success probability p.
+type Binomial struct {
+	n int
+	p float64
+}
+
+// NewBinomial is a constructor function that returns the binomial
+// distribution. Returns an error if n is negative or p is not in [0, 1].
+func NewBinomial(n int, p float64) (Binomial, error) {
+	if n < 0 {
+		return Binomial{}, errors.New("n must not be negative")
+	}
+	if !(p >= 0 && p <= 1) {
+		return Binomial{}, errors.New("p must be in [0, 1]")
+	}
+	return Binomial{n: n, p: p}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Binomial) Mean() float64 { return float64(d.n) * d.p }
+
+// Variance returns the variance of the distribution.
+func (d Binomial) Variance() float64 { return float64(d.n) * d.p * (1 - d.p) }
+
+// Sample draws n variates using the specified source.
+func (d Binomial) Sample(n int, src rand.Source) vector.Vector[float64] {
+	return d.SampleInt(n, src).CloneAsFloat64()
+}
+
+// SampleInt draws n variates using the specified source.
+func (d Binomial) SampleInt(n int, src rand.Source) vector.Vector[int] {
+	rng := rand.New(src)
+	return sampleInt(n, func() int { return binomial(rng, d.n, d.p) })
+}
+
+// Geometric is the distribution of the number of trials up to and including
+// the first success, with success probability p (support 1, 2, ...).
+type Geometric struct {
+	p float64
+}
+
+// NewGeometric is a constructor function that returns the geometric
+// distribution. Returns an error if p is not in (0, 1].
+func NewGeometric(p float64) (Geometric, error) {
+	if !(p > 0 && p <= 1) {
+		return Geometric{}, errors.New("p must be in (0, 1]")
+	}
+	return Geometric{p: p}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Geometric) Mean() float64 { return 1 / d.p }
+
+// Variance returns the variance of the distribution.
+func (d Geometric) Variance() float64 { return (1 - d.p) / (d.p * d.p) }
+
+// Sample draws n variates using the specified source.
+func (d Geometric) Sample(n int, src rand.Source) vector.Vector[float64] {
+	return d.SampleInt(n, src).CloneAsFloat64()
+}
+
+// SampleInt draws n variates using the specified source.
+func (d Geometric) SampleInt(n int, src rand.Source) vector.Vector[int] {
+	rng := rand.New(src)
+	return sampleInt(n, func() int {
+		if d.p == 1 {
+			return 1
+		}
+		// Inversion: 1 - Float64() lies in (0, 1], so the logarithm is finite.
+		return 1 + int(math.Floor(math.Log(1-rng.Float64())/math.Log1p(-d.p)))
+	})
+}
+
+// Poisson is the Poisson distribution with mean lambda.
+type Poisson struct {
+	lambda float64
+}
+
+// NewPoisson is a constructor function that returns the Poisson distribution
+// with mean lambda. Returns an error if lambda is not positive.
+func NewPoisson(lambda float64) (Poisson, error) {
+	if !(lambda > 0) || math.IsInf(lambda, 1) {
+		return Poisson{}, errors.New("lambda must be positive and finite")
+	}
+	return Poisson{lambda: lambda}, nil
+}
+
+// Mean returns the mean of the distribution.
+func (d Poisson) Mean() float64 { return d.lambda }
+
+// Variance returns the variance of the distribution.
+func (d Poisson) Variance() float64 { return d.lambda }
+
+// Sample draws n variates using the specified source.
+func (d Poisson) Sample(n int, src rand.Source) vector.Vector[float64] {
+	return d.SampleInt(n, src).CloneAsFloat64()
+}
+
+// SampleInt draws n variates using the specified source.
+func (d Poisson) SampleInt(n int, src rand.Source) vector.Vector[int] {
+	rng := rand.New(src)
+	return sampleInt(n, func() int { return poisson(rng, d.lambda) })
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+func sample(n int, next func() float64) vector.Vector[float64] {
+	result := vector.NewFromValue(0.0, max(n, 0))
+	for i := range result {
+		result[i] = next()
+	}
+	return result
+}
+
+func sampleInt(n int, next func() int) vector.Vector[int] {
+	result := vector.NewFromValue(0, max(n, 0))
+	for i := range result {
+		result[i] = next()
+	}
+	return result
+}
+
+// gamma returns a Gamma(shape, 1) variate using the method of Marsaglia and
+// Tsang; shapes below 1 are boosted with U^(1/shape).
+func gamma(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return gamma(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < x*x/2+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// binomial returns a Binomial(n, p) variate by inversion. To keep q^n from
+// underflowing, large n are split into chunks whose sums are added.
+func binomial(rng *rand.Rand, n int, p float64) int {
+	if p > 0.5 {
+		return n - binomial(rng, n, 1-p)
+	}
+	if p == 0 {
+		return 0
+	}
+	chunk := n
+	if limit := int(300 / p); chunk > limit {
+		chunk = limit
+	}
+	total := 0
+	for n > 0 {
+		m := min(n, chunk)
+		n -= m
+		q := 1 - p
+		s := p / q
+		a := float64(m+1) * s
+		r := math.Pow(q, float64(m))
+		u := rng.Float64()
+		x := 0
+		for u > r && x < m {
+			u -= r
+			x++
+			r *= a/float64(x) - s
+		}
+		total += x
+	}
+	return total
+}
+
+// poisson returns a Poisson(lambda) variate: multiplication of uniforms for
+// small lambda and Hörmann's transformed rejection (PTRS) otherwise.
+func poisson(rng *rand.Rand, lambda float64) int {
+	if lambda < 30 {
+		limit := math.Exp(-lambda)
+		k, product := 0, rng.Float64()
+		for product > limit {
+			k++
+			product *= rng.Float64()
+		}
+		return k
+	}
+	sqrtLambda := math.Sqrt(lambda)
+	logLambda := math.Log(lambda)
+	b := 0.931 + 2.53*sqrtLambda
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u := rng.Float64() - 0.5
+		v := rng.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + 0.43)
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		logGamma, _ := math.Lgamma(k + 1)
+		if math.Log(v*invAlpha/(a/(us*us)+b)) <= -lambda+k*logLambda-logGamma {
+			return int(k)
+		}
+	}
+}
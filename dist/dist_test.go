@@ -0,0 +1,70 @@
+package dist
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func moments(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, x := range values {
+		mean += x
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, x := range values {
+		variance += (x - mean) * (x - mean)
+	}
+	return mean, variance / float64(len(values)-1)
+}
+
+func TestSampleMoments(t *testing.T) {
+	must := func(d Distribution, err error) Distribution {
+		if err != nil {
+			t.Fatalf("constructor returned error: %v", err)
+		}
+		return d
+	}
+	distributions := []Distribution{
+		must(NewUniform(-1, 3)),
+		must(NewNormal(2, 0.5)),
+		must(NewExponential(4)),
+		must(NewGamma(0.5, 2)),
+		must(NewGamma(7, 1)),
+		must(NewBernoulli(0.3)),
+		must(NewBinomial(20, 0.7)),
+		must(NewBinomial(100000, 0.01)),
+		must(NewGeometric(0.25)),
+		must(NewPoisson(3)),
+		must(NewPoisson(250)),
+	}
+	const n = 50000
+	for _, d := range distributions {
+		mean, variance := moments(d.Sample(n, rand.NewPCG(1, 2)))
+		stdErr := math.Sqrt(d.Variance() / n)
+		if math.Abs(mean-d.Mean()) > 5*stdErr {
+			t.Fatalf("%T: sample mean = %v; want %v", d, mean, d.Mean())
+		}
+		if math.Abs(variance-d.Variance()) > 0.05*d.Variance() {
+			t.Fatalf("%T: sample variance = %v; want %v", d, variance, d.Variance())
+		}
+	}
+}
+
+func TestReproducible(t *testing.T) {
+	d, _ := NewPoisson(12)
+	a := d.SampleInt(100, rand.NewPCG(42, 7))
+	b := d.SampleInt(100, rand.NewPCG(42, 7))
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("same seed gave different samples")
+	}
+	var _ Discrete = d
+	if _, err := NewNormal(0, 0); err == nil {
+		t.Fatalf("NewNormal(0, 0) should return error")
+	}
+	if _, err := NewBinomial(-1, 0.5); err == nil {
+		t.Fatalf("NewBinomial(-1, 0.5) should return error")
+	}
+}
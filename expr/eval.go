@@ -0,0 +1,298 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// Backend defines how the nodes of an expression are evaluated for values of
+// type T. Implementations must not modify their arguments.
+type Backend[T any] interface {
+	// Number converts a numeric literal to a value.
+	Number(literal string) (T, error)
+	// Variable returns the value of a variable that was not found in the
+	// variables passed to Evaluate (e.g. a constant like pi).
+	Variable(name string) (T, error)
+	// Unary applies a unary operator ('+' or '-').
+	Unary(operator byte, operand T) (T, error)
+	// Binary applies a binary operator ('+', '-', '*', '/' or '^').
+	Binary(operator byte, left, right T) (T, error)
+	// Call calls the function with the specified name.
+	Call(name string, arguments []T) (T, error)
+}
+
+// Evaluate evaluates the expression with the specified backend. Variables are
+// looked up in `variables` first and then passed to the backend.
+func Evaluate[T any](e *Expr, backend Backend[T], variables map[string]T) (T, error) {
+	return evaluate(e.root, backend, variables)
+}
+
+func evaluate[T any](node Node, backend Backend[T], variables map[string]T) (T, error) {
+	var zero T
+	switch n := node.(type) {
+	case *NumberNode:
+		return backend.Number(n.Literal)
+	case *VariableNode:
+		if value, ok := variables[n.Name]; ok {
+			return value, nil
+		}
+		return backend.Variable(n.Name)
+	case *UnaryNode:
+		operand, err := evaluate(n.Operand, backend, variables)
+		if err != nil {
+			return zero, err
+		}
+		return backend.Unary(n.Operator, operand)
+	case *BinaryNode:
+		left, err := evaluate(n.Left, backend, variables)
+		if err != nil {
+			return zero, err
+		}
+		right, err := evaluate(n.Right, backend, variables)
+		if err != nil {
+			return zero, err
+		}
+		return backend.Binary(n.Operator, left, right)
+	case *CallNode:
+		arguments := make([]T, len(n.Arguments))
+		for i, argument := range n.Arguments {
+			value, err := evaluate(argument, backend, variables)
+			if err != nil {
+				return zero, err
+			}
+			arguments[i] = value
+		}
+		return backend.Call(n.Name, arguments)
+	default:
+		return zero, fmt.Errorf("unknown node type %T", node)
+	}
+}
+
+// Eval evaluates the expression with float64 values (see FloatBackend).
+func (e *Expr) Eval(variables map[string]float64) (float64, error) {
+	return Evaluate[float64](e, FloatBackend{}, variables)
+}
+
+// EvalFraction evaluates the expression exactly with Fraction values (see
+// FractionBackend). The Fractions in `variables` are not modified.
+func (e *Expr) EvalFraction(variables map[string]*fraction.Fraction) (*fraction.Fraction, error) {
+	return Evaluate[*fraction.Fraction](e, FractionBackend{}, variables)
+}
+
+// ============================================================================
+// Float backend
+// ============================================================================
+
+// FloatBackend evaluates expressions with float64 values. The constants pi
+// and e are available, as well as the functions abs, sqrt, cbrt, exp, ln,
+// log (base 10), log2, sin, cos, tan, asin, acos, atan, atan2, floor, ceil,
+// round, pow, min and max.
+type FloatBackend struct{}
+
+// floatFunctions maps function names to their implementation and their
+// number of arguments (-1 means: one or more).
+var floatFunctions = map[string]struct {
+	arity int
+	apply func(arguments []float64) float64
+}{
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"cbrt":  {1, func(a []float64) float64 { return math.Cbrt(a[0]) }},
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"ln":    {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"log2":  {1, func(a []float64) float64 { return math.Log2(a[0]) }},
+	"sin":   {1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"tan":   {1, func(a []float64) float64 { return math.Tan(a[0]) }},
+	"asin":  {1, func(a []float64) float64 { return math.Asin(a[0]) }},
+	"acos":  {1, func(a []float64) float64 { return math.Acos(a[0]) }},
+	"atan":  {1, func(a []float64) float64 { return math.Atan(a[0]) }},
+	"atan2": {2, func(a []float64) float64 { return math.Atan2(a[0], a[1]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"round": {1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"pow":   {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min": {-1, func(a []float64) float64 {
+		result := a[0]
+		for _, value := range a[1:] {
+			result = math.Min(result, value)
+		}
+		return result
+	}},
+	"max": {-1, func(a []float64) float64 {
+		result := a[0]
+		for _, value := range a[1:] {
+			result = math.Max(result, value)
+		}
+		return result
+	}},
+}
+
+// Number parses the literal as a float64.
+func (FloatBackend) Number(literal string) (float64, error) {
+	return strconv.ParseFloat(literal, 64)
+}
+
+// Variable returns the value of the constants pi and e.
+func (FloatBackend) Variable(name string) (float64, error) {
+	switch name {
+	case "pi":
+		return math.Pi, nil
+	case "e":
+		return math.E, nil
+	}
+	return 0, fmt.Errorf("unknown variable %q", name)
+}
+
+// Unary applies a unary operator.
+func (FloatBackend) Unary(operator byte, operand float64) (float64, error) {
+	if operator == '-' {
+		return -operand, nil
+	}
+	return operand, nil
+}
+
+// Binary applies a binary operator. Division by zero follows IEEE 754 and
+// yields an infinity or NaN.
+func (FloatBackend) Binary(operator byte, left, right float64) (float64, error) {
+	switch operator {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	case '/':
+		return left / right, nil
+	case '^':
+		return math.Pow(left, right), nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", operator)
+}
+
+// Call calls one of the supported functions.
+func (FloatBackend) Call(name string, arguments []float64) (float64, error) {
+	function, ok := floatFunctions[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown function %q", name)
+	}
+	if err := checkArity(name, function.arity, len(arguments)); err != nil {
+		return 0, err
+	}
+	return function.apply(arguments), nil
+}
+
+// ============================================================================
+// Fraction backend
+// ============================================================================
+
+// FractionBackend evaluates expressions exactly with Fraction values. Number
+// literals are converted exactly (0.1 becomes 1/10). Powers are supported for
+// rational exponents as long as the result is rational: 4^(1/2) = 2, but
+// 2^(1/2) returns an error. The functions abs, sqrt and pow are available.
+// All results are simplified.
+type FractionBackend struct{}
+
+// Number converts the literal to a Fraction.
+func (FractionBackend) Number(literal string) (*fraction.Fraction, error) {
+	return fraction.NewFromString(literal + "/1")
+}
+
+// Variable always returns an error: the fraction backend has no constants.
+func (FractionBackend) Variable(name string) (*fraction.Fraction, error) {
+	return nil, fmt.Errorf("unknown variable %q", name)
+}
+
+// Unary applies a unary operator.
+func (FractionBackend) Unary(operator byte, operand *fraction.Fraction) (*fraction.Fraction, error) {
+	if operand == nil {
+		return nil, fraction.ErrNilFraction
+	}
+	if operator == '-' {
+		return operand.Clone().MultiplyInt(-1), nil
+	}
+	return operand.Clone(), nil
+}
+
+// Binary applies a binary operator. Returns fraction.ErrDivisionByZero on
+// division by zero.
+func (FractionBackend) Binary(operator byte, left, right *fraction.Fraction) (*fraction.Fraction, error) {
+	if left == nil || right == nil {
+		return nil, fraction.ErrNilFraction
+	}
+	switch operator {
+	case '+':
+		return left.Clone().Add(right).Simplify(), nil
+	case '-':
+		return left.Clone().Subtract(right).Simplify(), nil
+	case '*':
+		return left.Clone().Multiply(right).Simplify(), nil
+	case '/':
+		reciprocal, err := right.Clone().Reciprocal()
+		if err != nil {
+			return nil, err
+		}
+		return left.Clone().Multiply(reciprocal).Simplify(), nil
+	case '^':
+		return powFraction(left, right)
+	}
+	return nil, fmt.Errorf("unknown operator %q", operator)
+}
+
+// Call calls one of the supported functions.
+func (b FractionBackend) Call(name string, arguments []*fraction.Fraction) (*fraction.Fraction, error) {
+	for _, argument := range arguments {
+		if argument == nil {
+			return nil, fraction.ErrNilFraction
+		}
+	}
+	switch name {
+	case "abs":
+		if err := checkArity(name, 1, len(arguments)); err != nil {
+			return nil, err
+		}
+		result := arguments[0].Clone()
+		if numerator, _ := result.Numerator(); numerator < 0 {
+			result.MultiplyInt(-1)
+		}
+		return result, nil
+	case "sqrt":
+		if err := checkArity(name, 1, len(arguments)); err != nil {
+			return nil, err
+		}
+		return arguments[0].Clone().Simplify().NthRoot(2)
+	case "pow":
+		if err := checkArity(name, 2, len(arguments)); err != nil {
+			return nil, err
+		}
+		return powFraction(arguments[0], arguments[1])
+	}
+	return nil, fmt.Errorf("unknown function %q", name)
+}
+
+// powFraction raises base to a rational exponent p/q with
+// Fraction.PowFraction. Returns an error if the result isn't rational or if
+// zero is raised to a negative power.
+func powFraction(base, exponent *fraction.Fraction) (*fraction.Fraction, error) {
+	return base.Clone().Simplify().PowFraction(exponent)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// checkArity returns an error if the number of arguments doesn't match the
+// expected number (-1 means: one or more).
+func checkArity(name string, expected, actual int) error {
+	if expected == -1 && actual == 0 {
+		return fmt.Errorf("function %q expects at least 1 argument", name)
+	}
+	if expected != -1 && expected != actual {
+		return fmt.Errorf("function %q expects %d argument(s) but got %d", name, expected, actual)
+	}
+	return nil
+}
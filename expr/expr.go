@@ -0,0 +1,393 @@
+// Package expr parses arithmetic expressions like "2*x^2 - sqrt(y) / 3" into
+// an abstract syntax tree (AST) that can be evaluated repeatedly against
+// different variable values.
+//
+// Evaluation is done through a Backend: FloatBackend evaluates with float64
+// values (including functions like sin, sqrt and pow), FractionBackend
+// evaluates exactly with *fraction.Fraction values. Custom backends can be
+// provided by implementing the Backend interface.
+//
+// Important details:
+//
+// (*) Supported operators are +, -, *, / and ^ (power, right-associative),
+// with the usual precedence. Unary minus binds weaker than ^, so -2^2 = -4.
+//
+// (*) Numbers can be integers or floats, including scientific notation.
+// Identifiers start with a letter or underscore and may contain digits.
+//
+// (*) An Expr is immutable after parsing and safe for concurrent evaluation.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// AST
+// ============================================================================
+
+// Node is a node of the abstract syntax tree of an expression. The concrete
+// node types are NumberNode, VariableNode, UnaryNode, BinaryNode and CallNode.
+type Node interface {
+	// String returns the node formatted as an (fully parenthesized) expression.
+	String() string
+}
+
+// NumberNode is a numeric literal, stored as written in the expression so
+// each backend can interpret it with its own precision.
+type NumberNode struct {
+	Literal string
+}
+
+// VariableNode is a reference to a variable by name.
+type VariableNode struct {
+	Name string
+}
+
+// UnaryNode is a unary operation: Operator is '+' or '-'.
+type UnaryNode struct {
+	Operator byte
+	Operand  Node
+}
+
+// BinaryNode is a binary operation: Operator is one of '+', '-', '*', '/'
+// or '^'.
+type BinaryNode struct {
+	Operator    byte
+	Left, Right Node
+}
+
+// CallNode is a function call with zero or more arguments.
+type CallNode struct {
+	Name      string
+	Arguments []Node
+}
+
+func (n *NumberNode) String() string   { return n.Literal }
+func (n *VariableNode) String() string { return n.Name }
+func (n *UnaryNode) String() string    { return fmt.Sprintf("(%c%s)", n.Operator, n.Operand) }
+func (n *BinaryNode) String() string {
+	return fmt.Sprintf("(%s %c %s)", n.Left, n.Operator, n.Right)
+}
+func (n *CallNode) String() string {
+	arguments := make([]string, len(n.Arguments))
+	for i, argument := range n.Arguments {
+		arguments[i] = argument.String()
+	}
+	return fmt.Sprintf("%s(%s)", n.Name, strings.Join(arguments, ", "))
+}
+
+// ============================================================================
+// Expr
+// ============================================================================
+
+// Expr is a parsed expression.
+type Expr struct {
+	source string
+	root   Node
+}
+
+// Parse parses the specified expression and returns a pointer to an Expr and
+// an error (which mentions the position of the problem) if the expression is
+// invalid.
+func Parse(source string) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %q at position %d", token.text, token.position)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// MustParse is identical to Parse but panics if an error occurs.
+func MustParse(source string) *Expr {
+	e, err := Parse(source)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Root returns the root node of the abstract syntax tree.
+func (e *Expr) Root() Node {
+	return e.root
+}
+
+// Source returns the expression as it was passed to Parse.
+func (e *Expr) Source() string {
+	return e.source
+}
+
+// String implements the fmt.Stringer interface and returns the fully
+// parenthesized form of the expression, which shows how it was parsed.
+func (e *Expr) String() string {
+	return e.root.String()
+}
+
+// Variables returns the sorted names of all variables used in the expression.
+func (e *Expr) Variables() []string {
+	seen := make(map[string]bool)
+	var walk func(Node)
+	walk = func(node Node) {
+		switch n := node.(type) {
+		case *VariableNode:
+			seen[n.Name] = true
+		case *UnaryNode:
+			walk(n.Operand)
+		case *BinaryNode:
+			walk(n.Left)
+			walk(n.Right)
+		case *CallNode:
+			for _, argument := range n.Arguments {
+				walk(argument)
+			}
+		}
+	}
+	walk(e.root)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ============================================================================
+// Tokenizer
+// ============================================================================
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenIdentifier
+	tokenOperator
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// tokenize splits the source into tokens. Identifiers may contain any Unicode
+// letters and digits, e.g. "π"; numbers consist of ASCII digits. Positions
+// are byte offsets.
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(source) {
+		c, size := utf8.DecodeRuneInString(source[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case isDigit(source[i]) || c == '.':
+			start := i
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			// Optional exponent: e / E, an optional sign and at least one digit.
+			if i < len(source) && (source[i] == 'e' || source[i] == 'E') {
+				j := i + 1
+				if j < len(source) && (source[j] == '+' || source[j] == '-') {
+					j++
+				}
+				if j < len(source) && isDigit(source[j]) {
+					for j < len(source) && isDigit(source[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			literal := source[start:i]
+			if strings.Count(literal, ".") > 1 || literal == "." {
+				return nil, fmt.Errorf("invalid number %q at position %d", literal, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: literal, position: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(source) {
+				r, n := utf8.DecodeRuneInString(source[i:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				i += n
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: source[start:i], position: start})
+		case strings.ContainsRune("+-*/^(),", c):
+			tokens = append(tokens, token{kind: tokenOperator, text: string(c), position: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEnd, text: "end of expression", position: len(source)})
+	return tokens, nil
+}
+
+// isDigit checks if b is an ASCII digit.
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+// parser is a recursive descent parser for the grammar:
+//
+//	expression := term (('+' | '-') term)*
+//	term       := unary (('*' | '/') unary)*
+//	unary      := ('+' | '-') unary | power
+//	power      := primary ('^' unary)?
+//	primary    := number | identifier | identifier '(' arguments ')' | '(' expression ')'
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEnd {
+		p.position++
+	}
+	return t
+}
+
+func (p *parser) isOperator(operators string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && strings.Contains(operators, t.text)
+}
+
+func (p *parser) expect(operator string) error {
+	t := p.next()
+	if t.kind != tokenOperator || t.text != operator {
+		return fmt.Errorf("expected %q but found %q at position %d", operator, t.text, t.position)
+	}
+	return nil
+}
+
+func (p *parser) parseExpression() (Node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+-") {
+		operator := p.next().text[0]
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryNode{Operator: operator, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*/") {
+		operator := p.next().text[0]
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryNode{Operator: operator, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.isOperator("+-") {
+		operator := p.next().text[0]
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryNode{Operator: operator, Operand: operand}, nil
+	}
+	return p.parsePower()
+}
+
+func (p *parser) parsePower() (Node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		p.next()
+		// Parsing the exponent as a unary makes ^ right-associative and
+		// allows negative exponents like 2^-1.
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &BinaryNode{Operator: '^', Left: base, Right: exponent}, nil
+	}
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenNumber:
+		return &NumberNode{Literal: t.text}, nil
+	case t.kind == tokenIdentifier:
+		if !p.isOperator("(") {
+			return &VariableNode{Name: t.text}, nil
+		}
+		p.next()
+		call := &CallNode{Name: t.text}
+		if p.isOperator(")") {
+			p.next()
+			return call, nil
+		}
+		for {
+			argument, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			call.Arguments = append(call.Arguments, argument)
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return call, nil
+	case t.kind == tokenOperator && t.text == "(":
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case t.kind == tokenEnd:
+		return nil, errors.New("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.position)
+	}
+}
@@ -0,0 +1,104 @@
+package expr
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func almostEqual(a, b float64) bool {
+	const eps = 1e-9
+	return math.Abs(a-b) <= eps
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"-2^2", "(-(2 ^ 2))"},
+		{"2^3^2", "(2 ^ (3 ^ 2))"},
+		{"2^-1", "(2 ^ (-1))"},
+		{"max(a, b, 1.5e3)", "max(a, b, 1.5e3)"},
+		{"π*2 + Δx", "((π * 2) + Δx)"},
+	}
+	for _, c := range cases {
+		e, err := Parse(c.source)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", c.source, err)
+		}
+		if got := e.String(); got != c.want {
+			t.Fatalf("Parse(%q) = %q; want %q", c.source, got, c.want)
+		}
+	}
+	for _, source := range []string{"", "1 +", "(1", "1 2", "2 $ 3", "f(1,", "1..2", "x\xff"} {
+		if _, err := Parse(source); err == nil {
+			t.Fatalf("Parse(%q) should return error", source)
+		}
+	}
+	// A non-ASCII character is reported as a whole, at its byte offset.
+	if _, err := Parse("x²"); err == nil || !strings.Contains(err.Error(), `'²' at position 1`) {
+		t.Fatalf("Parse(\"x²\") error = %v; want unexpected character '²' at position 1", err)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	e := MustParse("y * sin(x) + x / z")
+	if got := e.Variables(); !reflect.DeepEqual(got, []string{"x", "y", "z"}) {
+		t.Fatalf("Variables() = %v; want [x y z]", got)
+	}
+	if got, err := MustParse("2*r_π").Eval(map[string]float64{"r_π": 1.5}); err != nil || got != 3 {
+		t.Fatalf("Eval with non-ASCII identifier = %v, %v; want 3", got, err)
+	}
+}
+
+func TestEval(t *testing.T) {
+	e := MustParse("2*x^2 - sqrt(y) / 4 + sin(pi / 2)")
+	got, err := e.Eval(map[string]float64{"x": 3, "y": 16})
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if !almostEqual(got, 18) {
+		t.Fatalf("Eval = %v; want 18", got)
+	}
+	if _, err := e.Eval(nil); err == nil {
+		t.Fatalf("Eval with missing variables should return error")
+	}
+	if _, err := MustParse("foo(1)").Eval(nil); err == nil {
+		t.Fatalf("Eval with unknown function should return error")
+	}
+	if _, err := MustParse("pow(1)").Eval(nil); err == nil {
+		t.Fatalf("Eval with wrong number of arguments should return error")
+	}
+}
+
+func TestEvalFraction(t *testing.T) {
+	x := fraction.MustNew(1, 3)
+	got, err := MustParse("x + 0.5 * 2^-1 + (4/9)^(1/2)").EvalFraction(map[string]*fraction.Fraction{"x": x})
+	if err != nil {
+		t.Fatalf("EvalFraction returned error: %v", err)
+	}
+	if s := got.AsIntegerRatio(); s != "5/4" {
+		t.Fatalf("EvalFraction = %q; want \"5/4\"", s)
+	}
+	if s := x.AsIntegerRatio(); s != "1/3" {
+		t.Fatalf("EvalFraction modified variable: %q", s)
+	}
+	if _, err := MustParse("1 / (x - x)").EvalFraction(map[string]*fraction.Fraction{"x": x}); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("EvalFraction with division by zero error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := MustParse("0^-1").EvalFraction(nil); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("EvalFraction of 0^-1 error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := MustParse("2^(1/2)").EvalFraction(nil); !errors.Is(err, fraction.ErrNoExactRoot) {
+		t.Fatalf("EvalFraction of irrational power error = %v; want %v", err, fraction.ErrNoExactRoot)
+	}
+	if got, _ := MustParse("abs(-3/4)").EvalFraction(nil); got.AsIntegerRatio() != "3/4" {
+		t.Fatalf("abs(-3/4) = %v; want 3/4", got)
+	}
+}
@@ -0,0 +1,301 @@
+// Package fft provides fast Fourier transforms of complex128 slices of any
+// length, with an optimized transform for real input.
+//
+// Lengths that are a power of two use an iterative radix-2 Cooley-Tukey
+// algorithm. All other lengths use Bluestein's algorithm, which rewrites the
+// transform as a convolution that is evaluated with power-of-two transforms,
+// so every length runs in O(n log n).
+//
+// Important details:
+//
+// (*) The forward transform is unnormalized: X[k] = sum x[j] exp(-2πijk/n).
+// The inverse transform divides by n, so Inverse(Forward(x)) == x.
+//
+// (*) A Plan precomputes the twiddle factors for one length and can be reused
+// for many transforms (also concurrently). The package-level functions cache
+// their plans by length.
+//
+// (*) Input slices are never modified: all transforms return new slices.
+package fft
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"sync"
+)
+
+// ============================================================================
+// Plan
+// ============================================================================
+
+// Plan holds the precomputed data for transforms of a fixed length.
+type Plan struct {
+	n int
+	// Radix-2 data (n is a power of two)
+	twiddles []complex128
+	reversed []int
+	// Bluestein data (n is not a power of two)
+	chirp  []complex128
+	kernel []complex128
+	inner  *Plan
+}
+
+// NewPlan is a constructor function that returns a pointer to a Plan for
+// transforms of length n. Returns an error if n is smaller than 1.
+func NewPlan(n int) (*Plan, error) {
+	if n < 1 {
+		return nil, errors.New("transform length must be at least 1")
+	}
+	p := &Plan{n: n}
+	if n&(n-1) == 0 {
+		p.initRadix2()
+	} else {
+		p.initBluestein()
+	}
+	return p, nil
+}
+
+// Len returns the transform length of the Plan.
+func (p *Plan) Len() int {
+	return p.n
+}
+
+// Forward returns the discrete Fourier transform of x. Returns an error if
+// the length of x doesn't match the Plan.
+func (p *Plan) Forward(x []complex128) ([]complex128, error) {
+	if len(x) != p.n {
+		return nil, errors.New("input length does not match plan length")
+	}
+	result := append([]complex128(nil), x...)
+	p.transform(result)
+	return result, nil
+}
+
+// Inverse returns the inverse discrete Fourier transform of x (including the
+// 1/n normalization). Returns an error if the length of x doesn't match the
+// Plan.
+func (p *Plan) Inverse(x []complex128) ([]complex128, error) {
+	if len(x) != p.n {
+		return nil, errors.New("input length does not match plan length")
+	}
+	// ifft(x) = conj(fft(conj(x))) / n
+	result := make([]complex128, p.n)
+	for i, value := range x {
+		result[i] = cmplx.Conj(value)
+	}
+	p.transform(result)
+	scale := 1 / float64(p.n)
+	for i, value := range result {
+		result[i] = complex(real(value)*scale, -imag(value)*scale)
+	}
+	return result, nil
+}
+
+// transform computes the forward transform of x in-place.
+func (p *Plan) transform(x []complex128) {
+	if p.inner == nil {
+		p.radix2(x)
+	} else {
+		p.bluestein(x)
+	}
+}
+
+// ============================================================================
+// Radix-2
+// ============================================================================
+
+func (p *Plan) initRadix2() {
+	n := p.n
+	p.twiddles = make([]complex128, n/2)
+	for k := range p.twiddles {
+		p.twiddles[k] = unitRoot(-2*k, 2*n)
+	}
+	p.reversed = make([]int, n)
+	shift := 64 - bits.TrailingZeros(uint(n))
+	for i := range p.reversed {
+		if n > 1 {
+			p.reversed[i] = int(bits.Reverse64(uint64(i)) >> uint(shift))
+		}
+	}
+}
+
+// radix2 is the iterative in-place Cooley-Tukey transform.
+func (p *Plan) radix2(x []complex128) {
+	n := p.n
+	for i, j := range p.reversed {
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		step := n / size
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				t := p.twiddles[k*step] * x[start+k+half]
+				x[start+k+half] = x[start+k] - t
+				x[start+k] += t
+			}
+		}
+	}
+}
+
+// ============================================================================
+// Bluestein
+// ============================================================================
+
+func (p *Plan) initBluestein() {
+	n := p.n
+	// Convolution length: a power of two of at least 2n-1.
+	m := 1 << bits.Len(uint(2*n-2))
+	p.inner, _ = NewPlan(m)
+	// chirp[k] = exp(-πik²/n). k² is reduced modulo 2n to keep precision.
+	p.chirp = make([]complex128, n)
+	for k := range p.chirp {
+		p.chirp[k] = unitRoot(-(k*k)%(2*n), 2*n)
+	}
+	p.kernel = make([]complex128, m)
+	p.kernel[0] = cmplx.Conj(p.chirp[0])
+	for k := 1; k < n; k++ {
+		p.kernel[k] = cmplx.Conj(p.chirp[k])
+		p.kernel[m-k] = p.kernel[k]
+	}
+	p.inner.transform(p.kernel)
+}
+
+// bluestein computes the transform as a circular convolution of length m.
+func (p *Plan) bluestein(x []complex128) {
+	m := p.inner.n
+	a := make([]complex128, m)
+	for k, value := range x {
+		a[k] = value * p.chirp[k]
+	}
+	p.inner.transform(a)
+	for i := range a {
+		a[i] *= p.kernel[i]
+	}
+	// Inverse transform of a, without allocating a new slice.
+	for i := range a {
+		a[i] = cmplx.Conj(a[i])
+	}
+	p.inner.transform(a)
+	scale := 1 / float64(m)
+	for k := range x {
+		x[k] = p.chirp[k] * complex(real(a[k])*scale, -imag(a[k])*scale)
+	}
+}
+
+// ============================================================================
+// Package-level functions with plan cache
+// ============================================================================
+
+var (
+	planCache   = make(map[int]*Plan)
+	planCacheMu sync.Mutex
+)
+
+// CachedPlan returns a Plan for length n from the package-level cache,
+// creating it if needed. Returns an error if n is smaller than 1.
+func CachedPlan(n int) (*Plan, error) {
+	planCacheMu.Lock()
+	defer planCacheMu.Unlock()
+	if p, ok := planCache[n]; ok {
+		return p, nil
+	}
+	p, err := NewPlan(n)
+	if err != nil {
+		return nil, err
+	}
+	planCache[n] = p
+	return p, nil
+}
+
+// Forward returns the discrete Fourier transform of x using a cached Plan.
+// The transform of an empty slice is an empty slice.
+func Forward(x []complex128) []complex128 {
+	if len(x) == 0 {
+		return []complex128{}
+	}
+	p, _ := CachedPlan(len(x))
+	result, _ := p.Forward(x)
+	return result
+}
+
+// Inverse returns the inverse discrete Fourier transform of x using a cached
+// Plan. The transform of an empty slice is an empty slice.
+func Inverse(x []complex128) []complex128 {
+	if len(x) == 0 {
+		return []complex128{}
+	}
+	p, _ := CachedPlan(len(x))
+	result, _ := p.Inverse(x)
+	return result
+}
+
+// RFFT returns the discrete Fourier transform of the real input x. Because
+// the transform of real data is conjugate symmetric, only the first n/2+1
+// coefficients are returned. For even lengths the input is packed into a
+// complex transform of half the length, which is about twice as fast.
+func RFFT(x []float64) []complex128 {
+	n := len(x)
+	if n == 0 {
+		return []complex128{}
+	}
+	if n%2 != 0 {
+		complexX := make([]complex128, n)
+		for i, value := range x {
+			complexX[i] = complex(value, 0)
+		}
+		return Forward(complexX)[:n/2+1]
+	}
+	// Pack even samples in the real part and odd samples in the imaginary
+	// part: z[j] = x[2j] + i x[2j+1]. Then untangle the half-length transform:
+	// X[k] = E[k] + exp(-2πik/n) O[k].
+	half := n / 2
+	z := make([]complex128, half)
+	for j := range z {
+		z[j] = complex(x[2*j], x[2*j+1])
+	}
+	Z := Forward(z)
+	result := make([]complex128, half+1)
+	for k := 0; k <= half; k++ {
+		zk := Z[k%half]
+		zc := cmplx.Conj(Z[(half-k)%half])
+		even := (zk + zc) / 2
+		odd := (zk - zc) / complex(0, 2)
+		result[k] = even + unitRoot(-k, n)*odd
+	}
+	return result
+}
+
+// IRFFT returns the real signal of length n whose transform has the first
+// n/2+1 coefficients X (the output format of RFFT). Returns an error if n is
+// smaller than 1 or if X doesn't have n/2+1 elements.
+func IRFFT(X []complex128, n int) ([]float64, error) {
+	if n < 1 {
+		return nil, errors.New("transform length must be at least 1")
+	}
+	if len(X) != n/2+1 {
+		return nil, errors.New("input must have n/2+1 coefficients")
+	}
+	full := make([]complex128, n)
+	copy(full, X)
+	for k := n/2 + 1; k < n; k++ {
+		full[k] = cmplx.Conj(X[n-k])
+	}
+	inverse := Inverse(full)
+	result := make([]float64, n)
+	for i, value := range inverse {
+		result[i] = real(value)
+	}
+	return result, nil
+}
+
+// unitRoot returns exp(2πi k/n), with k reduced modulo n for precision.
+func unitRoot(k, n int) complex128 {
+	k %= n
+	sin, cos := math.Sincos(2 * math.Pi * float64(k) / float64(n))
+	return complex(cos, sin)
+}
@@ -0,0 +1,109 @@
+package fft
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+// dft is the O(n²) reference implementation.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	result := make([]complex128, n)
+	for k := range result {
+		for j, value := range x {
+			result[k] += value * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/float64(n)))
+		}
+	}
+	return result
+}
+
+func randomSignal(rng *rand.Rand, n int) []complex128 {
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(rng.Float64()-0.5, rng.Float64()-0.5)
+	}
+	return x
+}
+
+func almostEqual(a, b []complex128) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if cmplx.Abs(a[i]-b[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestForwardAndInverse(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 8, 12, 17, 64, 100} {
+		x := randomSignal(rng, n)
+		X := Forward(x)
+		if !almostEqual(X, dft(x)) {
+			t.Fatalf("Forward(n=%d) does not match the DFT", n)
+		}
+		if !almostEqual(Inverse(X), x) {
+			t.Fatalf("Inverse(Forward(x)) != x for n=%d", n)
+		}
+	}
+	if got := Forward(nil); len(got) != 0 {
+		t.Fatalf("Forward(nil) = %v; want empty", got)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	p, err := NewPlan(6)
+	if err != nil {
+		t.Fatalf("NewPlan returned error: %v", err)
+	}
+	x := []complex128{1, 2, 3, 4, 5, 6}
+	X, _ := p.Forward(x)
+	if x[0] != 1 || !almostEqual(X, dft(x)) {
+		t.Fatalf("Plan.Forward = %v; want %v", X, dft(x))
+	}
+	if _, err := p.Forward(x[:3]); err == nil {
+		t.Fatalf("Plan.Forward with wrong length should return error")
+	}
+	if _, err := NewPlan(0); err == nil {
+		t.Fatalf("NewPlan(0) should return error")
+	}
+	cached, _ := CachedPlan(6)
+	if again, _ := CachedPlan(6); again != cached {
+		t.Fatalf("CachedPlan should return the same Plan for the same length")
+	}
+}
+
+func TestRFFT(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, n := range []int{1, 2, 7, 10, 16, 33} {
+		x := make([]float64, n)
+		complexX := make([]complex128, n)
+		for i := range x {
+			x[i] = rng.Float64()
+			complexX[i] = complex(x[i], 0)
+		}
+		X := RFFT(x)
+		if !almostEqual(X, dft(complexX)[:n/2+1]) {
+			t.Fatalf("RFFT(n=%d) does not match the DFT", n)
+		}
+		back, err := IRFFT(X, n)
+		if err != nil {
+			t.Fatalf("IRFFT returned error: %v", err)
+		}
+		for i := range x {
+			if math.Abs(back[i]-x[i]) > tolerance {
+				t.Fatalf("IRFFT(RFFT(x)) != x for n=%d", n)
+			}
+		}
+	}
+	if _, err := IRFFT([]complex128{1, 2}, 8); err == nil {
+		t.Fatalf("IRFFT with wrong number of coefficients should return error")
+	}
+}
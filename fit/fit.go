@@ -0,0 +1,210 @@
+// Package fit provides ordinary least squares (OLS) regression over Vector
+// data: polynomial fits, fits on arbitrary basis functions and fits on a
+// general design matrix. Every fit returns a Model with the coefficients,
+// residuals, coefficient of determination (R²) and the standard errors of the
+// coefficients.
+//
+// The least squares problem is solved with a Householder QR decomposition of
+// the design matrix, which is numerically much more stable than solving the
+// normal equations.
+//
+// Important details:
+//
+// (*) The input Vectors are never modified.
+//
+// (*) Fitting p coefficients requires at least p observations and linearly
+// independent columns; otherwise an error is returned.
+//
+// (*) With exactly p observations the fit is exact and the standard errors are
+// undefined (NaN).
+package fit
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bogersw/wbmath/internal/qr"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Model is the result of a least squares fit.
+type Model struct {
+	// Coefficients holds one coefficient per basis function / column.
+	Coefficients vector.Vector[float64]
+	// Residuals holds y - prediction for every observation.
+	Residuals vector.Vector[float64]
+	// RSquared is the coefficient of determination 1 - RSS/TSS.
+	RSquared float64
+	// StandardErrors holds the standard error of every coefficient.
+	StandardErrors vector.Vector[float64]
+	// basis holds the basis functions for Predict (nil for FitDesign).
+	basis []func(float64) float64
+}
+
+// ============================================================================
+// Fit functions
+// ============================================================================
+
+// Polynomial fits y ≈ c0 + c1*x + ... + cd*x^d. The coefficients are returned
+// in increasing order of the power. Returns an error if the lengths of x and y
+// differ, if degree is negative or if there are too few distinct x values.
+func Polynomial(x, y vector.Vector[float64], degree int) (*Model, error) {
+	if degree < 0 {
+		return nil, errors.New("degree must not be negative")
+	}
+	basis := make([]func(float64) float64, degree+1)
+	for j := range basis {
+		power := j
+		basis[j] = func(x float64) float64 { return math.Pow(x, float64(power)) }
+	}
+	return Basis(x, y, basis...)
+}
+
+// Basis fits y ≈ c0*f0(x) + c1*f1(x) + ... for the specified basis functions.
+// For example: Basis(x, y, func(float64) float64 { return 1 }, math.Sin) fits
+// y ≈ c0 + c1*sin(x). Returns an error if the lengths of x and y differ, if no
+// basis functions are specified or if the problem is underdetermined.
+func Basis(x, y vector.Vector[float64], basis ...func(float64) float64) (*Model, error) {
+	if len(x) != len(y) {
+		return nil, errors.New("x and y must have the same length")
+	}
+	if len(basis) == 0 {
+		return nil, errors.New("at least one basis function is required")
+	}
+	columns := make([]vector.Vector[float64], len(basis))
+	for j, f := range basis {
+		columns[j] = vector.NewFromValue(0.0, len(x))
+		for i, value := range x {
+			columns[j][i] = f(value)
+		}
+	}
+	model, err := FitDesign(columns, y)
+	if err != nil {
+		return nil, err
+	}
+	model.basis = append([]func(float64) float64(nil), basis...)
+	return model, nil
+}
+
+// FitDesign fits y ≈ c0*columns[0] + c1*columns[1] + ... for a general design
+// matrix given as columns (one Vector per regressor). Add a column of ones to
+// include an intercept. Returns an error if the columns and y don't all have
+// the same length, if there are fewer observations than columns or if the
+// columns are linearly dependent.
+func FitDesign(columns []vector.Vector[float64], y vector.Vector[float64]) (*Model, error) {
+	p := len(columns)
+	n := len(y)
+	if p == 0 {
+		return nil, errors.New("at least one column is required")
+	}
+	for _, column := range columns {
+		if len(column) != n {
+			return nil, errors.New("all columns must have the same length as y")
+		}
+	}
+	if n < p {
+		return nil, errors.New("fewer observations than coefficients")
+	}
+	// a holds the columns of the design matrix, qty becomes Q^T y.
+	a := make([][]float64, p)
+	for j, column := range columns {
+		a[j] = append([]float64(nil), column...)
+	}
+	qty := append([]float64(nil), y...)
+	r, err := qr.Householder(a, qty)
+	if err != nil {
+		return nil, err
+	}
+	coefficients := vector.Vector[float64](qr.Substitute(r, qty))
+	model := &Model{Coefficients: coefficients}
+	// Residuals, RSS and TSS
+	model.Residuals = y.Clone()
+	for j, column := range columns {
+		model.Residuals.Subtract(column.Clone().Scale(coefficients[j]), 0)
+	}
+	rss := model.Residuals.Clone().Multiply(model.Residuals, 0).Sum()
+	mean := y.Sum() / float64(n)
+	tss := 0.0
+	for _, value := range y {
+		tss += (value - mean) * (value - mean)
+	}
+	switch {
+	case tss > 0:
+		model.RSquared = 1 - rss/tss
+	case rss <= 1e-24:
+		model.RSquared = 1
+	}
+	model.StandardErrors = standardErrors(r, rss, n)
+	return model, nil
+}
+
+// ============================================================================
+// Prediction
+// ============================================================================
+
+// Predict returns the value of the fitted model at x. Returns NaN for models
+// created with FitDesign: use PredictRow instead.
+func (m *Model) Predict(x float64) float64 {
+	if m.basis == nil {
+		return math.NaN()
+	}
+	result := 0.0
+	for j, f := range m.basis {
+		result += m.Coefficients[j] * f(x)
+	}
+	return result
+}
+
+// PredictVector returns the values of the fitted model at every element of x.
+func (m *Model) PredictVector(x vector.Vector[float64]) vector.Vector[float64] {
+	result := vector.NewFromValue(0.0, len(x))
+	for i, value := range x {
+		result[i] = m.Predict(value)
+	}
+	return result
+}
+
+// PredictRow returns the value of the fitted model for one row of regressor
+// values (one value per column of the design). Returns an error if the
+// length of the row doesn't match the number of coefficients.
+func (m *Model) PredictRow(row vector.Vector[float64]) (float64, error) {
+	return m.Coefficients.DotProduct(row)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// standardErrors returns sqrt(sigma² * diag((R^T R)^-1)) with sigma² the
+// residual variance RSS / (n - p).
+func standardErrors(r [][]float64, rss float64, n int) vector.Vector[float64] {
+	p := len(r)
+	result := vector.NewFromValue(math.NaN(), p)
+	if n <= p {
+		return result
+	}
+	sigma2 := rss / float64(n-p)
+	// rInv = R^-1 (upper triangular); diag((R^T R)^-1) = squared row norms.
+	rInv := make([][]float64, p)
+	for i := range rInv {
+		rInv[i] = make([]float64, p)
+	}
+	for j := 0; j < p; j++ {
+		rInv[j][j] = 1 / r[j][j]
+		for i := j - 1; i >= 0; i-- {
+			sum := 0.0
+			for k := i + 1; k <= j; k++ {
+				sum += r[i][k] * rInv[k][j]
+			}
+			rInv[i][j] = -sum / r[i][i]
+		}
+	}
+	for i := 0; i < p; i++ {
+		sum := 0.0
+		for j := i; j < p; j++ {
+			sum += rInv[i][j] * rInv[i][j]
+		}
+		result[i] = math.Sqrt(sigma2 * sum)
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package fit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+func almostEqual(a, b, eps float64) bool {
+	return math.Abs(a-b) <= eps
+}
+
+func TestPolynomial(t *testing.T) {
+	// y = 1 - 2x + 3x^2 exactly
+	x := vector.New(-2.0, -1, 0, 1, 2, 3)
+	y := vector.New(17.0, 6, 1, 2, 9, 22)
+	model, err := Polynomial(x, y, 2)
+	if err != nil {
+		t.Fatalf("Polynomial returned error: %v", err)
+	}
+	for i, want := range []float64{1, -2, 3} {
+		if !almostEqual(model.Coefficients[i], want, 1e-9) {
+			t.Fatalf("Coefficients = %v; want [1 -2 3]", model.Coefficients)
+		}
+	}
+	if !almostEqual(model.RSquared, 1, 1e-12) || !almostEqual(model.Predict(4), 41, 1e-9) {
+		t.Fatalf("RSquared = %v, Predict(4) = %v; want 1, 41", model.RSquared, model.Predict(4))
+	}
+	if x[0] != -2 || y[0] != 17 {
+		t.Fatalf("Polynomial modified its input")
+	}
+	if _, err := Polynomial(x, y, 6); err == nil {
+		t.Fatalf("Polynomial with too few observations should return error")
+	}
+	if _, err := Polynomial(x, y[:3], 1); err == nil {
+		t.Fatalf("Polynomial with different lengths should return error")
+	}
+}
+
+func TestLinearStatistics(t *testing.T) {
+	// Reference values computed with numpy / statsmodels.
+	x := vector.New(1.0, 2, 3, 4, 5)
+	y := vector.New(2.0, 4, 5, 4, 5)
+	model, err := Polynomial(x, y, 1)
+	if err != nil {
+		t.Fatalf("Polynomial returned error: %v", err)
+	}
+	if !almostEqual(model.Coefficients[0], 2.2, 1e-9) || !almostEqual(model.Coefficients[1], 0.6, 1e-9) {
+		t.Fatalf("Coefficients = %v; want [2.2 0.6]", model.Coefficients)
+	}
+	if !almostEqual(model.RSquared, 0.6, 1e-9) {
+		t.Fatalf("RSquared = %v; want 0.6", model.RSquared)
+	}
+	if !almostEqual(model.StandardErrors[0], 0.9380831519646859, 1e-9) ||
+		!almostEqual(model.StandardErrors[1], 0.282842712474619, 1e-9) {
+		t.Fatalf("StandardErrors = %v; want [0.938 0.283]", model.StandardErrors)
+	}
+	if want := []float64{-0.8, 0.6, 1, -0.6, -0.2}; !almostEqual(model.Residuals[0], want[0], 1e-9) ||
+		!almostEqual(model.Residuals[4], want[4], 1e-9) {
+		t.Fatalf("Residuals = %v; want %v", model.Residuals, want)
+	}
+}
+
+func TestBasisAndDesign(t *testing.T) {
+	x := vector.NewFromRange(0, math.Pi, 20)
+	y := x.Clone().Map(func(v float64) float64 { return 2 + 3*math.Sin(v) })
+	model, err := Basis(x, y, func(float64) float64 { return 1 }, math.Sin)
+	if err != nil {
+		t.Fatalf("Basis returned error: %v", err)
+	}
+	if !almostEqual(model.Coefficients[0], 2, 1e-9) || !almostEqual(model.Coefficients[1], 3, 1e-9) {
+		t.Fatalf("Coefficients = %v; want [2 3]", model.Coefficients)
+	}
+	ones := vector.NewFromValue(1.0, 4)
+	x1 := vector.New(1.0, 2, 3, 4)
+	x2 := vector.New(1.0, 0, 1, 0)
+	design, err := FitDesign([]vector.Vector[float64]{ones, x1, x2}, vector.New(4.0, 5, 8, 9))
+	if err != nil {
+		t.Fatalf("FitDesign returned error: %v", err)
+	}
+	if got, err := design.PredictRow(vector.New(1.0, 5, 1)); err != nil || !almostEqual(got, 12, 1e-9) {
+		t.Fatalf("PredictRow = %v, %v; want 12", got, err)
+	}
+	if !math.IsNaN(design.Predict(1)) {
+		t.Fatalf("Predict on a design model should return NaN")
+	}
+	if _, err := FitDesign([]vector.Vector[float64]{x1, x1.Clone().Scale(2)}, x1); err == nil {
+		t.Fatalf("FitDesign with dependent columns should return error")
+	}
+}
@@ -0,0 +1,224 @@
+// Package format provides human-friendly formatting of numbers: significant
+// figures, engineering notation, SI prefixes (1.5k, 3.2µ) and thousands
+// separators. Every function accepts float64, *fraction.Fraction and
+// decimal.Decimal values.
+//
+// Important details:
+//
+// (*) Significant figures are kept, including trailing zeros: Significant(2,
+// 3) is "2.00".
+//
+// (*) Thousands rounds fractions and decimals exactly (half-even); the other
+// functions work on the float64 value of their input.
+//
+// (*) NaN and infinities are formatted as "NaN", "+Inf" and "-Inf"; a nil
+// Fraction is formatted as "NaN".
+package format
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bogersw/wbmath/decimal"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// Number is the constraint for the values that can be formatted.
+type Number interface {
+	float64 | *fraction.Fraction | decimal.Decimal
+}
+
+// siPrefixes maps exponents (multiples of 3) to SI prefixes.
+var siPrefixes = map[int]string{
+	-24: "y", -21: "z", -18: "a", -15: "f", -12: "p", -9: "n", -6: "µ", -3: "m",
+	0: "", 3: "k", 6: "M", 9: "G", 12: "T", 15: "P", 18: "E", 21: "Z", 24: "Y",
+}
+
+// ============================================================================
+// Significant figures
+// ============================================================================
+
+// RoundSignificant rounds the value to the specified number of significant
+// figures (at least 1), e.g. RoundSignificant(123456.0, 2) is 120000.
+func RoundSignificant[T Number](value T, figures int) float64 {
+	x := toFloat(value)
+	if special(x) || x == 0 {
+		return x
+	}
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(x, 'e', max(figures, 1)-1, 64), 64)
+	return rounded
+}
+
+// Significant formats the value in plain notation with the specified number
+// of significant figures (at least 1), e.g. Significant(0.0012345, 3) is
+// "0.00123" and Significant(123456.0, 2) is "120000".
+func Significant[T Number](value T, figures int) string {
+	x := toFloat(value)
+	if special(x) {
+		return specialString(x)
+	}
+	digits, exponent, negative := significantDigits(x, figures)
+	var result string
+	switch {
+	case exponent < 0:
+		result = "0." + strings.Repeat("0", -exponent-1) + digits
+	case exponent+1 >= len(digits):
+		result = digits + strings.Repeat("0", exponent+1-len(digits))
+	default:
+		result = digits[:exponent+1] + "." + digits[exponent+1:]
+	}
+	return sign(negative) + result
+}
+
+// ============================================================================
+// Engineering notation and SI prefixes
+// ============================================================================
+
+// Engineering formats the value in engineering notation (an exponent that is
+// a multiple of 3) with the specified number of significant figures, e.g.
+// Engineering(12345.0, 3) is "12.3e3". The exponent is omitted when it is 0.
+func Engineering[T Number](value T, figures int) string {
+	x := toFloat(value)
+	if special(x) {
+		return specialString(x)
+	}
+	mantissa, exponent := engineering(x, figures)
+	if exponent == 0 {
+		return mantissa
+	}
+	return mantissa + "e" + strconv.Itoa(exponent)
+}
+
+// SI formats the value with an SI prefix and the specified number of
+// significant figures, e.g. SI(1500.0, 2) is "1.5k" and SI(0.0000032, 2) is
+// "3.2µ". Append a unit to get "1.5kΩ". Values beyond the range of the SI
+// prefixes (10^-24 to 10^27) fall back to engineering notation.
+func SI[T Number](value T, figures int) string {
+	x := toFloat(value)
+	if special(x) {
+		return specialString(x)
+	}
+	mantissa, exponent := engineering(x, figures)
+	prefix, ok := siPrefixes[exponent]
+	if !ok {
+		return mantissa + "e" + strconv.Itoa(exponent)
+	}
+	return mantissa + prefix
+}
+
+// ============================================================================
+// Thousands separators
+// ============================================================================
+
+// Thousands formats the value with the specified number of decimals and
+// commas between groups of three digits, e.g. Thousands(1234567.891, 2) is
+// "1,234,567.89". Fractions and decimals are rounded exactly (half-even);
+// a negative number of decimals is treated as 0.
+func Thousands[T Number](value T, decimals int) string {
+	decimals = max(decimals, 0)
+	var text string
+	switch v := any(value).(type) {
+	case float64:
+		if special(v) {
+			return specialString(v)
+		}
+		text = strconv.FormatFloat(v, 'f', decimals, 64)
+	case *fraction.Fraction:
+		if v == nil {
+			return "NaN"
+		}
+		d, err := decimal.NewFromFraction(v, min(decimals, decimal.MaxScale), decimal.HalfEven)
+		if err != nil {
+			// Too large for a Decimal: fall back to float64.
+			return Thousands(v.Evaluate(), decimals)
+		}
+		text = padDecimals(d.String(), decimals)
+	case decimal.Decimal:
+		text = padDecimals(v.Round(min(decimals, decimal.MaxScale), decimal.HalfEven).String(), decimals)
+	}
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+	integer, fractional, hasFraction := strings.Cut(text, ".")
+	var sb strings.Builder
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(c)
+	}
+	if hasFraction {
+		sb.WriteString("." + fractional)
+	}
+	return sign(negative) + sb.String()
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+func toFloat[T Number](value T) float64 {
+	switch v := any(value).(type) {
+	case float64:
+		return v
+	case *fraction.Fraction:
+		return v.Evaluate()
+	case decimal.Decimal:
+		return v.Float64()
+	}
+	return math.NaN()
+}
+
+func special(x float64) bool {
+	return math.IsNaN(x) || math.IsInf(x, 0)
+}
+
+func specialString(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+func sign(negative bool) string {
+	if negative {
+		return "-"
+	}
+	return ""
+}
+
+// significantDigits rounds |x| to the specified number of significant figures
+// and returns the digits, the decimal exponent of the first digit and the
+// sign, e.g. 0.012345 with 3 figures gives "123", -2, false.
+func significantDigits(x float64, figures int) (string, int, bool) {
+	text := strconv.FormatFloat(math.Abs(x), 'e', max(figures, 1)-1, 64)
+	mantissa, exponent, _ := strings.Cut(text, "e")
+	e, _ := strconv.Atoi(exponent)
+	return strings.Replace(mantissa, ".", "", 1), e, math.Signbit(x) && x != 0
+}
+
+// engineering returns the mantissa (in [1, 1000)) as text and the exponent,
+// a multiple of 3.
+func engineering(x float64, figures int) (string, int) {
+	digits, exponent, negative := significantDigits(x, figures)
+	engineeringExponent := exponent - ((exponent%3)+3)%3
+	point := exponent - engineeringExponent + 1
+	if len(digits) < point {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+	mantissa := digits[:point]
+	if point < len(digits) {
+		mantissa += "." + digits[point:]
+	}
+	return sign(negative) + mantissa, engineeringExponent
+}
+
+// padDecimals appends zeros so that the text has the specified number of
+// decimals (beyond the precision a Decimal can hold).
+func padDecimals(text string, decimals int) string {
+	_, fractional, hasFraction := strings.Cut(text, ".")
+	if decimals == 0 || len(fractional) >= decimals {
+		return text
+	}
+	if !hasFraction {
+		text += "."
+	}
+	return text + strings.Repeat("0", decimals-len(fractional))
+}
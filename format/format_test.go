@@ -0,0 +1,80 @@
+package format
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/decimal"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func TestSignificant(t *testing.T) {
+	tests := []struct {
+		value   float64
+		figures int
+		want    string
+	}{
+		{0.0012345, 3, "0.00123"},
+		{123456, 2, "120000"},
+		{2, 3, "2.00"},
+		{-9.996, 3, "-10.0"},
+		{0, 2, "0.0"},
+	}
+	for _, test := range tests {
+		if got := Significant(test.value, test.figures); got != test.want {
+			t.Fatalf("Significant(%v, %d) = %q; want %q", test.value, test.figures, got, test.want)
+		}
+	}
+	if got := RoundSignificant(fraction.MustNew(2, 3), 2); got != 0.67 {
+		t.Fatalf("RoundSignificant(2/3, 2) = %v; want 0.67", got)
+	}
+}
+
+func TestEngineeringAndSI(t *testing.T) {
+	tests := []struct {
+		value       float64
+		figures     int
+		engineering string
+		si          string
+	}{
+		{12345, 3, "12.3e3", "12.3k"},
+		{1500, 2, "1.5e3", "1.5k"},
+		{0.0000032, 2, "3.2e-6", "3.2µ"},
+		{-0.047, 2, "-47e-3", "-47m"},
+		{999.96, 4, "1.000e3", "1.000k"},
+		{42, 2, "42", "42"},
+		{1e30, 1, "1e30", "1e30"},
+	}
+	for _, test := range tests {
+		if got := Engineering(test.value, test.figures); got != test.engineering {
+			t.Fatalf("Engineering(%v, %d) = %q; want %q", test.value, test.figures, got, test.engineering)
+		}
+		if got := SI(test.value, test.figures); got != test.si {
+			t.Fatalf("SI(%v, %d) = %q; want %q", test.value, test.figures, got, test.si)
+		}
+	}
+	if got := SI(decimal.MustParse("0.000150"), 2); got != "150µ" {
+		t.Fatalf("SI(Decimal 0.000150, 2) = %q; want \"150µ\"", got)
+	}
+	if got := SI(math.Inf(-1), 2); got != "-Inf" {
+		t.Fatalf("SI(-Inf) = %q", got)
+	}
+}
+
+func TestThousands(t *testing.T) {
+	if got := Thousands(1234567.891, 2); got != "1,234,567.89" {
+		t.Fatalf("Thousands(1234567.891, 2) = %q", got)
+	}
+	if got := Thousands(-999.5, 0); got != "-1,000" {
+		t.Fatalf("Thousands(-999.5, 0) = %q", got)
+	}
+	if got := Thousands(fraction.MustNew(-2000001, 3), 3); got != "-666,667.000" {
+		t.Fatalf("Thousands(-2000001/3, 3) = %q", got)
+	}
+	if got := Thousands(decimal.MustParse("1234.5"), 3); got != "1,234.500" {
+		t.Fatalf("Thousands(Decimal 1234.5, 3) = %q", got)
+	}
+	if got := Thousands(fraction.MustNew(5, 2), 0); got != "2" {
+		t.Fatalf("Thousands(5/2, 0) = %q; want \"2\" (half-even)", got)
+	}
+}
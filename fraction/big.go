@@ -0,0 +1,463 @@
+package fraction
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Big represents a rational number with arbitrary precision numerator and
+// denominator (math/big). It mirrors the core API of Fraction (arithmetic,
+// powers and roots, comparison and string conversion): the arithmetic
+// methods modify the current instance in-place and return it, and
+// simplification is explicit. Use Big when chained Multiply, Add or Pow calls
+// would overflow the int fields of a Fraction. The Fraction conveniences
+// built on top of that core, like rounding (Floor, Round, ...), DivMod,
+// PowInt, Decimal, fmt and JSON support, are not available: convert with
+// the Fraction method when the value fits.
+//
+// The field "numerator" holds the signed numerator, the field "denominator"
+// is always positive, except in the zero value: it is read as 1, so the zero
+// value of Big is 0, like the zero value of big.Rat.
+type Big struct {
+	numerator   big.Int
+	denominator big.Int
+}
+
+// NewBig is a constructor function that takes two integer parameters - the
+// numerator and the denominator, respectively - and returns a pointer to a
+// Big struct and an error in case the denominator is zero.
+func NewBig(numerator, denominator int) (*Big, error) {
+	return NewBigFromInts(big.NewInt(int64(numerator)), big.NewInt(int64(denominator)))
+}
+
+// MustNewBig is a constructor identical to NewBig but which panics if an
+// error occurs.
+func MustNewBig(numerator, denominator int) *Big {
+	b, err := NewBig(numerator, denominator)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// NewBigFromInts is a constructor function that returns the fraction
+// numerator / denominator for big integers. The arguments are copied. Returns
+// an error if an argument is nil or if the denominator is zero.
+func NewBigFromInts(numerator, denominator *big.Int) (*Big, error) {
+	if numerator == nil || denominator == nil {
+		return nil, fmt.Errorf("%w: nil *big.Int", ErrNilFraction)
+	}
+	if denominator.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	b := &Big{}
+	b.numerator.Set(numerator)
+	b.denominator.Set(denominator)
+	b.normalize()
+	return b, nil
+}
+
+// NewBigFromFraction is a constructor function that converts a Fraction to
+// a Big. Returns nil if the Fraction instance is nil.
+func NewBigFromFraction(f *Fraction) *Big {
+	if f == nil {
+		return nil
+	}
+	b := &Big{}
+	b.numerator.SetInt64(int64(f.sign * f.numerator))
+	b.denominator.SetInt64(int64(f.denominator))
+	return b
+}
+
+// Fraction converts the current Big instance to a Fraction. Returns an error
+// if the Big instance is nil, or an error wrapping ErrOverflow if the
+// numerator or denominator doesn't fit in an int (simplify first to minimize
+// the magnitudes).
+func (b *Big) Fraction() (*Fraction, error) {
+	if b == nil {
+		return nil, ErrNilFraction
+	}
+	f := bigFraction(&b.numerator, b.denom())
+	if f == nil {
+		return nil, fmt.Errorf("%w: numerator or denominator doesn't fit", ErrOverflow)
+	}
+	return f, nil
+}
+
+// Clone returns a deep copy of the current Big instance. Returns nil if the
+// Big instance is nil.
+func (b *Big) Clone() *Big {
+	if b == nil {
+		return nil
+	}
+	clone := &Big{}
+	clone.numerator.Set(&b.numerator)
+	clone.denominator.Set(b.denom())
+	return clone
+}
+
+// Simplify divides the numerator and the denominator by their greatest
+// common divisor. Changes the current Big instance in-place and returns nil
+// if the Big instance is nil.
+func (b *Big) Simplify() *Big {
+	if b == nil {
+		return nil
+	}
+	var gcd big.Int
+	gcd.GCD(nil, nil, new(big.Int).Abs(&b.numerator), b.denom())
+	if gcd.Sign() != 0 && gcd.Cmp(big.NewInt(1)) != 0 {
+		b.numerator.Quo(&b.numerator, &gcd)
+		b.denominator.Quo(b.denom(), &gcd)
+	}
+	return b
+}
+
+// Evaluate calculates and returns the fraction as the nearest float value.
+// Returns NaN if the Big instance is nil.
+func (b *Big) Evaluate() float64 {
+	if b == nil {
+		return math.NaN()
+	}
+	value, _ := new(big.Rat).SetFrac(&b.numerator, b.denom()).Float64()
+	return value
+}
+
+// Float64Exact returns the float64 value nearest to the fraction and a
+// boolean value that indicates if it represents the fraction exactly.
+// Returns NaN and false if the Big instance is nil.
+func (b *Big) Float64Exact() (float64, bool) {
+	if b == nil {
+		return math.NaN(), false
+	}
+	return new(big.Rat).SetFrac(&b.numerator, b.denom()).Float64()
+}
+
+// String implements the fmt.Stringer interface and returns the fraction in
+// the same mixed form as Fraction, e.g. "-1 1/2".
+func (b *Big) String() string {
+	if b == nil {
+		return "NaN"
+	}
+	var whole, remainder big.Int
+	whole.QuoRem(new(big.Int).Abs(&b.numerator), b.denom(), &remainder)
+	var result string
+	switch {
+	case remainder.Sign() == 0:
+		result = whole.String()
+	case whole.Sign() == 0:
+		result = fmt.Sprintf("%s/%s", remainder.String(), b.denom().String())
+	default:
+		result = fmt.Sprintf("%s %s/%s", whole.String(), remainder.String(), b.denom().String())
+	}
+	if b.numerator.Sign() < 0 {
+		return "-" + result
+	}
+	return result
+}
+
+// AsIntegerRatio returns the string representation of the Big instance as
+// an integer ratio [-]a/b. If the Big instance is nil it will return NaN.
+func (b *Big) AsIntegerRatio() string {
+	if b == nil {
+		return "NaN"
+	}
+	return fmt.Sprintf("%s/%s", b.numerator.String(), b.denom().String())
+}
+
+// Numerator returns a copy of the (signed) numerator of the current Big
+// instance and a boolean value that indicates if the returned numerator is
+// valid.
+func (b *Big) Numerator() (*big.Int, bool) {
+	if b == nil {
+		return nil, false
+	}
+	return new(big.Int).Set(&b.numerator), true
+}
+
+// Denominator returns a copy of the (positive) denominator of the current
+// Big instance and a boolean value that indicates if the returned
+// denominator is valid.
+func (b *Big) Denominator() (*big.Int, bool) {
+	if b == nil {
+		return nil, false
+	}
+	return new(big.Int).Set(b.denom()), true
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Multiply multiplies the current Big instance with the specified Big
+// instance. Modifies the current Big instance in-place. Returns nil if either
+// Big instance is nil.
+func (b *Big) Multiply(other *Big) *Big {
+	if b == nil || other == nil {
+		return nil
+	}
+	b.numerator.Mul(&b.numerator, &other.numerator)
+	b.denominator.Mul(b.denom(), other.denom())
+	return b
+}
+
+// MultiplyInt multiplies the current Big instance with the specified
+// integer. Returns nil if the Big instance is nil.
+func (b *Big) MultiplyInt(value int) *Big {
+	if b == nil {
+		return nil
+	}
+	b.numerator.Mul(&b.numerator, big.NewInt(int64(value)))
+	return b
+}
+
+// Add adds the specified Big instance to the current Big instance. Modifies
+// the current Big instance in-place. Returns nil if either Big instance is
+// nil.
+func (b *Big) Add(other *Big) *Big {
+	if b == nil || other == nil {
+		return nil
+	}
+	// Compute the cross product first: b and other may be the same instance.
+	cross := new(big.Int).Mul(&other.numerator, b.denom())
+	b.numerator.Mul(&b.numerator, other.denom())
+	b.numerator.Add(&b.numerator, cross)
+	b.denominator.Mul(b.denom(), other.denom())
+	return b
+}
+
+// AddInt adds the specified integer to the current Big instance. Returns nil
+// if the Big instance is nil.
+func (b *Big) AddInt(value int) *Big {
+	if b == nil {
+		return nil
+	}
+	b.numerator.Add(&b.numerator, new(big.Int).Mul(big.NewInt(int64(value)), b.denom()))
+	return b
+}
+
+// Subtract subtracts the specified Big instance from the current Big
+// instance. Modifies the current Big instance in-place. Returns nil if either
+// Big instance is nil.
+func (b *Big) Subtract(other *Big) *Big {
+	if b == nil || other == nil {
+		return nil
+	}
+	// Compute the cross product first: b and other may be the same instance.
+	cross := new(big.Int).Mul(&other.numerator, b.denom())
+	b.numerator.Mul(&b.numerator, other.denom())
+	b.numerator.Sub(&b.numerator, cross)
+	b.denominator.Mul(b.denom(), other.denom())
+	return b
+}
+
+// SubtractInt subtracts the specified integer from the current Big instance.
+// Returns nil if the Big instance is nil.
+func (b *Big) SubtractInt(value int) *Big {
+	return b.AddInt(-value)
+}
+
+// Divide divides the current Big instance by the specified Big instance.
+// Modifies the current Big instance in-place and returns it (or returns nil
+// if either Big instance is nil). Unlike Fraction.Divide it also returns an
+// error, because a zero denominator cannot be represented.
+func (b *Big) Divide(other *Big) (*Big, error) {
+	if b == nil || other == nil {
+		return nil, ErrNilFraction
+	}
+	if other.numerator.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	// Copy other first: b and other may be the same instance.
+	numerator := new(big.Int).Set(&other.numerator)
+	b.numerator.Mul(&b.numerator, other.denom())
+	b.denominator.Mul(b.denom(), numerator)
+	b.normalize()
+	return b, nil
+}
+
+// DivideInt divides the current Big instance by the specified integer.
+// Modifies the current Big instance in-place and returns it (or returns nil
+// if the Big instance is nil). Also returns an error if value is zero.
+func (b *Big) DivideInt(value int) (*Big, error) {
+	if b == nil {
+		return nil, ErrNilFraction
+	}
+	if value == 0 {
+		return nil, ErrDivisionByZero
+	}
+	b.denominator.Mul(b.denom(), big.NewInt(int64(value)))
+	b.normalize()
+	return b, nil
+}
+
+// Pow raises the current Big instance to the specified power. Modifies the
+// current Big instance in-place and returns it (or returns nil if the Big
+// instance is nil).
+func (b *Big) Pow(exponent uint) *Big {
+	if b == nil {
+		return nil
+	}
+	e := new(big.Int).SetUint64(uint64(exponent))
+	b.numerator.Exp(&b.numerator, e, nil)
+	b.denominator.Exp(b.denom(), e, nil)
+	return b
+}
+
+// NthRoot determines the nth-root of the current Big instance. Modifies the
+// current Big instance in-place and returns it. Returns an error (and nil) if
+// the Big instance is nil, if degree is zero, if an even root of a negative
+// number is requested or if the root is not rational.
+func (b *Big) NthRoot(degree uint) (*Big, error) {
+	if b == nil {
+		return nil, ErrNilFraction
+	}
+	if degree == 0 {
+		return nil, fmt.Errorf("%w: the 0th root does not exist", ErrNoExactRoot)
+	}
+	negative := b.numerator.Sign() < 0
+	if negative && degree%2 == 0 {
+		return nil, fmt.Errorf("%w: the even nth-root of a negative number does not exist", ErrNoExactRoot)
+	}
+	numerator, ok := exactRoot(new(big.Int).Abs(&b.numerator), degree)
+	if !ok {
+		return nil, fmt.Errorf("%w: the nth-root of this fraction does not yield a valid fraction", ErrNoExactRoot)
+	}
+	denominator, ok := exactRoot(b.denom(), degree)
+	if !ok {
+		return nil, fmt.Errorf("%w: the nth-root of this fraction does not yield a valid fraction", ErrNoExactRoot)
+	}
+	if negative {
+		numerator.Neg(numerator)
+	}
+	b.numerator.Set(numerator)
+	b.denominator.Set(denominator)
+	return b, nil
+}
+
+// Reciprocal replaces the current Big instance with its reciprocal.
+// Modifies the current Big instance in-place and returns it (or returns nil
+// if the Big instance is nil). Also returns an error if the fraction is zero.
+func (b *Big) Reciprocal() (*Big, error) {
+	if b == nil {
+		return nil, ErrNilFraction
+	}
+	if b.numerator.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	// Swap through a copy: big.Int values must not be copied.
+	numerator := new(big.Int).Set(&b.numerator)
+	b.numerator.Set(b.denom())
+	b.denominator.Set(numerator)
+	b.normalize()
+	return b, nil
+}
+
+// Negate flips the sign of the current Big instance. Returns nil if the Big
+// instance is nil.
+func (b *Big) Negate() *Big {
+	if b == nil {
+		return nil
+	}
+	b.numerator.Neg(&b.numerator)
+	return b
+}
+
+// Abs makes the current Big instance non-negative. Returns nil if the Big
+// instance is nil.
+func (b *Big) Abs() *Big {
+	if b == nil {
+		return nil
+	}
+	b.numerator.Abs(&b.numerator)
+	return b
+}
+
+// ============================================================================
+// Comparison
+// ============================================================================
+
+// Compare compares the current Big instance with the specified Big instance
+// and returns -1 if b < other, 0 if b == other and 1 if b > other. A nil Big
+// instance is less than any other Big instance.
+func (b *Big) Compare(other *Big) int {
+	switch {
+	case b == nil && other == nil:
+		return 0
+	case b == nil:
+		return -1
+	case other == nil:
+		return 1
+	}
+	left := new(big.Int).Mul(&b.numerator, other.denom())
+	return left.Cmp(new(big.Int).Mul(&other.numerator, b.denom()))
+}
+
+// Equals checks if the current Big instance and the specified Big instance
+// represent the same rational number. Returns false if either Big instance
+// is nil.
+func (b *Big) Equals(other *Big) bool {
+	return b != nil && other != nil && b.Compare(other) == 0
+}
+
+// LessThan checks if the current Big instance is smaller than the specified
+// Big instance. Returns false if either Big instance is nil.
+func (b *Big) LessThan(other *Big) bool {
+	return b != nil && other != nil && b.Compare(other) < 0
+}
+
+// GreaterThan checks if the current Big instance is larger than the
+// specified Big instance. Returns false if either Big instance is nil.
+func (b *Big) GreaterThan(other *Big) bool {
+	return b != nil && other != nil && b.Compare(other) > 0
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// denom returns the denominator, which is 1 for the zero value of Big.
+func (b *Big) denom() *big.Int {
+	if b.denominator.Sign() == 0 {
+		return bigOne
+	}
+	return &b.denominator
+}
+
+// bigOne is the denominator of the zero value of Big. It must not be
+// modified.
+var bigOne = big.NewInt(1)
+
+// normalize makes the denominator positive.
+func (b *Big) normalize() {
+	if b.denominator.Sign() < 0 {
+		b.numerator.Neg(&b.numerator)
+		b.denominator.Neg(b.denom())
+	}
+}
+
+// exactRoot returns the integer nth root of the non-negative value x and
+// true if it is exact.
+func exactRoot(x *big.Int, n uint) (*big.Int, bool) {
+	if x.Sign() == 0 || n == 1 {
+		return new(big.Int).Set(x), true
+	}
+	// Newton's method from an initial guess that is at least the root:
+	// r -> ((n-1)·r + x / r^(n-1)) / n decreases until it reaches the floor
+	// of the root.
+	exponent := new(big.Int).SetUint64(uint64(n - 1))
+	divisor := new(big.Int).SetUint64(uint64(n))
+	root := new(big.Int).Lsh(big.NewInt(1), uint(x.BitLen())/n+1)
+	for {
+		next := new(big.Int).Exp(root, exponent, nil)
+		next.Quo(x, next)
+		next.Add(next, new(big.Int).Mul(root, exponent))
+		next.Quo(next, divisor)
+		if next.Cmp(root) >= 0 {
+			break
+		}
+		root = next
+	}
+	check := new(big.Int).Exp(root, divisor, nil)
+	return root, check.Cmp(x) == 0
+}
@@ -0,0 +1,192 @@
+package fraction
+
+// Builder chains arithmetic operations on a Fraction and keeps the first error
+// that occurs, so a long computation can be written without MustXxx panics or
+// error checks after every step. Once an error has occurred, every next
+// operation is a no-op; Result returns the error.
+//
+// Example:
+//
+//	result, err := fraction.NewBuilder(a).Add(b).Divide(c).PowInt(-2).Result()
+type Builder struct {
+	value *Fraction
+	err   error
+}
+
+// NewBuilder is a constructor function that returns a Builder starting from a
+// copy of the specified Fraction (which is not modified by the builder). The
+// copy keeps the AutoSimplify setting of f. A nil Fraction results in
+// ErrNilFraction.
+func NewBuilder(f *Fraction) *Builder {
+	if f == nil {
+		return &Builder{err: ErrNilFraction}
+	}
+	return &Builder{value: f.Clone()}
+}
+
+// Result returns the result of the chained operations, or the first error that
+// occurred (in which case the Fraction is nil).
+func (b *Builder) Result() (*Fraction, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.value, nil
+}
+
+// Err returns the first error that occurred (nil if there was none).
+func (b *Builder) Err() error {
+	return b.err
+}
+
+// Add adds the specified Fraction.
+func (b *Builder) Add(other *Fraction) *Builder {
+	if b.operand(other) {
+		b.value.Add(other)
+	}
+	return b
+}
+
+// AddInt adds the specified integer.
+func (b *Builder) AddInt(value int) *Builder {
+	if b.err == nil {
+		b.value.AddInt(value)
+	}
+	return b
+}
+
+// Subtract subtracts the specified Fraction.
+func (b *Builder) Subtract(other *Fraction) *Builder {
+	if b.operand(other) {
+		b.value.Subtract(other)
+	}
+	return b
+}
+
+// SubtractInt subtracts the specified integer.
+func (b *Builder) SubtractInt(value int) *Builder {
+	if b.err == nil {
+		b.value.SubtractInt(value)
+	}
+	return b
+}
+
+// Multiply multiplies by the specified Fraction.
+func (b *Builder) Multiply(other *Fraction) *Builder {
+	if b.operand(other) {
+		b.value.Multiply(other)
+	}
+	return b
+}
+
+// MultiplyInt multiplies by the specified integer.
+func (b *Builder) MultiplyInt(value int) *Builder {
+	if b.err == nil {
+		b.value.MultiplyInt(value)
+	}
+	return b
+}
+
+// Divide divides by the specified Fraction. Results in ErrDivisionByZero if
+// other is zero.
+func (b *Builder) Divide(other *Fraction) *Builder {
+	if !b.operand(other) {
+		return b
+	}
+	if other.numerator == 0 {
+		b.err = ErrDivisionByZero
+		return b
+	}
+	b.value.Divide(other)
+	return b
+}
+
+// DivideInt divides by the specified integer. Results in ErrDivisionByZero if
+// value is zero.
+func (b *Builder) DivideInt(value int) *Builder {
+	if b.err == nil {
+		_, b.err = b.value.DivideInt(value)
+	}
+	return b
+}
+
+// Pow raises the value to the specified power.
+func (b *Builder) Pow(exponent uint) *Builder {
+	if b.err == nil {
+		b.value.Pow(exponent)
+	}
+	return b
+}
+
+// PowInt raises the value to the specified (possibly negative) power. Results
+// in ErrDivisionByZero if zero is raised to a negative power.
+func (b *Builder) PowInt(exponent int) *Builder {
+	if b.err == nil {
+		_, b.err = b.value.PowInt(exponent)
+	}
+	return b
+}
+
+// PowFraction raises the value to the specified rational power. Results in
+// ErrNoExactRoot if the result is not rational.
+func (b *Builder) PowFraction(exponent *Fraction) *Builder {
+	if b.operand(exponent) {
+		_, b.err = b.value.PowFraction(exponent)
+	}
+	return b
+}
+
+// NthRoot takes the nth-root of the value. Results in ErrNoExactRoot if the
+// root is not rational.
+func (b *Builder) NthRoot(degree uint) *Builder {
+	if b.err == nil {
+		_, b.err = b.value.NthRoot(degree)
+	}
+	return b
+}
+
+// Reciprocal replaces the value with its reciprocal. Results in
+// ErrDivisionByZero if the value is zero.
+func (b *Builder) Reciprocal() *Builder {
+	if b.err == nil {
+		_, b.err = b.value.Reciprocal()
+	}
+	return b
+}
+
+// Negate flips the sign of the value.
+func (b *Builder) Negate() *Builder {
+	if b.err == nil {
+		b.value.Negate()
+	}
+	return b
+}
+
+// Abs makes the value non-negative.
+func (b *Builder) Abs() *Builder {
+	if b.err == nil {
+		b.value.Abs()
+	}
+	return b
+}
+
+// Simplify reduces the value to lowest terms.
+func (b *Builder) Simplify() *Builder {
+	if b.err == nil {
+		b.value.Simplify()
+	}
+	return b
+}
+
+// operand reports whether an operation with the specified operand can be
+// applied: there is no earlier error and the operand isn't nil (in which case
+// ErrNilFraction is recorded).
+func (b *Builder) operand(other *Fraction) bool {
+	if b.err != nil {
+		return false
+	}
+	if other == nil {
+		b.err = ErrNilFraction
+		return false
+	}
+	return true
+}
@@ -6,24 +6,74 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"math/bits"
+	"math/rand/v2"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bogersw/wbmath"
 )
 
+// Numbers can be integers or floats (the last ones with or without leading digits).
+// The numbers can have an optional sign and optional scientific exponent (e / E).
+const numPart = `(?:[+\-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+\-]?\d+)?)`
+
+var (
+	// ^   — start of string anchor (match begins at string start).
+	// \s* — zero or more whitespace characters (allows leading and /ot trailing spaces).
+	// /   — literal slash separator.
+	// $   — end of string anchor (ensures the entire string matches, no extra chars).
+	ratioPattern  = regexp.MustCompile(fmt.Sprintf(`^\s*(%s)\s*/\s*(%s)\s*$`, numPart, numPart))
+	numberPattern = regexp.MustCompile(fmt.Sprintf(`^\s*(%s)\s*$`, numPart))
+	// A mixed number: optional sign, whole number, whitespace, integer ratio.
+	mixedPattern = regexp.MustCompile(`^\s*([+\-]?)(\d+)\s+(\d+)\s*/\s*(\d+)\s*$`)
+)
+
+// Sentinel errors returned (possibly wrapped) by the functions and methods of
+// this package. Use errors.Is to test for them.
+var (
+	// ErrDivisionByZero is returned when a denominator or divisor is zero.
+	ErrDivisionByZero = errors.New("division by zero")
+	// ErrInvalidFormat is returned when a string can't be parsed as a fraction.
+	ErrInvalidFormat = errors.New("invalid fraction format")
+	// ErrNoExactRoot is returned when a root is not a rational number.
+	ErrNoExactRoot = errors.New("no exact root")
+	// ErrNilFraction is returned when a nil Fraction or Big instance is used.
+	ErrNilFraction = errors.New("invalid Fraction instance")
+	// ErrOverflow is returned when a numerator, denominator or other integer
+	// result doesn't fit in an int.
+	ErrOverflow = errors.New("integer overflow")
+)
+
+// vulgarFractions maps the unicode vulgar fractions to their numerator and
+// denominator.
+var vulgarFractions = map[rune][2]int{
+	'½': {1, 2}, '⅓': {1, 3}, '⅔': {2, 3}, '¼': {1, 4}, '¾': {3, 4},
+	'⅕': {1, 5}, '⅖': {2, 5}, '⅗': {3, 5}, '⅘': {4, 5}, '⅙': {1, 6},
+	'⅚': {5, 6}, '⅐': {1, 7}, '⅛': {1, 8}, '⅜': {3, 8}, '⅝': {5, 8},
+	'⅞': {7, 8}, '⅑': {1, 9}, '⅒': {1, 10}, '↉': {0, 3},
+}
+
 // Fraction represents a rational number stored with non-negative numerator and
 // denominator and a separate sign flag. Fields are unexported: use the package's
 // constructors and methods to create and manipulate values.
 //
 // The fields "numerator" and "denominator" hold absolute (non-negative) integers.
 // The field "sign" is -1 if the resulting value is negative: otherwise it is 1.
+//
+// With AutoSimplify(true) every arithmetic operation on the Fraction reduces
+// the result to lowest terms, which keeps long chains of operations from
+// overflowing. By default results are only simplified by calling Simplify.
 
 type Fraction struct {
 	numerator   int
 	denominator int
 	sign        int
+	// simplify is true if arithmetic results are reduced automatically.
+	simplify bool
 }
 
 // New is a constructor function that takes two integer parameters - the
@@ -31,10 +81,12 @@ type Fraction struct {
 // a Fraction struct and an error in case the denominator is zero.
 func New(numerator, denominator int) (*Fraction, error) {
 	if denominator == 0 {
-		return nil, errors.New("division by zero")
+		return nil, ErrDivisionByZero
 	}
+	// Compare the signs instead of the sign of the product, which can
+	// overflow for large values.
 	sign := 1
-	if numerator*denominator < 0 {
+	if (numerator < 0) != (denominator < 0) && numerator != 0 {
 		sign = -1
 	}
 	return &Fraction{
@@ -84,33 +136,72 @@ func NewFromNumber[T int | float64](num T) *Fraction {
 // NewFromString is a constructor function that accepts strings like
 // "a / b", with a and b either ints or floats (including scientific
 // notation (e / E)). Optional signs can be provided. Whitespace is
-// ignored. It returns a Fraction struct and an error.
+// ignored. It also accepts everything String emits, so parsing the output of
+// String round-trips:
+//
+//   - mixed numbers like "2 1/3" and "-2 1/3" (the sign applies to the whole
+//     number),
+//   - plain integers and decimals like "5" and "0.75",
+//   - unicode vulgar fractions like "½" and "¾", on their own or in a mixed
+//     number like "2½", and the fraction slash "⁄" (U+2044).
+//
+// It returns a Fraction struct and an error, which wraps ErrInvalidFormat,
+// ErrDivisionByZero or ErrOverflow (for a mixed number whose numerator
+// doesn't fit in an int).
 func NewFromString(num string) (*Fraction, error) {
-
-	// Numbers can be integers or floats (the last ones with or without leading digits).
-	// The numbers can have an optional sign and optional scientific exponent (e / E).
-	var numPart = `(?:[+\-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+\-]?\d+)?)`
-	// ^   — start of string anchor (match begins at string start).
-	// \s* — zero or more whitespace characters (allows leading and /ot trailing spaces).
-	// /   — literal slash separator.
-	// $   — end of string anchor (ensures the entire string matches, no extra chars).
-	var re = regexp.MustCompile(fmt.Sprintf(`^\s*(%s)\s*/\s*(%s)\s*$`, numPart, numPart))
+	num = strings.TrimSpace(strings.ReplaceAll(num, "\u2044", "/"))
+	// A trailing vulgar fraction is rewritten to a (mixed) ratio, e.g. "2½"
+	// becomes "2 1/2".
+	if last, size := utf8.DecodeLastRuneInString(num); size > 0 {
+		if vulgar, ok := vulgarFractions[last]; ok {
+			prefix := strings.TrimSpace(num[:len(num)-size])
+			if prefix == "" || prefix == "+" || prefix == "-" {
+				num = fmt.Sprintf("%s%d/%d", prefix, vulgar[0], vulgar[1])
+			} else {
+				num = fmt.Sprintf("%s %d/%d", prefix, vulgar[0], vulgar[1])
+			}
+		}
+	}
+	// Mixed number: [sign] whole numerator/denominator, all integers.
+	if match := mixedPattern.FindStringSubmatch(num); match != nil {
+		whole, errWhole := strconv.Atoi(match[2])
+		numerator, errNumerator := strconv.Atoi(match[3])
+		denominator, errDenominator := strconv.Atoi(match[4])
+		if err := errors.Join(errWhole, errNumerator, errDenominator); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+		}
+		if denominator == 0 {
+			return nil, ErrDivisionByZero
+		}
+		value, ok := mulAdd(whole, denominator, numerator)
+		if !ok {
+			return nil, fmt.Errorf("%w: mixed number %q", ErrOverflow, num)
+		}
+		if match[1] == "-" {
+			value = -value
+		}
+		return New(value, denominator)
+	}
 	// Check match: FindStringSubmatch returns a slice (or nil if there was no match)
 	// - index 0 is the full match,
 	// - index 1 is match 1 (in our case: the numerator),
 	// - index 2 is match 2 (in our case: the denominator).
-	match := re.FindStringSubmatch(strings.TrimSpace(num))
+	match := ratioPattern.FindStringSubmatch(num)
 	if match == nil {
-		return nil, errors.New("invalid fraction format")
+		// A single number is the numerator over 1.
+		if match = numberPattern.FindStringSubmatch(num); match == nil {
+			return nil, ErrInvalidFormat
+		}
+		match = append(match, "1")
 	}
 	numeratorStr, denominatorStr := match[1], match[2]
 	// If both numbers have no decimals/exponent, treat them as integers
 	if !strings.ContainsAny(numeratorStr, ".eE") && !strings.ContainsAny(denominatorStr, ".eE") {
 		if numerator, err := strconv.Atoi(numeratorStr); err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
 		} else {
 			if denominator, err := strconv.Atoi(denominatorStr); err != nil {
-				return nil, err
+				return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
 			} else {
 				return New(numerator, denominator)
 			}
@@ -118,14 +209,17 @@ func NewFromString(num string) (*Fraction, error) {
 	}
 	// Otherwise: parse as floats.
 	if numerator, err := strconv.ParseFloat(numeratorStr, 64); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
 	} else {
 		if denominator, err := strconv.ParseFloat(denominatorStr, 64); err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
 		} else {
 			fracNumerator := NewFromNumber(numerator)
 			fracDenominator := NewFromNumber(denominator)
-			result := fracNumerator.Divide(fracDenominator).Simplify()
+			if _, err := fracDenominator.Reciprocal(); err != nil {
+				return nil, err
+			}
+			result := fracNumerator.Multiply(fracDenominator).Simplify()
 			return result, nil
 		}
 	}
@@ -141,6 +235,17 @@ func MustNewFromString(num string) *Fraction {
 	return fraction
 }
 
+// Clone returns a deep copy of the current Fraction instance (including the
+// sign), so a chain of in-place operations can branch without modifying the
+// original. Returns nil if the Fraction instance is nil.
+func (f *Fraction) Clone() *Fraction {
+	if f == nil {
+		return nil
+	}
+	clone := *f
+	return &clone
+}
+
 // Simplify determines the greatest common divisor (gcd) to make the fraction as
 // simple as possible. Changes the current Fraction instance in-place and returns
 // nil if the Fraction instance is nil. Note that if gcd = 0 the current Fraction
@@ -157,6 +262,24 @@ func (f *Fraction) Simplify() *Fraction {
 	return f
 }
 
+// AutoSimplify enables or disables automatic simplification for the current
+// Fraction instance: when enabled, every arithmetic operation on it reduces
+// the result to lowest terms (and the fraction is simplified right away).
+// The setting is kept by Clone. Returns nil if the Fraction instance is nil.
+func (f *Fraction) AutoSimplify(enabled bool) *Fraction {
+	if f == nil {
+		return nil
+	}
+	f.simplify = enabled
+	return f.autoSimplify()
+}
+
+// IsAutoSimplify checks if automatic simplification is enabled for the
+// current Fraction instance.
+func (f *Fraction) IsAutoSimplify() bool {
+	return f != nil && f.simplify
+}
+
 // Evaluate calculates and returns the fraction as a float value. Returns
 // NaN if the Fraction instance is nil.
 func (f *Fraction) Evaluate() float64 {
@@ -169,6 +292,18 @@ func (f *Fraction) Evaluate() float64 {
 	return float64(f.numerator) / float64(f.denominator)
 }
 
+// Float64Exact returns the float64 value nearest to the fraction and a
+// boolean value that indicates if it represents the fraction exactly (like
+// big.Rat.Float64). Unlike Evaluate, which divides two rounded floats, the
+// result is always correctly rounded. For example 1/4 is exact, but 1/3 and
+// 1/10 are not. Returns NaN and false if the Fraction instance is nil.
+func (f *Fraction) Float64Exact() (float64, bool) {
+	if f == nil {
+		return math.NaN(), false
+	}
+	return f.Rat().Float64()
+}
+
 // String implements the fmt.Stringer interface and returns a string
 // with a nicely formatted fraction for use by the fmt package.
 func (f *Fraction) String() string {
@@ -204,6 +339,133 @@ func (f *Fraction) String() string {
 	return fmt.Sprintf("%s", result)
 }
 
+// Format implements the fmt.Formatter interface, so fractions can be used
+// with every verb of the fmt package:
+//
+//   - %v and %s give the mixed form of String, e.g. "-1 1/2"; with the '#'
+//     flag (%#v, %#s) they give the integer ratio "-3/2".
+//   - %d gives the integer ratio, e.g. "-3/2".
+//   - %f and %F give the exact decimal expansion, correctly rounded to the
+//     precision (default 6), e.g. "%.3f" of 2/3 gives "0.667".
+//   - %e, %E, %g and %G format the float value like for a float64.
+//   - %q gives the quoted mixed form.
+//
+// The width is honoured (padding with spaces, or with zeros after the sign
+// for the numeric verbs with the '0' flag), as are the '-' flag for left
+// alignment and the '+' flag to always print a sign. A nil Fraction instance
+// is formatted as "NaN".
+func (f *Fraction) Format(state fmt.State, verb rune) {
+	var body string
+	numeric := true
+	switch {
+	case f == nil:
+		body = "NaN"
+	case verb == 'v' || verb == 's' || verb == 'q':
+		numeric = false
+		body = f.String()
+		if state.Flag('#') && verb != 'q' {
+			body = f.AsIntegerRatio()
+		}
+	case verb == 'd':
+		body = f.AsIntegerRatio()
+	case verb == 'f' || verb == 'F':
+		precision, ok := state.Precision()
+		if !ok {
+			precision = 6
+		}
+		body = f.Rat().FloatString(precision)
+	case verb == 'e' || verb == 'E' || verb == 'g' || verb == 'G':
+		precision, ok := state.Precision()
+		if !ok {
+			precision = -1
+			if verb == 'e' || verb == 'E' {
+				precision = 6
+			}
+		}
+		body = strconv.FormatFloat(f.Evaluate(), byte(verb), precision, 64)
+	default:
+		fmt.Fprintf(state, "%%!%c(fraction=%s)", verb, f.String())
+		return
+	}
+	if state.Flag('+') && f != nil && !strings.HasPrefix(body, "-") {
+		body = "+" + body
+	}
+	if verb == 'q' {
+		body = strconv.Quote(body)
+	}
+	width, ok := state.Width()
+	if !ok || len([]rune(body)) >= width {
+		fmt.Fprint(state, body)
+		return
+	}
+	padding := width - len([]rune(body))
+	switch {
+	case state.Flag('-'):
+		body += strings.Repeat(" ", padding)
+	case state.Flag('0') && numeric && f != nil:
+		sign := ""
+		if strings.HasPrefix(body, "-") || strings.HasPrefix(body, "+") {
+			sign, body = body[:1], body[1:]
+		}
+		body = sign + strings.Repeat("0", padding) + body
+	default:
+		body = strings.Repeat(" ", padding) + body
+	}
+	fmt.Fprint(state, body)
+}
+
+// Decimal returns the exact decimal expansion of the fraction, with the
+// repeating part (the repetend) in parentheses, e.g. "0.(3)" for 1/3, "0.1(6)"
+// for 1/6, "3.(142857)" for 22/7 and "-0.75" for -3/4. At most maxDigits
+// digits are written after the decimal point: if the expansion (including one
+// full repetend) is longer, it is truncated and ends with "...". Returns "NaN"
+// if the Fraction instance is nil.
+func (f *Fraction) Decimal(maxDigits int) string {
+	if f == nil || f.denominator == 0 {
+		return "NaN"
+	}
+	var result strings.Builder
+	if f.sign == -1 && f.numerator != 0 {
+		result.WriteByte('-')
+	}
+	denominator := uint64(f.denominator)
+	result.WriteString(strconv.FormatUint(uint64(f.numerator)/denominator, 10))
+	remainder := uint64(f.numerator) % denominator
+	if remainder == 0 {
+		return result.String()
+	}
+	// Long division: the expansion repeats as soon as a remainder repeats.
+	digits := make([]byte, 0, 16)
+	seen := make(map[uint64]int)
+	for remainder != 0 {
+		if start, ok := seen[remainder]; ok {
+			result.WriteByte('.')
+			result.Write(digits[:start])
+			result.WriteByte('(')
+			result.Write(digits[start:])
+			result.WriteByte(')')
+			return result.String()
+		}
+		if len(digits) == maxDigits {
+			break
+		}
+		seen[remainder] = len(digits)
+		// remainder < denominator, so 10·remainder / denominator fits.
+		hi, lo := bits.Mul64(remainder, 10)
+		digit, next := bits.Div64(hi, lo, denominator)
+		digits = append(digits, byte('0'+digit))
+		remainder = next
+	}
+	if len(digits) > 0 {
+		result.WriteByte('.')
+		result.Write(digits)
+	}
+	if remainder != 0 {
+		result.WriteString("...")
+	}
+	return result.String()
+}
+
 // Multiply multiplies the current Fraction instance with the specified
 // Fraction instance. Modifies the current Fraction instance in-place.
 // Returns nil if either Fraction instance is nil.
@@ -214,16 +476,18 @@ func (f *Fraction) Multiply(other *Fraction) *Fraction {
 	f.numerator = f.numerator * other.numerator
 	f.denominator = f.denominator * other.denominator
 	f.sign = f.sign * other.sign
-	return f
+	return f.autoSimplify()
 }
 
 // MultiplyInt multiplies the current Fraction instance with the specified
-// integer. Returns nil if the Fraction instance is nil.
+// integer. Returns nil if the Fraction instance is nil. Doesn't allocate.
 func (f *Fraction) MultiplyInt(value int) *Fraction {
 	if f == nil {
 		return nil
 	}
-	return f.Multiply(NewFromNumber(value))
+	f.numerator = f.numerator * wbmath.Abs(value)
+	f.sign = f.sign * intSign(value)
+	return f.autoSimplify()
 }
 
 // Add adds the specified Fraction instance to the current Fraction instance.
@@ -236,21 +500,20 @@ func (f *Fraction) Add(other *Fraction) *Fraction {
 	numerator := f.sign*f.numerator*other.denominator + other.sign*other.numerator*f.denominator
 	f.numerator = wbmath.Abs(numerator)
 	f.denominator = f.denominator * other.denominator
-	if numerator >= 0 {
-		f.sign = 1
-	} else {
-		f.sign = -1
-	}
-	return f
+	f.sign = intSign(numerator)
+	return f.autoSimplify()
 }
 
 // AddInt adds the specified integer to the current Fraction instance.
-// Returns nil if the Fraction instance is nil.
+// Returns nil if the Fraction instance is nil. Doesn't allocate.
 func (f *Fraction) AddInt(value int) *Fraction {
 	if f == nil {
 		return nil
 	}
-	return f.Add(NewFromNumber(value))
+	numerator := f.sign*f.numerator + value*f.denominator
+	f.numerator = wbmath.Abs(numerator)
+	f.sign = intSign(numerator)
+	return f.autoSimplify()
 }
 
 // Divide divides the current Fraction instance with the specified Fraction
@@ -263,21 +526,23 @@ func (f *Fraction) Divide(other *Fraction) *Fraction {
 	f.numerator = f.numerator * other.denominator
 	f.denominator = f.denominator * other.numerator
 	f.sign = f.sign / other.sign
-	return f
+	return f.autoSimplify()
 }
 
 // DivideInt divides the current Fraction instance with the specified integer.
 // Modifies the current Fraction instance in-place and returns it (or returns
 // nil if the Fraction instance is nil). Also returns an error (which is nil
-// if no error occurs).
+// if no error occurs). Doesn't allocate.
 func (f *Fraction) DivideInt(value int) (*Fraction, error) {
 	if f == nil {
-		return nil, errors.New("invalid Fraction instance")
+		return nil, ErrNilFraction
 	}
 	if value == 0 {
-		return nil, errors.New("division by zero")
+		return nil, ErrDivisionByZero
 	}
-	return f.Divide(NewFromNumber(value)), nil
+	f.denominator = f.denominator * wbmath.Abs(value)
+	f.sign = f.sign * intSign(value)
+	return f.autoSimplify(), nil
 }
 
 // MustDivideInt is identical to DivideInt, but it panics if an error occurs.
@@ -292,23 +557,23 @@ func (f *Fraction) MustDivideInt(value int) *Fraction {
 // instance. Modifies the current Fraction instance in-place. Returns nil if
 // either Fraction instance is nil.
 func (f *Fraction) Subtract(other *Fraction) *Fraction {
-	// Subtracts two Fractions and returns a new Fraction with the result
 	if f == nil || other == nil {
 		return nil
 	}
-	if other.sign == -1 {
-		return f.Add(MustNew(other.numerator, other.denominator))
-	}
-	return f.Add(MustNew(-other.numerator, other.denominator))
+	numerator := f.sign*f.numerator*other.denominator - other.sign*other.numerator*f.denominator
+	f.numerator = wbmath.Abs(numerator)
+	f.denominator = f.denominator * other.denominator
+	f.sign = intSign(numerator)
+	return f.autoSimplify()
 }
 
 // SubtractInt subtracts the specified integer from the current Fraction
-// instance. Returns nil if the Fraction instance is nil.
+// instance. Returns nil if the Fraction instance is nil. Doesn't allocate.
 func (f *Fraction) SubtractInt(value int) *Fraction {
 	if f == nil {
 		return nil
 	}
-	return f.Subtract(NewFromNumber(value))
+	return f.AddInt(-value)
 }
 
 // Pow raises the current Fraction instance to the specified power. Modifies
@@ -322,7 +587,56 @@ func (f *Fraction) Pow(exponent uint) *Fraction {
 	f.denominator = wbmath.PowInt(f.denominator, exponent)
 	// For uneven powers a negative sign is preserved
 	f.sign = wbmath.PowInt(f.sign, exponent)
-	return f
+	return f.autoSimplify()
+}
+
+// PowInt raises the current Fraction instance to the specified (possibly
+// negative) integer power: a negative exponent inverts the fraction, e.g.
+// (2/3)^-2 = 9/4. Modifies the current Fraction instance in-place and returns
+// it. Returns an error (and nil) if the Fraction instance is nil or if zero is
+// raised to a negative power. Every exponent is valid, including math.MinInt,
+// but as with Pow the numerator and denominator silently overflow if the
+// result doesn't fit in an int: use Big for large powers.
+func (f *Fraction) PowInt(exponent int) (*Fraction, error) {
+	if f == nil {
+		return nil, ErrNilFraction
+	}
+	// Negate in uint: -math.MinInt overflows an int.
+	magnitude := uint(exponent)
+	if exponent < 0 {
+		if _, err := f.Reciprocal(); err != nil {
+			return nil, err
+		}
+		magnitude = -magnitude
+	}
+	return f.Pow(magnitude), nil
+}
+
+// PowFraction raises the current Fraction instance to the specified rational
+// power p/q, i.e. it takes the qth root and raises it to the power p, e.g.
+// (4/9)^(3/2) = 8/27 and (8/27)^(-2/3) = 9/4. The exponent is used in lowest
+// terms. Modifies the current Fraction instance in-place and returns it.
+// Returns an error (and nil, leaving the Fraction instance unchanged) if
+// either Fraction instance is nil, if the result is not rational (like
+// 2^(1/2)) or if zero is raised to a negative power.
+func (f *Fraction) PowFraction(exponent *Fraction) (*Fraction, error) {
+	if f == nil || exponent == nil {
+		return nil, ErrNilFraction
+	}
+	reduced := exponent.Clone().Simplify()
+	// Work on a copy, so f is unchanged if the root is not rational.
+	result := f.Clone()
+	if reduced.denominator > 1 {
+		// The root of 2/8 only exists in lowest terms.
+		if _, err := result.Simplify().NthRoot(uint(reduced.denominator)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := result.PowInt(reduced.sign * reduced.numerator); err != nil {
+		return nil, err
+	}
+	*f = *result
+	return f, nil
 }
 
 // NthRoot determines the nth-root of the current Fraction instance. Modifies
@@ -331,10 +645,10 @@ func (f *Fraction) Pow(exponent uint) *Fraction {
 // error (which is nil if no error occurs).
 func (f *Fraction) NthRoot(degree uint) (*Fraction, error) {
 	if f == nil {
-		return nil, errors.New("invalid Fraction instance")
+		return nil, ErrNilFraction
 	}
 	if f.sign == -1 && degree%2 == 0 {
-		return nil, errors.New("the even nth-root of a negative number does not exist")
+		return nil, fmt.Errorf("%w: the even nth-root of a negative number does not exist", ErrNoExactRoot)
 	}
 	if wbmath.IsNthRootInt(f.numerator, degree) && wbmath.IsNthRootInt(f.denominator, degree) {
 		// The nth-roots of the numerator and the denominator are integers => process
@@ -343,7 +657,7 @@ func (f *Fraction) NthRoot(degree uint) (*Fraction, error) {
 		return f, nil
 	} else {
 		// The nth-roots do not yield integers => invalid Fraction
-		return nil, errors.New("the nth-root of this fraction does not yield a valid fraction")
+		return nil, fmt.Errorf("%w: the nth-root of this fraction does not yield a valid fraction", ErrNoExactRoot)
 	}
 }
 
@@ -355,6 +669,159 @@ func (f *Fraction) MustNthRoot(degree uint) *Fraction {
 	return f
 }
 
+// Reciprocal replaces the current Fraction instance with its reciprocal
+// (flips the numerator and the denominator). Modifies the current Fraction
+// instance in-place and returns it (or returns nil if the Fraction instance
+// is nil). Also returns an error if the fraction is zero. Doesn't allocate.
+func (f *Fraction) Reciprocal() (*Fraction, error) {
+	if f == nil {
+		return nil, ErrNilFraction
+	}
+	if f.numerator == 0 {
+		return nil, ErrDivisionByZero
+	}
+	f.numerator, f.denominator = f.denominator, f.numerator
+	return f, nil
+}
+
+// MustReciprocal is identical to Reciprocal, but it panics if an error
+// occurs.
+func (f *Fraction) MustReciprocal() *Fraction {
+	if _, err := f.Reciprocal(); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Negate flips the sign of the current Fraction instance. Modifies the
+// current Fraction instance in-place and returns it (or returns nil if the
+// Fraction instance is nil). Zero keeps a positive sign. Doesn't allocate.
+func (f *Fraction) Negate() *Fraction {
+	if f == nil {
+		return nil
+	}
+	if f.numerator != 0 {
+		f.sign = -f.sign
+	}
+	return f
+}
+
+// Abs makes the current Fraction instance non-negative. Modifies the current
+// Fraction instance in-place and returns it (or returns nil if the Fraction
+// instance is nil). Doesn't allocate.
+func (f *Fraction) Abs() *Fraction {
+	if f == nil {
+		return nil
+	}
+	f.sign = 1
+	return f
+}
+
+// Floor returns the largest integer that is smaller than or equal to the
+// fraction, e.g. 3 for 7/2 and -4 for -7/2. Also returns a boolean value that
+// indicates if the returned value is valid (false if the Fraction instance is
+// nil).
+func (f *Fraction) Floor() (int, bool) {
+	if f == nil {
+		return 0, false
+	}
+	return floorDiv(f.sign*f.numerator, f.denominator), true
+}
+
+// Ceil returns the smallest integer that is larger than or equal to the
+// fraction, e.g. 4 for 7/2 and -3 for -7/2. Also returns a boolean value that
+// indicates if the returned value is valid (false if the Fraction instance is
+// nil).
+func (f *Fraction) Ceil() (int, bool) {
+	if f == nil {
+		return 0, false
+	}
+	return -floorDiv(-f.sign*f.numerator, f.denominator), true
+}
+
+// Trunc returns the integer part of the fraction (rounding towards zero),
+// e.g. 3 for 7/2 and -3 for -7/2. Also returns a boolean value that indicates
+// if the returned value is valid (false if the Fraction instance is nil).
+func (f *Fraction) Trunc() (int, bool) {
+	if f == nil {
+		return 0, false
+	}
+	return f.sign * (f.numerator / f.denominator), true
+}
+
+// Round rounds the current Fraction instance to the specified number of
+// decimal places (halves are rounded away from zero), e.g. 2/3 becomes 67/100
+// for 2 decimal places and 5/2 becomes 3/1 for 0 decimal places. The result
+// is simplified. Modifies the current Fraction instance in-place and returns
+// it. Returns nil if the Fraction instance is nil or if the result doesn't
+// fit in an int.
+func (f *Fraction) Round(decimalPlaces uint) *Fraction {
+	if f == nil {
+		return nil
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimalPlaces)), nil)
+	quotient, remainder := new(big.Int).QuoRem(
+		new(big.Int).Mul(big.NewInt(int64(f.numerator)), scale), big.NewInt(int64(f.denominator)), new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(big.NewInt(int64(f.denominator))) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	rounded := new(big.Rat).SetFrac(quotient, scale)
+	result := bigFraction(rounded.Num(), rounded.Denom())
+	if result == nil {
+		return nil
+	}
+	f.numerator, f.denominator = result.numerator, result.denominator
+	if f.numerator == 0 {
+		f.sign = 1
+	}
+	return f
+}
+
+// DivMod performs the Euclidean division of the current Fraction instance by
+// the specified Fraction instance: it returns the integer quotient q and the
+// remainder r = f - q·other with 0 <= r < |other|, e.g. q = 3 and r = 1/2
+// for 7/2 divided by 1. The current Fraction instance is not modified.
+// Returns an error if either Fraction instance is nil, if other is zero or
+// if the quotient or remainder doesn't fit in an int.
+func (f *Fraction) DivMod(other *Fraction) (int, *Fraction, error) {
+	if f == nil || other == nil {
+		return 0, nil, ErrNilFraction
+	}
+	if other.numerator == 0 {
+		return 0, nil, ErrDivisionByZero
+	}
+	// f / other = (a·d) / (b·c) for f = a/b and other = c/d.
+	a, b := big.NewInt(int64(f.sign*f.numerator)), big.NewInt(int64(f.denominator))
+	c, d := big.NewInt(int64(other.sign*other.numerator)), big.NewInt(int64(other.denominator))
+	// big.Int.Div is the Euclidean division, which is exactly what's needed:
+	// the remainder a·d - q·b·c of the scaled division is non-negative.
+	quotient, remainder := new(big.Int).DivMod(new(big.Int).Mul(a, d), new(big.Int).Mul(b, c), new(big.Int))
+	if !quotient.IsInt64() || quotient.Int64() > math.MaxInt || quotient.Int64() < math.MinInt {
+		return 0, nil, fmt.Errorf("%w: quotient", ErrOverflow)
+	}
+	// r = remainder / (b·d)
+	r := new(big.Rat).SetFrac(remainder, new(big.Int).Mul(b, d))
+	result := bigFraction(r.Num(), r.Denom())
+	if result == nil {
+		return 0, nil, fmt.Errorf("%w: remainder", ErrOverflow)
+	}
+	return int(quotient.Int64()), result, nil
+}
+
+// Mod replaces the current Fraction instance with the remainder of the
+// Euclidean division by the specified Fraction instance (see DivMod), which
+// is always non-negative: e.g. 7/2 mod 1 = 1/2 and -7/2 mod 1 = 1/2.
+// Modifies the current Fraction instance in-place and returns it (or returns
+// nil). Also returns an error for the same reasons as DivMod.
+func (f *Fraction) Mod(other *Fraction) (*Fraction, error) {
+	_, remainder, err := f.DivMod(other)
+	if err != nil {
+		return nil, err
+	}
+	f.numerator, f.denominator, f.sign = remainder.numerator, remainder.denominator, remainder.sign
+	return f, nil
+}
+
 // Numerator returns the numerator of the current Fraction instance. Note that
 // if the fraction is negative, the returned value for the numerator will be
 // negative. Returns the numerator value and a boolean value that indicates if
@@ -390,3 +857,778 @@ func (f *Fraction) AsIntegerRatio() string {
 	}
 	return fmt.Sprintf("%s", result)
 }
+
+// ============================================================================
+// Comparison
+// ============================================================================
+
+// Compare compares the current Fraction instance with the specified Fraction
+// instance and returns -1 if f < other, 0 if f == other and 1 if f > other.
+// The comparison is exact: it compares the cross-multiplied numerators with
+// 128-bit intermediate products, so it never overflows and never rounds. A
+// nil Fraction instance is less than any other Fraction instance.
+func (f *Fraction) Compare(other *Fraction) int {
+	switch {
+	case f == nil && other == nil:
+		return 0
+	case f == nil:
+		return -1
+	case other == nil:
+		return 1
+	}
+	fSign, otherSign := f.signum(), other.signum()
+	if fSign != otherSign {
+		if fSign < otherSign {
+			return -1
+		}
+		return 1
+	}
+	if fSign == 0 {
+		return 0
+	}
+	// Same sign: compare the magnitudes |f.n|·other.d and |other.n|·f.d.
+	leftHi, leftLo := bits.Mul64(uint64(f.numerator), uint64(other.denominator))
+	rightHi, rightLo := bits.Mul64(uint64(other.numerator), uint64(f.denominator))
+	result := 0
+	switch {
+	case leftHi < rightHi || (leftHi == rightHi && leftLo < rightLo):
+		result = -1
+	case leftHi > rightHi || (leftHi == rightHi && leftLo > rightLo):
+		result = 1
+	}
+	return fSign * result
+}
+
+// Equals checks if the current Fraction instance and the specified Fraction
+// instance represent the same rational number, e.g. 1/2 equals 2/4. Returns
+// false if either Fraction instance is nil.
+func (f *Fraction) Equals(other *Fraction) bool {
+	return f != nil && other != nil && f.Compare(other) == 0
+}
+
+// LessThan checks if the current Fraction instance is smaller than the
+// specified Fraction instance. Returns false if either Fraction instance is
+// nil.
+func (f *Fraction) LessThan(other *Fraction) bool {
+	return f != nil && other != nil && f.Compare(other) < 0
+}
+
+// GreaterThan checks if the current Fraction instance is larger than the
+// specified Fraction instance. Returns false if either Fraction instance is
+// nil.
+func (f *Fraction) GreaterThan(other *Fraction) bool {
+	return f != nil && other != nil && f.Compare(other) > 0
+}
+
+// signum returns -1, 0 or 1 depending on the sign of the value: unlike the
+// sign field it is 0 for zero.
+func (f *Fraction) signum() int {
+	if f.numerator == 0 {
+		return 0
+	}
+	return f.sign
+}
+
+// ============================================================================
+// Continued fractions
+// ============================================================================
+
+// ContinuedFraction returns the terms [a0; a1, a2, ...] of the simple
+// continued fraction expansion of the current Fraction instance, computed
+// with the Euclidean algorithm: a0 is the floor of the value (and can be
+// negative), all further terms are positive. The expansion is the canonical
+// one, so it never ends with a term 1 (except for the value 1 itself).
+// Returns nil if the Fraction instance is nil. See the contfrac package for
+// periodic continued fractions and convergents.
+func (f *Fraction) ContinuedFraction() []int {
+	if f == nil {
+		return nil
+	}
+	numerator, denominator := f.sign*f.numerator, f.denominator
+	var terms []int
+	for denominator != 0 {
+		a := floorDiv(numerator, denominator)
+		terms = append(terms, a)
+		numerator, denominator = denominator, numerator-a*denominator
+	}
+	return terms
+}
+
+// NewFromContinuedFraction is a constructor function that returns the
+// (simplified) value of the finite simple continued fraction [a0; a1, a2,
+// ...]. Returns an error if there are no terms, if a term after the first is
+// not positive or if the numerator or denominator overflows.
+func NewFromContinuedFraction(terms []int) (*Fraction, error) {
+	if len(terms) == 0 {
+		return nil, errors.New("continued fraction must have at least one term")
+	}
+	// The recurrences h(n) = a(n)·h(n-1) + h(n-2) and k(n) = a(n)·k(n-1) +
+	// k(n-2) give the numerator and the denominator of the convergents.
+	h, hPrevious := 1, 0
+	k, kPrevious := 0, 1
+	for i, a := range terms {
+		if i > 0 && a <= 0 {
+			return nil, fmt.Errorf("term %d must be positive, got %d", i, a)
+		}
+		hNext, hOk := mulAdd(a, h, hPrevious)
+		kNext, kOk := mulAdd(a, k, kPrevious)
+		if !hOk || !kOk {
+			return nil, fmt.Errorf("%w: continued fraction", ErrOverflow)
+		}
+		h, hPrevious = hNext, h
+		k, kPrevious = kNext, k
+	}
+	return New(h, k)
+}
+
+// Approximate returns the fraction closest to value among all fractions with
+// a denominator of at most maxDenominator, e.g. 1/3 for 0.3333333333 with
+// maxDenominator 100 (where NewFromNumber gives 3333333333/10000000000). It
+// walks the continued fraction expansion of the exact value of the float
+// (the path in the Stern-Brocot tree) and picks the best of the last
+// convergent and semiconvergent within the bound, so the result is always a
+// best rational approximation. Returns nil if value is NaN or infinite, if
+// maxDenominator is smaller than 1 or if the result doesn't fit in an int.
+func Approximate(value float64, maxDenominator int) *Fraction {
+	if math.IsNaN(value) || math.IsInf(value, 0) || maxDenominator < 1 {
+		return nil
+	}
+	return approximateRat(new(big.Rat).SetFloat64(value), maxDenominator)
+}
+
+// approximateRat returns the best rational approximation of exact with a
+// denominator of at most maxDenominator (see Approximate).
+func approximateRat(exact *big.Rat, maxDenominator int) *Fraction {
+	limit := big.NewInt(int64(maxDenominator))
+	if exact.Denom().Cmp(limit) <= 0 {
+		return bigFraction(exact.Num(), exact.Denom())
+	}
+	// (p0/q0, p1/q1) are the last two convergents, n/d is the remainder.
+	p0, q0, p1, q1 := big.NewInt(0), big.NewInt(1), big.NewInt(1), big.NewInt(0)
+	n, d := new(big.Int).Set(exact.Num()), new(big.Int).Set(exact.Denom())
+	a, q2, t := new(big.Int), new(big.Int), new(big.Int)
+	for {
+		a.Div(n, d) // floor division for positive d
+		q2.Add(q0, t.Mul(a, q1))
+		if q2.Cmp(limit) > 0 {
+			break
+		}
+		p0, p1 = p1, new(big.Int).Add(p0, t.Mul(a, p1))
+		q0, q1 = q1, new(big.Int).Set(q2)
+		n, d = d, new(big.Int).Sub(n, t.Mul(a, d))
+	}
+	// The largest semiconvergent within the bound.
+	k := new(big.Int).Sub(limit, q0)
+	k.Div(k, q1)
+	semi := new(big.Rat).SetFrac(new(big.Int).Add(p0, new(big.Int).Mul(k, p1)), new(big.Int).Add(q0, new(big.Int).Mul(k, q1)))
+	convergent := new(big.Rat).SetFrac(p1, q1)
+	semiDistance := new(big.Rat).Sub(semi, exact)
+	convergentDistance := new(big.Rat).Sub(convergent, exact)
+	if convergentDistance.Abs(convergentDistance).Cmp(semiDistance.Abs(semiDistance)) <= 0 {
+		return bigFraction(convergent.Num(), convergent.Denom())
+	}
+	return bigFraction(semi.Num(), semi.Denom())
+}
+
+// ============================================================================
+// Percentages
+// ============================================================================
+
+// NewFromPercent is a constructor function that returns the fraction closest
+// to p percent with a denominator of at most maxDenominator, e.g. 1/8 for
+// 12.5 and 1/3 for 33.33 with maxDenominator 10 (see Approximate). The
+// percentage is divided by 100 exactly, so 7 gives 7/100 for maxDenominator
+// 100. Returns an error if p is NaN or infinite, if maxDenominator is smaller
+// than 1 or if the result doesn't fit in an int.
+func NewFromPercent(p float64, maxDenominator int) (*Fraction, error) {
+	if math.IsNaN(p) || math.IsInf(p, 0) {
+		return nil, fmt.Errorf("invalid percentage %v", p)
+	}
+	if maxDenominator < 1 {
+		return nil, errors.New("maximum denominator must be at least 1")
+	}
+	exact := new(big.Rat).SetFloat64(p)
+	result := approximateRat(exact.Quo(exact, big.NewRat(100, 1)), maxDenominator)
+	if result == nil {
+		return nil, fmt.Errorf("%v%% doesn't fit in a Fraction", p)
+	}
+	return result, nil
+}
+
+// Percent returns the fraction as a percentage, e.g. 12.5 for 1/8. The result
+// is correctly rounded, so 7/100 gives exactly 7. Returns NaN if the Fraction
+// instance is nil.
+func (f *Fraction) Percent() float64 {
+	return f.scaled(100)
+}
+
+// PerMille returns the fraction in parts per thousand, e.g. 125 for 1/8.
+// Returns NaN if the Fraction instance is nil.
+func (f *Fraction) PerMille() float64 {
+	return f.scaled(1000)
+}
+
+// scaled returns the correctly rounded float64 value of factor times the
+// fraction, or NaN if the Fraction instance is nil.
+func (f *Fraction) scaled(factor int64) float64 {
+	if f == nil {
+		return math.NaN()
+	}
+	r := f.Rat()
+	value, _ := r.Mul(r, big.NewRat(factor, 1)).Float64()
+	return value
+}
+
+// ============================================================================
+// big.Rat conversion
+// ============================================================================
+
+// NewFromRat is a constructor function that converts a big.Rat to a
+// (simplified) Fraction. Returns an error if r is nil or if its numerator or
+// denominator doesn't fit in an int.
+func NewFromRat(r *big.Rat) (*Fraction, error) {
+	if r == nil {
+		return nil, fmt.Errorf("%w: nil *big.Rat", ErrNilFraction)
+	}
+	f := bigFraction(r.Num(), r.Denom())
+	if f == nil {
+		return nil, fmt.Errorf("%s doesn't fit in a Fraction", r.RatString())
+	}
+	return f, nil
+}
+
+// Rat returns the value of the current Fraction instance as a new big.Rat.
+// Returns nil if the Fraction instance is nil.
+func (f *Fraction) Rat() *big.Rat {
+	if f == nil {
+		return nil
+	}
+	return new(big.Rat).SetFrac64(int64(f.sign*f.numerator), int64(f.denominator))
+}
+
+// ============================================================================
+// Map keys
+// ============================================================================
+
+// Key is the canonical, comparable value form of a Fraction: the numerator
+// (carrying the sign) and the positive denominator in lowest terms. Equal
+// rational numbers have equal Keys, so Keys can be used as map keys and in
+// sets, e.g. map[fraction.Key]int counts 1/2 and 2/4 together.
+type Key struct {
+	Numerator   int
+	Denominator int
+}
+
+// Key returns the canonical Key of the current Fraction instance. Returns the
+// zero Key (0/0, which no Fraction has) if the Fraction instance is nil.
+func (f *Fraction) Key() Key {
+	if f == nil {
+		return Key{}
+	}
+	g := wbmath.Gcd(f.numerator, f.denominator)
+	return Key{Numerator: f.sign * f.numerator / g, Denominator: f.denominator / g}
+}
+
+// Fraction returns the Key as a new Fraction. Returns nil for the zero Key.
+func (k Key) Fraction() *Fraction {
+	f, err := New(k.Numerator, k.Denominator)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// String returns the Key as an integer ratio, e.g. "-1/2".
+func (k Key) String() string {
+	return fmt.Sprintf("%d/%d", k.Numerator, k.Denominator)
+}
+
+// ============================================================================
+// Aggregates
+// ============================================================================
+
+// Sum returns the exact sum of the fractions as a new, simplified Fraction
+// (0/1 for no fractions). The intermediate sums are simplified as well, which
+// keeps the denominators small. The inputs are not modified. Returns an
+// error if a Fraction instance is nil.
+func Sum(fs ...*Fraction) (*Fraction, error) {
+	return aggregate(MustNew(0, 1), fs, (*Fraction).Add)
+}
+
+// Product returns the exact product of the fractions as a new, simplified
+// Fraction (1/1 for no fractions). The inputs are not modified. Returns an
+// error if a Fraction instance is nil.
+func Product(fs ...*Fraction) (*Fraction, error) {
+	return aggregate(MustNew(1, 1), fs, (*Fraction).Multiply)
+}
+
+// Mean returns the exact arithmetic mean of the fractions as a new,
+// simplified Fraction. The inputs are not modified. Returns an error if there
+// are no fractions or if a Fraction instance is nil.
+func Mean(fs ...*Fraction) (*Fraction, error) {
+	if len(fs) == 0 {
+		return nil, errors.New("mean of no fractions is undefined")
+	}
+	sum, err := Sum(fs...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sum.DivideInt(len(fs)); err != nil {
+		return nil, err
+	}
+	return sum.Simplify(), nil
+}
+
+// Min returns a copy of the smallest fraction (compared exactly). Returns an
+// error if there are no fractions or if a Fraction instance is nil.
+func Min(fs ...*Fraction) (*Fraction, error) {
+	return extreme(fs, -1)
+}
+
+// Max returns a copy of the largest fraction (compared exactly). Returns an
+// error if there are no fractions or if a Fraction instance is nil.
+func Max(fs ...*Fraction) (*Fraction, error) {
+	return extreme(fs, 1)
+}
+
+// CommonDenominator rewrites the fractions over their least common
+// denominator: it returns the lcm of the (simplified) denominators and new
+// Fractions with that denominator, e.g. 1/4, 5/6 and 2/3 become 3/12, 10/12
+// and 8/12 with common denominator 12. Adding the numerators then gives the
+// sum without any intermediate blow-up. The inputs are not modified (the
+// common denominator is 1 for no fractions). Returns an error if a Fraction
+// instance is nil or if the result overflows int.
+func CommonDenominator(fs ...*Fraction) (int, []*Fraction, error) {
+	common := 1
+	for i, f := range fs {
+		if f == nil {
+			return 0, nil, fmt.Errorf("%w at index %d", ErrNilFraction, i)
+		}
+		denominator := f.denominator / wbmath.Gcd(f.numerator, f.denominator)
+		next, ok := mulAdd(common/wbmath.Gcd(common, denominator), denominator, 0)
+		if !ok {
+			return 0, nil, fmt.Errorf("%w: common denominator", ErrOverflow)
+		}
+		common = next
+	}
+	result := make([]*Fraction, len(fs))
+	for i, f := range fs {
+		// Scale the simplified fraction: common is a multiple of its
+		// denominator, but not necessarily of f.denominator.
+		g := wbmath.Gcd(f.numerator, f.denominator)
+		numerator, ok := mulAdd(f.sign*f.numerator/g, common/(f.denominator/g), 0)
+		if !ok {
+			return 0, nil, fmt.Errorf("%w: numerator at index %d", ErrOverflow, i)
+		}
+		result[i] = &Fraction{numerator: wbmath.Abs(numerator), denominator: common, sign: f.sign}
+		if numerator == 0 {
+			result[i].sign = 1
+		}
+	}
+	return common, result, nil
+}
+
+// ============================================================================
+// Mediants and Farey sequences
+// ============================================================================
+
+// Mediant replaces the current Fraction instance a/b with the mediant
+// (a + c)/(b + d) of a/b and the specified Fraction instance c/d (with signed
+// numerators). The mediant of two fractions lies strictly between them, and
+// the mediant of neighbours in a Farey sequence is the simplest fraction
+// between them. The result depends on the representation: simplify the
+// fractions first to get the mediant of the rational numbers. Modifies the
+// current Fraction instance in-place and returns it. Returns nil if either
+// Fraction instance is nil.
+func (f *Fraction) Mediant(other *Fraction) *Fraction {
+	if f == nil || other == nil {
+		return nil
+	}
+	numerator := f.sign*f.numerator + other.sign*other.numerator
+	f.numerator = wbmath.Abs(numerator)
+	f.denominator += other.denominator
+	f.sign = intSign(numerator)
+	return f.autoSimplify()
+}
+
+// Farey returns the Farey sequence of order n: all simplified fractions
+// between 0 and 1 (inclusive) with a denominator of at most n, in increasing
+// order, e.g. 0/1, 1/3, 1/2, 2/3, 1/1 for n = 3. Returns nil if n is smaller
+// than 1.
+func Farey(n int) []*Fraction {
+	if n < 1 {
+		return nil
+	}
+	// Every next term follows from the previous two: for neighbours a/b and
+	// c/d, the next term is (k·c - a)/(k·d - b) with k = (n + b) / d.
+	a, b, c, d := 0, 1, 1, n
+	result := []*Fraction{MustNew(a, b)}
+	for c <= n {
+		k := (n + b) / d
+		a, b, c, d = c, d, k*c-a, k*d-b
+		result = append(result, MustNew(a, b))
+	}
+	return result
+}
+
+// ============================================================================
+// Random fractions
+// ============================================================================
+
+// Random returns a random Fraction with a numerator drawn uniformly from
+// [-maxNumerator, maxNumerator] and a denominator drawn uniformly from
+// [1, maxDenominator], using the specified generator. The Fraction is not
+// simplified (use Abs for a non-negative one). The result is deterministic
+// for a seeded generator, e.g. rand.New(rand.NewPCG(1, 2)). Returns nil if
+// rng is nil, if maxNumerator is negative or if maxDenominator is smaller
+// than 1.
+func Random(rng *rand.Rand, maxNumerator, maxDenominator int) *Fraction {
+	if rng == nil || maxNumerator < 0 || maxDenominator < 1 {
+		return nil
+	}
+	numerator := rng.IntN(2*maxNumerator+1) - maxNumerator
+	denominator := rng.IntN(maxDenominator) + 1
+	return MustNew(numerator, denominator)
+}
+
+// RandomSimplified is identical to Random, but returns the Fraction in lowest
+// terms (so the denominator can be smaller than the one drawn).
+func RandomSimplified(rng *rand.Rand, maxNumerator, maxDenominator int) *Fraction {
+	return Random(rng, maxNumerator, maxDenominator).Simplify()
+}
+
+// ============================================================================
+// Arena allocation
+// ============================================================================
+
+// Arena allocates Fractions in blocks instead of one at a time, which reduces
+// the number of allocations and the pressure on the garbage collector in
+// bulk rational computations. Fractions from an arena behave like any other
+// Fraction until Reset is called: after that they must no longer be used,
+// because their memory is handed out again. An Arena is not safe for
+// concurrent use.
+type Arena struct {
+	blocks    [][]Fraction
+	block     int
+	next      int
+	blockSize int
+}
+
+// NewArena is a constructor function that returns an Arena that allocates
+// blocks of `blockSize` Fractions at a time (1024 if blockSize < 1).
+func NewArena(blockSize int) *Arena {
+	if blockSize < 1 {
+		blockSize = 1024
+	}
+	return &Arena{blockSize: blockSize}
+}
+
+// New is identical to the package-level New but allocates the Fraction in
+// the arena.
+func (a *Arena) New(numerator, denominator int) (*Fraction, error) {
+	if denominator == 0 {
+		return nil, ErrDivisionByZero
+	}
+	f := a.alloc()
+	*f = Fraction{
+		numerator:   wbmath.Abs(numerator),
+		denominator: wbmath.Abs(denominator),
+		sign:        intSign(numerator) * intSign(denominator),
+	}
+	if numerator == 0 {
+		f.sign = 1
+	}
+	return f, nil
+}
+
+// MustNew is identical to New but panics if an error occurs.
+func (a *Arena) MustNew(numerator, denominator int) *Fraction {
+	f, err := a.New(numerator, denominator)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Copy returns a copy of the specified Fraction allocated in the arena.
+// Returns nil if the Fraction instance is nil.
+func (a *Arena) Copy(f *Fraction) *Fraction {
+	if f == nil {
+		return nil
+	}
+	result := a.alloc()
+	*result = *f
+	return result
+}
+
+// Len returns the number of Fractions allocated since the last Reset.
+func (a *Arena) Len() int {
+	if len(a.blocks) == 0 {
+		return 0
+	}
+	return a.block*a.blockSize + a.next
+}
+
+// Reset makes all memory of the arena available again without freeing it.
+// Fractions allocated before the Reset must no longer be used.
+func (a *Arena) Reset() {
+	a.block, a.next = 0, 0
+}
+
+// alloc returns the next free Fraction, adding a block when needed.
+func (a *Arena) alloc() *Fraction {
+	if len(a.blocks) == 0 {
+		a.blocks = append(a.blocks, make([]Fraction, a.blockSize))
+	}
+	if a.next == a.blockSize {
+		a.block++
+		a.next = 0
+		if a.block == len(a.blocks) {
+			a.blocks = append(a.blocks, make([]Fraction, a.blockSize))
+		}
+	}
+	f := &a.blocks[a.block][a.next]
+	a.next++
+	return f
+}
+
+// ============================================================================
+// Batches
+// ============================================================================
+
+// Fractions is a batch of rational numbers stored as parallel slices of
+// numerators, denominators and signs (a structure of arrays), with the same
+// conventions as Fraction. Operating on a batch avoids the pointer chasing and
+// the per-element allocations of a []*Fraction, which matters for workloads
+// with millions of rationals. The batch operations modify the batch in-place.
+type Fractions struct {
+	numerators   []int
+	denominators []int
+	signs        []int
+}
+
+// NewFractions is a constructor function that returns a batch of n
+// fractions that are all zero (0/1).
+func NewFractions(n int) *Fractions {
+	b := &Fractions{
+		numerators:   make([]int, n),
+		denominators: make([]int, n),
+		signs:        make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		b.denominators[i] = 1
+		b.signs[i] = 1
+	}
+	return b
+}
+
+// NewFractionsFrom is a constructor function that returns a batch with
+// copies of the specified fractions. Returns an error if a Fraction instance
+// is nil.
+func NewFractionsFrom(fractions ...*Fraction) (*Fractions, error) {
+	b := &Fractions{
+		numerators:   make([]int, 0, len(fractions)),
+		denominators: make([]int, 0, len(fractions)),
+		signs:        make([]int, 0, len(fractions)),
+	}
+	for i, f := range fractions {
+		if f == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilFraction, i)
+		}
+		b.numerators = append(b.numerators, f.numerator)
+		b.denominators = append(b.denominators, f.denominator)
+		b.signs = append(b.signs, f.sign)
+	}
+	return b, nil
+}
+
+// Len returns the number of fractions in the batch.
+func (b *Fractions) Len() int {
+	return len(b.numerators)
+}
+
+// Append adds the fraction numerator / denominator to the batch. Returns an
+// error if the denominator is zero.
+func (b *Fractions) Append(numerator, denominator int) error {
+	if denominator == 0 {
+		return ErrDivisionByZero
+	}
+	sign := intSign(numerator) * intSign(denominator)
+	if numerator == 0 {
+		sign = 1
+	}
+	b.numerators = append(b.numerators, wbmath.Abs(numerator))
+	b.denominators = append(b.denominators, wbmath.Abs(denominator))
+	b.signs = append(b.signs, sign)
+	return nil
+}
+
+// At returns a copy of the fraction at index i as a new Fraction. Returns
+// nil if the index is out of range.
+func (b *Fractions) At(i int) *Fraction {
+	if i < 0 || i >= len(b.numerators) {
+		return nil
+	}
+	return &Fraction{numerator: b.numerators[i], denominator: b.denominators[i], sign: b.signs[i]}
+}
+
+// Set replaces the fraction at index i with a copy of f. Returns an error if
+// the index is out of range or if the Fraction instance is nil.
+func (b *Fractions) Set(i int, f *Fraction) error {
+	if i < 0 || i >= len(b.numerators) {
+		return fmt.Errorf("index %d out of range for batch of length %d", i, len(b.numerators))
+	}
+	if f == nil {
+		return ErrNilFraction
+	}
+	b.numerators[i], b.denominators[i], b.signs[i] = f.numerator, f.denominator, f.sign
+	return nil
+}
+
+// AddAll adds the fractions of other element-wise to the batch. Returns an
+// error if the lengths of the batches differ.
+func (b *Fractions) AddAll(other *Fractions) error {
+	if err := b.check(other); err != nil {
+		return err
+	}
+	for i, denominator := range other.denominators {
+		numerator := b.signs[i]*b.numerators[i]*denominator + other.signs[i]*other.numerators[i]*b.denominators[i]
+		b.numerators[i] = wbmath.Abs(numerator)
+		b.denominators[i] *= denominator
+		b.signs[i] = intSign(numerator)
+	}
+	return nil
+}
+
+// MulAll multiplies the batch element-wise with the fractions of other.
+// Returns an error if the lengths of the batches differ.
+func (b *Fractions) MulAll(other *Fractions) error {
+	if err := b.check(other); err != nil {
+		return err
+	}
+	for i := range other.numerators {
+		b.numerators[i] *= other.numerators[i]
+		b.denominators[i] *= other.denominators[i]
+		b.signs[i] *= other.signs[i]
+		if b.numerators[i] == 0 {
+			b.signs[i] = 1
+		}
+	}
+	return nil
+}
+
+// SimplifyAll simplifies every fraction in the batch.
+func (b *Fractions) SimplifyAll() *Fractions {
+	for i, numerator := range b.numerators {
+		if gcd := wbmath.Gcd(numerator, b.denominators[i]); gcd > 1 {
+			b.numerators[i] = numerator / gcd
+			b.denominators[i] /= gcd
+		}
+	}
+	return b
+}
+
+// EvaluateAll returns the values of the fractions as float values.
+func (b *Fractions) EvaluateAll() []float64 {
+	result := make([]float64, len(b.numerators))
+	for i, numerator := range b.numerators {
+		result[i] = float64(b.signs[i]*numerator) / float64(b.denominators[i])
+	}
+	return result
+}
+
+// check returns an error if the batches have different lengths.
+func (b *Fractions) check(other *Fractions) error {
+	if other == nil || len(other.numerators) != len(b.numerators) {
+		return errors.New("batches must have the same length")
+	}
+	return nil
+}
+
+// autoSimplify simplifies the fraction if automatic simplification is
+// enabled and returns it.
+func (f *Fraction) autoSimplify() *Fraction {
+	if f.simplify {
+		f.Simplify()
+	}
+	return f
+}
+
+// bigFraction returns the Fraction numerator/denominator, or nil if it
+// doesn't fit in an int.
+func bigFraction(numerator, denominator *big.Int) *Fraction {
+	if !numerator.IsInt64() || !denominator.IsInt64() ||
+		numerator.Int64() > math.MaxInt || numerator.Int64() < -math.MaxInt || denominator.Int64() > math.MaxInt {
+		return nil
+	}
+	f, _ := New(int(numerator.Int64()), int(denominator.Int64()))
+	return f
+}
+
+// aggregate combines the fractions into the accumulator with auto
+// simplification enabled, and returns the simplified accumulator.
+func aggregate(accumulator *Fraction, fs []*Fraction, combine func(*Fraction, *Fraction) *Fraction) (*Fraction, error) {
+	accumulator.AutoSimplify(true)
+	for i, f := range fs {
+		if f == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilFraction, i)
+		}
+		combine(accumulator, f)
+	}
+	return accumulator.AutoSimplify(false), nil
+}
+
+// extreme returns a copy of the smallest (direction -1) or the largest
+// (direction 1) fraction.
+func extreme(fs []*Fraction, direction int) (*Fraction, error) {
+	if len(fs) == 0 {
+		return nil, errors.New("no fractions specified")
+	}
+	var result *Fraction
+	for i, f := range fs {
+		if f == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilFraction, i)
+		}
+		if result == nil || f.Compare(result) == direction {
+			result = f
+		}
+	}
+	return result.Clone(), nil
+}
+
+// floorDiv returns floor(a / b).
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// mulAdd returns a*b + c and a boolean value that indicates if the result
+// fits in an int.
+func mulAdd(a, b, c int) (int, bool) {
+	negative := (a < 0) != (b < 0)
+	hi, lo := bits.Mul64(uint64(wbmath.Abs(a)), uint64(wbmath.Abs(b)))
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	product := int(lo)
+	if negative {
+		product = -product
+	}
+	sum := product + c
+	if (c > 0 && sum < product) || (c < 0 && sum > product) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// intSign returns -1 for negative values and 1 otherwise (including zero),
+// matching the sign convention of Fraction.
+func intSign(value int) int {
+	if value < 0 {
+		return -1
+	}
+	return 1
+}
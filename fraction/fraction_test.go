@@ -1,7 +1,14 @@
 package fraction
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
+	"math/big"
+	"math/rand/v2"
+	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -177,6 +184,42 @@ func TestAsIntegerRatioAndNilReceiver(t *testing.T) {
 	}
 }
 
+func TestNewFromStringFormats(t *testing.T) {
+	tests := map[string]string{
+		"2 1/3":     "7/3",
+		"-2 1/3":    "-7/3",
+		" +1  2/4 ": "6/4",
+		"5":         "5/1",
+		"-12":       "-12/1",
+		"0.75":      "3/4",
+		"-1.5e-1":   "-3/20",
+		"½":         "1/2",
+		"-¾":        "-3/4",
+		"2½":        "5/2",
+		"-3 ⅛":      "-25/8",
+		"7⁄8":       "7/8",
+		"3 / -4":    "-3/4",
+	}
+	for input, want := range tests {
+		f, err := NewFromString(input)
+		if err != nil || f.AsIntegerRatio() != want {
+			t.Fatalf("NewFromString(%q) = %v, %v; want %s", input, f.AsIntegerRatio(), err, want)
+		}
+	}
+	// Parsing the output of String round-trips.
+	for _, f := range []*Fraction{MustNew(7, 3), MustNew(-7, 3), MustNew(-1, 4), MustNew(12, 1), MustNew(0, 1)} {
+		parsed, err := NewFromString(f.String())
+		if err != nil || !parsed.Equals(f) {
+			t.Fatalf("NewFromString(%q) = %v, %v; want %v", f.String(), parsed, err, f)
+		}
+	}
+	for _, input := range []string{"", "abc", "1 2", "2 1/0", "1.5/0", "½½", "2 -1/3"} {
+		if _, err := NewFromString(input); err == nil {
+			t.Fatalf("NewFromString(%q) should return error", input)
+		}
+	}
+}
+
 func TestMustNewFromString(t *testing.T) {
 	f := MustNewFromString("4/6")
 	if f == nil {
@@ -186,3 +229,946 @@ func TestMustNewFromString(t *testing.T) {
 		t.Fatalf("MustNewFromString Evaluate = %v; want %v", v, 2.0/3.0)
 	}
 }
+
+func TestIntFastPaths(t *testing.T) {
+	// The integer fast paths must agree with the general operations.
+	for _, value := range []int{-3, -1, 0, 2, 5} {
+		for _, start := range [][2]int{{3, 4}, {-5, 6}, {0, 1}} {
+			fast, slow := MustNew(start[0], start[1]), MustNew(start[0], start[1])
+			fast.AddInt(value).MultiplyInt(value).SubtractInt(value)
+			slow.Add(MustNew(value, 1)).Multiply(MustNew(value, 1)).Subtract(MustNew(value, 1))
+			if fast.AsIntegerRatio() != slow.AsIntegerRatio() {
+				t.Fatalf("fast path = %s; want %s", fast.AsIntegerRatio(), slow.AsIntegerRatio())
+			}
+		}
+	}
+	f := MustNew(3, 4)
+	f.MustDivideInt(-3)
+	if f.Simplify().String() != "-1/4" {
+		t.Fatalf("3/4 / -3 = %v; want -1/4", f)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { f.AddInt(1).SubtractInt(1).MultiplyInt(1) }); allocs != 0 {
+		t.Fatalf("integer fast paths allocate %v times; want 0", allocs)
+	}
+}
+
+func TestArena(t *testing.T) {
+	arena := NewArena(2)
+	a := arena.MustNew(1, -2)
+	b := arena.MustNew(0, -5)
+	c := arena.Copy(a).AddInt(1)
+	if a.String() != "-1/2" || b.String() != "0" || c.String() != "1/2" {
+		t.Fatalf("arena fractions = %v, %v, %v", a, b, c)
+	}
+	if _, err := arena.New(1, 0); err == nil {
+		t.Fatalf("Arena.New(1, 0) should return error")
+	}
+	if arena.Len() != 3 {
+		t.Fatalf("Len() = %d; want 3", arena.Len())
+	}
+	arena.Reset()
+	if arena.Len() != 0 {
+		t.Fatalf("Len() after Reset = %d; want 0", arena.Len())
+	}
+	if allocs := testing.AllocsPerRun(10, func() { arena.Reset(); arena.MustNew(1, 2); arena.MustNew(3, 4) }); allocs != 0 {
+		t.Fatalf("arena allocates %v times when reused; want 0", allocs)
+	}
+}
+
+func TestClone(t *testing.T) {
+	f := MustNew(-3, 4)
+	c := f.Clone()
+	c.AddInt(1)
+	if f.AsIntegerRatio() != "-3/4" || c.AsIntegerRatio() != "1/4" {
+		t.Fatalf("after c.AddInt(1): f = %s, c = %s; want -3/4, 1/4", f.AsIntegerRatio(), c.AsIntegerRatio())
+	}
+	var nilFraction *Fraction
+	if nilFraction.Clone() != nil {
+		t.Fatalf("Clone() of nil should return nil")
+	}
+	b := MustNewBig(-3, 4)
+	d := b.Clone().AddInt(1)
+	if b.AsIntegerRatio() != "-3/4" || d.AsIntegerRatio() != "1/4" {
+		t.Fatalf("after d.AddInt(1): b = %s, d = %s; want -3/4, 1/4", b.AsIntegerRatio(), d.AsIntegerRatio())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type payload struct {
+		Ratio *Fraction `json:"ratio"`
+		Other *Fraction `json:"other,omitempty"`
+	}
+	data, err := json.Marshal(payload{Ratio: MustNew(6, -8)})
+	if err != nil || string(data) != `{"ratio":"-6/8"}` {
+		t.Fatalf("Marshal = %s, %v; want {\"ratio\":\"-6/8\"}", data, err)
+	}
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil || p.Ratio.AsIntegerRatio() != "-6/8" {
+		t.Fatalf("Unmarshal(%s) = %v, %v; want -6/8", data, p.Ratio, err)
+	}
+	data, err = json.Marshal(JSONObjectOf(MustNew(-3, 4)))
+	if err != nil || string(data) != `{"num":-3,"den":4}` {
+		t.Fatalf("Marshal in object form = %s, %v", data, err)
+	}
+	type objects struct {
+		Ratio JSONObject `json:"ratio"`
+		Other JSONObject `json:"other"`
+	}
+	data, err = json.Marshal(objects{Ratio: JSONObjectOf(MustNew(6, -8))})
+	if err != nil || string(data) != `{"ratio":{"num":-6,"den":8},"other":null}` {
+		t.Fatalf("Marshal of JSONObject fields = %s, %v", data, err)
+	}
+	var o objects
+	if err := json.Unmarshal([]byte(`{"ratio":{"num":-6,"den":8},"other":"1/2"}`), &o); err != nil ||
+		o.Ratio.AsIntegerRatio() != "-6/8" || o.Other.AsIntegerRatio() != "1/2" {
+		t.Fatalf("Unmarshal of JSONObject fields = %v, %v, %v", o.Ratio, o.Other, err)
+	}
+	// The default encoding is still the ratio string.
+	if data, _ := json.Marshal(MustNew(-3, 4)); string(data) != `"-3/4"` {
+		t.Fatalf("Marshal = %s; want \"-3/4\"", data)
+	}
+	tests := map[string]string{
+		`{"num":-3,"den":4}`: "-3/4",
+		`"1 / 3"`:            "1/3",
+		`"5"`:                "5/1",
+		`"0.25"`:             "1/4",
+		`7`:                  "7/1",
+		`-1.5`:               "-3/2",
+	}
+	for input, want := range tests {
+		f := MustNew(0, 1)
+		if err := json.Unmarshal([]byte(input), f); err != nil || f.AsIntegerRatio() != want {
+			t.Fatalf("Unmarshal(%s) = %v, %v; want %s", input, f.AsIntegerRatio(), err, want)
+		}
+	}
+	for _, input := range []string{`"1/0"`, `{"num":1}`, `true`, `"abc"`} {
+		if err := json.Unmarshal([]byte(input), MustNew(0, 1)); err == nil {
+			t.Fatalf("Unmarshal(%s) should return error", input)
+		}
+	}
+}
+
+func TestReciprocalNegateAbs(t *testing.T) {
+	f, err := MustNew(-3, 4).Reciprocal()
+	if err != nil || f.AsIntegerRatio() != "-4/3" {
+		t.Fatalf("Reciprocal(-3/4) = %v, %v; want -4/3", f, err)
+	}
+	if _, err := MustNew(0, 5).Reciprocal(); err == nil {
+		t.Fatalf("Reciprocal(0) should return error")
+	}
+	if got := MustNew(-3, 4).Negate().AsIntegerRatio(); got != "3/4" {
+		t.Fatalf("Negate(-3/4) = %s; want 3/4", got)
+	}
+	if got := MustNew(0, 4).Negate().AsIntegerRatio(); got != "0/4" {
+		t.Fatalf("Negate(0/4) = %s; want 0/4", got)
+	}
+	if got := MustNew(-3, 4).Abs().MustReciprocal().AsIntegerRatio(); got != "4/3" {
+		t.Fatalf("Abs(-3/4).MustReciprocal() = %s; want 4/3", got)
+	}
+	b, err := MustNewBig(-3, 4).Reciprocal()
+	if err != nil || b.AsIntegerRatio() != "-4/3" {
+		t.Fatalf("Big.Reciprocal(-3/4) = %v, %v; want -4/3", b, err)
+	}
+	if got := b.Negate().AsIntegerRatio(); got != "4/3" {
+		t.Fatalf("Big.Negate(-4/3) = %s; want 4/3", got)
+	}
+	if got := MustNewBig(-1, 2).Abs().AsIntegerRatio(); got != "1/2" {
+		t.Fatalf("Big.Abs(-1/2) = %s; want 1/2", got)
+	}
+}
+
+func TestAutoSimplify(t *testing.T) {
+	f := MustNew(6, 8).AutoSimplify(true)
+	if !f.IsAutoSimplify() || f.AsIntegerRatio() != "3/4" {
+		t.Fatalf("AutoSimplify(true) = %s; want 3/4", f.AsIntegerRatio())
+	}
+	// Without simplification the denominator of the harmonic sum 1 + 1/2 +
+	// ... + 1/30 would be 30! and overflow.
+	sum := MustNew(0, 1).AutoSimplify(true)
+	for k := 1; k <= 30; k++ {
+		sum.Add(MustNew(1, k))
+	}
+	if got := sum.AsIntegerRatio(); got != "9304682830147/2329089562800" {
+		t.Fatalf("H(30) = %s; want 9304682830147/2329089562800", got)
+	}
+	c := f.Clone().Multiply(MustNew(2, 3))
+	if !c.IsAutoSimplify() || c.AsIntegerRatio() != "1/2" {
+		t.Fatalf("Clone().Multiply(2/3) = %s; want 1/2", c.AsIntegerRatio())
+	}
+	if got := f.AutoSimplify(false).MultiplyInt(2).AsIntegerRatio(); got != "6/4" {
+		t.Fatalf("MultiplyInt(2) without AutoSimplify = %s; want 6/4", got)
+	}
+	// Arena memory that is handed out again doesn't keep the setting.
+	arena := NewArena(1)
+	arena.MustNew(1, 2).AutoSimplify(true)
+	arena.Reset()
+	if arena.MustNew(2, 4).IsAutoSimplify() {
+		t.Fatalf("Arena.New after Reset should not auto-simplify")
+	}
+}
+
+func TestContinuedFraction(t *testing.T) {
+	tests := []struct {
+		f    *Fraction
+		want []int
+	}{
+		{MustNew(415, 93), []int{4, 2, 6, 7}},
+		{MustNew(-7, 3), []int{-3, 1, 2}},
+		{MustNew(6, 4), []int{1, 2}},
+		{MustNew(0, 3), []int{0}},
+		{MustNew(5, 1), []int{5}},
+	}
+	for _, test := range tests {
+		got := test.f.ContinuedFraction()
+		if !slices.Equal(got, test.want) {
+			t.Fatalf("%s.ContinuedFraction() = %v; want %v", test.f.AsIntegerRatio(), got, test.want)
+		}
+		back, err := NewFromContinuedFraction(got)
+		if err != nil || !back.Equals(test.f) {
+			t.Fatalf("NewFromContinuedFraction(%v) = %v, %v; want %v", got, back, err, test.f)
+		}
+	}
+	// A non-canonical expansion ending with 1 gives the same value.
+	if f, err := NewFromContinuedFraction([]int{4, 2, 6, 6, 1}); err != nil || f.AsIntegerRatio() != "415/93" {
+		t.Fatalf("NewFromContinuedFraction([4; 2, 6, 6, 1]) = %v, %v; want 415/93", f, err)
+	}
+	if _, err := NewFromContinuedFraction(nil); err == nil {
+		t.Fatalf("NewFromContinuedFraction(nil) should return error")
+	}
+	if _, err := NewFromContinuedFraction([]int{1, 0}); err == nil {
+		t.Fatalf("NewFromContinuedFraction([1; 0]) should return error")
+	}
+	if _, err := NewFromContinuedFraction([]int{1, math.MaxInt32, math.MaxInt32, math.MaxInt32}); err == nil {
+		t.Fatalf("NewFromContinuedFraction with overflow should return error")
+	}
+}
+
+func TestApproximate(t *testing.T) {
+	tests := []struct {
+		value          float64
+		maxDenominator int
+		want           string
+	}{
+		{0.3333333333, 100, "1/3"},
+		{math.Pi, 10, "22/7"},
+		{math.Pi, 1000, "355/113"},
+		{-math.Pi, 100, "-311/99"},
+		{0.5, 1, "0/1"},    // tie: 0/1 and 1/1 are equally close, the convergent wins
+		{0.75, 100, "3/4"}, // exact
+		{2.0, 5, "2/1"},
+		{1e-9, 1000, "0/1"},
+	}
+	for _, test := range tests {
+		got := Approximate(test.value, test.maxDenominator)
+		if got.AsIntegerRatio() != test.want {
+			t.Fatalf("Approximate(%v, %d) = %s; want %s", test.value, test.maxDenominator, got.AsIntegerRatio(), test.want)
+		}
+	}
+	if Approximate(math.NaN(), 10) != nil || Approximate(1, 0) != nil || Approximate(1e300, 10) != nil {
+		t.Fatalf("Approximate with invalid input should return nil")
+	}
+}
+
+func TestFloat64Exact(t *testing.T) {
+	tests := []struct {
+		f     *Fraction
+		want  float64
+		exact bool
+	}{
+		{MustNew(1, 4), 0.25, true},
+		{MustNew(-3, 8), -0.375, true},
+		{MustNew(1, 3), 1.0 / 3, false},
+		{MustNew(1, 10), 0.1, false},
+		{MustNew(1<<53+1, 1), 1 << 53, false},
+		{MustNew(0, 7), 0, true},
+	}
+	for _, test := range tests {
+		got, exact := test.f.Float64Exact()
+		if got != test.want || exact != test.exact {
+			t.Fatalf("%s.Float64Exact() = %v, %v; want %v, %v", test.f.AsIntegerRatio(), got, exact, test.want, test.exact)
+		}
+	}
+	if got, exact := MustNewBig(3, 4).Float64Exact(); got != 0.75 || !exact {
+		t.Fatalf("Big(3/4).Float64Exact() = %v, %v; want 0.75, true", got, exact)
+	}
+	var nilFraction *Fraction
+	if got, exact := nilFraction.Float64Exact(); !math.IsNaN(got) || exact {
+		t.Fatalf("nil.Float64Exact() = %v, %v; want NaN, false", got, exact)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	var nilFraction *Fraction
+	tests := []struct {
+		format string
+		f      *Fraction
+		want   string
+	}{
+		{"%v", MustNew(-3, 2), "-1 1/2"},
+		{"%s", MustNew(3, 4), "3/4"},
+		{"%#v", MustNew(-3, 2), "-3/2"},
+		{"%d", MustNew(6, 4), "6/4"},
+		{"%+d", MustNew(3, 4), "+3/4"},
+		{"%f", MustNew(2, 3), "0.666667"},
+		{"%.3f", MustNew(-2, 3), "-0.667"},
+		{"%.0f", MustNew(5, 2), "3"},
+		{"%.20f", MustNew(1, 3), "0.33333333333333333333"},
+		{"%e", MustNew(1, 8), "1.250000e-01"},
+		{"%g", MustNew(1, 8), "0.125"},
+		{"%q", MustNew(7, 3), `"2 1/3"`},
+		{"[%8s]", MustNew(7, 3), "[   2 1/3]"},
+		{"[%-8s]", MustNew(7, 3), "[2 1/3   ]"},
+		{"[%08.2f]", MustNew(-1, 4), "[-0000.25]"},
+		{"[%6v]", MustNew(1, 2), "[   1/2]"},
+		{"%v", nilFraction, "NaN"},
+		{"%x", MustNew(1, 2), "%!x(fraction=1/2)"},
+	}
+	for _, test := range tests {
+		if got := fmt.Sprintf(test.format, test.f); got != test.want {
+			t.Fatalf("Sprintf(%q, %s) = %q; want %q", test.format, test.f.AsIntegerRatio(), got, test.want)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		f         *Fraction
+		maxDigits int
+		want      string
+	}{
+		{MustNew(1, 3), 10, "0.(3)"},
+		{MustNew(22, 7), 10, "3.(142857)"},
+		{MustNew(1, 6), 10, "0.1(6)"},
+		{MustNew(-3, 4), 10, "-0.75"},
+		{MustNew(5, 1), 10, "5"},
+		{MustNew(0, 3), 10, "0"},
+		{MustNew(1, 17), 20, "0.(0588235294117647)"},
+		{MustNew(1, 17), 5, "0.05882..."},
+		{MustNew(1, 8), 2, "0.12..."},
+		{MustNew(7, 3), 0, "2..."},
+		{MustNew(1, math.MaxInt), 3, "0.000..."},
+	}
+	for _, test := range tests {
+		if got := test.f.Decimal(test.maxDigits); got != test.want {
+			t.Fatalf("%s.Decimal(%d) = %q; want %q", test.f.AsIntegerRatio(), test.maxDigits, got, test.want)
+		}
+	}
+}
+
+func TestDivisionAndRounding(t *testing.T) {
+	tests := []struct {
+		f                  *Fraction
+		floor, ceil, trunc int
+	}{
+		{MustNew(7, 2), 3, 4, 3},
+		{MustNew(-7, 2), -4, -3, -3},
+		{MustNew(6, 3), 2, 2, 2},
+		{MustNew(-1, 3), -1, 0, 0},
+	}
+	for _, test := range tests {
+		floor, _ := test.f.Floor()
+		ceil, _ := test.f.Ceil()
+		trunc, _ := test.f.Trunc()
+		if floor != test.floor || ceil != test.ceil || trunc != test.trunc {
+			t.Fatalf("%s: Floor, Ceil, Trunc = %d, %d, %d; want %d, %d, %d", test.f.AsIntegerRatio(), floor, ceil, trunc, test.floor, test.ceil, test.trunc)
+		}
+	}
+	divMod := []struct {
+		f, other  *Fraction
+		quotient  int
+		remainder string
+	}{
+		{MustNew(7, 2), MustNew(1, 1), 3, "1/2"},
+		{MustNew(-7, 2), MustNew(1, 1), -4, "1/2"},
+		{MustNew(7, 2), MustNew(-1, 1), -3, "1/2"},
+		{MustNew(-7, 2), MustNew(-1, 1), 4, "1/2"},
+		{MustNew(5, 6), MustNew(1, 4), 3, "1/12"},
+		{MustNew(3, 4), MustNew(3, 8), 2, "0/1"},
+	}
+	for _, test := range divMod {
+		q, r, err := test.f.DivMod(test.other)
+		if err != nil || q != test.quotient || r.AsIntegerRatio() != test.remainder {
+			t.Fatalf("%s.DivMod(%s) = %d, %v, %v; want %d, %s", test.f.AsIntegerRatio(), test.other.AsIntegerRatio(), q, r, err, test.quotient, test.remainder)
+		}
+	}
+	if f, err := MustNew(-7, 2).Mod(MustNew(1, 1)); err != nil || f.AsIntegerRatio() != "1/2" {
+		t.Fatalf("-7/2 mod 1 = %v, %v; want 1/2", f, err)
+	}
+	if _, err := MustNew(1, 2).Mod(MustNew(0, 1)); err == nil {
+		t.Fatalf("Mod(0) should return error")
+	}
+	rounding := []struct {
+		f             *Fraction
+		decimalPlaces uint
+		want          string
+	}{
+		{MustNew(2, 3), 2, "67/100"},
+		{MustNew(-2, 3), 2, "-67/100"},
+		{MustNew(5, 2), 0, "3/1"},
+		{MustNew(-5, 2), 0, "-3/1"},
+		{MustNew(1, 8), 2, "13/100"},
+		{MustNew(1, 4), 3, "1/4"},
+		{MustNew(-1, 1000), 2, "0/1"},
+	}
+	for _, test := range rounding {
+		ratio := test.f.AsIntegerRatio()
+		if got := test.f.Round(test.decimalPlaces).AsIntegerRatio(); got != test.want {
+			t.Fatalf("%s.Round(%d) = %s; want %s", ratio, test.decimalPlaces, got, test.want)
+		}
+	}
+}
+
+func TestMediantAndFarey(t *testing.T) {
+	if got := MustNew(1, 3).Mediant(MustNew(1, 2)).AsIntegerRatio(); got != "2/5" {
+		t.Fatalf("Mediant(1/3, 1/2) = %s; want 2/5", got)
+	}
+	if got := MustNew(-1, 2).Mediant(MustNew(1, 3)).AsIntegerRatio(); got != "0/5" {
+		t.Fatalf("Mediant(-1/2, 1/3) = %s; want 0/5", got)
+	}
+	var ratios []string
+	for _, f := range Farey(5) {
+		ratios = append(ratios, f.AsIntegerRatio())
+	}
+	want := []string{"0/1", "1/5", "1/4", "1/3", "2/5", "1/2", "3/5", "2/3", "3/4", "4/5", "1/1"}
+	if !slices.Equal(ratios, want) {
+		t.Fatalf("Farey(5) = %v; want %v", ratios, want)
+	}
+	// |F(n)| = 1 + Σ φ(k) for k = 1..n: 33 for n = 10.
+	if got := len(Farey(10)); got != 33 {
+		t.Fatalf("len(Farey(10)) = %d; want 33", got)
+	}
+	if len(Farey(1)) != 2 || Farey(0) != nil {
+		t.Fatalf("Farey(1) = %v, Farey(0) = %v; want [0 1], nil", Farey(1), Farey(0))
+	}
+}
+
+func TestPowIntAndPowFraction(t *testing.T) {
+	if f, err := MustNew(2, 3).PowInt(-2); err != nil || f.AsIntegerRatio() != "9/4" {
+		t.Fatalf("(2/3)^-2 = %v, %v; want 9/4", f, err)
+	}
+	if f, err := MustNew(-2, 3).PowInt(3); err != nil || f.AsIntegerRatio() != "-8/27" {
+		t.Fatalf("(-2/3)^3 = %v, %v; want -8/27", f, err)
+	}
+	if _, err := MustNew(0, 1).PowInt(-1); err == nil {
+		t.Fatalf("0^-1 should return error")
+	}
+	// -math.MinInt doesn't fit in an int, but the exponent is still even.
+	if f, err := MustNew(-1, 1).PowInt(math.MinInt); err != nil || f.AsIntegerRatio() != "1/1" {
+		t.Fatalf("(-1)^MinInt = %v, %v; want 1/1", f, err)
+	}
+	if f, err := MustNew(-1, 1).PowInt(math.MinInt + 1); err != nil || f.AsIntegerRatio() != "-1/1" {
+		t.Fatalf("(-1)^(MinInt+1) = %v, %v; want -1/1", f, err)
+	}
+	if _, err := MustNew(0, 1).PowInt(math.MinInt); !errors.Is(err, ErrDivisionByZero) {
+		t.Fatalf("0^MinInt error = %v; want %v", err, ErrDivisionByZero)
+	}
+	tests := []struct {
+		base, exponent *Fraction
+		want           string
+	}{
+		{MustNew(4, 9), MustNew(3, 2), "8/27"},
+		{MustNew(8, 27), MustNew(-2, 3), "9/4"},
+		{MustNew(-8, 27), MustNew(1, 3), "-2/3"},
+		{MustNew(2, 8), MustNew(2, 4), "1/2"},
+		{MustNew(5, 7), MustNew(0, 1), "1/1"},
+	}
+	for _, test := range tests {
+		f, err := test.base.Clone().PowFraction(test.exponent)
+		if err != nil || f.AsIntegerRatio() != test.want {
+			t.Fatalf("(%s)^(%s) = %v, %v; want %s", test.base.AsIntegerRatio(), test.exponent.AsIntegerRatio(), f, err, test.want)
+		}
+	}
+	two := MustNew(2, 1)
+	if _, err := two.PowFraction(MustNew(1, 2)); err == nil || two.AsIntegerRatio() != "2/1" {
+		t.Fatalf("2^(1/2) should return error and leave 2 unchanged, got %s, %v", two.AsIntegerRatio(), err)
+	}
+	if _, err := MustNew(-4, 1).PowFraction(MustNew(1, 2)); err == nil {
+		t.Fatalf("(-4)^(1/2) should return error")
+	}
+}
+
+func TestAggregates(t *testing.T) {
+	fs := []*Fraction{MustNew(1, 2), MustNew(-1, 3), MustNew(5, 6), MustNew(2, 4)}
+	check := func(name string, f *Fraction, err error, want string) {
+		t.Helper()
+		if err != nil || f.AsIntegerRatio() != want {
+			t.Fatalf("%s = %v, %v; want %s", name, f, err, want)
+		}
+	}
+	sum, err := Sum(fs...)
+	check("Sum", sum, err, "3/2")
+	product, err := Product(fs...)
+	check("Product", product, err, "-5/72")
+	mean, err := Mean(fs...)
+	check("Mean", mean, err, "3/8")
+	mean, err = Mean(MustNew(1, 1), MustNew(1, 1))
+	check("Mean(1, 1)", mean, err, "1/1")
+	least, err := Min(fs...)
+	check("Min", least, err, "-1/3")
+	greatest, err := Max(fs...)
+	check("Max", greatest, err, "5/6")
+	// The results are new Fractions and the inputs are unchanged.
+	greatest.AddInt(1)
+	if fs[2].AsIntegerRatio() != "5/6" || fs[3].AsIntegerRatio() != "2/4" || sum.IsAutoSimplify() {
+		t.Fatalf("aggregates modified their inputs: %v", fs)
+	}
+	empty, err := Sum()
+	check("Sum()", empty, err, "0/1")
+	empty, err = Product()
+	check("Product()", empty, err, "1/1")
+	// The harmonic sum up to 1/40 only fits in an int because the
+	// intermediate sums are simplified.
+	var harmonic []*Fraction
+	for k := 1; k <= 40; k++ {
+		harmonic = append(harmonic, MustNew(1, k))
+	}
+	if h, err := Sum(harmonic...); err != nil || !almostEqual(h.Evaluate(), 4.278543038936377) {
+		t.Fatalf("Sum(1/1, ..., 1/40) = %v, %v", h, err)
+	}
+	if _, err := Mean(); err == nil {
+		t.Fatalf("Mean() should return error")
+	}
+	if _, err := Max(); err == nil {
+		t.Fatalf("Max() should return error")
+	}
+	if _, err := Sum(MustNew(1, 2), nil); err == nil {
+		t.Fatalf("Sum with nil should return error")
+	}
+}
+
+func TestCommonDenominator(t *testing.T) {
+	common, fs, err := CommonDenominator(MustNew(1, 4), MustNew(-5, 6), MustNew(4, 6), MustNew(0, 9))
+	if err != nil || common != 12 {
+		t.Fatalf("CommonDenominator = %d, %v; want 12", common, err)
+	}
+	var ratios []string
+	for _, f := range fs {
+		ratios = append(ratios, f.AsIntegerRatio())
+	}
+	if want := []string{"3/12", "-10/12", "8/12", "0/12"}; !slices.Equal(ratios, want) {
+		t.Fatalf("CommonDenominator fractions = %v; want %v", ratios, want)
+	}
+	if common, fs, err := CommonDenominator(); err != nil || common != 1 || len(fs) != 0 {
+		t.Fatalf("CommonDenominator() = %d, %v, %v; want 1, [], nil", common, fs, err)
+	}
+	if _, _, err := CommonDenominator(MustNew(1, math.MaxInt32), MustNew(1, math.MaxInt32-1), MustNew(1, math.MaxInt32-2)); err == nil {
+		t.Fatalf("CommonDenominator with overflow should return error")
+	}
+	if _, _, err := CommonDenominator(nil); err == nil {
+		t.Fatalf("CommonDenominator(nil) should return error")
+	}
+}
+
+func TestKey(t *testing.T) {
+	counts := map[Key]int{}
+	for _, f := range []*Fraction{MustNew(1, 2), MustNew(2, 4), MustNew(-3, -6), MustNew(-1, 2), MustNew(0, 5), MustNew(0, -2)} {
+		counts[f.Key()]++
+	}
+	if len(counts) != 3 || counts[Key{1, 2}] != 3 || counts[Key{-1, 2}] != 1 || counts[Key{0, 1}] != 2 {
+		t.Fatalf("counts = %v; want map[-1/2:1 0/1:2 1/2:3]", counts)
+	}
+	if got := MustNew(6, -8).Key(); got.String() != "-3/4" || got.Fraction().AsIntegerRatio() != "-3/4" {
+		t.Fatalf("Key(6/-8) = %v; want -3/4", got)
+	}
+	var nilFraction *Fraction
+	if nilFraction.Key() != (Key{}) || (Key{}).Fraction() != nil {
+		t.Fatalf("nil Fraction should have the zero Key")
+	}
+}
+
+func TestRat(t *testing.T) {
+	f, _ := New(-6, 8)
+	if got := f.Rat(); got.Cmp(big.NewRat(-3, 4)) != 0 {
+		t.Fatalf("Rat() = %v; want -3/4", got)
+	}
+	var nilFraction *Fraction
+	if got := nilFraction.Rat(); got != nil {
+		t.Fatalf("Rat() of nil = %v; want nil", got)
+	}
+	g, err := NewFromRat(big.NewRat(10, -4))
+	if err != nil || g.AsIntegerRatio() != "-5/2" {
+		t.Fatalf("NewFromRat(-5/2) = %v, %v; want -5/2", g, err)
+	}
+	huge := new(big.Rat).SetFrac(new(big.Int).Lsh(big.NewInt(1), 70), big.NewInt(3))
+	if _, err := NewFromRat(huge); err == nil {
+		t.Fatalf("NewFromRat(2^70/3) should return error")
+	}
+	if _, err := NewFromRat(nil); err == nil {
+		t.Fatalf("NewFromRat(nil) should return error")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var nilFraction *Fraction
+	var nilBig *Big
+	two, _ := New(2, 1)
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"New(1, 0)", func() error { _, err := New(1, 0); return err }(), ErrDivisionByZero},
+		{"NewFromString(1/0)", func() error { _, err := NewFromString("1/0"); return err }(), ErrDivisionByZero},
+		{"NewFromString(1.5/0)", func() error { _, err := NewFromString("1.5/0"); return err }(), ErrDivisionByZero},
+		{"DivideInt(0)", func() error { _, err := two.Clone().DivideInt(0); return err }(), ErrDivisionByZero},
+		{"NewFromString(abc)", func() error { _, err := NewFromString("abc"); return err }(), ErrInvalidFormat},
+		{"NewFromString(overflow)", func() error { _, err := NewFromString("99999999999999999999/1"); return err }(), ErrInvalidFormat},
+		{"UnmarshalJSON(true)", nilFraction.UnmarshalJSON([]byte("true")), ErrNilFraction},
+		{"NthRoot(2/1, 2)", func() error { _, err := two.Clone().NthRoot(2); return err }(), ErrNoExactRoot},
+		{"Big.NthRoot(0)", func() error { _, err := NewBigFromFraction(two).NthRoot(0); return err }(), ErrNoExactRoot},
+		{"nil.Reciprocal()", func() error { _, err := nilFraction.Reciprocal(); return err }(), ErrNilFraction},
+		{"nil Big.Divide", func() error { _, err := nilBig.Divide(nilBig); return err }(), ErrNilFraction},
+		{"Sum(nil)", func() error { _, err := Sum(two, nil); return err }(), ErrNilFraction},
+		{"NewBigFromInts(nil, 1)", func() error { _, err := NewBigFromInts(nil, big.NewInt(1)); return err }(), ErrNilFraction},
+		{"NewFromRat(nil)", func() error { _, err := NewFromRat(nil); return err }(), ErrNilFraction},
+		{"NewFromString(mixed overflow)", func() error { _, err := NewFromString("9223372036854775807 1/2"); return err }(), ErrOverflow},
+		{"DivMod(quotient overflow)", func() error { _, _, err := MustNew(math.MaxInt, 1).DivMod(MustNew(1, 2)); return err }(), ErrOverflow},
+		{"NewFromContinuedFraction(overflow)", func() error { _, err := NewFromContinuedFraction([]int{math.MaxInt, 2}); return err }(), ErrOverflow},
+		{"CommonDenominator(overflow)", func() error {
+			_, _, err := CommonDenominator(MustNew(1, math.MaxInt), MustNew(1, math.MaxInt-1))
+			return err
+		}(), ErrOverflow},
+		{"Big.Fraction(overflow)", func() error { _, err := MustNewBig(math.MaxInt, 1).MultiplyInt(2).Fraction(); return err }(), ErrOverflow},
+	}
+	for _, test := range tests {
+		if !errors.Is(test.err, test.want) {
+			t.Fatalf("%s error = %v; want %v", test.name, test.err, test.want)
+		}
+	}
+	var object Fraction
+	if err := object.UnmarshalJSON([]byte(`{"num":1}`)); !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("UnmarshalJSON({num:1}) error = %v; want %v", err, ErrInvalidFormat)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	a, _ := New(1, 2)
+	b, _ := New(1, 3)
+	// ((1/2 + 1/3) / (1/3))^-2 = (5/2)^-2 = 4/25
+	result, err := NewBuilder(a).Add(b).Divide(b).Simplify().PowInt(-2).Result()
+	if err != nil || result.AsIntegerRatio() != "4/25" {
+		t.Fatalf("Builder result = %v, %v; want 4/25", result, err)
+	}
+	if a.AsIntegerRatio() != "1/2" {
+		t.Fatalf("Builder modified its input: %v", a)
+	}
+	zero, _ := New(0, 1)
+	builder := NewBuilder(a).Divide(zero).AddInt(1).NthRoot(2)
+	if _, err := builder.Result(); !errors.Is(err, ErrDivisionByZero) {
+		t.Fatalf("Divide(0) error = %v; want %v", err, ErrDivisionByZero)
+	}
+	if _, err := NewBuilder(a).NthRoot(2).Divide(zero).Result(); !errors.Is(err, ErrNoExactRoot) {
+		t.Fatalf("NthRoot(2) error = %v; want the first error %v", err, ErrNoExactRoot)
+	}
+	if err := NewBuilder(nil).AddInt(1).Err(); !errors.Is(err, ErrNilFraction) {
+		t.Fatalf("NewBuilder(nil) error = %v; want %v", err, ErrNilFraction)
+	}
+	if err := NewBuilder(a).Multiply(nil).Err(); !errors.Is(err, ErrNilFraction) {
+		t.Fatalf("Multiply(nil) error = %v; want %v", err, ErrNilFraction)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	a := rand.New(rand.NewPCG(1, 2))
+	b := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 100; i++ {
+		f, g := Random(a, 5, 4), Random(b, 5, 4)
+		if !f.Equals(g) {
+			t.Fatalf("Random with equal seeds = %v and %v", f, g)
+		}
+		numerator, _ := f.Numerator()
+		denominator, _ := f.Denominator()
+		if numerator < -5 || numerator > 5 || denominator < 1 || denominator > 4 {
+			t.Fatalf("Random(5, 4) = %v; out of range", f)
+		}
+		s := RandomSimplified(a, 50, 50)
+		RandomSimplified(b, 50, 50)
+		if simplified := s.Clone().Simplify(); s.AsIntegerRatio() != simplified.AsIntegerRatio() {
+			t.Fatalf("RandomSimplified = %v; want lowest terms", s)
+		}
+	}
+	if f := Random(a, -1, 4); f != nil {
+		t.Fatalf("Random(-1, 4) = %v; want nil", f)
+	}
+	if f := RandomSimplified(a, 1, 0); f != nil {
+		t.Fatalf("RandomSimplified(1, 0) = %v; want nil", f)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		numerator, denominator int
+		percent, perMille      float64
+	}{
+		{1, 8, 12.5, 125},
+		{7, 100, 7, 70},
+		{-3, 2, -150, -1500},
+		{0, 5, 0, 0},
+	}
+	for _, test := range tests {
+		f, _ := New(test.numerator, test.denominator)
+		if got := f.Percent(); got != test.percent {
+			t.Fatalf("Percent(%v) = %v; want %v", f, got, test.percent)
+		}
+		if got := f.PerMille(); got != test.perMille {
+			t.Fatalf("PerMille(%v) = %v; want %v", f, got, test.perMille)
+		}
+	}
+	var nilFraction *Fraction
+	if got := nilFraction.Percent(); !math.IsNaN(got) {
+		t.Fatalf("Percent() of nil = %v; want NaN", got)
+	}
+	conversions := []struct {
+		percent        float64
+		maxDenominator int
+		want           string
+	}{
+		{12.5, 100, "1/8"},
+		{7, 100, "7/100"},
+		{33.33, 10, "1/3"},
+		{-250, 2, "-5/2"},
+		{0.1, 1000, "1/1000"},
+	}
+	for _, test := range conversions {
+		f, err := NewFromPercent(test.percent, test.maxDenominator)
+		if err != nil || f.AsIntegerRatio() != test.want {
+			t.Fatalf("NewFromPercent(%v, %v) = %v, %v; want %v", test.percent, test.maxDenominator, f, err, test.want)
+		}
+	}
+	if _, err := NewFromPercent(math.NaN(), 10); err == nil {
+		t.Fatalf("NewFromPercent(NaN) should return error")
+	}
+	if _, err := NewFromPercent(50, 0); err == nil {
+		t.Fatalf("NewFromPercent(50, 0) should return error")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b *Fraction
+		want int
+	}{
+		{MustNew(1, 2), MustNew(2, 4), 0},
+		{MustNew(1, 3), MustNew(1, 2), -1},
+		{MustNew(-1, 2), MustNew(-1, 3), -1},
+		{MustNew(-1, 2), MustNew(1, 3), -1},
+		{MustNew(0, 5), MustNew(0, -3), 0},
+		{MustNew(0, 1), MustNew(-1, 100), 1},
+		// The cross products overflow int64 but the comparison stays exact.
+		{MustNew(math.MaxInt64, math.MaxInt64-1), MustNew(math.MaxInt64-1, math.MaxInt64-2), -1},
+		{nil, MustNew(-5, 1), -1},
+		{nil, nil, 0},
+	}
+	for _, test := range tests {
+		if got := test.a.Compare(test.b); got != test.want {
+			t.Fatalf("%v.Compare(%v) = %d; want %d", test.a, test.b, got, test.want)
+		}
+		if got := test.b.Compare(test.a); got != -test.want {
+			t.Fatalf("%v.Compare(%v) = %d; want %d", test.b, test.a, got, -test.want)
+		}
+	}
+	if !MustNew(3, 6).Equals(MustNew(-1, -2)) || MustNew(1, 2).Equals(nil) {
+		t.Fatalf("Equals returned a wrong result")
+	}
+	if !MustNew(1, 3).LessThan(MustNew(1, 2)) || MustNew(1, 2).LessThan(MustNew(1, 2)) {
+		t.Fatalf("LessThan returned a wrong result")
+	}
+	if !MustNew(1, 2).GreaterThan(MustNew(-1, 2)) || MustNew(-1, 2).GreaterThan(nil) {
+		t.Fatalf("GreaterThan returned a wrong result")
+	}
+}
+
+func TestBig(t *testing.T) {
+	// 3^50 / 2^70 overflows int, but not Big.
+	b := MustNewBig(3, 2).Pow(50).Multiply(MustNewBig(1, 1<<20))
+	if got := b.AsIntegerRatio(); got != "717897987691852588770249/1180591620717411303424" {
+		t.Fatalf("(3/2)^50 / 2^20 = %s", got)
+	}
+	if got := b.Evaluate(); !almostEqual(got/math.Pow(1.5, 50)*(1<<20), 1) {
+		t.Fatalf("Evaluate() = %v", got)
+	}
+	if _, err := b.Fraction(); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("Fraction() of a huge value error = %v; want %v", err, ErrOverflow)
+	}
+	// Reciprocal copies the values instead of swapping the big.Int structs.
+	r := MustNewBig(-2, 3)
+	if _, err := r.Reciprocal(); err != nil || r.AsIntegerRatio() != "-3/2" {
+		t.Fatalf("Reciprocal(-2/3) = %v, %v; want -3/2", r, err)
+	}
+	if got := r.MultiplyInt(5).Add(MustNewBig(1, 2)).Simplify().AsIntegerRatio(); got != "-7/1" {
+		t.Fatalf("-3/2 · 5 + 1/2 = %s; want -7/1", got)
+	}
+	// The zero value is 0.
+	var zero Big
+	if zero.String() != "0" || zero.AsIntegerRatio() != "0/1" || zero.Evaluate() != 0 {
+		t.Fatalf("zero value = %s (%s, %v); want 0", zero.String(), zero.AsIntegerRatio(), zero.Evaluate())
+	}
+	if !zero.Equals(MustNewBig(0, 5)) || !(&Big{}).LessThan(MustNewBig(1, 3)) {
+		t.Fatalf("zero value should equal 0 and be less than 1/3")
+	}
+	if got := new(Big).Add(MustNewBig(1, 3)).AsIntegerRatio(); got != "1/3" {
+		t.Fatalf("zero + 1/3 = %s; want 1/3", got)
+	}
+	if got := new(Big).AddInt(2).Simplify().String(); got != "2" {
+		t.Fatalf("zero + 2 = %s; want 2", got)
+	}
+	if f, err := new(Big).Fraction(); err != nil || f.AsIntegerRatio() != "0/1" {
+		t.Fatalf("zero.Fraction() = %v, %v; want 0/1", f, err)
+	}
+	// 1/2 - 1/3 + 1/6 = 1/3; b.Add(b) doubles.
+	sum := MustNewBig(1, 2).Subtract(MustNewBig(1, 3)).Add(MustNewBig(1, 6)).Simplify()
+	if got := sum.String(); got != "1/3" {
+		t.Fatalf("1/2 - 1/3 + 1/6 = %s; want 1/3", got)
+	}
+	if got := sum.Add(sum).Simplify().AsIntegerRatio(); got != "2/3" {
+		t.Fatalf("b.Add(b) = %s; want 2/3", got)
+	}
+	if got := MustNewBig(-7, 2).String(); got != "-3 1/2" {
+		t.Fatalf("String() = %s; want -3 1/2", got)
+	}
+	q, err := MustNewBig(1, 2).Divide(MustNewBig(-3, 4))
+	if err != nil || q.Simplify().AsIntegerRatio() != "-2/3" {
+		t.Fatalf("1/2 / -3/4 = %v, %v; want -2/3", q, err)
+	}
+	if _, err := MustNewBig(1, 2).Divide(MustNewBig(0, 1)); err == nil {
+		t.Fatalf("Divide by zero should return error")
+	}
+	if _, err := MustNewBig(1, 2).DivideInt(0); err == nil {
+		t.Fatalf("DivideInt(0) should return error")
+	}
+	root, err := MustNewBig(-8, 27).Pow(7).NthRoot(21)
+	if err != nil || root.AsIntegerRatio() != "-2/3" {
+		t.Fatalf("NthRoot(21) = %v, %v; want -2/3", root, err)
+	}
+	if _, err := MustNewBig(2, 1).NthRoot(2); err == nil {
+		t.Fatalf("NthRoot of 2 should return error")
+	}
+	f, err := MustNewBig(6, -4).MultiplyInt(2).AddInt(1).SubtractInt(1).Simplify().Fraction()
+	if err != nil || f.AsIntegerRatio() != "-3/1" {
+		t.Fatalf("Fraction() = %v, %v; want -3/1", f, err)
+	}
+	if !NewBigFromFraction(MustNew(-1, 2)).Equals(MustNewBig(2, -4)) {
+		t.Fatalf("NewBigFromFraction(-1/2) should equal 2/-4")
+	}
+	if !MustNewBig(1, 3).LessThan(MustNewBig(1, 2)) || !MustNewBig(1, 2).GreaterThan(MustNewBig(-1, 2)) {
+		t.Fatalf("LessThan/GreaterThan returned a wrong result")
+	}
+	if _, err := NewBig(1, 0); err == nil {
+		t.Fatalf("NewBig(1, 0) should return error")
+	}
+}
+
+// TestBigParity fails when a method is added to Fraction without deciding
+// whether Big needs it too: every Fraction method must exist on Big or be
+// listed as Fraction-only.
+func TestBigParity(t *testing.T) {
+	fractionOnly := map[string]bool{
+		"AutoSimplify": true, "IsAutoSimplify": true, "Floor": true, "Ceil": true,
+		"Trunc": true, "Round": true, "DivMod": true, "Mod": true, "PowInt": true,
+		"PowFraction": true, "MustDivideInt": true, "MustNthRoot": true,
+		"MustReciprocal": true, "Decimal": true, "Format": true, "Percent": true,
+		"PerMille": true, "Mediant": true, "ContinuedFraction": true, "Key": true,
+		"Rat": true, "MarshalJSON": true, "UnmarshalJSON": true,
+	}
+	fractionType, bigType := reflect.TypeOf(&Fraction{}), reflect.TypeOf(&Big{})
+	for i := 0; i < fractionType.NumMethod(); i++ {
+		name := fractionType.Method(i).Name
+		_, onBig := bigType.MethodByName(name)
+		if onBig == fractionOnly[name] {
+			t.Fatalf("Fraction.%s: on Big = %v, listed as Fraction-only = %v", name, onBig, fractionOnly[name])
+		}
+	}
+}
+
+func TestFractions(t *testing.T) {
+	a, err := NewFractionsFrom(MustNew(1, 2), MustNew(-1, 3), MustNew(3, 4))
+	if err != nil {
+		t.Fatalf("NewFractionsFrom error: %v", err)
+	}
+	b := NewFractions(0)
+	for _, pair := range [][2]int{{1, 2}, {1, 6}, {-3, 4}} {
+		if err := b.Append(pair[0], pair[1]); err != nil {
+			t.Fatalf("Append(%v) error: %v", pair, err)
+		}
+	}
+	if err := a.AddAll(b); err != nil {
+		t.Fatalf("AddAll error: %v", err)
+	}
+	a.SimplifyAll()
+	for i, want := range []string{"1/1", "-1/6", "0/1"} {
+		if got := a.At(i).AsIntegerRatio(); got != want {
+			t.Fatalf("AddAll: At(%d) = %s; want %s", i, got, want)
+		}
+	}
+	if err := a.MulAll(b); err != nil {
+		t.Fatalf("MulAll error: %v", err)
+	}
+	a.SimplifyAll()
+	for i, want := range []string{"1/2", "-1/36", "0/1"} {
+		if got := a.At(i).AsIntegerRatio(); got != want {
+			t.Fatalf("MulAll: At(%d) = %s; want %s", i, got, want)
+		}
+	}
+	values := b.EvaluateAll()
+	if len(values) != 3 || values[0] != 0.5 || values[2] != -0.75 {
+		t.Fatalf("EvaluateAll() = %v; want [0.5 0.1666... -0.75]", values)
+	}
+	if err := b.Set(1, MustNew(-2, 5)); err != nil || b.At(1).AsIntegerRatio() != "-2/5" {
+		t.Fatalf("Set(1, -2/5) = %v, %v", b.At(1), err)
+	}
+	if b.Len() != 3 || b.At(3) != nil {
+		t.Fatalf("Len() = %d, At(3) = %v; want 3, nil", b.Len(), b.At(3))
+	}
+	if err := a.AddAll(NewFractions(2)); err == nil {
+		t.Fatalf("AddAll with different lengths should return error")
+	}
+	if err := b.Append(1, 0); err == nil {
+		t.Fatalf("Append(1, 0) should return error")
+	}
+	if _, err := NewFractionsFrom(MustNew(1, 2), nil); err == nil {
+		t.Fatalf("NewFractionsFrom with nil should return error")
+	}
+}
+
+func BenchmarkAddInt(b *testing.B) {
+	b.ReportAllocs()
+	f := MustNew(1, 3)
+	for i := 0; i < b.N; i++ {
+		f.AddInt(1).SubtractInt(1)
+	}
+}
+
+func BenchmarkNewHeap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sum := MustNew(0, 1)
+		for k := 1; k <= 20; k++ {
+			sum.Add(MustNew(1, k)).Simplify()
+		}
+	}
+}
+
+func BenchmarkNewArena(b *testing.B) {
+	b.ReportAllocs()
+	arena := NewArena(64)
+	for i := 0; i < b.N; i++ {
+		arena.Reset()
+		sum := arena.MustNew(0, 1)
+		for k := 1; k <= 20; k++ {
+			sum.Add(arena.MustNew(1, k)).Simplify()
+		}
+	}
+}
+
+func BenchmarkFractionsAddAll(b *testing.B) {
+	b.ReportAllocs()
+	x, y := NewFractions(0), NewFractions(0)
+	for k := 1; k <= 1000; k++ {
+		x.Append(1, k)
+		y.Append(-1, k+1)
+	}
+	for i := 0; i < b.N; i++ {
+		x.AddAll(y)
+		x.SimplifyAll()
+	}
+}
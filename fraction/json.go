@@ -0,0 +1,105 @@
+package fraction
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonObject is the object form of a Fraction.
+type jsonObject struct {
+	Num *int `json:"num"`
+	Den *int `json:"den"`
+}
+
+// JSONObject wraps a Fraction that is encoded as an object with a signed
+// numerator and a denominator, e.g. {"num":-3,"den":4}, instead of the integer
+// ratio string of Fraction.MarshalJSON. Use it as the type of a struct field
+// or wrap a single value with JSONObjectOf. Decoding accepts every format
+// Fraction.UnmarshalJSON accepts.
+type JSONObject struct {
+	*Fraction
+}
+
+// JSONObjectOf wraps f, so it is encoded as a JSON object.
+func JSONObjectOf(f *Fraction) JSONObject {
+	return JSONObject{Fraction: f}
+}
+
+// MarshalJSON implements the json.Marshaler interface. The Fraction is
+// encoded without simplification, so it round-trips losslessly. A nil
+// Fraction instance is encoded as null.
+func (o JSONObject) MarshalJSON() ([]byte, error) {
+	if o.Fraction == nil {
+		return []byte("null"), nil
+	}
+	numerator, denominator := o.sign*o.numerator, o.denominator
+	return json.Marshal(jsonObject{Num: &numerator, Den: &denominator})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface (see
+// Fraction.UnmarshalJSON). A new Fraction is allocated if the wrapped
+// Fraction instance is nil.
+func (o *JSONObject) UnmarshalJSON(data []byte) error {
+	if o.Fraction == nil {
+		o.Fraction = &Fraction{}
+	}
+	return o.Fraction.UnmarshalJSON(data)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The Fraction is
+// encoded as an integer ratio string, e.g. "-3/4", without simplification, so
+// it round-trips losslessly (use JSONObject for the object form). A nil
+// Fraction instance is encoded as null.
+func (f *Fraction) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.AsIntegerRatio())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts a
+// string in any form NewFromString accepts, an object {"num":n,"den":d} or a
+// plain JSON number. The Fraction instance is modified in-place (and
+// keeps its AutoSimplify setting); null leaves it unchanged. Returns an error
+// if the input is not a valid fraction.
+func (f *Fraction) UnmarshalJSON(data []byte) error {
+	if f == nil {
+		return ErrNilFraction
+	}
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	var result *Fraction
+	var err error
+	switch {
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		result, err = NewFromString(s)
+	case len(data) > 0 && data[0] == '{':
+		var object jsonObject
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		if object.Num == nil || object.Den == nil {
+			return fmt.Errorf(`%w: object must have "num" and "den" fields`, ErrInvalidFormat)
+		}
+		result, err = New(*object.Num, *object.Den)
+	default:
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidFormat, data)
+		}
+		result, err = NewFromString(number.String())
+	}
+	if err != nil {
+		return err
+	}
+	result.simplify = f.simplify
+	*f = *result.autoSimplify()
+	return nil
+}
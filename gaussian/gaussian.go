@@ -0,0 +1,202 @@
+// Package gaussian provides exact complex arithmetic on Gaussian rationals:
+// complex numbers a + b·i with rational real part a and imaginary part b.
+//
+// Where complex128 accumulates rounding errors, a Rational keeps every
+// intermediate result exact, e.g. for checking a DFT by hand or for algebra
+// in the field Q(i).
+//
+// Important details:
+//
+// (*) A Rational is an immutable value type: methods return a new Rational.
+// The parts are always simplified.
+//
+// (*) Like the rest of wbmath, the parts are Fractions with int numerators
+// and denominators, so long chains of operations can overflow silently.
+package gaussian
+
+import (
+	"fmt"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// Rational represents the complex number re + im·i.
+type Rational struct {
+	re, im *fraction.Fraction
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the Gaussian rational re + im·i.
+// Returns an error if re or im is nil.
+func New(re, im *fraction.Fraction) (Rational, error) {
+	if re == nil || im == nil {
+		return Rational{}, fraction.ErrNilFraction
+	}
+	return newRational(re.Clone(), im.Clone()), nil
+}
+
+// MustNew is a constructor identical to New but which panics if an error
+// occurs.
+func MustNew(re, im *fraction.Fraction) Rational {
+	z, err := New(re, im)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// NewFromFraction is a constructor function that returns the rational number
+// f as a Rational with a zero imaginary part. Returns an error if f is nil.
+func NewFromFraction(f *fraction.Fraction) (Rational, error) {
+	return New(f, fraction.MustNew(0, 1))
+}
+
+// ============================================================================
+// Accessors
+// ============================================================================
+
+// Real returns a copy of the real part.
+func (z Rational) Real() *fraction.Fraction { return z.re.Clone() }
+
+// Imag returns a copy of the imaginary part.
+func (z Rational) Imag() *fraction.Fraction { return z.im.Clone() }
+
+// IsReal checks if the imaginary part is zero.
+func (z Rational) IsReal() bool { return isZero(z.im) }
+
+// IsZero checks if z is zero.
+func (z Rational) IsZero() bool { return isZero(z.re) && isZero(z.im) }
+
+// Equals checks if two Gaussian rationals are equal.
+func (z Rational) Equals(other Rational) bool {
+	return equal(z.re, other.re) && equal(z.im, other.im)
+}
+
+// Complex128 returns the value as a complex128.
+func (z Rational) Complex128() complex128 {
+	return complex(z.re.Evaluate(), z.im.Evaluate())
+}
+
+// String implements the fmt.Stringer interface and returns the number with
+// both parts as integer ratios, e.g. "1/2 + 3/4 i", "2 - i", "-5/3 i" or
+// "7".
+func (z Rational) String() string {
+	if z.re == nil || z.im == nil {
+		return "NaN"
+	}
+	if z.IsReal() {
+		return ratio(z.re)
+	}
+	im := z.im.Clone()
+	sign := "+"
+	if numerator, _ := im.Numerator(); numerator < 0 {
+		sign = "-"
+		im.Negate()
+	}
+	imaginary := "i"
+	if text := ratio(im); text != "1" {
+		imaginary = text + " i"
+	}
+	switch {
+	case isZero(z.re) && sign == "-":
+		return "-" + imaginary
+	case isZero(z.re):
+		return imaginary
+	}
+	return fmt.Sprintf("%s %s %s", ratio(z.re), sign, imaginary)
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns z + other.
+func (z Rational) Add(other Rational) Rational {
+	return newRational(z.re.Clone().Add(other.re), z.im.Clone().Add(other.im))
+}
+
+// Subtract returns z - other.
+func (z Rational) Subtract(other Rational) Rational {
+	return z.Add(other.Negate())
+}
+
+// Multiply returns z · other, using (a + bi)(c + di) = (ac - bd) + (ad + bc)i.
+func (z Rational) Multiply(other Rational) Rational {
+	re := z.re.Clone().Multiply(other.re).Subtract(z.im.Clone().Multiply(other.im))
+	im := z.re.Clone().Multiply(other.im).Add(z.im.Clone().Multiply(other.re))
+	return newRational(re, im)
+}
+
+// Divide returns z / other. Returns an error if other is zero.
+func (z Rational) Divide(other Rational) (Rational, error) {
+	inverse, err := other.Inverse()
+	if err != nil {
+		return Rational{}, err
+	}
+	return z.Multiply(inverse), nil
+}
+
+// Scale returns f · z. Returns an error if f is nil.
+func (z Rational) Scale(f *fraction.Fraction) (Rational, error) {
+	if f == nil {
+		return Rational{}, fraction.ErrNilFraction
+	}
+	return newRational(z.re.Clone().Multiply(f), z.im.Clone().Multiply(f)), nil
+}
+
+// Negate returns -z.
+func (z Rational) Negate() Rational {
+	return newRational(z.re.Clone().Negate(), z.im.Clone().Negate())
+}
+
+// Conjugate returns the complex conjugate a - b·i.
+func (z Rational) Conjugate() Rational {
+	return newRational(z.re.Clone(), z.im.Clone().Negate())
+}
+
+// AbsSquared returns z · Conjugate() = a² + b², the square of the modulus,
+// which is always rational (unlike the modulus itself).
+func (z Rational) AbsSquared() *fraction.Fraction {
+	return z.re.Clone().Multiply(z.re).Add(z.im.Clone().Multiply(z.im)).Simplify()
+}
+
+// Inverse returns 1 / z = Conjugate() / AbsSquared(). Returns an error if z
+// is zero.
+func (z Rational) Inverse() (Rational, error) {
+	norm := z.AbsSquared()
+	if isZero(norm) {
+		return Rational{}, fraction.ErrDivisionByZero
+	}
+	return z.Conjugate().Scale(fraction.MustNew(1, 1).Divide(norm))
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+func newRational(re, im *fraction.Fraction) Rational {
+	return Rational{re: re.Simplify(), im: im.Simplify()}
+}
+
+// ratio formats a simplified fraction as "p/q", or "p" for integers.
+func ratio(f *fraction.Fraction) string {
+	numerator, _ := f.Numerator()
+	denominator, _ := f.Denominator()
+	if denominator == 1 {
+		return fmt.Sprint(numerator)
+	}
+	return fmt.Sprintf("%d/%d", numerator, denominator)
+}
+
+func isZero(f *fraction.Fraction) bool {
+	numerator, _ := f.Numerator()
+	return numerator == 0
+}
+
+// equal compares two simplified fractions.
+func equal(x, y *fraction.Fraction) bool {
+	return x.AsIntegerRatio() == y.AsIntegerRatio()
+}
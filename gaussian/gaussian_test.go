@@ -0,0 +1,70 @@
+package gaussian
+
+import (
+	"errors"
+	"math/cmplx"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func f(numerator, denominator int) *fraction.Fraction {
+	return fraction.MustNew(numerator, denominator)
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		z    Rational
+		want string
+	}{
+		{MustNew(f(1, 2), f(3, 4)), "1/2 + 3/4 i"},
+		{MustNew(f(2, 1), f(-1, 1)), "2 - i"},
+		{MustNew(f(0, 1), f(-10, 6)), "-5/3 i"},
+		{MustNew(f(0, 1), f(1, 1)), "i"},
+		{MustNew(f(14, 2), f(0, 1)), "7"},
+		{MustNew(f(-1, 3), f(0, 1)), "-1/3"},
+		{Rational{}, "NaN"},
+	}
+	for _, test := range tests {
+		if got := test.z.String(); got != test.want {
+			t.Fatalf("String() = %q; want %q", got, test.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	z := MustNew(f(1, 1), f(2, 1))  // 1 + 2i
+	w := MustNew(f(3, 1), f(-1, 2)) // 3 - 1/2 i
+	if got := z.Add(w).String(); got != "4 + 3/2 i" {
+		t.Fatalf("z + w = %v; want 4 + 3/2 i", got)
+	}
+	if got := z.Subtract(w).String(); got != "-2 + 5/2 i" {
+		t.Fatalf("z - w = %v; want -2 + 5/2 i", got)
+	}
+	if got := z.Multiply(w).String(); got != "4 + 11/2 i" {
+		t.Fatalf("z · w = %v; want 4 + 11/2 i", got)
+	}
+	if got := z.Multiply(z.Conjugate()); !got.Equals(MustNew(f(5, 1), f(0, 1))) || got.AbsSquared().AsIntegerRatio() != "25/1" {
+		t.Fatalf("z · conj(z) = %v; want 5", got)
+	}
+	quotient, err := z.Multiply(w).Divide(w)
+	if err != nil || !quotient.Equals(z) {
+		t.Fatalf("(z · w) / w = %v, %v; want %v", quotient, err, z)
+	}
+	inverse, _ := MustNew(f(0, 1), f(1, 1)).Inverse()
+	if inverse.String() != "-i" {
+		t.Fatalf("1 / i = %v; want -i", inverse)
+	}
+	if got, _ := z.Divide(w); cmplx.Abs(got.Complex128()-z.Complex128()/w.Complex128()) > 1e-12 {
+		t.Fatalf("z / w = %v; want %v", got.Complex128(), z.Complex128()/w.Complex128())
+	}
+	if _, err := z.Divide(Rational{re: f(0, 1), im: f(0, 1)}); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("Divide by zero error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := New(nil, f(1, 1)); !errors.Is(err, fraction.ErrNilFraction) {
+		t.Fatalf("New(nil, 1) error = %v; want %v", err, fraction.ErrNilFraction)
+	}
+	if _, err := z.Scale(nil); !errors.Is(err, fraction.ErrNilFraction) {
+		t.Fatalf("Scale(nil) error = %v; want %v", err, fraction.ErrNilFraction)
+	}
+}
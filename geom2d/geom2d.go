@@ -0,0 +1,409 @@
+// Package geom2d provides computational geometry in the plane: the convex
+// hull of a point set (Andrew's monotone chain), the area and centroid of a
+// polygon and the closest pair of points (divide and conquer).
+//
+// Every algorithm comes in two modes: a fast mode on float64 Points and an
+// exact mode on FracPoints, whose coordinates are Fractions. The exact mode
+// evaluates all predicates (e.g. "are these three points collinear?") without
+// rounding, so it is robust in degenerate configurations where floating-point
+// rounding gives inconsistent answers.
+//
+// Important details:
+//
+// (*) Point and FracPoint are small value types; FracPoint copies its
+// Fractions, so modifying the Fractions passed to NewFracPoint doesn't modify
+// the point.
+//
+// (*) Polygons are slices of vertices in order; the last vertex connects to
+// the first. Counterclockwise polygons have a positive signed area.
+//
+// (*) The exact mode computes with big.Rat internally, so intermediate values
+// never overflow. Functions that return a Fraction return an error if the
+// result doesn't fit in a Fraction.
+package geom2d
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// Point represents a point in the plane with float64 coordinates.
+type Point struct {
+	X, Y float64
+}
+
+// FracPoint represents a point in the plane with exact rational coordinates.
+type FracPoint struct {
+	x, y *fraction.Fraction
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// NewPoint is a constructor function that returns a Point with the specified
+// coordinates.
+func NewPoint(x, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+// NewFracPoint is a constructor function that returns a FracPoint with (a
+// copy of) the specified coordinates. Returns an error if a coordinate is
+// nil.
+func NewFracPoint(x, y *fraction.Fraction) (FracPoint, error) {
+	if x == nil || y == nil {
+		return FracPoint{}, fraction.ErrNilFraction
+	}
+	return FracPoint{x: x.Clone(), y: y.Clone()}, nil
+}
+
+// MustNewFracPoint is a constructor identical to NewFracPoint but which
+// panics if an error occurs.
+func MustNewFracPoint(x, y *fraction.Fraction) FracPoint {
+	p, err := NewFracPoint(x, y)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// X returns a copy of the x coordinate.
+func (p FracPoint) X() *fraction.Fraction {
+	return p.x.Clone()
+}
+
+// Y returns a copy of the y coordinate.
+func (p FracPoint) Y() *fraction.Fraction {
+	return p.y.Clone()
+}
+
+// Point returns the FracPoint rounded to a float64 Point.
+func (p FracPoint) Point() Point {
+	return Point{X: p.x.Evaluate(), Y: p.y.Evaluate()}
+}
+
+// String implements the fmt.Stringer interface, e.g. "(1/2, 3)".
+func (p FracPoint) String() string {
+	return fmt.Sprintf("(%s, %s)", p.x.AsIntegerRatio(), p.y.AsIntegerRatio())
+}
+
+// ============================================================================
+// Convex hull
+// ============================================================================
+
+// ConvexHull returns the vertices of the convex hull of the points in
+// counterclockwise order, starting with the lowest of the leftmost points.
+// Points on the edges of the hull (collinear points) and duplicates are not
+// included. Fewer than three distinct points give the distinct points.
+func ConvexHull(points []Point) []Point {
+	indices := hull(len(points), func(i, j int) int {
+		return comparePoints(points[i], points[j])
+	}, func(o, a, b int) int {
+		return floatSign(cross(points[o], points[a], points[b]))
+	})
+	return pick(points, indices)
+}
+
+// ConvexHullExact is the exact variant of ConvexHull.
+func ConvexHullExact(points []FracPoint) []FracPoint {
+	rats := toRats(points)
+	indices := hull(len(points), func(i, j int) int {
+		if c := rats[i].x.Cmp(rats[j].x); c != 0 {
+			return c
+		}
+		return rats[i].y.Cmp(rats[j].y)
+	}, func(o, a, b int) int {
+		return crossRat(rats[o], rats[a], rats[b]).Sign()
+	})
+	return pick(points, indices)
+}
+
+// ============================================================================
+// Polygons
+// ============================================================================
+
+// PolygonArea returns the signed area of the polygon (shoelace formula):
+// positive for counterclockwise and negative for clockwise vertex order.
+func PolygonArea(polygon []Point) float64 {
+	sum := 0.0
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		sum += p.X*q.Y - q.X*p.Y
+	}
+	return sum / 2
+}
+
+// PolygonAreaExact is the exact variant of PolygonArea. Returns an error if
+// the area doesn't fit in a Fraction.
+func PolygonAreaExact(polygon []FracPoint) (*fraction.Fraction, error) {
+	return fraction.NewFromRat(areaRat(toRats(polygon)))
+}
+
+// Centroid returns the centroid (center of mass) of the area enclosed by the
+// polygon. Returns an error if the area of the polygon is zero.
+func Centroid(polygon []Point) (Point, error) {
+	area := PolygonArea(polygon)
+	if area == 0 {
+		return Point{}, errors.New("polygon has zero area")
+	}
+	var cx, cy float64
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		w := p.X*q.Y - q.X*p.Y
+		cx += (p.X + q.X) * w
+		cy += (p.Y + q.Y) * w
+	}
+	return Point{X: cx / (6 * area), Y: cy / (6 * area)}, nil
+}
+
+// CentroidExact is the exact variant of Centroid. Returns an error if the
+// area of the polygon is zero or if a coordinate doesn't fit in a Fraction.
+func CentroidExact(polygon []FracPoint) (FracPoint, error) {
+	rats := toRats(polygon)
+	area := areaRat(rats)
+	if area.Sign() == 0 {
+		return FracPoint{}, errors.New("polygon has zero area")
+	}
+	cx, cy := new(big.Rat), new(big.Rat)
+	w, t := new(big.Rat), new(big.Rat)
+	for i, p := range rats {
+		q := rats[(i+1)%len(rats)]
+		w.Sub(t.Mul(p.x, q.y), new(big.Rat).Mul(q.x, p.y))
+		cx.Add(cx, new(big.Rat).Mul(t.Add(p.x, q.x), w))
+		cy.Add(cy, new(big.Rat).Mul(t.Add(p.y, q.y), w))
+	}
+	scale := new(big.Rat).Mul(area, big.NewRat(6, 1))
+	x, err := fraction.NewFromRat(cx.Quo(cx, scale))
+	if err != nil {
+		return FracPoint{}, err
+	}
+	y, err := fraction.NewFromRat(cy.Quo(cy, scale))
+	if err != nil {
+		return FracPoint{}, err
+	}
+	return FracPoint{x: x, y: y}, nil
+}
+
+// ============================================================================
+// Closest pair
+// ============================================================================
+
+// ClosestPair returns the two points with the smallest distance and that
+// distance, using the O(n log² n) divide and conquer algorithm. Returns an
+// error if fewer than two points are specified.
+func ClosestPair(points []Point) (Point, Point, float64, error) {
+	if len(points) < 2 {
+		return Point{}, Point{}, 0, errors.New("at least two points are required")
+	}
+	square := func(d float64) float64 { return d * d }
+	i, j := closestPair(len(points), metric[float64]{
+		compareX: func(i, j int) int { return comparePoints(points[i], points[j]) },
+		compareY: func(i, j int) int { return floatSign(points[i].Y - points[j].Y) },
+		dx2:      func(i, j int) float64 { return square(points[i].X - points[j].X) },
+		dy2:      func(i, j int) float64 { return square(points[i].Y - points[j].Y) },
+		add:      func(a, b float64) float64 { return a + b },
+		less:     func(a, b float64) bool { return a < b },
+	})
+	return points[i], points[j], math.Hypot(points[i].X-points[j].X, points[i].Y-points[j].Y), nil
+}
+
+// ClosestPairExact is the exact variant of ClosestPair. It returns the
+// squared distance, which (unlike the distance) is rational. Returns an
+// error if fewer than two points are specified or if the squared distance
+// doesn't fit in a Fraction.
+func ClosestPairExact(points []FracPoint) (FracPoint, FracPoint, *fraction.Fraction, error) {
+	if len(points) < 2 {
+		return FracPoint{}, FracPoint{}, nil, errors.New("at least two points are required")
+	}
+	rats := toRats(points)
+	square := func(a, b *big.Rat) *big.Rat {
+		d := new(big.Rat).Sub(a, b)
+		return d.Mul(d, d)
+	}
+	i, j := closestPair(len(points), metric[*big.Rat]{
+		compareX: func(i, j int) int {
+			if c := rats[i].x.Cmp(rats[j].x); c != 0 {
+				return c
+			}
+			return rats[i].y.Cmp(rats[j].y)
+		},
+		compareY: func(i, j int) int { return rats[i].y.Cmp(rats[j].y) },
+		dx2:      func(i, j int) *big.Rat { return square(rats[i].x, rats[j].x) },
+		dy2:      func(i, j int) *big.Rat { return square(rats[i].y, rats[j].y) },
+		add:      func(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) },
+		less:     func(a, b *big.Rat) bool { return a.Cmp(b) < 0 },
+	})
+	d2 := square(rats[i].x, rats[j].x)
+	d2.Add(d2, square(rats[i].y, rats[j].y))
+	distance, err := fraction.NewFromRat(d2)
+	if err != nil {
+		return FracPoint{}, FracPoint{}, nil, err
+	}
+	return points[i], points[j], distance, nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// ratPoint is a FracPoint converted to big.Rat coordinates.
+type ratPoint struct {
+	x, y *big.Rat
+}
+
+// metric describes the coordinates of a point set to the closest pair
+// algorithm, so it runs on float64 and exact coordinates alike.
+type metric[T any] struct {
+	compareX func(i, j int) int // orders by x, then by y
+	compareY func(i, j int) int
+	dx2      func(i, j int) T // squared difference in x
+	dy2      func(i, j int) T // squared difference in y
+	add      func(a, b T) T
+	less     func(a, b T) bool
+}
+
+// hull returns the indices of the convex hull vertices using Andrew's
+// monotone chain: the points are sorted and the lower and upper hulls are
+// built by discarding every point that doesn't make a left turn.
+func hull(n int, compare func(i, j int) int, orientation func(o, a, b int) int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, compare)
+	order = slices.CompactFunc(order, func(i, j int) bool { return compare(i, j) == 0 })
+	if len(order) < 3 {
+		return order
+	}
+	result := make([]int, 0, 2*len(order))
+	// Build the lower hull left to right, then the upper hull right to left;
+	// lower is the size of the lower hull that the upper hull must keep.
+	build := func(i, lower int) {
+		for len(result) >= lower+2 && orientation(result[len(result)-2], result[len(result)-1], i) <= 0 {
+			result = result[:len(result)-1]
+		}
+		result = append(result, i)
+	}
+	for _, i := range order {
+		build(i, 0)
+	}
+	lower := len(result) - 1
+	for k := len(order) - 2; k >= 0; k-- {
+		build(order[k], lower)
+	}
+	// The last point is the first point again.
+	return result[:len(result)-1]
+}
+
+// closestPair returns the indices of the closest pair of points.
+func closestPair[T any](n int, m metric[T]) (int, int) {
+	byX := make([]int, n)
+	for i := range byX {
+		byX[i] = i
+	}
+	slices.SortFunc(byX, m.compareX)
+	dist2 := func(i, j int) T {
+		return m.add(m.dx2(i, j), m.dy2(i, j))
+	}
+	var solve func(points []int) (int, int, T)
+	solve = func(points []int) (int, int, T) {
+		if len(points) <= 3 {
+			bi, bj := points[0], points[1]
+			best := dist2(bi, bj)
+			for a := range points {
+				for b := a + 1; b < len(points); b++ {
+					if d := dist2(points[a], points[b]); m.less(d, best) {
+						bi, bj, best = points[a], points[b], d
+					}
+				}
+			}
+			return bi, bj, best
+		}
+		mid := len(points) / 2
+		bi, bj, best := solve(points[:mid])
+		if ri, rj, d := solve(points[mid:]); m.less(d, best) {
+			bi, bj, best = ri, rj, d
+		}
+		// Check the pairs that straddle the dividing line within a strip of
+		// half-width sqrt(best), in order of y.
+		var strip []int
+		for _, i := range points {
+			if m.less(m.dx2(i, points[mid]), best) {
+				strip = append(strip, i)
+			}
+		}
+		slices.SortFunc(strip, m.compareY)
+		for a := range strip {
+			for b := a + 1; b < len(strip) && m.less(m.dy2(strip[a], strip[b]), best); b++ {
+				if d := dist2(strip[a], strip[b]); m.less(d, best) {
+					bi, bj, best = strip[a], strip[b], d
+				}
+			}
+		}
+		return bi, bj, best
+	}
+	i, j, _ := solve(byX)
+	return i, j
+}
+
+// cross returns the z component of (a - o) × (b - o): positive if o, a, b
+// make a left (counterclockwise) turn, negative for a right turn and zero if
+// the points are collinear.
+func cross(o, a, b Point) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// crossRat is the exact variant of cross.
+func crossRat(o, a, b ratPoint) *big.Rat {
+	ax, ay := new(big.Rat).Sub(a.x, o.x), new(big.Rat).Sub(a.y, o.y)
+	bx, by := new(big.Rat).Sub(b.x, o.x), new(big.Rat).Sub(b.y, o.y)
+	return ax.Mul(ax, by).Sub(ax, ay.Mul(ay, bx))
+}
+
+func areaRat(polygon []ratPoint) *big.Rat {
+	sum, t := new(big.Rat), new(big.Rat)
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		sum.Add(sum, t.Mul(p.x, q.y))
+		sum.Sub(sum, t.Mul(q.x, p.y))
+	}
+	return sum.Quo(sum, big.NewRat(2, 1))
+}
+
+func comparePoints(p, q Point) int {
+	if c := floatSign(p.X - q.X); c != 0 {
+		return c
+	}
+	return floatSign(p.Y - q.Y)
+}
+
+func floatSign(x float64) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+func pick[P any](points []P, indices []int) []P {
+	result := make([]P, len(indices))
+	for k, i := range indices {
+		result[k] = points[i]
+	}
+	return result
+}
+
+func toRats(points []FracPoint) []ratPoint {
+	rats := make([]ratPoint, len(points))
+	for i, p := range points {
+		rats[i] = ratPoint{x: p.x.Rat(), y: p.y.Rat()}
+	}
+	return rats
+}
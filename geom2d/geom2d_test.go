@@ -0,0 +1,123 @@
+package geom2d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func frac(numerator, denominator int) *fraction.Fraction {
+	return fraction.MustNew(numerator, denominator)
+}
+
+func TestConvexHull(t *testing.T) {
+	points := []Point{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}, {1, 0}, {0, 0}, {1, 2}}
+	hull := ConvexHull(points)
+	want := []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	if len(hull) != len(want) {
+		t.Fatalf("ConvexHull = %v; want %v", hull, want)
+	}
+	for i := range want {
+		if hull[i] != want[i] {
+			t.Fatalf("ConvexHull = %v; want %v", hull, want)
+		}
+	}
+	if got := ConvexHull([]Point{{1, 1}, {1, 1}}); len(got) != 1 {
+		t.Fatalf("ConvexHull of duplicates = %v; want [{1 1}]", got)
+	}
+}
+
+func TestConvexHullExact(t *testing.T) {
+	// The points (1/3, 1/3) and (2/3, 2/3) lie exactly on the diagonal, which
+	// float64 rounding can't decide reliably.
+	points := []FracPoint{
+		MustNewFracPoint(frac(0, 1), frac(0, 1)),
+		MustNewFracPoint(frac(1, 3), frac(1, 3)),
+		MustNewFracPoint(frac(2, 3), frac(2, 3)),
+		MustNewFracPoint(frac(1, 1), frac(1, 1)),
+		MustNewFracPoint(frac(1, 1), frac(0, 1)),
+	}
+	hull := ConvexHullExact(points)
+	if len(hull) != 3 || hull[2].String() != "(1/1, 1/1)" {
+		t.Fatalf("ConvexHullExact = %v; want [(0/1, 0/1) (1/1, 0/1) (1/1, 1/1)]", hull)
+	}
+	if _, err := NewFracPoint(nil, frac(1, 2)); err == nil {
+		t.Fatalf("NewFracPoint with nil coordinate should return error")
+	}
+}
+
+func TestPolygonAreaAndCentroid(t *testing.T) {
+	triangle := []Point{{0, 0}, {4, 0}, {0, 3}}
+	if got := PolygonArea(triangle); got != 6 {
+		t.Fatalf("PolygonArea = %v; want 6", got)
+	}
+	if got := PolygonArea([]Point{{0, 0}, {0, 3}, {4, 0}}); got != -6 {
+		t.Fatalf("PolygonArea clockwise = %v; want -6", got)
+	}
+	centroid, err := Centroid(triangle)
+	if err != nil || math.Abs(centroid.X-4.0/3) > 1e-12 || centroid.Y != 1 {
+		t.Fatalf("Centroid = %v, %v; want {1.333 1}", centroid, err)
+	}
+	if _, err := Centroid([]Point{{0, 0}, {1, 1}, {2, 2}}); err == nil {
+		t.Fatalf("Centroid of a degenerate polygon should return error")
+	}
+
+	exact := []FracPoint{
+		MustNewFracPoint(frac(0, 1), frac(0, 1)),
+		MustNewFracPoint(frac(1, 1), frac(0, 1)),
+		MustNewFracPoint(frac(0, 1), frac(1, 3)),
+	}
+	if area, _ := PolygonAreaExact(exact); area.AsIntegerRatio() != "1/6" {
+		t.Fatalf("PolygonAreaExact = %v; want 1/6", area.AsIntegerRatio())
+	}
+	if c, _ := CentroidExact(exact); c.String() != "(1/3, 1/9)" {
+		t.Fatalf("CentroidExact = %v; want (1/3, 1/9)", c)
+	}
+}
+
+func TestClosestPair(t *testing.T) {
+	points := make([]Point, 0, 100)
+	for i := 0; i < 100; i++ {
+		// A scattered grid; the pair (37, 38) is moved close together.
+		points = append(points, Point{float64(i%10) * 10, float64(i/10)*10 + float64(i%3)})
+	}
+	points[38] = Point{points[37].X + 0.3, points[37].Y + 0.4}
+	p, q, d, err := ClosestPair(points)
+	if err != nil || math.Abs(d-0.5) > 1e-12 {
+		t.Fatalf("ClosestPair = %v, %v, %v, %v; want distance 0.5", p, q, d, err)
+	}
+	if !(p == points[37] && q == points[38]) && !(p == points[38] && q == points[37]) {
+		t.Fatalf("ClosestPair = %v, %v; want %v, %v", p, q, points[37], points[38])
+	}
+	// Compare with brute force on pseudo-random points.
+	for n := 2; n < 60; n++ {
+		points := make([]Point, n)
+		for i := range points {
+			points[i] = Point{math.Mod(float64(i*7919%1000)*1.37, 97), math.Mod(float64(i*104729%1000)*0.91, 89)}
+		}
+		best := math.Inf(1)
+		for i := range points {
+			for j := i + 1; j < n; j++ {
+				best = math.Min(best, math.Hypot(points[i].X-points[j].X, points[i].Y-points[j].Y))
+			}
+		}
+		if _, _, d, _ := ClosestPair(points); d != best {
+			t.Fatalf("ClosestPair of %d points = %v; want %v", n, d, best)
+		}
+	}
+	if _, _, _, err := ClosestPair(points[:1]); err == nil {
+		t.Fatalf("ClosestPair of one point should return error")
+	}
+
+	exact := []FracPoint{
+		MustNewFracPoint(frac(0, 1), frac(0, 1)),
+		MustNewFracPoint(frac(3, 1), frac(4, 1)),
+		MustNewFracPoint(frac(1, 2), frac(1, 3)),
+		MustNewFracPoint(frac(5, 1), frac(5, 1)),
+	}
+	_, _, d2, err := ClosestPairExact(exact)
+	if err != nil || d2.AsIntegerRatio() != "13/36" {
+		t.Fatalf("ClosestPairExact squared distance = %v, %v; want 13/36", d2, err)
+	}
+}
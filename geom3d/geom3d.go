@@ -0,0 +1,350 @@
+// Package geom3d provides float64 based 3D geometry: a Vec3 type with the
+// usual vector operations (dot, cross, projection), 3x3 rotation matrices
+// (around the coordinate axes or an arbitrary axis) and the Plane and Ray
+// types with ray-plane and ray-sphere intersection.
+//
+// Important details:
+//
+// (*) Contrary to vector.Vector, all types in this package are small value
+// types: methods never modify the receiver but return a new value.
+//
+// (*) Vec3 values can be converted to and from vector.Vector[float64] with
+// Vector and NewVec3FromVector, so both packages can be mixed freely.
+//
+// (*) Angles are in radians. Rotations follow the right-hand rule.
+package geom3d
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// epsilon is the tolerance used to decide whether a float value should be
+// considered zero (for example: parallel rays and planes).
+const epsilon = 1e-12
+
+// ============================================================================
+// Vec3
+// ============================================================================
+
+// Vec3 represents a vector (or point) in 3D space.
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+// NewVec3 is a constructor function that returns a Vec3 with the specified
+// coordinates.
+func NewVec3(x, y, z float64) Vec3 {
+	return Vec3{X: x, Y: y, Z: z}
+}
+
+// NewVec3FromVector is a constructor function that converts a Vector with
+// exactly three elements to a Vec3. Returns an error if the Vector does not
+// have three elements.
+func NewVec3FromVector[T wbmath.SignedNumber](v vector.Vector[T]) (Vec3, error) {
+	if len(v) != 3 {
+		return Vec3{}, errors.New("vector must have exactly 3 elements")
+	}
+	return Vec3{X: float64(v[0]), Y: float64(v[1]), Z: float64(v[2])}, nil
+}
+
+// Vector returns the Vec3 as a Vector of type float64 with three elements.
+func (v Vec3) Vector() vector.Vector[float64] {
+	return vector.New(v.X, v.Y, v.Z)
+}
+
+// Add returns the sum of the current Vec3 and the specified Vec3.
+func (v Vec3) Add(other Vec3) Vec3 {
+	return Vec3{X: v.X + other.X, Y: v.Y + other.Y, Z: v.Z + other.Z}
+}
+
+// Subtract returns the difference of the current Vec3 and the specified Vec3.
+func (v Vec3) Subtract(other Vec3) Vec3 {
+	return Vec3{X: v.X - other.X, Y: v.Y - other.Y, Z: v.Z - other.Z}
+}
+
+// Scale returns the current Vec3 with every coordinate multiplied by `factor`.
+func (v Vec3) Scale(factor float64) Vec3 {
+	return Vec3{X: v.X * factor, Y: v.Y * factor, Z: v.Z * factor}
+}
+
+// Negate returns the current Vec3 pointing in the opposite direction.
+func (v Vec3) Negate() Vec3 {
+	return v.Scale(-1)
+}
+
+// Dot returns the dot product of the current Vec3 and the specified Vec3. If
+// the dot product is zero then the two vectors are perpendicular.
+func (v Vec3) Dot(other Vec3) float64 {
+	return v.X*other.X + v.Y*other.Y + v.Z*other.Z
+}
+
+// Cross returns the cross product of the current Vec3 and the specified Vec3:
+// a vector perpendicular to both, with a magnitude equal to the area of the
+// parallelogram they span.
+func (v Vec3) Cross(other Vec3) Vec3 {
+	return Vec3{
+		X: v.Y*other.Z - v.Z*other.Y,
+		Y: v.Z*other.X - v.X*other.Z,
+		Z: v.X*other.Y - v.Y*other.X,
+	}
+}
+
+// Magnitude returns the size / length of the Vec3.
+func (v Vec3) Magnitude() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Distance returns the distance between the current Vec3 and the specified
+// Vec3 (both interpreted as points).
+func (v Vec3) Distance(other Vec3) float64 {
+	return v.Subtract(other).Magnitude()
+}
+
+// Normalize returns a unit Vec3 pointing in the same direction as the current
+// Vec3. The zero vector is returned unchanged.
+func (v Vec3) Normalize() Vec3 {
+	magnitude := v.Magnitude()
+	if magnitude == 0 {
+		return v
+	}
+	return v.Scale(1 / magnitude)
+}
+
+// Project returns the projection of the current Vec3 onto the specified Vec3.
+// Projecting onto the zero vector returns the zero vector.
+func (v Vec3) Project(onto Vec3) Vec3 {
+	denominator := onto.Dot(onto)
+	if denominator == 0 {
+		return Vec3{}
+	}
+	return onto.Scale(v.Dot(onto) / denominator)
+}
+
+// Reject returns the rejection of the current Vec3 from the specified Vec3:
+// the component of the current Vec3 perpendicular to `from`.
+func (v Vec3) Reject(from Vec3) Vec3 {
+	return v.Subtract(v.Project(from))
+}
+
+// Angle returns the angle (in radians, between 0 and pi) between the current
+// Vec3 and the specified Vec3. Returns NaN if either vector is the zero vector.
+func (v Vec3) Angle(other Vec3) float64 {
+	denominator := v.Magnitude() * other.Magnitude()
+	if denominator == 0 {
+		return math.NaN()
+	}
+	// Clamp to guard against rounding errors just outside [-1, 1]
+	cos := math.Max(-1, math.Min(1, v.Dot(other)/denominator))
+	return math.Acos(cos)
+}
+
+// AlmostEqual checks if all coordinates of the current Vec3 and the specified
+// Vec3 differ by at most `tolerance`.
+func (v Vec3) AlmostEqual(other Vec3, tolerance float64) bool {
+	return math.Abs(v.X-other.X) <= tolerance &&
+		math.Abs(v.Y-other.Y) <= tolerance &&
+		math.Abs(v.Z-other.Z) <= tolerance
+}
+
+// ============================================================================
+// Mat3
+// ============================================================================
+
+// Mat3 is a 3x3 matrix stored in row-major order. It is mainly used to
+// represent rotations and other linear transforms of a Vec3.
+type Mat3 [3][3]float64
+
+// Identity returns the 3x3 identity matrix.
+func Identity() Mat3 {
+	return Mat3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// RotationX returns the matrix that rotates a Vec3 by `angle` radians around
+// the x-axis.
+func RotationX(angle float64) Mat3 {
+	sin, cos := math.Sincos(angle)
+	return Mat3{{1, 0, 0}, {0, cos, -sin}, {0, sin, cos}}
+}
+
+// RotationY returns the matrix that rotates a Vec3 by `angle` radians around
+// the y-axis.
+func RotationY(angle float64) Mat3 {
+	sin, cos := math.Sincos(angle)
+	return Mat3{{cos, 0, sin}, {0, 1, 0}, {-sin, 0, cos}}
+}
+
+// RotationZ returns the matrix that rotates a Vec3 by `angle` radians around
+// the z-axis.
+func RotationZ(angle float64) Mat3 {
+	sin, cos := math.Sincos(angle)
+	return Mat3{{cos, -sin, 0}, {sin, cos, 0}, {0, 0, 1}}
+}
+
+// AxisAngle returns the matrix that rotates a Vec3 by `angle` radians around
+// the specified axis (Rodrigues' rotation formula). The axis does not have to
+// be a unit vector. Returns an error if the axis is the zero vector.
+func AxisAngle(axis Vec3, angle float64) (Mat3, error) {
+	if axis.Magnitude() < epsilon {
+		return Mat3{}, errors.New("rotation axis must not be the zero vector")
+	}
+	u := axis.Normalize()
+	sin, cos := math.Sincos(angle)
+	t := 1 - cos
+	return Mat3{
+		{cos + u.X*u.X*t, u.X*u.Y*t - u.Z*sin, u.X*u.Z*t + u.Y*sin},
+		{u.Y*u.X*t + u.Z*sin, cos + u.Y*u.Y*t, u.Y*u.Z*t - u.X*sin},
+		{u.Z*u.X*t - u.Y*sin, u.Z*u.Y*t + u.X*sin, cos + u.Z*u.Z*t},
+	}, nil
+}
+
+// Apply returns the specified Vec3 transformed by the matrix (m * v).
+func (m Mat3) Apply(v Vec3) Vec3 {
+	return Vec3{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+// Multiply returns the matrix product m * other. Applying the result to a
+// Vec3 is the same as first applying `other` and then `m`.
+func (m Mat3) Multiply(other Mat3) Mat3 {
+	var result Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				result[i][j] += m[i][k] * other[k][j]
+			}
+		}
+	}
+	return result
+}
+
+// Transpose returns the transposed matrix. For rotation matrices this is also
+// the inverse rotation.
+func (m Mat3) Transpose() Mat3 {
+	var result Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			result[i][j] = m[j][i]
+		}
+	}
+	return result
+}
+
+// Determinant returns the determinant of the matrix. Rotation matrices have a
+// determinant of 1.
+func (m Mat3) Determinant() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// ============================================================================
+// Plane and Ray
+// ============================================================================
+
+// Plane represents the infinite plane of all points p for which
+// Normal·p = Offset. The Normal is always a unit vector.
+type Plane struct {
+	Normal Vec3
+	Offset float64
+}
+
+// NewPlane is a constructor function that returns the Plane through `point`
+// perpendicular to `normal`. Returns an error if the normal is the zero vector.
+func NewPlane(point Vec3, normal Vec3) (Plane, error) {
+	if normal.Magnitude() < epsilon {
+		return Plane{}, errors.New("plane normal must not be the zero vector")
+	}
+	n := normal.Normalize()
+	return Plane{Normal: n, Offset: n.Dot(point)}, nil
+}
+
+// NewPlaneFromPoints is a constructor function that returns the Plane through
+// the three specified points. The normal follows the right-hand rule for the
+// order a, b, c. Returns an error if the points are collinear.
+func NewPlaneFromPoints(a, b, c Vec3) (Plane, error) {
+	normal := b.Subtract(a).Cross(c.Subtract(a))
+	if normal.Magnitude() < epsilon {
+		return Plane{}, errors.New("points are collinear")
+	}
+	return NewPlane(a, normal)
+}
+
+// SignedDistance returns the distance from the specified point to the Plane.
+// The distance is positive on the side the normal points to and negative on
+// the other side.
+func (p Plane) SignedDistance(point Vec3) float64 {
+	return p.Normal.Dot(point) - p.Offset
+}
+
+// ProjectPoint returns the point on the Plane closest to the specified point.
+func (p Plane) ProjectPoint(point Vec3) Vec3 {
+	return point.Subtract(p.Normal.Scale(p.SignedDistance(point)))
+}
+
+// Ray represents a half-line starting at Origin and extending in Direction.
+// The Direction is always a unit vector, so the parameter t of a point on the
+// Ray equals its distance to the Origin.
+type Ray struct {
+	Origin    Vec3
+	Direction Vec3
+}
+
+// NewRay is a constructor function that returns a Ray with the specified
+// origin and direction. Returns an error if the direction is the zero vector.
+func NewRay(origin Vec3, direction Vec3) (Ray, error) {
+	if direction.Magnitude() < epsilon {
+		return Ray{}, errors.New("ray direction must not be the zero vector")
+	}
+	return Ray{Origin: origin, Direction: direction.Normalize()}, nil
+}
+
+// At returns the point on the Ray at parameter t: Origin + t * Direction.
+func (r Ray) At(t float64) Vec3 {
+	return r.Origin.Add(r.Direction.Scale(t))
+}
+
+// IntersectPlane returns the parameter t at which the Ray hits the specified
+// Plane and a boolean value that indicates if there is an intersection. A Ray
+// parallel to the Plane, or pointing away from it, does not intersect.
+func (r Ray) IntersectPlane(p Plane) (float64, bool) {
+	denominator := p.Normal.Dot(r.Direction)
+	if math.Abs(denominator) < epsilon {
+		return 0, false
+	}
+	t := (p.Offset - p.Normal.Dot(r.Origin)) / denominator
+	if t < 0 {
+		return 0, false
+	}
+	return t, true
+}
+
+// IntersectSphere returns the parameter t of the first point at which the Ray
+// hits the sphere with the specified center and radius, and a boolean value
+// that indicates if there is an intersection. If the origin of the Ray lies
+// inside the sphere the exit point is returned.
+func (r Ray) IntersectSphere(center Vec3, radius float64) (float64, bool) {
+	// Solve |O + tD - C|^2 = r^2 for t. D is a unit vector, so the quadratic
+	// reduces to t^2 + 2bt + c = 0.
+	oc := r.Origin.Subtract(center)
+	b := oc.Dot(r.Direction)
+	c := oc.Dot(oc) - radius*radius
+	discriminant := b*b - c
+	if discriminant < 0 {
+		return 0, false
+	}
+	root := math.Sqrt(discriminant)
+	if t := -b - root; t >= 0 {
+		return t, true
+	}
+	if t := -b + root; t >= 0 {
+		return t, true
+	}
+	return 0, false
+}
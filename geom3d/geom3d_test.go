@@ -0,0 +1,101 @@
+package geom3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+const tolerance = 1e-9
+
+func TestVec3Operations(t *testing.T) {
+	a := NewVec3(1, 0, 0)
+	b := NewVec3(0, 1, 0)
+	if got := a.Cross(b); !got.AlmostEqual(NewVec3(0, 0, 1), tolerance) {
+		t.Fatalf("Cross = %v; want (0, 0, 1)", got)
+	}
+	if got := a.Dot(b); got != 0 {
+		t.Fatalf("Dot = %v; want 0", got)
+	}
+	if got := NewVec3(3, 4, 0).Magnitude(); got != 5 {
+		t.Fatalf("Magnitude = %v; want 5", got)
+	}
+	if got := NewVec3(2, 3, 0).Project(a); !got.AlmostEqual(NewVec3(2, 0, 0), tolerance) {
+		t.Fatalf("Project = %v; want (2, 0, 0)", got)
+	}
+	if got := NewVec3(2, 3, 0).Reject(a); !got.AlmostEqual(NewVec3(0, 3, 0), tolerance) {
+		t.Fatalf("Reject = %v; want (0, 3, 0)", got)
+	}
+	if got := a.Angle(b); math.Abs(got-math.Pi/2) > tolerance {
+		t.Fatalf("Angle = %v; want pi/2", got)
+	}
+}
+
+func TestVec3VectorConversion(t *testing.T) {
+	v, err := NewVec3FromVector(vector.New(1, 2, 3))
+	if err != nil {
+		t.Fatalf("NewVec3FromVector returned error: %v", err)
+	}
+	if v != NewVec3(1, 2, 3) {
+		t.Fatalf("NewVec3FromVector = %v; want (1, 2, 3)", v)
+	}
+	if got := v.Vector(); len(got) != 3 || got[2] != 3 {
+		t.Fatalf("Vector() = %v; want [1 2 3]", got)
+	}
+	if _, err := NewVec3FromVector(vector.New(1, 2)); err == nil {
+		t.Fatalf("NewVec3FromVector with 2 elements should return error")
+	}
+}
+
+func TestRotations(t *testing.T) {
+	x := NewVec3(1, 0, 0)
+	if got := RotationZ(math.Pi / 2).Apply(x); !got.AlmostEqual(NewVec3(0, 1, 0), tolerance) {
+		t.Fatalf("RotationZ(pi/2) * x = %v; want (0, 1, 0)", got)
+	}
+	m, err := AxisAngle(NewVec3(0, 0, 2), math.Pi/2)
+	if err != nil {
+		t.Fatalf("AxisAngle returned error: %v", err)
+	}
+	if got := m.Apply(x); !got.AlmostEqual(NewVec3(0, 1, 0), tolerance) {
+		t.Fatalf("AxisAngle(z, pi/2) * x = %v; want (0, 1, 0)", got)
+	}
+	got := m.Multiply(m.Transpose())
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(got[i][j]-Identity()[i][j]) > tolerance {
+				t.Fatalf("R * R^T = %v; want identity", got)
+			}
+		}
+	}
+	if got := RotationX(0.3).Determinant(); math.Abs(got-1) > tolerance {
+		t.Fatalf("Determinant = %v; want 1", got)
+	}
+	if _, err := AxisAngle(Vec3{}, 1); err == nil {
+		t.Fatalf("AxisAngle with zero axis should return error")
+	}
+}
+
+func TestRayIntersections(t *testing.T) {
+	ray, err := NewRay(NewVec3(0, 0, -5), NewVec3(0, 0, 2))
+	if err != nil {
+		t.Fatalf("NewRay returned error: %v", err)
+	}
+	plane, _ := NewPlane(NewVec3(0, 0, 1), NewVec3(0, 0, 1))
+	if got, ok := ray.IntersectPlane(plane); !ok || math.Abs(got-6) > tolerance {
+		t.Fatalf("IntersectPlane = %v, %v; want 6, true", got, ok)
+	}
+	parallel, _ := NewPlane(NewVec3(1, 0, 0), NewVec3(1, 0, 0))
+	if _, ok := ray.IntersectPlane(parallel); ok {
+		t.Fatalf("IntersectPlane with parallel plane should not intersect")
+	}
+	if got, ok := ray.IntersectSphere(Vec3{}, 2); !ok || math.Abs(got-3) > tolerance {
+		t.Fatalf("IntersectSphere = %v, %v; want 3, true", got, ok)
+	}
+	if _, ok := ray.IntersectSphere(NewVec3(5, 0, 0), 1); ok {
+		t.Fatalf("IntersectSphere should miss")
+	}
+	if _, err := NewPlaneFromPoints(Vec3{}, NewVec3(1, 0, 0), NewVec3(2, 0, 0)); err == nil {
+		t.Fatalf("NewPlaneFromPoints with collinear points should return error")
+	}
+}
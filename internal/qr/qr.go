@@ -0,0 +1,87 @@
+// Package qr solves linear least-squares problems with a Householder QR
+// decomposition. It is shared by the fit and vector packages.
+package qr
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrDependent is returned when the columns of the matrix are linearly
+// dependent, so the least-squares solution is not unique.
+var ErrDependent = errors.New("columns are linearly dependent")
+
+// Householder computes the QR decomposition of the matrix with columns a
+// in-place, applies Q^T to b and returns the upper triangular p×p matrix R.
+// Returns ErrDependent if the columns are linearly dependent.
+func Householder(a [][]float64, b []float64) ([][]float64, error) {
+	p := len(a)
+	n := len(b)
+	scale := 0.0
+	for _, column := range a {
+		for _, value := range column {
+			scale = math.Max(scale, math.Abs(value))
+		}
+	}
+	for k := 0; k < p; k++ {
+		// Householder vector for column k, rows k..n-1
+		norm := 0.0
+		for i := k; i < n; i++ {
+			norm = math.Hypot(norm, a[k][i])
+		}
+		if norm <= 1e-12*scale*math.Sqrt(float64(n)) {
+			return nil, ErrDependent
+		}
+		if a[k][k] > 0 {
+			norm = -norm
+		}
+		// v = a[k][k:] - norm*e1, stored in place; beta = 2 / v·v
+		a[k][k] -= norm
+		vv := 0.0
+		for i := k; i < n; i++ {
+			vv += a[k][i] * a[k][i]
+		}
+		reflect := func(x []float64) {
+			dot := 0.0
+			for i := k; i < n; i++ {
+				dot += a[k][i] * x[i]
+			}
+			factor := 2 * dot / vv
+			for i := k; i < n; i++ {
+				x[i] -= factor * a[k][i]
+			}
+		}
+		for j := k + 1; j < p; j++ {
+			reflect(a[j])
+		}
+		reflect(b)
+		// The reflected column k is norm*e1.
+		for i := k + 1; i < n; i++ {
+			a[k][i] = 0
+		}
+		a[k][k] = norm
+	}
+	r := make([][]float64, p)
+	for i := range r {
+		r[i] = make([]float64, p)
+		for j := i; j < p; j++ {
+			r[i][j] = a[j][i]
+		}
+	}
+	return r, nil
+}
+
+// Substitute solves R·c = (Q^T b)[:p] by back substitution, with R and Q^T b
+// from Householder, and returns c: the least-squares solution.
+func Substitute(r [][]float64, qtb []float64) []float64 {
+	p := len(r)
+	c := make([]float64, p)
+	for i := p - 1; i >= 0; i-- {
+		sum := qtb[i]
+		for j := i + 1; j < p; j++ {
+			sum -= r[i][j] * c[j]
+		}
+		c[i] = sum / r[i][i]
+	}
+	return c
+}
@@ -0,0 +1,28 @@
+package qr
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHouseholderAndSubstitute(t *testing.T) {
+	// y = 1 + 2x through (0, 1), (1, 3), (2, 5), (3, 7)
+	a := [][]float64{{1, 1, 1, 1}, {0, 1, 2, 3}}
+	b := []float64{1, 3, 5, 7}
+	r, err := Householder(a, b)
+	if err != nil {
+		t.Fatalf("Householder returned error: %v", err)
+	}
+	if r[1][0] != 0 || math.Abs(math.Abs(r[0][0])-2) > 1e-12 {
+		t.Fatalf("R = %v; want an upper triangular matrix with |R[0][0]| = 2", r)
+	}
+	c := Substitute(r, b)
+	if math.Abs(c[0]-1) > 1e-12 || math.Abs(c[1]-2) > 1e-12 {
+		t.Fatalf("Substitute = %v; want [1 2]", c)
+	}
+	dependent := [][]float64{{1, 2, 3}, {2, 4, 6}}
+	if _, err := Householder(dependent, []float64{1, 2, 3}); !errors.Is(err, ErrDependent) {
+		t.Fatalf("Householder with dependent columns returned %v; want ErrDependent", err)
+	}
+}
@@ -0,0 +1,274 @@
+// Package interp provides piecewise cubic interpolation of data points given
+// as x/y Vectors: natural and clamped cubic splines, monotone PCHIP
+// (piecewise cubic Hermite interpolation) and Akima splines.
+//
+// Every interpolant is a Spline that can be evaluated (Eval, EvalVector),
+// differentiated (Derivative) and integrated (Integral).
+//
+// Important details:
+//
+// (*) The x values must be strictly increasing and there must be at least two
+// points. The constructors return an error otherwise.
+//
+// (*) Outside the range of the data the first and last cubic pieces are
+// extended (extrapolation).
+//
+// (*) Cubic splines have continuous second derivatives. PCHIP preserves
+// monotonicity (no overshoot) and Akima splines are less sensitive to
+// outliers; both only have continuous first derivatives.
+package interp
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Spline is a piecewise cubic Hermite interpolant: it is defined by the knots,
+// the values and the first derivatives at the knots.
+type Spline struct {
+	x, y, slopes []float64
+	// integrals[i] is the integral from x[0] to x[i].
+	integrals []float64
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// NewNatural is a constructor function that returns the natural cubic spline
+// through the points: the second derivative is zero at both ends. Returns an
+// error if the points are invalid.
+func NewNatural(x, y vector.Vector[float64]) (*Spline, error) {
+	if err := validate(x, y); err != nil {
+		return nil, err
+	}
+	return newCubic(x, y, math.NaN(), math.NaN()), nil
+}
+
+// NewClamped is a constructor function that returns the cubic spline through
+// the points with the specified first derivatives at the first and the last
+// point. Returns an error if the points are invalid.
+func NewClamped(x, y vector.Vector[float64], startSlope, endSlope float64) (*Spline, error) {
+	if err := validate(x, y); err != nil {
+		return nil, err
+	}
+	return newCubic(x, y, startSlope, endSlope), nil
+}
+
+// NewPCHIP is a constructor function that returns the monotone piecewise
+// cubic Hermite interpolant (Fritsch-Carlson) through the points. The
+// interpolant is monotone wherever the data is. Returns an error if the
+// points are invalid.
+func NewPCHIP(x, y vector.Vector[float64]) (*Spline, error) {
+	if err := validate(x, y); err != nil {
+		return nil, err
+	}
+	n := len(x)
+	h, delta := differences(x, y)
+	slopes := make([]float64, n)
+	if n == 2 {
+		slopes[0], slopes[1] = delta[0], delta[0]
+		return newSpline(x, y, slopes), nil
+	}
+	for k := 1; k < n-1; k++ {
+		if delta[k-1]*delta[k] <= 0 {
+			continue
+		}
+		w1, w2 := 2*h[k]+h[k-1], h[k]+2*h[k-1]
+		slopes[k] = (w1 + w2) / (w1/delta[k-1] + w2/delta[k])
+	}
+	slopes[0] = pchipEnd(h[0], h[1], delta[0], delta[1])
+	slopes[n-1] = pchipEnd(h[n-2], h[n-3], delta[n-2], delta[n-3])
+	return newSpline(x, y, slopes), nil
+}
+
+// NewAkima is a constructor function that returns the Akima spline through
+// the points. Returns an error if the points are invalid.
+func NewAkima(x, y vector.Vector[float64]) (*Spline, error) {
+	if err := validate(x, y); err != nil {
+		return nil, err
+	}
+	n := len(x)
+	_, delta := differences(x, y)
+	slopes := make([]float64, n)
+	if n == 2 {
+		slopes[0], slopes[1] = delta[0], delta[0]
+		return newSpline(x, y, slopes), nil
+	}
+	// m[i+2] is the slope of segment i; two extra slopes are extrapolated
+	// linearly on both sides.
+	m := make([]float64, n+3)
+	copy(m[2:], delta)
+	m[1] = 2*m[2] - m[3]
+	m[0] = 2*m[1] - m[2]
+	m[n+1] = 2*m[n] - m[n-1]
+	m[n+2] = 2*m[n+1] - m[n]
+	for i := range slopes {
+		w1 := math.Abs(m[i+3] - m[i+2])
+		w2 := math.Abs(m[i+1] - m[i])
+		if w1+w2 == 0 {
+			slopes[i] = (m[i+1] + m[i+2]) / 2
+		} else {
+			slopes[i] = (w1*m[i+1] + w2*m[i+2]) / (w1 + w2)
+		}
+	}
+	return newSpline(x, y, slopes), nil
+}
+
+// ============================================================================
+// Evaluation
+// ============================================================================
+
+// Eval returns the value of the interpolant at t.
+func (s *Spline) Eval(t float64) float64 {
+	i, u := s.locate(t)
+	c0, c1, c2, c3 := s.coefficients(i)
+	return c0 + u*(c1+u*(c2+u*c3))
+}
+
+// EvalVector returns the values of the interpolant at every element of ts.
+func (s *Spline) EvalVector(ts vector.Vector[float64]) vector.Vector[float64] {
+	result := ts.Clone()
+	for i, t := range ts {
+		result[i] = s.Eval(t)
+	}
+	return result
+}
+
+// Derivative returns the first derivative of the interpolant at t.
+func (s *Spline) Derivative(t float64) float64 {
+	i, u := s.locate(t)
+	_, c1, c2, c3 := s.coefficients(i)
+	return c1 + u*(2*c2+3*c3*u)
+}
+
+// Integral returns the integral of the interpolant from a to b.
+func (s *Spline) Integral(a, b float64) float64 {
+	return s.antiderivative(b) - s.antiderivative(a)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+func validate(x, y vector.Vector[float64]) error {
+	if len(x) != len(y) {
+		return errors.New("x and y must have the same length")
+	}
+	if len(x) < 2 {
+		return errors.New("at least two points are required")
+	}
+	for i := 1; i < len(x); i++ {
+		if !(x[i] > x[i-1]) {
+			return errors.New("x values must be strictly increasing")
+		}
+	}
+	return nil
+}
+
+// differences returns the interval widths and the slopes of the segments.
+func differences(x, y []float64) ([]float64, []float64) {
+	h := make([]float64, len(x)-1)
+	delta := make([]float64, len(x)-1)
+	for i := range h {
+		h[i] = x[i+1] - x[i]
+		delta[i] = (y[i+1] - y[i]) / h[i]
+	}
+	return h, delta
+}
+
+// newCubic computes the cubic spline with continuous second derivatives.
+// NaN end slopes select the natural end condition. The second derivatives M
+// solve a tridiagonal system (Thomas algorithm) and are converted to slopes.
+func newCubic(x, y []float64, startSlope, endSlope float64) *Spline {
+	n := len(x)
+	h, delta := differences(x, y)
+	lower, diagonal, upper, rhs := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		lower[i], diagonal[i], upper[i] = h[i-1], 2*(h[i-1]+h[i]), h[i]
+		rhs[i] = 6 * (delta[i] - delta[i-1])
+	}
+	if math.IsNaN(startSlope) {
+		diagonal[0] = 1
+	} else {
+		diagonal[0], upper[0], rhs[0] = 2*h[0], h[0], 6*(delta[0]-startSlope)
+	}
+	if math.IsNaN(endSlope) {
+		diagonal[n-1] = 1
+	} else {
+		lower[n-1], diagonal[n-1], rhs[n-1] = h[n-2], 2*h[n-2], 6*(endSlope-delta[n-2])
+	}
+	for i := 1; i < n; i++ {
+		w := lower[i] / diagonal[i-1]
+		diagonal[i] -= w * upper[i-1]
+		rhs[i] -= w * rhs[i-1]
+	}
+	m := make([]float64, n)
+	m[n-1] = rhs[n-1] / diagonal[n-1]
+	for i := n - 2; i >= 0; i-- {
+		m[i] = (rhs[i] - upper[i]*m[i+1]) / diagonal[i]
+	}
+	slopes := make([]float64, n)
+	for i := 0; i < n-1; i++ {
+		slopes[i] = delta[i] - h[i]*(2*m[i]+m[i+1])/6
+	}
+	slopes[n-1] = delta[n-2] + h[n-2]*(m[n-2]+2*m[n-1])/6
+	return newSpline(x, y, slopes)
+}
+
+// pchipEnd returns the shape-preserving three-point end slope.
+func pchipEnd(h0, h1, delta0, delta1 float64) float64 {
+	d := ((2*h0+h1)*delta0 - h0*delta1) / (h0 + h1)
+	switch {
+	case math.Signbit(d) != math.Signbit(delta0) || delta0 == 0:
+		return 0
+	case math.Signbit(delta0) != math.Signbit(delta1) && math.Abs(d) > 3*math.Abs(delta0):
+		return 3 * delta0
+	}
+	return d
+}
+
+func newSpline(x, y, slopes []float64) *Spline {
+	s := &Spline{
+		x:         append([]float64(nil), x...),
+		y:         append([]float64(nil), y...),
+		slopes:    slopes,
+		integrals: make([]float64, len(x)),
+	}
+	for i := 0; i < len(x)-1; i++ {
+		s.integrals[i+1] = s.integrals[i] + s.segmentIntegral(i, x[i+1]-x[i])
+	}
+	return s
+}
+
+// locate returns the index of the segment used for t and the offset of t
+// from the start of that segment.
+func (s *Spline) locate(t float64) (int, float64) {
+	i := sort.SearchFloat64s(s.x, t) - 1
+	i = max(0, min(i, len(s.x)-2))
+	return i, t - s.x[i]
+}
+
+// coefficients returns the coefficients of segment i as a polynomial in the
+// offset from x[i].
+func (s *Spline) coefficients(i int) (float64, float64, float64, float64) {
+	h := s.x[i+1] - s.x[i]
+	delta := (s.y[i+1] - s.y[i]) / h
+	d0, d1 := s.slopes[i], s.slopes[i+1]
+	return s.y[i], d0, (3*delta - 2*d0 - d1) / h, (d0 + d1 - 2*delta) / (h * h)
+}
+
+// segmentIntegral returns the integral of segment i from x[i] to x[i] + u.
+func (s *Spline) segmentIntegral(i int, u float64) float64 {
+	c0, c1, c2, c3 := s.coefficients(i)
+	return u * (c0 + u*(c1/2+u*(c2/3+u*c3/4)))
+}
+
+// antiderivative returns the integral from x[0] to t.
+func (s *Spline) antiderivative(t float64) float64 {
+	i, u := s.locate(t)
+	return s.integrals[i] + s.segmentIntegral(i, u)
+}
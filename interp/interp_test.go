@@ -0,0 +1,81 @@
+package interp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestClampedReproducesCubic(t *testing.T) {
+	f := func(x float64) float64 { return x*x*x - 2*x + 1 }
+	x := vector.New(0.0, 0.5, 1.25, 2, 3)
+	y := x.Clone().Map(f)
+	s, err := NewClamped(x, y, -2, 25)
+	if err != nil {
+		t.Fatalf("NewClamped returned error: %v", err)
+	}
+	for _, p := range []float64{0.1, 0.9, 1.7, 2.5, 3.5} {
+		if got := s.Eval(p); !almostEqual(got, f(p)) {
+			t.Fatalf("Eval(%v) = %v; want %v", p, got, f(p))
+		}
+		if got := s.Derivative(p); !almostEqual(got, 3*p*p-2) {
+			t.Fatalf("Derivative(%v) = %v; want %v", p, got, 3*p*p-2)
+		}
+	}
+	if got := s.Integral(0, 2); !almostEqual(got, 2) {
+		t.Fatalf("Integral(0, 2) = %v; want 2", got)
+	}
+}
+
+func TestNatural(t *testing.T) {
+	x := vector.New(0.0, 1, 2, 3)
+	y := vector.New(0.0, 1, 0, 1)
+	s, _ := NewNatural(x, y)
+	for i := range x {
+		if got := s.Eval(x[i]); !almostEqual(got, y[i]) {
+			t.Fatalf("Eval(%v) = %v; want %v", x[i], got, y[i])
+		}
+	}
+	// The second derivative vanishes at the ends: compare slopes near x[0].
+	const h = 1e-4
+	if second := (s.Derivative(h) - s.Derivative(0)) / h; math.Abs(second) > 1e-3 {
+		t.Fatalf("second derivative at start = %v; want 0", second)
+	}
+	if got := s.Eval(1.5); !almostEqual(got, 0.5) {
+		t.Fatalf("Eval(1.5) = %v; want 0.5 by symmetry", got)
+	}
+}
+
+func TestPCHIPIsMonotone(t *testing.T) {
+	x := vector.New(0.0, 1, 2, 3, 4, 5)
+	y := vector.New(0.0, 0, 0.1, 5, 5.1, 10)
+	s, _ := NewPCHIP(x, y)
+	previous := s.Eval(0)
+	for p := 0.01; p <= 5; p += 0.01 {
+		value := s.Eval(p)
+		if value < previous-1e-12 {
+			t.Fatalf("PCHIP is not monotone at %v: %v < %v", p, value, previous)
+		}
+		previous = value
+	}
+}
+
+func TestAkima(t *testing.T) {
+	x := vector.New(0.0, 1, 2, 3, 4)
+	y := x.Clone().Scale(3).Map(func(v float64) float64 { return v - 1 })
+	s, _ := NewAkima(x, y)
+	if got := s.Eval(2.7); !almostEqual(got, 7.1) {
+		t.Fatalf("Eval(2.7) = %v; want 7.1", got)
+	}
+	if got := s.Integral(1, 3); !almostEqual(got, 10) {
+		t.Fatalf("Integral(1, 3) = %v; want 10", got)
+	}
+	if _, err := NewAkima(vector.New(0.0, 0), vector.New(1.0, 2)); err == nil {
+		t.Fatalf("NewAkima with duplicate x should return error")
+	}
+}
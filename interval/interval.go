@@ -0,0 +1,432 @@
+// Package interval provides interval arithmetic: an Interval[T] [lo, hi] is
+// a set of real numbers that is guaranteed to contain the exact result of a
+// computation, even though every floating-point operation rounds.
+//
+// Available functionality includes constructors (New, Point, NewWithError,
+// FromFloat, FromFraction), accessors (Lo, Hi, Width, Midpoint, Radius,
+// IsPoint), set operations (Contains, ContainsInterval, Intersects,
+// Intersection, Union, Hull) and arithmetic (Add, Subtract, Multiply,
+// Divide, Negate, Scale, Square).
+//
+// Important details:
+//
+// (*) Endpoints are rounded outward: a lower endpoint is rounded down and an
+// upper endpoint up, so the result always encloses the exact result for
+// every choice of operands in the intervals. Go has no control over the
+// rounding mode, so the direction is determined with error-free
+// transformations (TwoSum, FMA): an endpoint is only moved by one unit in
+// the last place if the operation was inexact, and intervals of exactly
+// representable results stay tight.
+//
+// (*) An Interval is an immutable value type: methods return a new Interval.
+//
+// (*) Endpoints may be infinite for an unbounded interval. As in IEEE 1788,
+// 0 · ±Inf is 0, and a result of finite endpoints that overflows is only
+// infinite at the side that is rounded away from zero.
+//
+// (*) Division by an interval that contains zero returns an error instead of
+// an unbounded (or a union of two) interval(s).
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// Interval represents the closed interval [lo, hi].
+type Interval[T float32 | float64] struct {
+	lo, hi T
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the interval [lo, hi]. Returns
+// an error if an endpoint is NaN or if lo > hi.
+func New[T float32 | float64](lo, hi T) (Interval[T], error) {
+	if lo != lo || hi != hi {
+		return Interval[T]{}, errors.New("interval endpoints must not be NaN")
+	}
+	if lo > hi {
+		return Interval[T]{}, fmt.Errorf("lower endpoint %v is larger than upper endpoint %v", lo, hi)
+	}
+	return Interval[T]{lo: lo, hi: hi}, nil
+}
+
+// MustNew is a constructor identical to New but which panics if an error
+// occurs.
+func MustNew[T float32 | float64](lo, hi T) Interval[T] {
+	i, err := New(lo, hi)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+// Point is a constructor function that returns the degenerate interval
+// [x, x] for a value that is known exactly.
+func Point[T float32 | float64](x T) Interval[T] {
+	return Interval[T]{lo: x, hi: x}
+}
+
+// NewWithError is a constructor function that returns the interval
+// [x - e, x + e] (rounded outward) for a measurement x with absolute error
+// e. Returns an error if x or e is NaN or if e is negative.
+func NewWithError[T float32 | float64](x, e T) (Interval[T], error) {
+	if e < 0 {
+		return Interval[T]{}, fmt.Errorf("error %v must not be negative", e)
+	}
+	return New(add(x, -e, down), add(x, e, up))
+}
+
+// FromFloat is a constructor function that returns the interval of real
+// numbers that can have been rounded to x: [x - ulp, x + ulp] with ulp the
+// distance to the neighbouring floating-point numbers. Use it to turn a
+// value that already carries a rounding error (e.g. the float64 0.1) into an
+// enclosure of the intended real number. Returns an error if x is NaN.
+func FromFloat(x float64) (Interval[float64], error) {
+	return New(math.Nextafter(x, math.Inf(-1)), math.Nextafter(x, math.Inf(1)))
+}
+
+// FromFraction is a constructor function that returns the smallest float64
+// interval that contains the exact value of f: a point interval if f is
+// exactly representable (like 3/4), and otherwise the two neighbouring
+// floating-point numbers (like for 1/3). Returns an error if f is nil.
+func FromFraction(f *fraction.Fraction) (Interval[float64], error) {
+	if f == nil {
+		return Interval[float64]{}, fraction.ErrNilFraction
+	}
+	exact := f.Rat()
+	x, isExact := exact.Float64()
+	if isExact {
+		return Point(x), nil
+	}
+	if new(big.Rat).SetFloat64(x).Cmp(exact) < 0 {
+		return New(x, math.Nextafter(x, math.Inf(1)))
+	}
+	return New(math.Nextafter(x, math.Inf(-1)), x)
+}
+
+// ============================================================================
+// Accessors
+// ============================================================================
+
+// Lo returns the lower endpoint.
+func (i Interval[T]) Lo() T { return i.lo }
+
+// Hi returns the upper endpoint.
+func (i Interval[T]) Hi() T { return i.hi }
+
+// Width returns hi - lo, rounded up so it is never smaller than the exact
+// width.
+func (i Interval[T]) Width() T {
+	return add(i.hi, -i.lo, up)
+}
+
+// Radius returns half the width, rounded up.
+func (i Interval[T]) Radius() T {
+	return i.Width() / 2
+}
+
+// Midpoint returns the (rounded) midpoint of the interval, which always lies
+// within the interval. For an unbounded interval it returns the finite
+// endpoint, or 0 if both endpoints are infinite.
+func (i Interval[T]) Midpoint() T {
+	lowerInf, upperInf := math.IsInf(float64(i.lo), 0), math.IsInf(float64(i.hi), 0)
+	switch {
+	case i.lo == i.hi:
+		return i.lo
+	case lowerInf && upperInf:
+		return 0
+	case lowerInf:
+		return i.hi
+	case upperInf:
+		return i.lo
+	}
+	// lo/2 + hi/2 doesn't overflow, unlike (lo + hi)/2.
+	return min(max(i.lo/2+i.hi/2, i.lo), i.hi)
+}
+
+// IsPoint checks if the interval contains exactly one number (lo = hi).
+func (i Interval[T]) IsPoint() bool {
+	return i.lo == i.hi
+}
+
+// String implements the fmt.Stringer interface and returns the interval
+// formatted as "[lo, hi]".
+func (i Interval[T]) String() string {
+	return fmt.Sprintf("[%v, %v]", i.lo, i.hi)
+}
+
+// ============================================================================
+// Set operations
+// ============================================================================
+
+// Contains checks if x lies in the interval.
+func (i Interval[T]) Contains(x T) bool {
+	return i.lo <= x && x <= i.hi
+}
+
+// ContainsInterval checks if other is a subset of the interval.
+func (i Interval[T]) ContainsInterval(other Interval[T]) bool {
+	return i.lo <= other.lo && other.hi <= i.hi
+}
+
+// Intersects checks if the intervals have at least one number in common.
+func (i Interval[T]) Intersects(other Interval[T]) bool {
+	return i.lo <= other.hi && other.lo <= i.hi
+}
+
+// Intersection returns the numbers that lie in both intervals. Returns false
+// if the intervals don't intersect.
+func (i Interval[T]) Intersection(other Interval[T]) (Interval[T], bool) {
+	if !i.Intersects(other) {
+		return Interval[T]{}, false
+	}
+	return Interval[T]{lo: max(i.lo, other.lo), hi: min(i.hi, other.hi)}, true
+}
+
+// Union returns the numbers that lie in either interval. Returns an error if
+// the intervals don't intersect, because the union is not an interval then;
+// use Hull instead.
+func (i Interval[T]) Union(other Interval[T]) (Interval[T], error) {
+	if !i.Intersects(other) {
+		return Interval[T]{}, fmt.Errorf("union of disjoint intervals %v and %v", i, other)
+	}
+	return i.Hull(other), nil
+}
+
+// Hull returns the smallest interval that contains both intervals.
+func (i Interval[T]) Hull(other Interval[T]) Interval[T] {
+	return Interval[T]{lo: min(i.lo, other.lo), hi: max(i.hi, other.hi)}
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns i + other = [i.lo + other.lo, i.hi + other.hi].
+func (i Interval[T]) Add(other Interval[T]) Interval[T] {
+	return Interval[T]{lo: add(i.lo, other.lo, down), hi: add(i.hi, other.hi, up)}
+}
+
+// Subtract returns i - other = [i.lo - other.hi, i.hi - other.lo].
+func (i Interval[T]) Subtract(other Interval[T]) Interval[T] {
+	return i.Add(other.Negate())
+}
+
+// Negate returns -i = [-hi, -lo] (which is exact).
+func (i Interval[T]) Negate() Interval[T] {
+	return Interval[T]{lo: -i.hi, hi: -i.lo}
+}
+
+// Multiply returns i · other: the smallest and largest of the four products
+// of the endpoints. As in IEEE 1788, 0 · ±Inf is 0 for an unbounded
+// interval: [0, 1] · [1, +Inf] is [0, +Inf].
+func (i Interval[T]) Multiply(other Interval[T]) Interval[T] {
+	lo, hi := T(math.Inf(1)), T(math.Inf(-1))
+	for _, a := range [2]T{i.lo, i.hi} {
+		for _, b := range [2]T{other.lo, other.hi} {
+			lo = min(lo, multiply(a, b, down))
+			hi = max(hi, multiply(a, b, up))
+		}
+	}
+	return Interval[T]{lo: lo, hi: hi}
+}
+
+// Scale returns factor · i.
+func (i Interval[T]) Scale(factor T) Interval[T] {
+	return i.Multiply(Point(factor))
+}
+
+// Square returns {x² | x in i}, which is tighter than i.Multiply(i) for an
+// interval that contains zero: [-1, 2]² is [0, 4], not [-2, 4].
+func (i Interval[T]) Square() Interval[T] {
+	switch {
+	case i.lo >= 0:
+		return Interval[T]{lo: multiply(i.lo, i.lo, down), hi: multiply(i.hi, i.hi, up)}
+	case i.hi <= 0:
+		return Interval[T]{lo: multiply(i.hi, i.hi, down), hi: multiply(i.lo, i.lo, up)}
+	}
+	return Interval[T]{lo: 0, hi: max(multiply(i.lo, i.lo, up), multiply(i.hi, i.hi, up))}
+}
+
+// Divide returns i / other: the smallest and largest of the four quotients
+// of the endpoints, where ±Inf / ±Inf encloses every quotient between 0 and
+// ±Inf. Returns an error if other contains zero.
+func (i Interval[T]) Divide(other Interval[T]) (Interval[T], error) {
+	if other.Contains(0) {
+		return Interval[T]{}, fmt.Errorf("division by interval %v that contains zero", other)
+	}
+	lo, hi := T(math.Inf(1)), T(math.Inf(-1))
+	for _, a := range [2]T{i.lo, i.hi} {
+		for _, b := range [2]T{other.lo, other.hi} {
+			lo = min(lo, divide(a, b, down))
+			hi = max(hi, divide(a, b, up))
+		}
+	}
+	return Interval[T]{lo: lo, hi: hi}, nil
+}
+
+// ============================================================================
+// Directed rounding
+// ============================================================================
+
+// direction is the direction in which a result is rounded.
+type direction bool
+
+const (
+	down direction = false
+	up   direction = true
+)
+
+// add returns a + b rounded in the specified direction.
+func add[T float32 | float64](a, b T, d direction) T {
+	return round[T](addFloat64(float64(a), float64(b), d), d)
+}
+
+// multiply returns a · b rounded in the specified direction.
+func multiply[T float32 | float64](a, b T, d direction) T {
+	return round[T](multiplyFloat64(float64(a), float64(b), d), d)
+}
+
+// divide returns a / b rounded in the specified direction.
+func divide[T float32 | float64](a, b T, d direction) T {
+	return round[T](divideFloat64(float64(a), float64(b), d), d)
+}
+
+// addFloat64 returns a + b rounded in the specified direction. The rounding
+// error of s = a + b is computed exactly with Knuth's TwoSum.
+func addFloat64(a, b float64, d direction) float64 {
+	s := a + b
+	if math.IsInf(s, 0) && !math.IsInf(a, 0) && !math.IsInf(b, 0) {
+		return overflow(s, d)
+	}
+	if math.IsInf(s, 0) || math.IsNaN(s) {
+		return s
+	}
+	v := s - a
+	e := (a - (s - v)) + (b - v)
+	return nudge(s, e, d)
+}
+
+// multiplyFloat64 returns a · b rounded in the specified direction. The
+// rounding error of p = a · b is computed exactly with a fused multiply-add,
+// except in the subnormal range: there the error itself may underflow to 0,
+// so a subnormal result without a visible error is always moved outward.
+func multiplyFloat64(a, b float64, d direction) float64 {
+	if a == 0 || b == 0 {
+		// Also 0 · ±Inf: an infinite endpoint stands for an unbounded
+		// interval, not for a number.
+		return 0
+	}
+	p := a * b
+	if math.IsInf(p, 0) && !math.IsInf(a, 0) && !math.IsInf(b, 0) {
+		return overflow(p, d)
+	}
+	if math.IsInf(p, 0) || math.IsNaN(p) {
+		return p
+	}
+	e := math.FMA(a, b, -p)
+	switch {
+	case p == 0:
+		// Underflow: the exact product has the sign of a · b.
+		e = math.Copysign(1, a) * math.Copysign(1, b)
+	case e == 0 && math.Abs(p) < smallestNormal:
+		return outward(p, d)
+	}
+	return nudge(p, e, d)
+}
+
+// divideFloat64 returns a / b rounded in the specified direction. The
+// remainder r = a - q·b is exact with a fused multiply-add, and the exact
+// quotient is q + r/b.
+func divideFloat64(a, b float64, d direction) float64 {
+	if math.IsInf(a, 0) && math.IsInf(b, 0) {
+		// Both endpoints are unbounded: the quotient can be anything between
+		// 0 and ±Inf.
+		q := math.Copysign(1, a) * math.Copysign(1, b)
+		if (q > 0) == (d == up) {
+			return math.Inf(int(q))
+		}
+		return 0
+	}
+	q := a / b
+	if math.IsInf(q, 0) && !math.IsInf(a, 0) && b != 0 {
+		return overflow(q, d)
+	}
+	if math.IsInf(q, 0) || math.IsNaN(q) {
+		return q
+	}
+	r := math.FMA(-q, b, a)
+	switch {
+	case q == 0 && a != 0:
+		// Underflow: the exact quotient has the sign of a / b.
+		r = a
+	case r == 0 && math.Abs(q) < smallestNormal:
+		// The remainder of a subnormal quotient may underflow to 0.
+		return outward(q, d)
+	}
+	if b < 0 {
+		r = -r
+	}
+	return nudge(q, r, d)
+}
+
+// overflow rounds x = ±Inf, the overflowed result of an operation on finite
+// operands, in direction d. The exact result is finite, so towards zero it
+// rounds to ±MaxFloat64 instead; away from zero it stays ±Inf.
+func overflow(x float64, d direction) float64 {
+	if (x > 0) == (d == down) {
+		return math.Copysign(math.MaxFloat64, x)
+	}
+	return x
+}
+
+// smallestNormal is the smallest positive normal float64: the rounding error
+// of a smaller (subnormal) result may not be representable.
+const smallestNormal = 0x1p-1022
+
+// outward moves x one unit in the last place in direction d.
+func outward(x float64, d direction) float64 {
+	if d == up {
+		return math.Nextafter(x, math.Inf(1))
+	}
+	return math.Nextafter(x, math.Inf(-1))
+}
+
+// nudge moves x one unit in the last place in direction d if the exact
+// value x + e lies beyond x in that direction.
+func nudge(x, e float64, d direction) float64 {
+	switch {
+	case d == down && e < 0:
+		return math.Nextafter(x, math.Inf(-1))
+	case d == up && e > 0:
+		return math.Nextafter(x, math.Inf(1))
+	}
+	return x
+}
+
+// round converts a (directed-rounded) float64 to T, rounding again in the
+// same direction when T is float32.
+func round[T float32 | float64](x float64, d direction) T {
+	result := T(x)
+	if float64(result) == x || math.IsNaN(x) {
+		return result
+	}
+	// float32: the conversion rounded to nearest, possibly in the wrong
+	// direction.
+	r := float32(x)
+	switch {
+	case d == down && float64(r) > x:
+		r = math.Nextafter32(r, float32(math.Inf(-1)))
+	case d == up && float64(r) < x:
+		r = math.Nextafter32(r, float32(math.Inf(1)))
+	}
+	return T(r)
+}
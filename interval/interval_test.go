@@ -0,0 +1,208 @@
+package interval
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func TestConstructors(t *testing.T) {
+	if _, err := New(2.0, 1.0); err == nil {
+		t.Fatalf("New(2, 1) should return error")
+	}
+	if _, err := New(math.NaN(), 1.0); err == nil {
+		t.Fatalf("New(NaN, 1) should return error")
+	}
+	if _, err := NewWithError(1.0, -0.5); err == nil {
+		t.Fatalf("NewWithError with negative error should return error")
+	}
+	i, _ := NewWithError(10.0, 0.5)
+	if i.Lo() != 9.5 || i.Hi() != 10.5 || i.Width() != 1 || i.Midpoint() != 10 || i.Radius() != 0.5 {
+		t.Fatalf("NewWithError(10, 0.5) = %v", i)
+	}
+	// 3/4 is exactly representable, 1/3 is not.
+	if i, _ := FromFraction(fraction.MustNew(3, 4)); !i.IsPoint() || i.Lo() != 0.75 {
+		t.Fatalf("FromFraction(3/4) = %v; want [0.75, 0.75]", i)
+	}
+	third := fraction.MustNew(1, 3)
+	i, err := FromFraction(third)
+	if err != nil || i.IsPoint() || math.Nextafter(i.Lo(), 1) != i.Hi() {
+		t.Fatalf("FromFraction(1/3) = %v, %v; want two neighbouring floats", i, err)
+	}
+	if new(big.Rat).SetFloat64(i.Lo()).Cmp(third.Rat()) > 0 || new(big.Rat).SetFloat64(i.Hi()).Cmp(third.Rat()) < 0 {
+		t.Fatalf("FromFraction(1/3) = %v doesn't contain 1/3", i)
+	}
+	if _, err := FromFraction(nil); !errors.Is(err, fraction.ErrNilFraction) {
+		t.Fatalf("FromFraction(nil) error = %v; want %v", err, fraction.ErrNilFraction)
+	}
+	if i, _ := FromFloat(0.1); !i.Contains(0.1) || i.IsPoint() {
+		t.Fatalf("FromFloat(0.1) = %v", i)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a, b, c := MustNew(1.0, 3.0), MustNew(2.0, 5.0), MustNew(4.0, 6.0)
+	if got, ok := a.Intersection(b); !ok || got != MustNew(2.0, 3.0) {
+		t.Fatalf("Intersection = %v, %v; want [2, 3]", got, ok)
+	}
+	if _, ok := a.Intersection(c); ok {
+		t.Fatalf("Intersection of disjoint intervals should return false")
+	}
+	if got, err := a.Union(b); err != nil || got != MustNew(1.0, 5.0) {
+		t.Fatalf("Union = %v, %v; want [1, 5]", got, err)
+	}
+	if _, err := a.Union(c); err == nil {
+		t.Fatalf("Union of disjoint intervals should return error")
+	}
+	if got := a.Hull(c); got != MustNew(1.0, 6.0) || !got.ContainsInterval(b) || b.ContainsInterval(got) {
+		t.Fatalf("Hull = %v; want [1, 6]", got)
+	}
+	if !a.Contains(3) || a.Contains(3.5) || a.String() != "[1, 3]" {
+		t.Fatalf("Contains or String of %v is wrong", a)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustNew(-1.0, 2.0), MustNew(3.0, 4.0)
+	tests := []struct {
+		name      string
+		got, want Interval[float64]
+	}{
+		{"Add", a.Add(b), MustNew(2.0, 6.0)},
+		{"Subtract", a.Subtract(b), MustNew(-5.0, -1.0)},
+		{"Multiply", a.Multiply(b), MustNew(-4.0, 8.0)},
+		{"Square", a.Square(), MustNew(0.0, 4.0)},
+		{"Scale", b.Scale(-2), MustNew(-8.0, -6.0)},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Fatalf("%s = %v; want %v", test.name, test.got, test.want)
+		}
+	}
+	// The endpoints -1/3 and 2/3 are inexact, so they are rounded outward.
+	if got, err := a.Divide(b); err != nil || got.Lo() >= -1.0/3 || got.Hi() <= 2.0/3 {
+		t.Fatalf("Divide = %v, %v; want an enclosure of [-1/3, 2/3]", got, err)
+	}
+	if _, err := b.Divide(a); err == nil {
+		t.Fatalf("Divide by an interval containing zero should return error")
+	}
+}
+
+func TestOutwardRounding(t *testing.T) {
+	// 0.1 + 0.2 rounds to 0.30000000000000004 in float64; the interval must
+	// contain the exact sum of the two float64 values.
+	x, y := Point(0.1), Point(0.2)
+	sum := x.Add(y)
+	exact := new(big.Rat).Add(new(big.Rat).SetFloat64(0.1), new(big.Rat).SetFloat64(0.2))
+	lo, hi := new(big.Rat).SetFloat64(sum.Lo()), new(big.Rat).SetFloat64(sum.Hi())
+	if lo.Cmp(exact) > 0 || hi.Cmp(exact) < 0 || sum.IsPoint() {
+		t.Fatalf("0.1 + 0.2 = %v doesn't enclose the exact sum", sum)
+	}
+	// Exact operations stay tight.
+	if got := Point(0.5).Add(Point(0.25)).Multiply(Point(4.0)); !got.IsPoint() || got.Lo() != 3 {
+		t.Fatalf("(0.5 + 0.25) · 4 = %v; want [3, 3]", got)
+	}
+	// float32 endpoints are rounded outward as well.
+	third, _ := Point[float32](1).Divide(Point[float32](3))
+	if third.IsPoint() || float64(third.Lo()) > 1.0/3 || float64(third.Hi()) < 1.0/3 {
+		t.Fatalf("1/3 in float32 = %v", third)
+	}
+	// Summing 0.1 ten times encloses 1 even though the float64 sum doesn't
+	// equal 1.
+	total, naive := Point(0.0), 0.0
+	tenth, _ := FromFraction(fraction.MustNew(1, 10))
+	for range 10 {
+		total = total.Add(tenth)
+		naive += 0.1
+	}
+	if !total.Contains(1) || naive == 1 {
+		t.Fatalf("Sum of ten times 1/10 = %v (naive %v); want an enclosure of 1", total, naive)
+	}
+	// (1 + 2⁻⁵²)·2⁻⁵³⁷ squared is 2⁻¹⁰⁷⁴ + 2⁻¹¹²⁶: the rounding error is below
+	// the smallest subnormal, so the FMA error underflows to 0.
+	a := Point(0x1.0000000000001p-537)
+	b := Point(0x1p-537)
+	if got := a.Multiply(b); got.Lo() > 0x1p-1074 || got.Hi() <= 0x1p-1074 {
+		t.Fatalf("Subnormal product = %v; want an enclosure of 2⁻¹⁰⁷⁴ + 2⁻¹¹²⁶", got)
+	}
+	if got, _ := Point(0x1.8p-1073).Divide(Point(1.5)); !got.Contains(0x1p-1073) {
+		t.Fatalf("Subnormal quotient = %v; want an enclosure of 2⁻¹⁰⁷³", got)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	// The exact results are finite, so only the endpoint rounded away from
+	// zero may overflow to infinity.
+	largest, inf := math.MaxFloat64, math.Inf(1)
+	tests := []struct {
+		name      string
+		got, want Interval[float64]
+	}{
+		{"Add", MustNew(largest, largest).Add(MustNew(largest, largest)), MustNew(largest, inf)},
+		{"Add negative", MustNew(-largest, -largest).Add(MustNew(-largest, -largest)), MustNew(-inf, -largest)},
+		{"Multiply", MustNew(1e200, 1e200).Multiply(MustNew(1e200, 1e200)), MustNew(largest, inf)},
+		{"Multiply negative", MustNew(-1e200, -1e200).Multiply(MustNew(1e200, 1e200)), MustNew(-inf, -largest)},
+		{"Square", MustNew(1e200, 1e200).Square(), MustNew(largest, inf)},
+		{"Scale", Point(1e300).Scale(1e10), MustNew(largest, inf)},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Fatalf("%s = %v; want %v", test.name, test.got, test.want)
+		}
+	}
+	if got, err := Point(1e300).Divide(Point(1e-300)); err != nil || got != MustNew(largest, inf) {
+		t.Fatalf("Divide = %v, %v; want [%v, +Inf]", got, err, largest)
+	}
+	// float32 endpoints overflow the same way.
+	if got := Point[float32](math.MaxFloat32).Add(Point[float32](math.MaxFloat32)); got.Lo() != math.MaxFloat32 || !math.IsInf(float64(got.Hi()), 1) {
+		t.Fatalf("float32 Add = %v; want [%v, +Inf]", got, float32(math.MaxFloat32))
+	}
+	// Infinite endpoints stay infinite.
+	if got := MustNew(1, inf).Add(Point(1.0)); got != MustNew(2, inf) {
+		t.Fatalf("[1, +Inf] + 1 = %v; want [2, +Inf]", got)
+	}
+}
+
+func TestUnbounded(t *testing.T) {
+	// 0 · ±Inf is 0 for unbounded intervals (IEEE 1788), not NaN.
+	inf := math.Inf(1)
+	tests := []struct {
+		name      string
+		got, want Interval[float64]
+	}{
+		{"[0, 1] · [1, +Inf]", MustNew(0, 1.0).Multiply(MustNew(1, inf)), MustNew(0, inf)},
+		{"[-1, 1] · [0, +Inf]", MustNew(-1, 1.0).Multiply(MustNew(0, inf)), MustNew(-inf, inf)},
+		{"[-Inf, 0] · [0, 2]", MustNew(-inf, 0).Multiply(MustNew(0, 2.0)), MustNew(-inf, 0)},
+		{"0 · [1, +Inf]", MustNew(1, inf).Scale(0), Point(0.0)},
+		{"[0, +Inf]²", MustNew(0, inf).Square(), MustNew(0, inf)},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Fatalf("%s = %v; want %v", test.name, test.got, test.want)
+		}
+	}
+	midpoints := []struct {
+		interval Interval[float64]
+		want     float64
+	}{
+		{MustNew(-inf, inf), 0},
+		{MustNew(-inf, -2), -2},
+		{MustNew(3, inf), 3},
+		{MustNew(inf, inf), inf},
+	}
+	for _, test := range midpoints {
+		if got := test.interval.Midpoint(); got != test.want {
+			t.Fatalf("%v.Midpoint() = %v; want %v", test.interval, got, test.want)
+		}
+	}
+	// ±Inf / ±Inf encloses every quotient between 0 and ±Inf.
+	if got, err := MustNew(1, inf).Divide(MustNew(1, inf)); err != nil || got != MustNew(0, inf) {
+		t.Fatalf("[1, +Inf] / [1, +Inf] = %v, %v; want [0, +Inf]", got, err)
+	}
+	if got, err := MustNew(-inf, -1).Divide(MustNew(1, inf)); err != nil || got != MustNew(-inf, 0) {
+		t.Fatalf("[-Inf, -1] / [1, +Inf] = %v, %v; want [-Inf, 0]", got, err)
+	}
+}
@@ -0,0 +1,265 @@
+// Package intset provides a Set of non-negative integers backed by a dense
+// bitset: one bit per possible element. It is compact and fast for sets of
+// small to medium integers, like sieve outputs and the index sets used in
+// combinatorial algorithms.
+//
+// Important details:
+//
+// (*) Memory use is proportional to the largest element, not to the number
+// of elements: a Set containing only 1e9 uses ~125 MB.
+//
+// (*) Negative values can never be elements: Add ignores them and Contains
+// returns false.
+//
+// (*) Like the Fraction type, the set operations (Union, Intersection, ...)
+// modify the receiver in-place and return it to allow chaining. Call Clone()
+// first when an independent copy is needed.
+package intset
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"strings"
+)
+
+const wordSize = 64
+
+// Set is a set of non-negative integers. The zero value is an empty set
+// ready to use.
+type Set struct {
+	words []uint64
+}
+
+// New is a constructor function that returns a pointer to a Set containing
+// the specified values. Negative values are ignored.
+func New(values ...int) *Set {
+	return (&Set{}).Add(values...)
+}
+
+// NewRange is a constructor function that returns a pointer to a Set
+// containing all integers in [lo, hi). Negative values are ignored.
+func NewRange(lo, hi int) *Set {
+	s := &Set{}
+	lo = max(lo, 0)
+	for x := lo; x < hi; x++ {
+		s.Add(x)
+	}
+	return s
+}
+
+// ============================================================================
+// Elements
+// ============================================================================
+
+// Add adds the specified values to the Set (in-place) and returns it.
+// Negative values are ignored.
+func (s *Set) Add(values ...int) *Set {
+	for _, x := range values {
+		if x < 0 {
+			continue
+		}
+		word := x / wordSize
+		if word >= len(s.words) {
+			s.words = append(s.words, make([]uint64, word+1-len(s.words))...)
+		}
+		s.words[word] |= 1 << uint(x%wordSize)
+	}
+	return s
+}
+
+// Remove removes the specified values from the Set (in-place) and returns it.
+func (s *Set) Remove(values ...int) *Set {
+	for _, x := range values {
+		if x < 0 || x/wordSize >= len(s.words) {
+			continue
+		}
+		s.words[x/wordSize] &^= 1 << uint(x%wordSize)
+	}
+	return s.trim()
+}
+
+// Contains checks if the specified value is an element of the Set.
+func (s *Set) Contains(x int) bool {
+	if x < 0 || x/wordSize >= len(s.words) {
+		return false
+	}
+	return s.words[x/wordSize]&(1<<uint(x%wordSize)) != 0
+}
+
+// Len returns the number of elements (the cardinality) of the Set.
+func (s *Set) Len() int {
+	count := 0
+	for _, word := range s.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// IsEmpty checks if the Set has no elements.
+func (s *Set) IsEmpty() bool {
+	return len(s.trim().words) == 0
+}
+
+// Min returns the smallest element of the Set and a boolean value that
+// indicates if the Set is not empty.
+func (s *Set) Min() (int, bool) {
+	for i, word := range s.words {
+		if word != 0 {
+			return i*wordSize + bits.TrailingZeros64(word), true
+		}
+	}
+	return 0, false
+}
+
+// Max returns the largest element of the Set and a boolean value that
+// indicates if the Set is not empty.
+func (s *Set) Max() (int, bool) {
+	for i := len(s.words) - 1; i >= 0; i-- {
+		if word := s.words[i]; word != 0 {
+			return i*wordSize + wordSize - 1 - bits.LeadingZeros64(word), true
+		}
+	}
+	return 0, false
+}
+
+// Clear removes all elements from the Set (in-place) and returns it.
+func (s *Set) Clear() *Set {
+	s.words = nil
+	return s
+}
+
+// Clone returns an independent copy of the Set.
+func (s *Set) Clone() *Set {
+	return &Set{words: append([]uint64(nil), s.words...)}
+}
+
+// ============================================================================
+// Set operations
+// ============================================================================
+
+// Union adds all elements of the specified Set to the current Set (in-place)
+// and returns it.
+func (s *Set) Union(other *Set) *Set {
+	if len(other.words) > len(s.words) {
+		s.words = append(s.words, make([]uint64, len(other.words)-len(s.words))...)
+	}
+	for i, word := range other.words {
+		s.words[i] |= word
+	}
+	return s
+}
+
+// Intersection removes all elements from the current Set that are not in the
+// specified Set (in-place) and returns it.
+func (s *Set) Intersection(other *Set) *Set {
+	for i := range s.words {
+		if i < len(other.words) {
+			s.words[i] &= other.words[i]
+		} else {
+			s.words[i] = 0
+		}
+	}
+	return s.trim()
+}
+
+// Difference removes all elements of the specified Set from the current Set
+// (in-place) and returns it.
+func (s *Set) Difference(other *Set) *Set {
+	for i := range s.words {
+		if i < len(other.words) {
+			s.words[i] &^= other.words[i]
+		}
+	}
+	return s.trim()
+}
+
+// SymmetricDifference keeps the elements that are in exactly one of both
+// Sets (in-place) and returns the current Set.
+func (s *Set) SymmetricDifference(other *Set) *Set {
+	if len(other.words) > len(s.words) {
+		s.words = append(s.words, make([]uint64, len(other.words)-len(s.words))...)
+	}
+	for i, word := range other.words {
+		s.words[i] ^= word
+	}
+	return s.trim()
+}
+
+// ComplementInRange returns a new Set with all integers in [lo, hi) that are
+// not elements of the current Set.
+func (s *Set) ComplementInRange(lo, hi int) *Set {
+	return NewRange(lo, hi).Difference(s)
+}
+
+// IsSubsetOf checks if every element of the current Set is also an element
+// of the specified Set.
+func (s *Set) IsSubsetOf(other *Set) bool {
+	for i, word := range s.words {
+		var otherWord uint64
+		if i < len(other.words) {
+			otherWord = other.words[i]
+		}
+		if word&^otherWord != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks if both Sets have exactly the same elements.
+func (s *Set) Equal(other *Set) bool {
+	return s.IsSubsetOf(other) && other.IsSubsetOf(s)
+}
+
+// ============================================================================
+// Iteration and formatting
+// ============================================================================
+
+// All returns an iterator over the elements of the Set in increasing order.
+func (s *Set) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, word := range s.words {
+			for word != 0 {
+				bit := bits.TrailingZeros64(word)
+				if !yield(i*wordSize + bit) {
+					return
+				}
+				// Clear the lowest set bit
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// Slice returns the elements of the Set in increasing order.
+func (s *Set) Slice() []int {
+	result := make([]int, 0, s.Len())
+	for x := range s.All() {
+		result = append(result, x)
+	}
+	return result
+}
+
+// String implements the fmt.Stringer interface and returns the elements of
+// the Set in increasing order, e.g. "{1, 2, 3}".
+func (s *Set) String() string {
+	if s == nil {
+		return "{}"
+	}
+	elements := make([]string, 0, s.Len())
+	for x := range s.All() {
+		elements = append(elements, fmt.Sprintf("%d", x))
+	}
+	return "{" + strings.Join(elements, ", ") + "}"
+}
+
+// trim removes trailing zero words so Len, Max and Equal stay cheap.
+func (s *Set) trim() *Set {
+	n := len(s.words)
+	for n > 0 && s.words[n-1] == 0 {
+		n--
+	}
+	s.words = s.words[:n]
+	return s
+}
@@ -0,0 +1,76 @@
+package intset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestElements(t *testing.T) {
+	s := New(3, 1, 200, -5, 3)
+	if got := s.Slice(); !reflect.DeepEqual(got, []int{1, 3, 200}) {
+		t.Fatalf("Slice() = %v; want [1 3 200]", got)
+	}
+	if s.Len() != 3 || !s.Contains(200) || s.Contains(2) || s.Contains(-5) {
+		t.Fatalf("Len/Contains mismatch for %v", s)
+	}
+	if lo, ok := s.Min(); !ok || lo != 1 {
+		t.Fatalf("Min() = %d, %v; want 1, true", lo, ok)
+	}
+	if hi, ok := s.Max(); !ok || hi != 200 {
+		t.Fatalf("Max() = %d, %v; want 200, true", hi, ok)
+	}
+	s.Remove(200, 1000)
+	if hi, _ := s.Max(); hi != 3 {
+		t.Fatalf("Max() after Remove = %d; want 3", hi)
+	}
+	if !New().IsEmpty() || !New(1).Remove(1).IsEmpty() {
+		t.Fatalf("IsEmpty() = false; want true")
+	}
+	if s := New(1, 64, 65).String(); s != "{1, 64, 65}" {
+		t.Fatalf("String() = %q; want \"{1, 64, 65}\"", s)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := New(1, 2, 3, 100)
+	b := New(2, 3, 4)
+	cases := []struct {
+		name string
+		got  *Set
+		want []int
+	}{
+		{"Union", a.Clone().Union(b), []int{1, 2, 3, 4, 100}},
+		{"Intersection", a.Clone().Intersection(b), []int{2, 3}},
+		{"Difference", a.Clone().Difference(b), []int{1, 100}},
+		{"SymmetricDifference", a.Clone().SymmetricDifference(b), []int{1, 4, 100}},
+		{"ComplementInRange", b.ComplementInRange(0, 6), []int{0, 1, 5}},
+	}
+	for _, c := range cases {
+		if got := c.got.Slice(); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("%s = %v; want %v", c.name, got, c.want)
+		}
+	}
+	if a.Len() != 4 {
+		t.Fatalf("Clone() did not protect the original: %v", a)
+	}
+	if !New(2, 3).IsSubsetOf(b) || b.IsSubsetOf(a) {
+		t.Fatalf("IsSubsetOf mismatch")
+	}
+	if !a.Clone().Union(New(500)).Remove(500).Equal(a) {
+		t.Fatalf("Equal() = false; want true")
+	}
+}
+
+func TestIteration(t *testing.T) {
+	s := NewRange(60, 70)
+	sum := 0
+	for x := range s.All() {
+		if x == 68 {
+			break
+		}
+		sum += x
+	}
+	if sum != 60+61+62+63+64+65+66+67 {
+		t.Fatalf("sum of elements before 68 = %d", sum)
+	}
+}
@@ -0,0 +1,320 @@
+// Package linsolve solves systems of linear equations Ax = b exactly, using
+// Gauss-Jordan elimination over fractions, and computes exact determinants and
+// inverses of square matrices. Because no floating point numbers are involved,
+// the rank and the structure of the solution space are always determined
+// correctly, which makes the package well suited for teaching and for exact
+// engineering checks.
+//
+// The general solution of a consistent system is returned as a particular
+// solution plus a basis of the solution space of the homogeneous system
+// Ax = 0 (the null space of A): x = particular + t1*n1 + t2*n2 + ...
+//
+// Important details:
+//
+// (*) Matrices are passed as row slices ([][]*fraction.Fraction). The input
+// is never modified.
+//
+// (*) All returned fractions are simplified.
+package linsolve
+
+import (
+	"errors"
+
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/realnum"
+)
+
+// Solution describes the complete solution set of a linear system.
+type Solution struct {
+	// Rank is the rank of the coefficient matrix A.
+	Rank int
+	// Consistent is false if the system has no solution. In that case
+	// Particular is nil.
+	Consistent bool
+	// Particular is one solution of Ax = b (with all free variables zero).
+	Particular []*fraction.Fraction
+	// Nullspace is a basis of the solutions of Ax = 0: one vector per free
+	// variable.
+	Nullspace [][]*fraction.Fraction
+}
+
+// Dimension returns the dimension of the solution space: the number of free
+// variables. Returns -1 if the system is inconsistent.
+func (s *Solution) Dimension() int {
+	if !s.Consistent {
+		return -1
+	}
+	return len(s.Nullspace)
+}
+
+// IsUnique checks if the system has exactly one solution.
+func (s *Solution) IsUnique() bool {
+	return s.Consistent && len(s.Nullspace) == 0
+}
+
+// ============================================================================
+// Solvers
+// ============================================================================
+
+// Solve solves the linear system Ax = b exactly. Returns an error if A is
+// empty, if the rows of A have different lengths, if the length of b doesn't
+// match the number of rows or if any element is nil. An inconsistent system is
+// not an error: check the Consistent field of the Solution.
+func Solve(a [][]*fraction.Fraction, b []*fraction.Fraction) (*Solution, error) {
+	if err := validate(a); err != nil {
+		return nil, err
+	}
+	if len(b) != len(a) {
+		return nil, errors.New("right-hand side length must equal the number of rows")
+	}
+	for _, value := range b {
+		if value == nil {
+			return nil, fraction.ErrNilFraction
+		}
+	}
+	rows, cols := len(a), len(a[0])
+	// Augmented matrix [A | b]
+	augmented := make([][]*fraction.Fraction, rows)
+	for i, row := range a {
+		augmented[i] = make([]*fraction.Fraction, cols+1)
+		for j, value := range row {
+			augmented[i][j] = value.Clone()
+		}
+		augmented[i][cols] = b[i].Clone()
+	}
+	pivots := reduce(augmented, cols)
+	solution := &Solution{Rank: len(pivots), Consistent: true}
+	// Inconsistent if a zero row of A has a non-zero right-hand side.
+	for i := len(pivots); i < rows; i++ {
+		if !isZero(augmented[i][cols]) {
+			solution.Consistent = false
+			return solution, nil
+		}
+	}
+	solution.Particular = zeros(cols)
+	for i, pivot := range pivots {
+		solution.Particular[pivot] = augmented[i][cols]
+	}
+	solution.Nullspace = nullspace(augmented, pivots, cols)
+	return solution, nil
+}
+
+// SolveInts is identical to Solve but accepts integer coefficients.
+func SolveInts(a [][]int, b []int) (*Solution, error) {
+	fa := FromInts(a)
+	fb := make([]*fraction.Fraction, len(b))
+	for i, value := range b {
+		fb[i] = fraction.NewFromNumber(value)
+	}
+	return Solve(fa, fb)
+}
+
+// Rank returns the rank of the matrix A. Returns an error if A is empty, if
+// the rows have different lengths or if any element is nil.
+func Rank(a [][]*fraction.Fraction) (int, error) {
+	if err := validate(a); err != nil {
+		return 0, err
+	}
+	return len(reduce(copyMatrix(a), len(a[0]))), nil
+}
+
+// Nullspace returns a basis of the solutions of Ax = 0. Returns an error if A
+// is empty, if the rows have different lengths or if any element is nil.
+func Nullspace(a [][]*fraction.Fraction) ([][]*fraction.Fraction, error) {
+	if err := validate(a); err != nil {
+		return nil, err
+	}
+	m := copyMatrix(a)
+	pivots := reduce(m, len(a[0]))
+	return nullspace(m, pivots, len(a[0])), nil
+}
+
+// ============================================================================
+// Determinant and inverse
+// ============================================================================
+
+// Determinant returns the exact determinant of the square matrix A, computed
+// with the generic realnum.Determinant over realnum.Fraction values. Returns an
+// error if A is empty or not square, or if any element is nil.
+func Determinant(a [][]*fraction.Fraction) (*fraction.Fraction, error) {
+	if err := validateSquare(a); err != nil {
+		return nil, err
+	}
+	determinant, err := realnum.Determinant(toReal(a))
+	if err != nil {
+		return nil, err
+	}
+	return determinant.Value(), nil
+}
+
+// Inverse returns the exact inverse of the square matrix A, computed with the
+// generic realnum.Inverse over realnum.Fraction values. Returns an error if A
+// is empty or not square, or if any element is nil, and wbmath.ErrSingular if
+// A is singular.
+func Inverse(a [][]*fraction.Fraction) ([][]*fraction.Fraction, error) {
+	if err := validateSquare(a); err != nil {
+		return nil, err
+	}
+	inverse, err := realnum.Inverse(toReal(a))
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]*fraction.Fraction, len(inverse))
+	for i, row := range inverse {
+		result[i] = make([]*fraction.Fraction, len(row))
+		for j, value := range row {
+			result[i][j] = value.Value()
+		}
+	}
+	return result, nil
+}
+
+// FromInts converts a matrix with integer elements to a matrix of fractions,
+// e.g. to pass it to Determinant or Inverse.
+func FromInts(a [][]int) [][]*fraction.Fraction {
+	result := make([][]*fraction.Fraction, len(a))
+	for i, row := range a {
+		result[i] = make([]*fraction.Fraction, len(row))
+		for j, value := range row {
+			result[i][j] = fraction.NewFromNumber(value)
+		}
+	}
+	return result
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// reduce transforms the first `cols` columns of m to reduced row echelon form
+// (in-place, applying the row operations to all columns) and returns the
+// pivot column of every non-zero row.
+func reduce(m [][]*fraction.Fraction, cols int) []int {
+	var pivots []int
+	row := 0
+	for col := 0; col < cols && row < len(m); col++ {
+		// Find a row with a non-zero entry in this column.
+		pivotRow := -1
+		for i := row; i < len(m); i++ {
+			if !isZero(m[i][col]) {
+				pivotRow = i
+				break
+			}
+		}
+		if pivotRow == -1 {
+			continue
+		}
+		m[row], m[pivotRow] = m[pivotRow], m[row]
+		// Scale the pivot row so the pivot becomes 1.
+		pivot := m[row][col].Clone()
+		for j := range m[row] {
+			m[row][j].Divide(pivot).Simplify()
+		}
+		// Eliminate the column from all other rows.
+		for i := range m {
+			if i == row || isZero(m[i][col]) {
+				continue
+			}
+			factor := m[i][col].Clone()
+			for j := range m[i] {
+				m[i][j].Subtract(m[row][j].Clone().Multiply(factor)).Simplify()
+			}
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+	return pivots
+}
+
+// nullspace returns the null space basis from a matrix in reduced row echelon
+// form: for each free column f, x_f = 1 and x_p = -m[i][f] for the pivot p of
+// row i.
+func nullspace(m [][]*fraction.Fraction, pivots []int, cols int) [][]*fraction.Fraction {
+	isPivot := make([]bool, cols)
+	for _, pivot := range pivots {
+		isPivot[pivot] = true
+	}
+	basis := [][]*fraction.Fraction{}
+	for free := 0; free < cols; free++ {
+		if isPivot[free] {
+			continue
+		}
+		v := zeros(cols)
+		v[free] = fraction.NewFromNumber(1)
+		for i, pivot := range pivots {
+			v[pivot] = m[i][free].Clone().MultiplyInt(-1)
+		}
+		basis = append(basis, v)
+	}
+	return basis
+}
+
+// validate checks that a is a non-empty rectangular matrix without nil
+// elements.
+func validate(a [][]*fraction.Fraction) error {
+	if len(a) == 0 || len(a[0]) == 0 {
+		return errors.New("matrix must not be empty")
+	}
+	for _, row := range a {
+		if len(row) != len(a[0]) {
+			return errors.New("all rows must have the same length")
+		}
+		for _, value := range row {
+			if value == nil {
+				return fraction.ErrNilFraction
+			}
+		}
+	}
+	return nil
+}
+
+// validateSquare checks that a is a non-empty square matrix without nil
+// elements.
+func validateSquare(a [][]*fraction.Fraction) error {
+	if err := validate(a); err != nil {
+		return err
+	}
+	if len(a) != len(a[0]) {
+		return errors.New("matrix must be square")
+	}
+	return nil
+}
+
+// toReal converts a validated matrix to realnum.Fraction values.
+func toReal(a [][]*fraction.Fraction) [][]realnum.Fraction {
+	result := make([][]realnum.Fraction, len(a))
+	for i, row := range a {
+		result[i] = make([]realnum.Fraction, len(row))
+		for j, value := range row {
+			result[i][j], _ = realnum.NewFraction(value)
+		}
+	}
+	return result
+}
+
+// copyMatrix returns a deep copy of m.
+func copyMatrix(m [][]*fraction.Fraction) [][]*fraction.Fraction {
+	result := make([][]*fraction.Fraction, len(m))
+	for i, row := range m {
+		result[i] = make([]*fraction.Fraction, len(row))
+		for j, value := range row {
+			result[i][j] = value.Clone()
+		}
+	}
+	return result
+}
+
+// zeros returns a slice of n zero fractions.
+func zeros(n int) []*fraction.Fraction {
+	result := make([]*fraction.Fraction, n)
+	for i := range result {
+		result[i] = fraction.NewFromNumber(0)
+	}
+	return result
+}
+
+// isZero checks if the Fraction equals zero.
+func isZero(f *fraction.Fraction) bool {
+	numerator, _ := f.Numerator()
+	return numerator == 0
+}
@@ -0,0 +1,117 @@
+package linsolve
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func ratios(fs []*fraction.Fraction) []string {
+	result := make([]string, len(fs))
+	for i, f := range fs {
+		result[i] = f.AsIntegerRatio()
+	}
+	return result
+}
+
+func TestUniqueSolution(t *testing.T) {
+	// 2x + y - z = 8, -3x - y + 2z = -11, -2x + y + 2z = -3 => (2, 3, -1)
+	s, err := SolveInts([][]int{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}}, []int{8, -11, -3})
+	if err != nil {
+		t.Fatalf("SolveInts returned error: %v", err)
+	}
+	if !s.IsUnique() || s.Rank != 3 || s.Dimension() != 0 {
+		t.Fatalf("Solution = %+v; want unique with rank 3", s)
+	}
+	if got := ratios(s.Particular); !reflect.DeepEqual(got, []string{"2/1", "3/1", "-1/1"}) {
+		t.Fatalf("Particular = %v; want [2 3 -1]", got)
+	}
+	// Exact fractions: x + y = 1, x - y = 1/3 => (2/3, 1/3)
+	a := [][]*fraction.Fraction{{fraction.MustNew(1, 1), fraction.MustNew(1, 1)},
+		{fraction.MustNew(1, 1), fraction.MustNew(-1, 1)}}
+	b := []*fraction.Fraction{fraction.MustNew(1, 1), fraction.MustNew(1, 3)}
+	s, _ = Solve(a, b)
+	if got := ratios(s.Particular); !reflect.DeepEqual(got, []string{"2/3", "1/3"}) {
+		t.Fatalf("Particular = %v; want [2/3 1/3]", got)
+	}
+	if a[0][0].AsIntegerRatio() != "1/1" || b[1].AsIntegerRatio() != "1/3" {
+		t.Fatalf("Solve modified its input")
+	}
+}
+
+func TestUnderdeterminedAndInconsistent(t *testing.T) {
+	// x + 2y + 3z = 6, 2x + 4y + 6z = 12 => rank 1, two free variables
+	s, err := SolveInts([][]int{{1, 2, 3}, {2, 4, 6}}, []int{6, 12})
+	if err != nil {
+		t.Fatalf("SolveInts returned error: %v", err)
+	}
+	if !s.Consistent || s.Rank != 1 || s.Dimension() != 2 {
+		t.Fatalf("Solution = %+v; want rank 1 and dimension 2", s)
+	}
+	if got := ratios(s.Particular); !reflect.DeepEqual(got, []string{"6/1", "0/1", "0/1"}) {
+		t.Fatalf("Particular = %v; want [6 0 0]", got)
+	}
+	want := [][]string{{"-2/1", "1/1", "0/1"}, {"-3/1", "0/1", "1/1"}}
+	for i, v := range s.Nullspace {
+		if got := ratios(v); !reflect.DeepEqual(got, want[i]) {
+			t.Fatalf("Nullspace[%d] = %v; want %v", i, got, want[i])
+		}
+	}
+	s, _ = SolveInts([][]int{{1, 1}, {1, 1}}, []int{1, 2})
+	if s.Consistent || s.Dimension() != -1 || s.Particular != nil {
+		t.Fatalf("Solution = %+v; want inconsistent", s)
+	}
+}
+
+func TestRankAndValidation(t *testing.T) {
+	a := [][]*fraction.Fraction{
+		{fraction.MustNew(1, 2), fraction.MustNew(1, 3)},
+		{fraction.MustNew(3, 2), fraction.MustNew(1, 1)},
+	}
+	if rank, err := Rank(a); err != nil || rank != 1 {
+		t.Fatalf("Rank = %d, %v; want 1", rank, err)
+	}
+	if basis, _ := Nullspace(a); len(basis) != 1 || ratios(basis[0])[0] != "-2/3" {
+		t.Fatalf("Nullspace = %v; want [[-2/3 1]]", basis)
+	}
+	if _, err := SolveInts([][]int{{1, 2}, {1}}, []int{1, 2}); err == nil {
+		t.Fatalf("SolveInts with ragged rows should return error")
+	}
+	if _, err := SolveInts([][]int{{1}}, []int{1, 2}); err == nil {
+		t.Fatalf("SolveInts with wrong right-hand side length should return error")
+	}
+}
+
+func TestDeterminantAndInverse(t *testing.T) {
+	a := FromInts([][]int{{0, 2, 1}, {1, 1, 0}, {3, 0, 1}})
+	if det, err := Determinant(a); err != nil || det.AsIntegerRatio() != "-5/1" {
+		t.Fatalf("Determinant = %v, %v; want -5", det, err)
+	}
+	inverse, err := Inverse(a)
+	if err != nil {
+		t.Fatalf("Inverse returned error: %v", err)
+	}
+	want := [][]string{{"-1/5", "2/5", "1/5"}, {"1/5", "3/5", "-1/5"}, {"3/5", "-6/5", "2/5"}}
+	for i, row := range inverse {
+		if got := ratios(row); !reflect.DeepEqual(got, want[i]) {
+			t.Fatalf("Inverse row %d = %v; want %v", i, got, want[i])
+		}
+	}
+	// The input is not modified.
+	if got := ratios(a[0]); !reflect.DeepEqual(got, []string{"0/1", "2/1", "1/1"}) {
+		t.Fatalf("Inverse modified its input: %v", got)
+	}
+	singular := FromInts([][]int{{1, 2}, {2, 4}})
+	if det, _ := Determinant(singular); det.AsIntegerRatio() != "0/1" {
+		t.Fatalf("Determinant of singular matrix = %v; want 0", det)
+	}
+	if _, err := Inverse(singular); !errors.Is(err, wbmath.ErrSingular) {
+		t.Fatalf("Inverse of singular matrix returned %v; want ErrSingular", err)
+	}
+	if _, err := Determinant(FromInts([][]int{{1, 2}})); err == nil {
+		t.Fatalf("Determinant of non-square matrix should return error")
+	}
+}
@@ -0,0 +1,429 @@
+// Package lp solves linear programs exactly with the simplex method over
+// fractions:
+//
+//	maximize (or minimize) c·x
+//	subject to a_i·x <= b_i, a_i·x >= b_i or a_i·x = b_i for every constraint i
+//	and x >= 0
+//
+// Because no floating point numbers are involved, feasibility, optimality
+// and degeneracy are decided exactly: there are no tolerances, and an
+// optimal solution like x = 2/3 is returned as exactly 2/3. This makes the
+// package well suited for small resource allocation and diet problems and
+// for teaching.
+//
+// The two-phase simplex method is used: phase one finds a feasible basis
+// with artificial variables, phase two optimizes the objective. Bland's rule
+// selects the pivots, which guarantees termination (no cycling).
+//
+// Important details:
+//
+// (*) All variables are non-negative. Model a free variable x as the
+// difference x⁺ - x⁻ of two non-negative variables.
+//
+// (*) Infeasible and unbounded problems are not errors: check the Status
+// field of the Result.
+//
+// (*) The input fractions are never modified. All returned fractions are
+// simplified.
+package lp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// Relation is the relation between the left and right-hand side of a
+// constraint.
+type Relation int
+
+const (
+	// LessEqual is the constraint a·x <= b.
+	LessEqual Relation = iota
+	// GreaterEqual is the constraint a·x >= b.
+	GreaterEqual
+	// Equal is the constraint a·x = b.
+	Equal
+)
+
+// Status is the outcome of solving a Problem.
+type Status int
+
+const (
+	// Optimal means an optimal solution was found.
+	Optimal Status = iota
+	// Infeasible means no x satisfies all constraints.
+	Infeasible
+	// Unbounded means the objective can be made arbitrarily good.
+	Unbounded
+)
+
+// String implements the fmt.Stringer interface.
+func (s Status) String() string {
+	switch s {
+	case Optimal:
+		return "optimal"
+	case Infeasible:
+		return "infeasible"
+	case Unbounded:
+		return "unbounded"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Problem is a linear program. Create it with Maximize or Minimize and add
+// constraints with AddConstraint.
+type Problem struct {
+	objective   []*fraction.Fraction
+	minimize    bool
+	constraints []constraint
+}
+
+type constraint struct {
+	coefficients []*fraction.Fraction
+	relation     Relation
+	rhs          *fraction.Fraction
+}
+
+// Result is the solution of a Problem.
+type Result struct {
+	// Status tells if the problem has an optimal solution.
+	Status Status
+	// Value is the optimal value of the objective (nil unless Optimal).
+	Value *fraction.Fraction
+	// X holds the optimal value of every variable (nil unless Optimal).
+	X []*fraction.Fraction
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// Maximize is a constructor function that returns the Problem of maximizing
+// c·x, with one coefficient per variable. Returns an error if no coefficients
+// are specified or if a coefficient is nil.
+func Maximize(c ...*fraction.Fraction) (*Problem, error) {
+	if err := validate(c); err != nil {
+		return nil, err
+	}
+	return &Problem{objective: copyRow(c)}, nil
+}
+
+// Minimize is a constructor function that returns the Problem of minimizing
+// c·x, with one coefficient per variable. Returns an error if no coefficients
+// are specified or if a coefficient is nil.
+func Minimize(c ...*fraction.Fraction) (*Problem, error) {
+	p, err := Maximize(c...)
+	if err != nil {
+		return nil, err
+	}
+	p.minimize = true
+	return p, nil
+}
+
+// AddConstraint adds the constraint a·x <relation> b. Returns an error if
+// the number of coefficients doesn't match the number of variables, if the
+// relation is unknown or if a fraction is nil.
+func (p *Problem) AddConstraint(a []*fraction.Fraction, relation Relation, b *fraction.Fraction) error {
+	if err := validate(a); err != nil {
+		return err
+	}
+	if len(a) != len(p.objective) {
+		return fmt.Errorf("constraint has %d coefficients; want %d", len(a), len(p.objective))
+	}
+	if relation < LessEqual || relation > Equal {
+		return fmt.Errorf("unknown relation %d", relation)
+	}
+	if b == nil {
+		return fraction.ErrNilFraction
+	}
+	p.constraints = append(p.constraints, constraint{coefficients: copyRow(a), relation: relation, rhs: b.Clone().Simplify()})
+	return nil
+}
+
+// ============================================================================
+// Solver
+// ============================================================================
+
+// Solve solves the Problem with the two-phase simplex method.
+func (p *Problem) Solve() *Result {
+	t := newTableau(p)
+	// Phase one: maximize -(sum of the artificial variables). The problem is
+	// feasible if and only if the maximum is zero.
+	if t.artificials > 0 {
+		cost := zeros(t.cols)
+		for j := t.cols - t.artificials; j < t.cols; j++ {
+			cost[j] = fraction.NewFromNumber(-1)
+		}
+		t.setObjective(cost)
+		t.optimize(t.cols)
+		if !isZero(t.objective[t.cols]) {
+			return &Result{Status: Infeasible}
+		}
+		t.removeArtificials()
+	}
+	// Phase two: optimize the objective, never letting an artificial
+	// variable re-enter the basis.
+	cost := zeros(t.cols)
+	for j, c := range p.objective {
+		cost[j] = c.Clone().Simplify()
+		if p.minimize {
+			cost[j].MultiplyInt(-1)
+		}
+	}
+	t.setObjective(cost)
+	if !t.optimize(t.cols - t.artificials) {
+		return &Result{Status: Unbounded}
+	}
+	result := &Result{Status: Optimal, Value: t.objective[t.cols].Clone().Simplify(), X: zeros(len(p.objective))}
+	if p.minimize {
+		result.Value.MultiplyInt(-1)
+	}
+	for i, basic := range t.basis {
+		if basic < len(p.objective) {
+			result.X[basic] = t.rows[i][t.cols].Clone().Simplify()
+		}
+	}
+	return result
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// tableau is a simplex tableau. Every row holds the coefficients of the
+// columns (the original variables, then the slack and surplus variables,
+// then the artificial variables) followed by the right-hand side. The
+// objective row holds the reduced costs and, in the last column, the current
+// value of the objective.
+type tableau struct {
+	rows        [][]*fraction.Fraction
+	objective   []*fraction.Fraction
+	basis       []int // the basic variable of every row
+	cols        int
+	artificials int
+}
+
+// newTableau builds the initial tableau with a feasible basis of slack and
+// artificial variables.
+func newTableau(p *Problem) *tableau {
+	n := len(p.objective)
+	slacks, artificials := 0, 0
+	for _, c := range p.constraints {
+		relation := normalized(c)
+		if relation != Equal {
+			slacks++
+		}
+		if relation != LessEqual {
+			artificials++
+		}
+	}
+	t := &tableau{cols: n + slacks + artificials, artificials: artificials}
+	slack, artificial := n, n+slacks
+	for _, c := range p.constraints {
+		row := zeros(t.cols + 1)
+		sign := 1
+		if isNegative(c.rhs) {
+			sign = -1
+		}
+		for j, a := range c.coefficients {
+			row[j] = a.Clone().Simplify().MultiplyInt(sign)
+		}
+		row[t.cols] = c.rhs.Clone().Simplify().MultiplyInt(sign)
+		switch normalized(c) {
+		case LessEqual:
+			row[slack] = fraction.NewFromNumber(1)
+			t.basis = append(t.basis, slack)
+			slack++
+		case GreaterEqual:
+			row[slack] = fraction.NewFromNumber(-1)
+			slack++
+			fallthrough
+		case Equal:
+			row[artificial] = fraction.NewFromNumber(1)
+			t.basis = append(t.basis, artificial)
+			artificial++
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+// normalized returns the relation of the constraint after multiplying it by
+// -1 if the right-hand side is negative.
+func normalized(c constraint) Relation {
+	if !isNegative(c.rhs) || c.relation == Equal {
+		return c.relation
+	}
+	if c.relation == LessEqual {
+		return GreaterEqual
+	}
+	return LessEqual
+}
+
+// setObjective sets the objective row for maximizing cost·x: the reduced
+// costs are -cost, corrected for the current basis.
+func (t *tableau) setObjective(cost []*fraction.Fraction) {
+	t.objective = zeros(t.cols + 1)
+	for j, c := range cost {
+		t.objective[j] = c.Clone().Simplify().MultiplyInt(-1)
+	}
+	for i, basic := range t.basis {
+		if !isZero(t.objective[basic]) {
+			subtractRow(t.objective, t.rows[i], t.objective[basic].Clone().Simplify())
+		}
+	}
+}
+
+// optimize runs the simplex iterations with Bland's rule, letting only the
+// first `allowed` columns enter the basis. Returns false if the objective is
+// unbounded.
+func (t *tableau) optimize(allowed int) bool {
+	for {
+		entering := -1
+		for j := 0; j < allowed; j++ {
+			if isNegative(t.objective[j]) {
+				entering = j
+				break
+			}
+		}
+		if entering == -1 {
+			return true
+		}
+		leaving := -1
+		var best *fraction.Fraction
+		for i, row := range t.rows {
+			if !isPositive(row[entering]) {
+				continue
+			}
+			ratio := row[t.cols].Clone().Simplify().Divide(row[entering]).Simplify()
+			if leaving == -1 {
+				leaving, best = i, ratio
+				continue
+			}
+			switch compare(ratio, best) {
+			case -1:
+				leaving, best = i, ratio
+			case 0:
+				if t.basis[i] < t.basis[leaving] {
+					leaving = i
+				}
+			}
+		}
+		if leaving == -1 {
+			return false
+		}
+		t.pivot(leaving, entering)
+	}
+}
+
+// pivot makes column `col` basic in row `row`.
+func (t *tableau) pivot(row, col int) {
+	pivot := t.rows[row][col].Clone().Simplify()
+	for _, value := range t.rows[row] {
+		value.Divide(pivot).Simplify()
+	}
+	for i, other := range t.rows {
+		if i != row && !isZero(other[col]) {
+			subtractRow(other, t.rows[row], other[col].Clone().Simplify())
+		}
+	}
+	if !isZero(t.objective[col]) {
+		subtractRow(t.objective, t.rows[row], t.objective[col].Clone().Simplify())
+	}
+	t.basis[row] = col
+}
+
+// removeArtificials pivots the artificial variables that are still basic (at
+// zero level) out of the basis after phase one. A row in which no other
+// variable can be pivoted in is redundant and is removed.
+func (t *tableau) removeArtificials() {
+	first := t.cols - t.artificials
+	for i := 0; i < len(t.rows); i++ {
+		if t.basis[i] < first {
+			continue
+		}
+		col := -1
+		for j := 0; j < first; j++ {
+			if !isZero(t.rows[i][j]) {
+				col = j
+				break
+			}
+		}
+		if col == -1 {
+			t.rows = append(t.rows[:i], t.rows[i+1:]...)
+			t.basis = append(t.basis[:i], t.basis[i+1:]...)
+			i--
+			continue
+		}
+		t.pivot(i, col)
+	}
+}
+
+// subtractRow subtracts factor·source from target in-place.
+func subtractRow(target, source []*fraction.Fraction, factor *fraction.Fraction) {
+	for j := range target {
+		if !isZero(source[j]) {
+			target[j].Subtract(source[j].Clone().Simplify().Multiply(factor)).Simplify()
+		}
+	}
+}
+
+// compare returns -1, 0 or 1 if a is smaller than, equal to or larger than b.
+func compare(a, b *fraction.Fraction) int {
+	difference := a.Clone().Simplify().Subtract(b)
+	switch {
+	case isNegative(difference):
+		return -1
+	case isZero(difference):
+		return 0
+	}
+	return 1
+}
+
+// validate checks that the row is non-empty and has no nil elements.
+func validate(row []*fraction.Fraction) error {
+	if len(row) == 0 {
+		return errors.New("no coefficients specified")
+	}
+	for _, value := range row {
+		if value == nil {
+			return fraction.ErrNilFraction
+		}
+	}
+	return nil
+}
+
+// copyRow returns a deep copy of the row.
+func copyRow(row []*fraction.Fraction) []*fraction.Fraction {
+	result := make([]*fraction.Fraction, len(row))
+	for i, value := range row {
+		result[i] = value.Clone().Simplify()
+	}
+	return result
+}
+
+// zeros returns a slice of n zero fractions.
+func zeros(n int) []*fraction.Fraction {
+	result := make([]*fraction.Fraction, n)
+	for i := range result {
+		result[i] = fraction.NewFromNumber(0)
+	}
+	return result
+}
+
+func isZero(f *fraction.Fraction) bool {
+	numerator, _ := f.Numerator()
+	return numerator == 0
+}
+
+func isNegative(f *fraction.Fraction) bool {
+	numerator, _ := f.Numerator()
+	return numerator < 0
+}
+
+func isPositive(f *fraction.Fraction) bool {
+	numerator, _ := f.Numerator()
+	return numerator > 0
+}
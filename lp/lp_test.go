@@ -0,0 +1,112 @@
+package lp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func ints(values ...int) []*fraction.Fraction {
+	result := make([]*fraction.Fraction, len(values))
+	for i, value := range values {
+		result[i] = fraction.NewFromNumber(value)
+	}
+	return result
+}
+
+func ratios(fs []*fraction.Fraction) []string {
+	result := make([]string, len(fs))
+	for i, f := range fs {
+		result[i] = f.AsIntegerRatio()
+	}
+	return result
+}
+
+func mustAdd(t *testing.T, p *Problem, a []*fraction.Fraction, relation Relation, b int) {
+	t.Helper()
+	if err := p.AddConstraint(a, relation, fraction.NewFromNumber(b)); err != nil {
+		t.Fatalf("AddConstraint returned error: %v", err)
+	}
+}
+
+func TestMaximize(t *testing.T) {
+	p, _ := Maximize(ints(3, 5)...)
+	mustAdd(t, p, ints(1, 0), LessEqual, 4)
+	mustAdd(t, p, ints(0, 2), LessEqual, 12)
+	mustAdd(t, p, ints(3, 2), LessEqual, 18)
+	result := p.Solve()
+	if result.Status != Optimal || result.Value.AsIntegerRatio() != "36/1" {
+		t.Fatalf("Solve() = %v, %v; want optimal, 36/1", result.Status, result.Value)
+	}
+	if got, want := ratios(result.X), []string{"2/1", "6/1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("X = %v; want %v", got, want)
+	}
+
+	// An optimum at a fractional vertex is found exactly.
+	p, _ = Maximize(ints(1, 1)...)
+	mustAdd(t, p, ints(2, 1), LessEqual, 2)
+	mustAdd(t, p, ints(1, 3), LessEqual, 2)
+	result = p.Solve()
+	if got, want := ratios(append(result.X, result.Value)), []string{"4/5", "2/5", "6/5"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("X, Value = %v; want %v", got, want)
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	// A diet problem: reach 4 and 6 units of two nutrients at minimal cost.
+	p, _ := Minimize(ints(2, 3)...)
+	mustAdd(t, p, ints(1, 1), GreaterEqual, 4)
+	mustAdd(t, p, ints(1, 3), GreaterEqual, 6)
+	result := p.Solve()
+	if result.Status != Optimal || result.Value.AsIntegerRatio() != "9/1" {
+		t.Fatalf("Solve() = %v, %v; want optimal, 9/1", result.Status, result.Value)
+	}
+	if got, want := ratios(result.X), []string{"3/1", "1/1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("X = %v; want %v", got, want)
+	}
+
+	// Redundant equalities and negative right-hand sides.
+	p, _ = Minimize(ints(1, 0)...)
+	mustAdd(t, p, ints(1, 1), Equal, 2)
+	mustAdd(t, p, ints(2, 2), Equal, 4)
+	mustAdd(t, p, ints(-1, 0), LessEqual, -1)
+	result = p.Solve()
+	if got, want := ratios(append(result.X, result.Value)), []string{"1/1", "1/1", "1/1"}; result.Status != Optimal || !reflect.DeepEqual(got, want) {
+		t.Fatalf("Solve() = %v, %v; want optimal, %v", result.Status, got, want)
+	}
+}
+
+func TestInfeasibleAndUnbounded(t *testing.T) {
+	p, _ := Maximize(ints(1)...)
+	mustAdd(t, p, ints(1), LessEqual, 1)
+	mustAdd(t, p, ints(1), GreaterEqual, 2)
+	if result := p.Solve(); result.Status != Infeasible || result.X != nil {
+		t.Fatalf("Solve() = %v; want infeasible", result.Status)
+	}
+	p, _ = Maximize(ints(1, 0)...)
+	mustAdd(t, p, ints(1, -1), LessEqual, 1)
+	if result := p.Solve(); result.Status != Unbounded || result.Status.String() != "unbounded" {
+		t.Fatalf("Solve() = %v; want unbounded", result.Status)
+	}
+	if err := p.AddConstraint(ints(1), LessEqual, fraction.NewFromNumber(1)); err == nil {
+		t.Fatalf("AddConstraint with the wrong number of coefficients should return error")
+	}
+	if _, err := Maximize(); err == nil {
+		t.Fatalf("Maximize without coefficients should return error")
+	}
+}
+
+func TestDegenerateCycling(t *testing.T) {
+	// Beale's example cycles with the textbook pivot rule; Bland's rule
+	// terminates with the optimum 5/4.
+	f := fraction.MustNew
+	p, _ := Maximize(f(3, 4), f(-20, 1), f(1, 2), f(-6, 1))
+	p.AddConstraint([]*fraction.Fraction{f(1, 4), f(-8, 1), f(-1, 1), f(9, 1)}, LessEqual, f(0, 1))
+	p.AddConstraint([]*fraction.Fraction{f(1, 2), f(-12, 1), f(-1, 2), f(3, 1)}, LessEqual, f(0, 1))
+	p.AddConstraint([]*fraction.Fraction{f(0, 1), f(0, 1), f(1, 1), f(0, 1)}, LessEqual, f(1, 1))
+	result := p.Solve()
+	if result.Status != Optimal || result.Value.AsIntegerRatio() != "5/4" {
+		t.Fatalf("Solve() = %v, %v; want optimal, 5/4", result.Status, result.Value)
+	}
+}
@@ -0,0 +1,506 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// maxSweeps and maxQRIterations bound the iterative eigenvalue algorithms.
+const (
+	maxSweeps       = 100
+	maxQRIterations = 30
+)
+
+// SymmetricEigen is the eigendecomposition of a symmetric matrix.
+type SymmetricEigen struct {
+	// Values holds the eigenvalues in increasing order.
+	Values vector.Vector[float64]
+	// Vectors holds the orthonormal eigenvectors as columns, in the order
+	// of Values.
+	Vectors *Matrix[float64]
+	// Sweeps is the number of Jacobi sweeps that were needed.
+	Sweeps int
+	// OffDiagonal is the Frobenius norm of the off-diagonal part that was
+	// left after the last sweep.
+	OffDiagonal float64
+	// Condition is the ratio of the largest to the smallest absolute
+	// eigenvalue: the 2-norm condition number (+Inf if singular).
+	Condition float64
+}
+
+// Eigen is the eigendecomposition of a general real matrix. Complex
+// eigenvalues come in conjugate pairs.
+type Eigen struct {
+	// Values holds the eigenvalues, in no particular order.
+	Values []complex128
+	// Vectors[i] is a unit eigenvector for Values[i].
+	Vectors [][]complex128
+	// Iterations is the total number of QR iterations.
+	Iterations int
+	// Residual is the largest ‖A·v - λ·v‖ over all eigenpairs.
+	Residual float64
+	// Condition is the ratio of the largest to the smallest eigenvalue
+	// magnitude (+Inf if singular).
+	Condition float64
+}
+
+// EigenSymmetric computes the eigenvalues and eigenvectors of a symmetric
+// matrix with the cyclic Jacobi method. Returns an error if the matrix is not
+// square or not symmetric, or if the method doesn't converge.
+func EigenSymmetric[T wbmath.SignedNumber](m *Matrix[T]) (*SymmetricEigen, error) {
+	if m.rows != m.cols {
+		return nil, errors.New("matrix must be square")
+	}
+	n := m.rows
+	a := m.float64Rows()
+	scale := 0.0
+	for i := range a {
+		for j := range a[i] {
+			scale = math.Max(scale, math.Abs(a[i][j]))
+		}
+	}
+	for i := range a {
+		for j := 0; j < i; j++ {
+			if math.Abs(a[i][j]-a[j][i]) > 1e-12*scale {
+				return nil, errors.New("matrix must be symmetric")
+			}
+		}
+	}
+	v := identityRows(n)
+	result := &SymmetricEigen{}
+	for ; ; result.Sweeps++ {
+		off, total := 0.0, 0.0
+		for i := range a {
+			for j := range a[i] {
+				total += a[i][j] * a[i][j]
+				if i != j {
+					off += a[i][j] * a[i][j]
+				}
+			}
+		}
+		result.OffDiagonal = math.Sqrt(off)
+		if off <= 1e-30*total || off == 0 {
+			break
+		}
+		if result.Sweeps == maxSweeps {
+			return nil, errors.New("Jacobi method did not converge")
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				// A = Jᵀ·A·J and V = V·J for the rotation J in the (p, q) plane.
+				for k := 0; k < n; k++ {
+					a[k][p], a[k][q] = c*a[k][p]-s*a[k][q], s*a[k][p]+c*a[k][q]
+					v[k][p], v[k][q] = c*v[k][p]-s*v[k][q], s*v[k][p]+c*v[k][q]
+				}
+				for k := 0; k < n; k++ {
+					a[p][k], a[q][k] = c*a[p][k]-s*a[q][k], s*a[p][k]+c*a[q][k]
+				}
+			}
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return a[order[i]][order[i]] < a[order[j]][order[j]] })
+	result.Values = vector.NewFromValue(0.0, n)
+	result.Vectors, _ = New[float64](n, n)
+	for i, k := range order {
+		result.Values[i] = a[k][k]
+		for row := 0; row < n; row++ {
+			result.Vectors.data[row*n+i] = v[row][k]
+		}
+	}
+	result.Condition = condition(len(result.Values), func(i int) float64 { return math.Abs(result.Values[i]) })
+	return result, nil
+}
+
+// EigenGeneral computes the eigenvalues and eigenvectors of a general real
+// square matrix: the matrix is reduced to upper Hessenberg form, the
+// eigenvalues are found with the shifted (Francis double-shift) QR algorithm
+// and the eigenvectors with inverse iteration. Returns an error if the
+// matrix is not square or if the QR algorithm doesn't converge.
+//
+// Inverse iteration finds one eigenvector per distinct eigenvalue: for
+// repeated eigenvalues the returned vectors may coincide.
+func EigenGeneral[T wbmath.SignedNumber](m *Matrix[T]) (*Eigen, error) {
+	if m.rows != m.cols {
+		return nil, errors.New("matrix must be square")
+	}
+	n := m.rows
+	h := m.float64Rows()
+	hessenberg(h)
+	realParts, imaginaryParts, iterations, err := hqr(h)
+	if err != nil {
+		return nil, err
+	}
+	a := m.float64Rows()
+	result := &Eigen{Values: make([]complex128, n), Vectors: make([][]complex128, n), Iterations: iterations}
+	for i := range result.Values {
+		lambda := complex(realParts[i], imaginaryParts[i])
+		result.Values[i] = lambda
+		result.Vectors[i] = inverseIteration(a, lambda)
+		result.Residual = math.Max(result.Residual, residual(a, lambda, result.Vectors[i]))
+	}
+	result.Condition = condition(n, func(i int) float64 { return cmplx.Abs(result.Values[i]) })
+	return result, nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// float64Rows returns a copy of the matrix as rows of float64 values.
+func (m *Matrix[T]) float64Rows() [][]float64 {
+	rows := make([][]float64, m.rows)
+	for i := range rows {
+		rows[i] = make([]float64, m.cols)
+		for j := range rows[i] {
+			rows[i][j] = float64(m.data[i*m.cols+j])
+		}
+	}
+	return rows
+}
+
+func identityRows(n int) [][]float64 {
+	rows := make([][]float64, n)
+	for i := range rows {
+		rows[i] = make([]float64, n)
+		rows[i][i] = 1
+	}
+	return rows
+}
+
+// condition returns max |λ| / min |λ| for the n magnitudes.
+func condition(n int, magnitude func(i int) float64) float64 {
+	if n == 0 {
+		return 1
+	}
+	largest, smallest := 0.0, math.Inf(1)
+	for i := 0; i < n; i++ {
+		largest = math.Max(largest, magnitude(i))
+		smallest = math.Min(smallest, magnitude(i))
+	}
+	if smallest == 0 {
+		return math.Inf(1)
+	}
+	return largest / smallest
+}
+
+// hessenberg reduces a to upper Hessenberg form in-place with stabilized
+// elementary similarity transformations (Gaussian elimination with
+// pivoting).
+func hessenberg(a [][]float64) {
+	n := len(a)
+	for m := 1; m < n-1; m++ {
+		x, pivot := 0.0, m
+		for j := m; j < n; j++ {
+			if math.Abs(a[j][m-1]) > math.Abs(x) {
+				x, pivot = a[j][m-1], j
+			}
+		}
+		if pivot != m {
+			a[pivot], a[m] = a[m], a[pivot]
+			for j := 0; j < n; j++ {
+				a[j][pivot], a[j][m] = a[j][m], a[j][pivot]
+			}
+		}
+		if x == 0 {
+			continue
+		}
+		for i := m + 1; i < n; i++ {
+			y := a[i][m-1] / x
+			if y == 0 {
+				continue
+			}
+			a[i][m-1] = 0
+			for j := m; j < n; j++ {
+				a[i][j] -= y * a[m][j]
+			}
+			for j := 0; j < n; j++ {
+				a[j][m] += y * a[j][i]
+			}
+		}
+	}
+}
+
+// hqr computes the eigenvalues of the upper Hessenberg matrix a with the
+// Francis double-shift QR algorithm (after Numerical Recipes). The matrix is
+// destroyed. Returns the real and imaginary parts and the number of
+// iterations.
+func hqr(a [][]float64) ([]float64, []float64, int, error) {
+	n := len(a)
+	realParts, imaginaryParts := make([]float64, n), make([]float64, n)
+	anorm := 0.0
+	for i := 0; i < n; i++ {
+		for j := max(i-1, 0); j < n; j++ {
+			anorm += math.Abs(a[i][j])
+		}
+	}
+	const eps = 0x1p-52
+	total := 0
+	nn, t := n-1, 0.0
+	for nn >= 0 {
+		its, l := 0, 0
+		for {
+			for l = nn; l > 0; l-- {
+				s := math.Abs(a[l-1][l-1]) + math.Abs(a[l][l])
+				if s == 0 {
+					s = anorm
+				}
+				if math.Abs(a[l][l-1]) <= eps*s {
+					a[l][l-1] = 0
+					break
+				}
+			}
+			x := a[nn][nn]
+			if l == nn {
+				// One root found.
+				realParts[nn] = x + t
+				nn--
+			} else {
+				y := a[nn-1][nn-1]
+				w := a[nn][nn-1] * a[nn-1][nn]
+				if l == nn-1 {
+					// Two roots found.
+					p := 0.5 * (y - x)
+					q := p*p + w
+					z := math.Sqrt(math.Abs(q))
+					x += t
+					if q >= 0 {
+						z = p + math.Copysign(z, p)
+						realParts[nn-1], realParts[nn] = x+z, x+z
+						if z != 0 {
+							realParts[nn] = x - w/z
+						}
+					} else {
+						realParts[nn-1], realParts[nn] = x+p, x+p
+						imaginaryParts[nn-1], imaginaryParts[nn] = -z, z
+					}
+					nn -= 2
+				} else {
+					if its == maxQRIterations {
+						return nil, nil, total, errors.New("QR algorithm did not converge")
+					}
+					if its == 10 || its == 20 {
+						// Exceptional shift.
+						t += x
+						for i := 0; i <= nn; i++ {
+							a[i][i] -= x
+						}
+						s := math.Abs(a[nn][nn-1]) + math.Abs(a[nn-1][nn-2])
+						x = 0.75 * s
+						y = x
+						w = -0.4375 * s * s
+					}
+					its++
+					total++
+					qrStep(a, l, nn, x, y, w)
+				}
+			}
+			if l+1 >= nn {
+				break
+			}
+		}
+	}
+	return realParts, imaginaryParts, total, nil
+}
+
+// qrStep performs one double-shift QR step on rows and columns l..nn.
+func qrStep(a [][]float64, l, nn int, x, y, w float64) {
+	const eps = 0x1p-52
+	var m int
+	var p, q, r, z float64
+	for m = nn - 2; m >= l; m-- {
+		z = a[m][m]
+		r = x - z
+		s := y - z
+		p = (r*s-w)/a[m+1][m] + a[m][m+1]
+		q = a[m+1][m+1] - z - r - s
+		r = a[m+2][m+1]
+		s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+		p, q, r = p/s, q/s, r/s
+		if m == l {
+			break
+		}
+		u := math.Abs(a[m][m-1]) * (math.Abs(q) + math.Abs(r))
+		v := math.Abs(p) * (math.Abs(a[m-1][m-1]) + math.Abs(z) + math.Abs(a[m+1][m+1]))
+		if u <= eps*v {
+			break
+		}
+	}
+	for i := m; i < nn-1; i++ {
+		a[i+2][i] = 0
+		if i != m {
+			a[i+2][i-1] = 0
+		}
+	}
+	for k := m; k < nn; k++ {
+		if k != m {
+			p = a[k][k-1]
+			q = a[k+1][k-1]
+			r = 0
+			if k+1 != nn {
+				r = a[k+2][k-1]
+			}
+			if x = math.Abs(p) + math.Abs(q) + math.Abs(r); x != 0 {
+				p, q, r = p/x, q/x, r/x
+			}
+		}
+		s := math.Copysign(math.Sqrt(p*p+q*q+r*r), p)
+		if s == 0 {
+			continue
+		}
+		if k == m {
+			if l != m {
+				a[k][k-1] = -a[k][k-1]
+			}
+		} else {
+			a[k][k-1] = -s * x
+		}
+		p += s
+		x, y, z = p/s, q/s, r/s
+		q, r = q/p, r/p
+		for j := k; j <= nn; j++ {
+			p = a[k][j] + q*a[k+1][j]
+			if k+1 != nn {
+				p += r * a[k+2][j]
+				a[k+2][j] -= p * z
+			}
+			a[k+1][j] -= p * y
+			a[k][j] -= p * x
+		}
+		for i := l; i <= min(nn, k+3); i++ {
+			p = x*a[i][k] + y*a[i][k+1]
+			if k+1 != nn {
+				p += z * a[i][k+2]
+				a[i][k+2] -= p * r
+			}
+			a[i][k+1] -= p * q
+			a[i][k] -= p
+		}
+	}
+}
+
+// inverseIteration returns a unit eigenvector of a for the eigenvalue
+// lambda by solving (A - μI)·x = b repeatedly with μ slightly off lambda.
+func inverseIteration(a [][]float64, lambda complex128) []complex128 {
+	n := len(a)
+	mu := lambda + complex(1e-10*math.Max(1, cmplx.Abs(lambda)), 0)
+	lu := make([][]complex128, n)
+	for i := range lu {
+		lu[i] = make([]complex128, n)
+		for j := range lu[i] {
+			lu[i][j] = complex(a[i][j], 0)
+		}
+		lu[i][i] -= mu
+	}
+	permutation := complexLU(lu)
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(1/math.Sqrt(float64(n)), float64(i)*1e-3)
+	}
+	for iteration := 0; iteration < 3; iteration++ {
+		x = complexSolve(lu, permutation, x)
+		normalize(x)
+	}
+	return x
+}
+
+// complexLU factorizes a in-place with partial pivoting and returns the row
+// permutation. Zero pivots are replaced by a tiny value, which is what
+// inverse iteration needs.
+func complexLU(a [][]complex128) []int {
+	n := len(a)
+	permutation := make([]int, n)
+	for i := range permutation {
+		permutation[i] = i
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if cmplx.Abs(a[row][col]) > cmplx.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		permutation[col], permutation[pivot] = permutation[pivot], permutation[col]
+		if a[col][col] == 0 {
+			a[col][col] = 1e-300
+		}
+		for row := col + 1; row < n; row++ {
+			a[row][col] /= a[col][col]
+			for k := col + 1; k < n; k++ {
+				a[row][k] -= a[row][col] * a[col][k]
+			}
+		}
+	}
+	return permutation
+}
+
+func complexSolve(lu [][]complex128, permutation []int, b []complex128) []complex128 {
+	n := len(lu)
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = b[permutation[i]]
+		for k := 0; k < i; k++ {
+			x[i] -= lu[i][k] * x[k]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for k := i + 1; k < n; k++ {
+			x[i] -= lu[i][k] * x[k]
+		}
+		x[i] /= lu[i][i]
+	}
+	return x
+}
+
+// normalize scales x to unit length, with its largest element real and
+// positive.
+func normalize(x []complex128) {
+	norm, largest := 0.0, 0
+	for i, value := range x {
+		norm += real(value)*real(value) + imag(value)*imag(value)
+		if cmplx.Abs(value) > cmplx.Abs(x[largest]) {
+			largest = i
+		}
+	}
+	if norm == 0 {
+		return
+	}
+	phase := x[largest] / complex(cmplx.Abs(x[largest]), 0)
+	factor := 1 / (complex(math.Sqrt(norm), 0) * phase)
+	for i := range x {
+		x[i] *= factor
+	}
+}
+
+// residual returns ‖A·v - λ·v‖.
+func residual(a [][]float64, lambda complex128, v []complex128) float64 {
+	sum := 0.0
+	for i := range a {
+		var value complex128
+		for j := range a[i] {
+			value += complex(a[i][j], 0) * v[j]
+		}
+		value -= lambda * v[i]
+		sum += real(value)*real(value) + imag(value)*imag(value)
+	}
+	return math.Sqrt(sum)
+}
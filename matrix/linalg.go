@@ -0,0 +1,193 @@
+package matrix
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// LU is the LU decomposition with partial pivoting of a square matrix A:
+// P·A = L·U, where P is a row permutation.
+type LU struct {
+	// L is unit lower triangular (ones on the diagonal).
+	L *Matrix[float64]
+	// U is upper triangular.
+	U *Matrix[float64]
+	// Permutation[i] is the row of A that is row i of P·A.
+	Permutation []int
+	// Sign is the sign of the permutation: +1 for an even and -1 for an odd
+	// number of row swaps.
+	Sign int
+}
+
+// ============================================================================
+// LU decomposition and linear systems
+// ============================================================================
+
+// LUDecompose computes the LU decomposition of a square matrix with Gaussian
+// elimination with partial pivoting (O(n³)). Returns an error if the matrix is
+// not square, or wbmath.ErrSingular if it is singular to working precision.
+func LUDecompose[T wbmath.SignedNumber](m *Matrix[T]) (*LU, error) {
+	if m.rows != m.cols {
+		return nil, errors.New("matrix must be square")
+	}
+	n := m.rows
+	a := m.float64Rows()
+	permutation, sign, err := eliminate(a)
+	if err != nil {
+		return nil, err
+	}
+	l, _ := Identity[float64](n)
+	u, _ := New[float64](n, n)
+	for i, row := range a {
+		copy(l.data[i*n:i*n+i], row[:i])
+		copy(u.data[i*n+i:(i+1)*n], row[i:])
+	}
+	return &LU{L: l, U: u, Permutation: permutation, Sign: sign}, nil
+}
+
+// Solve solves A·x = b for x with the decomposition (O(n²)), so a system with
+// the same matrix and several right-hand sides is decomposed only once.
+// Returns an error if b doesn't have length n.
+func (lu *LU) Solve(b vector.Vector[float64]) (vector.Vector[float64], error) {
+	n := lu.L.rows
+	if len(b) != n {
+		return nil, fmt.Errorf("vector with length %d doesn't match %dx%d matrix", len(b), n, n)
+	}
+	x := make(vector.Vector[float64], n)
+	// Forward substitution: L·y = P·b.
+	for i := 0; i < n; i++ {
+		sum := b[lu.Permutation[i]]
+		for j := 0; j < i; j++ {
+			sum -= lu.L.data[i*n+j] * x[j]
+		}
+		x[i] = sum
+	}
+	// Back substitution: U·x = y.
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for j := i + 1; j < n; j++ {
+			sum -= lu.U.data[i*n+j] * x[j]
+		}
+		x[i] = sum / lu.U.data[i*n+i]
+	}
+	return x, nil
+}
+
+// Determinant returns the determinant of the decomposed matrix: the product
+// of the diagonal of U, times the sign of the permutation.
+func (lu *LU) Determinant() float64 {
+	n := lu.U.rows
+	determinant := float64(lu.Sign)
+	for i := 0; i < n; i++ {
+		determinant *= lu.U.data[i*n+i]
+	}
+	return determinant
+}
+
+// Solve solves the linear system A·x = b with Gaussian elimination with
+// partial pivoting. Returns an error if A is not square or if b doesn't match
+// its size, or wbmath.ErrSingular if A is singular to working precision (the
+// system has no unique solution). Use LUDecompose to solve several systems
+// with the same matrix.
+func Solve[T wbmath.SignedNumber](a *Matrix[T], b vector.Vector[T]) (vector.Vector[float64], error) {
+	lu, err := LUDecompose(a)
+	if err != nil {
+		return nil, err
+	}
+	return lu.Solve(vector.Convert[float64](b))
+}
+
+// ============================================================================
+// Determinant and inverse
+// ============================================================================
+
+// Determinant returns the determinant of a square matrix, computed with an LU
+// decomposition in float64 (O(n³)), so the result of an integer matrix is
+// rounded. A matrix that is singular to working precision has determinant 0.
+// Returns an error if the matrix is not square. The determinant of the 0x0
+// matrix is 1.
+func Determinant[T wbmath.SignedNumber](m *Matrix[T]) (float64, error) {
+	lu, err := LUDecompose(m)
+	if errors.Is(err, wbmath.ErrSingular) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return lu.Determinant(), nil
+}
+
+// Inverse returns the inverse of a square matrix as a new Matrix of type
+// float64, computed column by column from an LU decomposition. Returns an
+// error if the matrix is not square, or wbmath.ErrSingular if it is singular
+// to working precision.
+func Inverse[T wbmath.SignedNumber](m *Matrix[T]) (*Matrix[float64], error) {
+	lu, err := LUDecompose(m)
+	if err != nil {
+		return nil, err
+	}
+	n := m.rows
+	inverse, _ := New[float64](n, n)
+	unit := make(vector.Vector[float64], n)
+	for j := 0; j < n; j++ {
+		unit[j] = 1
+		column, _ := lu.Solve(unit)
+		unit[j] = 0
+		for i, value := range column {
+			inverse.data[i*n+j] = value
+		}
+	}
+	return inverse, nil
+}
+
+// eliminate reduces the square matrix a in-place to its combined LU factors
+// with Gaussian elimination with partial pivoting: U on and above the
+// diagonal, the multipliers of L below it. It returns the row permutation and
+// its sign, or wbmath.ErrSingular if a pivot is negligible compared to both
+// its row and its column (so diag(1e-20, 1) is not singular).
+func eliminate(a [][]float64) ([]int, int, error) {
+	n := len(a)
+	rowScale, columnScale := make([]float64, n), make([]float64, n)
+	for i, row := range a {
+		for j, value := range row {
+			rowScale[i] = math.Max(rowScale[i], math.Abs(value))
+			columnScale[j] = math.Max(columnScale[j], math.Abs(value))
+		}
+	}
+	permutation := make([]int, n)
+	for i := range permutation {
+		permutation[i] = i
+	}
+	sign := 1
+	for k := 0; k < n; k++ {
+		// Partial pivoting: the largest element in column k on or below the
+		// diagonal.
+		pivot := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i][k]) > math.Abs(a[pivot][k]) {
+				pivot = i
+			}
+		}
+		scale := math.Min(rowScale[permutation[pivot]], columnScale[k])
+		if math.Abs(a[pivot][k]) <= 1e-14*scale*float64(n) {
+			return nil, 0, wbmath.ErrSingular
+		}
+		if pivot != k {
+			a[k], a[pivot] = a[pivot], a[k]
+			permutation[k], permutation[pivot] = permutation[pivot], permutation[k]
+			sign = -sign
+		}
+		for i := k + 1; i < n; i++ {
+			factor := a[i][k] / a[k][k]
+			a[i][k] = factor
+			for j := k + 1; j < n; j++ {
+				a[i][j] -= factor * a[k][j]
+			}
+		}
+	}
+	return permutation, sign, nil
+}
@@ -0,0 +1,243 @@
+// Package matrix provides a generic, dense Matrix[T] type that is backed by a
+// flat, row-major vector.Vector, so matrices and vectors compose without
+// copy/convert glue.
+//
+// Available functionality includes constructors (New, NewFromRows, Zeros,
+// Ones, Identity), element access (At, Set, Dims), row and column extraction
+// (Row, Col), the transpose (Transpose), matrix-matrix and matrix-vector
+// products (Mul, MatVec, VecMat), the determinant and the inverse of small
+// matrices (Determinant, Inverse), LU decompositions and linear systems
+// (LUDecompose, Solve), eigendecompositions (EigenSymmetric, EigenGeneral)
+// and sparse matrices in COO and CSR format with a conjugate gradient solver
+// (ConjugateGradient).
+//
+// Important details:
+//
+// (*) Row returns a view: modifying the returned Vector (e.g. with the
+// in-place Vector methods) modifies the matrix. Col returns a copy because
+// the elements of a column are not adjacent in memory.
+//
+// (*) The type parameter T must satisfy wbmath.SignedNumber, like Vector.
+package matrix
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Matrix is a dense matrix with its elements stored row by row.
+type Matrix[T wbmath.SignedNumber] struct {
+	data       vector.Vector[T]
+	rows, cols int
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns a rows x cols Matrix. Without
+// elements the matrix is filled with zeros, otherwise the elements are
+// copied in row-major order. Returns an error if a dimension is negative or
+// if the number of elements doesn't match the dimensions.
+func New[T wbmath.SignedNumber](rows, cols int, elements ...T) (*Matrix[T], error) {
+	if rows < 0 || cols < 0 {
+		return nil, errors.New("dimensions must not be negative")
+	}
+	if len(elements) == 0 {
+		return &Matrix[T]{data: vector.NewFromValue(T(0), rows*cols), rows: rows, cols: cols}, nil
+	}
+	if len(elements) != rows*cols {
+		return nil, fmt.Errorf("%d elements don't fit a %dx%d matrix", len(elements), rows, cols)
+	}
+	return &Matrix[T]{data: vector.New(elements...), rows: rows, cols: cols}, nil
+}
+
+// NewFromRows is a constructor function that returns a Matrix with a copy of
+// the specified rows. Returns an error if the rows have different lengths.
+func NewFromRows[T wbmath.SignedNumber](rows ...[]T) (*Matrix[T], error) {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	m, _ := New[T](len(rows), cols)
+	for i, row := range rows {
+		if len(row) != cols {
+			return nil, errors.New("rows must have the same length")
+		}
+		copy(m.data[i*cols:], row)
+	}
+	return m, nil
+}
+
+// Zeros is a constructor function that returns a rows x cols Matrix filled
+// with zeros. Returns an error if a dimension is negative.
+func Zeros[T wbmath.SignedNumber](rows, cols int) (*Matrix[T], error) {
+	return New[T](rows, cols)
+}
+
+// Ones is a constructor function that returns a rows x cols Matrix filled
+// with ones. Returns an error if a dimension is negative.
+func Ones[T wbmath.SignedNumber](rows, cols int) (*Matrix[T], error) {
+	m, err := New[T](rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	m.data.Map(func(T) T { return 1 })
+	return m, nil
+}
+
+// Identity is a constructor function that returns the n x n identity matrix:
+// ones on the diagonal and zeros elsewhere. Returns an error if n is negative.
+func Identity[T wbmath.SignedNumber](n int) (*Matrix[T], error) {
+	m, err := New[T](n, n)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m, nil
+}
+
+// Clone returns a deep copy of the matrix.
+func (m *Matrix[T]) Clone() *Matrix[T] {
+	return &Matrix[T]{data: m.data.Clone(), rows: m.rows, cols: m.cols}
+}
+
+// ============================================================================
+// Element access
+// ============================================================================
+
+// Dims returns the number of rows and columns.
+func (m *Matrix[T]) Dims() (int, int) {
+	return m.rows, m.cols
+}
+
+// At returns the element at row i and column j. Returns an error if the
+// index is out of range.
+func (m *Matrix[T]) At(i, j int) (T, error) {
+	if err := m.check(i, j); err != nil {
+		return 0, err
+	}
+	return m.data[i*m.cols+j], nil
+}
+
+// Set sets the element at row i and column j to `value`. Returns an error if
+// the index is out of range.
+func (m *Matrix[T]) Set(i, j int, value T) error {
+	if err := m.check(i, j); err != nil {
+		return err
+	}
+	m.data[i*m.cols+j] = value
+	return nil
+}
+
+// Row returns row i as a Vector that shares its elements with the matrix.
+// Returns an error if i is out of range.
+func (m *Matrix[T]) Row(i int) (vector.Vector[T], error) {
+	if i < 0 || i >= m.rows {
+		return nil, fmt.Errorf("row %d out of range for %dx%d matrix", i, m.rows, m.cols)
+	}
+	// Limit the capacity so appending to the row can't overwrite the next one.
+	return m.data[i*m.cols : (i+1)*m.cols : (i+1)*m.cols], nil
+}
+
+// Col returns a copy of column j as a Vector. Returns an error if j is out
+// of range.
+func (m *Matrix[T]) Col(j int) (vector.Vector[T], error) {
+	if j < 0 || j >= m.cols {
+		return nil, fmt.Errorf("column %d out of range for %dx%d matrix", j, m.rows, m.cols)
+	}
+	col := vector.NewFromValue(T(0), m.rows)
+	for i := range col {
+		col[i] = m.data[i*m.cols+j]
+	}
+	return col, nil
+}
+
+// ============================================================================
+// Matrix operations
+// ============================================================================
+
+// Transpose returns the transpose of the matrix as a new Matrix: element
+// (i, j) of the result is element (j, i) of m.
+func (m *Matrix[T]) Transpose() *Matrix[T] {
+	result, _ := New[T](m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			result.data[j*m.rows+i] = m.data[i*m.cols+j]
+		}
+	}
+	return result
+}
+
+// Mul returns the matrix product a·b as a new Matrix. Returns an error if the
+// number of columns of a doesn't match the number of rows of b.
+func Mul[T wbmath.SignedNumber](a, b *Matrix[T]) (*Matrix[T], error) {
+	if a.cols != b.rows {
+		return nil, fmt.Errorf("cannot multiply %dx%d matrix with %dx%d matrix", a.rows, a.cols, b.rows, b.cols)
+	}
+	result, _ := New[T](a.rows, b.cols)
+	for i := 0; i < a.rows; i++ {
+		// Row i of the result is row i of a times b (the i-k-j loop order
+		// walks both b and the result row by row).
+		row, _ := result.Row(i)
+		for k := 0; k < a.cols; k++ {
+			factor := a.data[i*a.cols+k]
+			other, _ := b.Row(k)
+			for j, element := range other {
+				row[j] += factor * element
+			}
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Matrix-vector products
+// ============================================================================
+
+// MatVec returns the matrix-vector product m·v as a new Vector. Returns an
+// error if the length of v doesn't match the number of columns.
+func MatVec[T wbmath.SignedNumber](m *Matrix[T], v vector.Vector[T]) (vector.Vector[T], error) {
+	if len(v) != m.cols {
+		return nil, fmt.Errorf("cannot multiply %dx%d matrix with vector of length %d", m.rows, m.cols, len(v))
+	}
+	result := vector.NewFromValue(T(0), m.rows)
+	for i := range result {
+		row, _ := m.Row(i)
+		result[i], _ = row.DotProduct(v)
+	}
+	return result, nil
+}
+
+// VecMat returns the vector-matrix product v·m (v as a row vector) as a new
+// Vector. Returns an error if the length of v doesn't match the number of
+// rows.
+func VecMat[T wbmath.SignedNumber](v vector.Vector[T], m *Matrix[T]) (vector.Vector[T], error) {
+	if len(v) != m.rows {
+		return nil, fmt.Errorf("cannot multiply vector of length %d with %dx%d matrix", len(v), m.rows, m.cols)
+	}
+	result := vector.NewFromValue(T(0), m.cols)
+	for i, factor := range v {
+		row, _ := m.Row(i)
+		for j, element := range row {
+			result[j] += factor * element
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+func (m *Matrix[T]) check(i, j int) error {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		return fmt.Errorf("index (%d, %d) out of range for %dx%d matrix", i, j, m.rows, m.cols)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"reflect"
+	"testing"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+func TestRowAndCol(t *testing.T) {
+	m, err := NewFromRows([]int{1, 2, 3}, []int{4, 5, 6})
+	if err != nil {
+		t.Fatalf("NewFromRows returned error: %v", err)
+	}
+	row, _ := m.Row(1)
+	col, _ := m.Col(2)
+	if !reflect.DeepEqual(row, vector.New(4, 5, 6)) || !reflect.DeepEqual(col, vector.New(3, 6)) {
+		t.Fatalf("Row(1) = %v, Col(2) = %v", row, col)
+	}
+	// Rows are views: in-place Vector methods modify the matrix.
+	row.Scale(10)
+	if v, _ := m.At(1, 0); v != 40 {
+		t.Fatalf("At(1, 0) after scaling the row = %d; want 40", v)
+	}
+	_ = append(row, 99)
+	if rows, cols := m.Dims(); rows != 2 || cols != 3 || len(m.data) != 6 {
+		t.Fatalf("appending to a row changed the matrix")
+	}
+	if _, err := m.Row(2); err == nil {
+		t.Fatalf("Row(2) should return error")
+	}
+	if _, err := NewFromRows([]int{1}, []int{1, 2}); err == nil {
+		t.Fatalf("NewFromRows with ragged rows should return error")
+	}
+}
+
+func TestProducts(t *testing.T) {
+	m, _ := New(2, 3, 1.0, 2, 3, 4, 5, 6)
+	mv, err := MatVec(m, vector.New(1.0, 0, -1))
+	if err != nil || !reflect.DeepEqual(mv, vector.New(-2.0, -2)) {
+		t.Fatalf("MatVec = %v, %v", mv, err)
+	}
+	vm, err := VecMat(vector.New(1.0, 1), m)
+	if err != nil || !reflect.DeepEqual(vm, vector.New(5.0, 7, 9)) {
+		t.Fatalf("VecMat = %v, %v", vm, err)
+	}
+	if _, err := MatVec(m, vector.New(1.0)); err == nil {
+		t.Fatalf("MatVec with wrong length should return error")
+	}
+	if _, err := New(2, 2, 1, 2, 3); err == nil {
+		t.Fatalf("New with wrong number of elements should return error")
+	}
+}
+
+func TestEigenSymmetric(t *testing.T) {
+	m, _ := NewFromRows([]float64{4, 1, 2}, []float64{1, 3, 0}, []float64{2, 0, 5})
+	eigen, err := EigenSymmetric(m)
+	if err != nil {
+		t.Fatalf("EigenSymmetric returned error: %v", err)
+	}
+	for i, lambda := range eigen.Values {
+		v, _ := eigen.Vectors.Col(i)
+		av, _ := MatVec(m, v)
+		if residual := av.Subtract(v.Clone().Scale(lambda), 0).Magnitude(); residual > 1e-10 {
+			t.Fatalf("‖A·v - λ·v‖ = %v for λ = %v", residual, lambda)
+		}
+		if norm := v.Magnitude(); math.Abs(norm-1) > 1e-12 {
+			t.Fatalf("eigenvector norm = %v; want 1", norm)
+		}
+	}
+	// The trace equals the sum of the eigenvalues.
+	if sum := eigen.Values.Sum(); math.Abs(sum-12) > 1e-10 {
+		t.Fatalf("sum of eigenvalues = %v; want 12", sum)
+	}
+	if eigen.Values[0] > eigen.Values[1] || eigen.Values[1] > eigen.Values[2] {
+		t.Fatalf("eigenvalues not sorted: %v", eigen.Values)
+	}
+	if eigen.Condition < 1 || eigen.OffDiagonal > 1e-10 {
+		t.Fatalf("diagnostics = %v, %v", eigen.Condition, eigen.OffDiagonal)
+	}
+	asymmetric, _ := NewFromRows([]int{1, 2}, []int{3, 4})
+	if _, err := EigenSymmetric(asymmetric); err == nil {
+		t.Fatalf("EigenSymmetric of asymmetric matrix should return error")
+	}
+}
+
+func TestEigenGeneral(t *testing.T) {
+	// Rotation by 90 degrees scaled by 2 plus a real eigenvalue 3:
+	// eigenvalues 3 and ±2i.
+	m, _ := NewFromRows([]int{0, -2, 0}, []int{2, 0, 0}, []int{1, 1, 3})
+	eigen, err := EigenGeneral(m)
+	if err != nil {
+		t.Fatalf("EigenGeneral returned error: %v", err)
+	}
+	want := map[complex128]bool{3: false, 2i: false, -2i: false}
+	for _, lambda := range eigen.Values {
+		for w := range want {
+			if cmplx.Abs(lambda-w) < 1e-10 {
+				want[w] = true
+			}
+		}
+	}
+	for w, found := range want {
+		if !found {
+			t.Fatalf("eigenvalue %v not found in %v", w, eigen.Values)
+		}
+	}
+	if eigen.Residual > 1e-8 {
+		t.Fatalf("Residual = %v", eigen.Residual)
+	}
+	// A larger non-symmetric matrix: compare the trace and the residuals.
+	rows := make([][]float64, 6)
+	for i := range rows {
+		rows[i] = make([]float64, 6)
+		for j := range rows[i] {
+			rows[i][j] = float64((i*7+j*3)%11) - 5
+		}
+	}
+	big, _ := NewFromRows(rows...)
+	eigen, err = EigenGeneral(big)
+	if err != nil {
+		t.Fatalf("EigenGeneral returned error: %v", err)
+	}
+	var sum complex128
+	trace := 0.0
+	for i, lambda := range eigen.Values {
+		sum += lambda
+		trace += rows[i][i]
+	}
+	if cmplx.Abs(sum-complex(trace, 0)) > 1e-9 {
+		t.Fatalf("sum of eigenvalues = %v; want %v", sum, trace)
+	}
+	if eigen.Residual > 1e-8 {
+		t.Fatalf("Residual = %v", eigen.Residual)
+	}
+}
+
+func TestSparseConversions(t *testing.T) {
+	coo, _ := NewCOO[int](3, 4)
+	for _, e := range [][3]int{{2, 1, 5}, {0, 3, 1}, {0, 0, 2}, {2, 1, 1}, {1, 2, 0}} {
+		if err := coo.Append(e[0], e[1], e[2]); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+	if err := coo.Append(3, 0, 1); err == nil {
+		t.Fatalf("Append out of range should return error")
+	}
+	csr := coo.ToCSR()
+	if csr.NNZ() != 3 {
+		t.Fatalf("NNZ() = %d; want 3 (duplicates summed, zeros dropped)", csr.NNZ())
+	}
+	if got, _ := csr.At(2, 1); got != 6 {
+		t.Fatalf("At(2, 1) = %d; want 6", got)
+	}
+	if got, _ := csr.At(1, 1); got != 0 {
+		t.Fatalf("At(1, 1) = %d; want 0", got)
+	}
+	dense := csr.ToDense()
+	want, _ := NewFromRows([]int{2, 0, 0, 1}, []int{0, 0, 0, 0}, []int{0, 6, 0, 0})
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			got, _ := dense.At(i, j)
+			w, _ := want.At(i, j)
+			fromCOO, _ := coo.ToDense().At(i, j)
+			roundTrip, _ := NewCSRFromDense(dense).ToCOO().ToCSR().At(i, j)
+			if got != w || fromCOO != w || roundTrip != w {
+				t.Fatalf("element (%d, %d) = %d, %d, %d; want %d", i, j, got, fromCOO, roundTrip, w)
+			}
+		}
+	}
+	product, err := csr.MatVec(vector.New(1, 2, 3, 4))
+	if err != nil || product[0] != 6 || product[1] != 0 || product[2] != 12 {
+		t.Fatalf("MatVec = %v, %v; want [6 0 12]", product, err)
+	}
+	if _, err := csr.MatVec(vector.New(1, 2)); err == nil {
+		t.Fatalf("MatVec with wrong length should return error")
+	}
+}
+
+func TestConjugateGradient(t *testing.T) {
+	// The 1D Poisson matrix tridiag(-1, 2, -1) is symmetric positive definite.
+	n := 50
+	coo, _ := NewCOO[float64](n, n)
+	for i := 0; i < n; i++ {
+		coo.Append(i, i, 2)
+		if i > 0 {
+			coo.Append(i, i-1, -1)
+			coo.Append(i-1, i, -1)
+		}
+	}
+	a := coo.ToCSR()
+	want := vector.NewFromValue(0.0, n)
+	for i := range want {
+		want[i] = math.Sin(float64(i))
+	}
+	b, _ := a.MatVec(want)
+	x, iterations, err := ConjugateGradient(a, b, 1e-12, 100)
+	if err != nil {
+		t.Fatalf("ConjugateGradient returned error: %v", err)
+	}
+	if iterations > n {
+		t.Fatalf("ConjugateGradient took %d iterations; want at most %d", iterations, n)
+	}
+	for i := range x {
+		if math.Abs(x[i]-want[i]) > 1e-9 {
+			t.Fatalf("x[%d] = %v; want %v", i, x[i], want[i])
+		}
+	}
+	indefinite, _ := NewFromRows([]float64{1, 0}, []float64{0, -1})
+	if _, _, err := ConjugateGradient(NewCSRFromDense(indefinite), vector.New(0.0, 1), 1e-12, 10); err == nil {
+		t.Fatalf("ConjugateGradient with an indefinite matrix should return error")
+	}
+	if _, _, err := ConjugateGradient(a, b, 1e-12, 2); err == nil {
+		t.Fatalf("ConjugateGradient with too few iterations should return error")
+	}
+}
+
+func TestConstructorsAndTranspose(t *testing.T) {
+	identity, _ := Identity[int](2)
+	ones, _ := Ones[int](2, 3)
+	zeros, _ := Zeros[int](3, 2)
+	if !reflect.DeepEqual(identity.data, vector.New(1, 0, 0, 1)) {
+		t.Fatalf("Identity(2) = %v", identity.data)
+	}
+	if !reflect.DeepEqual(ones.data, vector.New(1, 1, 1, 1, 1, 1)) || !reflect.DeepEqual(zeros.data, vector.New(0, 0, 0, 0, 0, 0)) {
+		t.Fatalf("Ones = %v, Zeros = %v", ones.data, zeros.data)
+	}
+	if _, err := Identity[int](-1); err == nil {
+		t.Fatalf("Identity(-1) should return error")
+	}
+	m, _ := NewFromRows([]int{1, 2, 3}, []int{4, 5, 6})
+	transposed := m.Transpose()
+	if rows, cols := transposed.Dims(); rows != 3 || cols != 2 || !reflect.DeepEqual(transposed.data, vector.New(1, 4, 2, 5, 3, 6)) {
+		t.Fatalf("Transpose() = %v", transposed.data)
+	}
+}
+
+func TestMul(t *testing.T) {
+	a, _ := NewFromRows([]int{1, 2, 3}, []int{4, 5, 6})
+	b, _ := NewFromRows([]int{7, 8}, []int{9, 10}, []int{11, 12})
+	product, err := Mul(a, b)
+	if err != nil {
+		t.Fatalf("Mul returned error: %v", err)
+	}
+	if rows, cols := product.Dims(); rows != 2 || cols != 2 || !reflect.DeepEqual(product.data, vector.New(58, 64, 139, 154)) {
+		t.Fatalf("Mul(a, b) = %v", product.data)
+	}
+	identity, _ := Identity[int](3)
+	if product, _ := Mul(a, identity); !reflect.DeepEqual(product.data, a.data) {
+		t.Fatalf("Mul(a, I) = %v; want %v", product.data, a.data)
+	}
+	if _, err := Mul(a, a); err == nil {
+		t.Fatalf("Mul with mismatched dimensions should return error")
+	}
+}
+
+func TestDeterminantAndInverse(t *testing.T) {
+	m, _ := NewFromRows([]int{0, 2, 1}, []int{1, 1, 0}, []int{3, 0, 1})
+	if det, err := Determinant(m); err != nil || math.Abs(det+5) > 1e-12 {
+		t.Fatalf("Determinant = %v, %v; want -5", det, err)
+	}
+	inverse, err := Inverse(m)
+	if err != nil {
+		t.Fatalf("Inverse returned error: %v", err)
+	}
+	floats, _ := NewFromRows([]float64{0, 2, 1}, []float64{1, 1, 0}, []float64{3, 0, 1})
+	product, _ := Mul(floats, inverse)
+	identity, _ := Identity[float64](3)
+	if !product.data.AlmostEqual(identity.data, 1e-12) {
+		t.Fatalf("m·Inverse(m) = %v; want identity", product.data)
+	}
+	singular, _ := NewFromRows([]int{1, 2}, []int{2, 4})
+	if det, _ := Determinant(singular); det != 0 {
+		t.Fatalf("Determinant of singular matrix = %v; want 0", det)
+	}
+	if _, err := Inverse(singular); err == nil {
+		t.Fatalf("Inverse of singular matrix should return error")
+	}
+	rectangular, _ := New[int](2, 3)
+	if _, err := Determinant(rectangular); err == nil {
+		t.Fatalf("Determinant of non-square matrix should return error")
+	}
+}
+
+func TestLUAndSolve(t *testing.T) {
+	a, _ := NewFromRows([]int{2, 1, 1}, []int{4, -6, 0}, []int{-2, 7, 2})
+	lu, err := LUDecompose(a)
+	if err != nil {
+		t.Fatalf("LUDecompose returned error: %v", err)
+	}
+	// P·A = L·U
+	product, _ := Mul(lu.L, lu.U)
+	for i, row := range lu.Permutation {
+		for j := 0; j < 3; j++ {
+			got, _ := product.At(i, j)
+			want, _ := a.At(row, j)
+			if math.Abs(got-float64(want)) > 1e-12 {
+				t.Fatalf("(L·U)[%d][%d] = %v; want %v", i, j, got, want)
+			}
+		}
+	}
+	if det := lu.Determinant(); math.Abs(det+16) > 1e-12 {
+		t.Fatalf("Determinant() = %v; want -16", det)
+	}
+	x, err := Solve(a, vector.New(5, -2, 9))
+	if err != nil || !x.AlmostEqual(vector.New(1.0, 1, 2), 1e-12) {
+		t.Fatalf("Solve = %v, %v; want [1 1 2]", x, err)
+	}
+	singular, _ := NewFromRows([]int{1, 2}, []int{2, 4})
+	if _, err := Solve(singular, vector.New(1, 2)); !errors.Is(err, wbmath.ErrSingular) {
+		t.Fatalf("Solve with singular matrix returned %v; want ErrSingular", err)
+	}
+	if _, err := Solve(a, vector.New(1, 2)); err == nil {
+		t.Fatalf("Solve with mismatched vector should return error")
+	}
+	// Singularity is relative to the pivot's own row and column, not to the
+	// largest element of the matrix.
+	tiny, _ := NewFromRows([]float64{1e-20, 0}, []float64{0, 1})
+	if det, err := Determinant(tiny); err != nil || det != 1e-20 {
+		t.Fatalf("Determinant(diag(1e-20, 1)) = %v, %v; want 1e-20", det, err)
+	}
+	if x, err := Solve(tiny, vector.New(1e-20, 2)); err != nil || !x.AlmostEqual(vector.New(1.0, 2), 1e-12) {
+		t.Fatalf("Solve(diag(1e-20, 1)) = %v, %v; want [1 2]", x, err)
+	}
+	wide, _ := NewFromRows([]float64{1e-20, 1}, []float64{0, 1})
+	if inverse, err := Inverse(wide); err != nil || inverse.data[0] != 1e20 || inverse.data[1] != -1e20 {
+		t.Fatalf("Inverse([[1e-20 1] [0 1]]) = %v, %v; want [[1e20 -1e20] [0 1]]", inverse, err)
+	}
+	nearlySingular, _ := NewFromRows([]float64{0.1, 0.3}, []float64{0.3, 0.9})
+	if det, err := Determinant(nearlySingular); err != nil || det != 0 {
+		t.Fatalf("Determinant([[0.1 0.3] [0.3 0.9]]) = %v, %v; want 0", det, err)
+	}
+}
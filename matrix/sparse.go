@@ -0,0 +1,232 @@
+package matrix
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// COO is a sparse matrix in coordinate format: a list of (row, column,
+// value) triplets. It is the convenient format to build a sparse matrix
+// incrementally; convert it to CSR for computations. Duplicate entries are
+// summed by the conversions.
+type COO[T wbmath.SignedNumber] struct {
+	rows, cols int
+	rowIndex   []int
+	colIndex   []int
+	values     []T
+}
+
+// CSR is a sparse matrix in compressed sparse row format: the column indices
+// and values of the non-zero elements row by row, with rowStart[i] the
+// position of the first element of row i. Only the non-zero elements are
+// stored, so matrix-vector products take time proportional to their number.
+type CSR[T wbmath.SignedNumber] struct {
+	rows, cols int
+	rowStart   []int // length rows+1
+	colIndex   []int
+	values     []T
+}
+
+// ============================================================================
+// COO
+// ============================================================================
+
+// NewCOO is a constructor function that returns an empty rows x cols COO
+// matrix. Returns an error if a dimension is negative.
+func NewCOO[T wbmath.SignedNumber](rows, cols int) (*COO[T], error) {
+	if rows < 0 || cols < 0 {
+		return nil, errors.New("dimensions must not be negative")
+	}
+	return &COO[T]{rows: rows, cols: cols}, nil
+}
+
+// Append adds the value at row i and column j; values appended at the same
+// position are summed. Returns an error if the index is out of range.
+func (c *COO[T]) Append(i, j int, value T) error {
+	if i < 0 || i >= c.rows || j < 0 || j >= c.cols {
+		return fmt.Errorf("index (%d, %d) out of range for %dx%d matrix", i, j, c.rows, c.cols)
+	}
+	c.rowIndex = append(c.rowIndex, i)
+	c.colIndex = append(c.colIndex, j)
+	c.values = append(c.values, value)
+	return nil
+}
+
+// Dims returns the number of rows and columns.
+func (c *COO[T]) Dims() (int, int) {
+	return c.rows, c.cols
+}
+
+// NNZ returns the number of stored entries (including duplicates).
+func (c *COO[T]) NNZ() int {
+	return len(c.values)
+}
+
+// ToCSR converts the COO matrix to CSR format, summing duplicate entries and
+// dropping entries that are (or sum to) zero.
+func (c *COO[T]) ToCSR() *CSR[T] {
+	order := make([]int, len(c.values))
+	for k := range order {
+		order[k] = k
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if c.rowIndex[a] != c.rowIndex[b] {
+			return c.rowIndex[a] - c.rowIndex[b]
+		}
+		return c.colIndex[a] - c.colIndex[b]
+	})
+	s := &CSR[T]{rows: c.rows, cols: c.cols, rowStart: make([]int, c.rows+1)}
+	for n := 0; n < len(order); {
+		i, j := c.rowIndex[order[n]], c.colIndex[order[n]]
+		var sum T
+		for ; n < len(order) && c.rowIndex[order[n]] == i && c.colIndex[order[n]] == j; n++ {
+			sum += c.values[order[n]]
+		}
+		if sum != 0 {
+			s.colIndex = append(s.colIndex, j)
+			s.values = append(s.values, sum)
+			s.rowStart[i+1]++
+		}
+	}
+	for i := 0; i < s.rows; i++ {
+		s.rowStart[i+1] += s.rowStart[i]
+	}
+	return s
+}
+
+// ToDense converts the COO matrix to a dense Matrix, summing duplicate
+// entries.
+func (c *COO[T]) ToDense() *Matrix[T] {
+	m, _ := New[T](c.rows, c.cols)
+	for k, value := range c.values {
+		m.data[c.rowIndex[k]*c.cols+c.colIndex[k]] += value
+	}
+	return m
+}
+
+// ============================================================================
+// CSR
+// ============================================================================
+
+// NewCSRFromDense is a constructor function that converts a dense Matrix to
+// CSR format, storing only the non-zero elements.
+func NewCSRFromDense[T wbmath.SignedNumber](m *Matrix[T]) *CSR[T] {
+	s := &CSR[T]{rows: m.rows, cols: m.cols, rowStart: make([]int, m.rows+1)}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if value := m.data[i*m.cols+j]; value != 0 {
+				s.colIndex = append(s.colIndex, j)
+				s.values = append(s.values, value)
+			}
+		}
+		s.rowStart[i+1] = len(s.values)
+	}
+	return s
+}
+
+// Dims returns the number of rows and columns.
+func (s *CSR[T]) Dims() (int, int) {
+	return s.rows, s.cols
+}
+
+// NNZ returns the number of stored (non-zero) elements.
+func (s *CSR[T]) NNZ() int {
+	return len(s.values)
+}
+
+// At returns the element at row i and column j. Returns an error if the
+// index is out of range.
+func (s *CSR[T]) At(i, j int) (T, error) {
+	if i < 0 || i >= s.rows || j < 0 || j >= s.cols {
+		return 0, fmt.Errorf("index (%d, %d) out of range for %dx%d matrix", i, j, s.rows, s.cols)
+	}
+	columns := s.colIndex[s.rowStart[i]:s.rowStart[i+1]]
+	if k, found := slices.BinarySearch(columns, j); found {
+		return s.values[s.rowStart[i]+k], nil
+	}
+	return 0, nil
+}
+
+// ToDense converts the CSR matrix to a dense Matrix.
+func (s *CSR[T]) ToDense() *Matrix[T] {
+	m, _ := New[T](s.rows, s.cols)
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			m.data[i*s.cols+s.colIndex[k]] = s.values[k]
+		}
+	}
+	return m
+}
+
+// ToCOO converts the CSR matrix to COO format.
+func (s *CSR[T]) ToCOO() *COO[T] {
+	c := &COO[T]{rows: s.rows, cols: s.cols, colIndex: slices.Clone(s.colIndex), values: slices.Clone(s.values)}
+	c.rowIndex = make([]int, len(s.values))
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			c.rowIndex[k] = i
+		}
+	}
+	return c
+}
+
+// MatVec returns the matrix-vector product s·v as a new Vector. Returns an
+// error if the length of v doesn't match the number of columns.
+func (s *CSR[T]) MatVec(v vector.Vector[T]) (vector.Vector[T], error) {
+	if len(v) != s.cols {
+		return nil, fmt.Errorf("cannot multiply %dx%d matrix with vector of length %d", s.rows, s.cols, len(v))
+	}
+	result := vector.NewFromValue(T(0), s.rows)
+	for i := range result {
+		for k := s.rowStart[i]; k < s.rowStart[i+1]; k++ {
+			result[i] += s.values[k] * v[s.colIndex[k]]
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Iterative solvers
+// ============================================================================
+
+// ConjugateGradient solves a·x = b for a symmetric positive definite (SPD)
+// matrix a with the conjugate gradient method, starting from x = 0. The
+// iteration stops when the residual norm |b - a·x| is at most tolerance·|b|.
+// Returns the solution and the number of iterations. Returns an error if the
+// dimensions don't match, if the method breaks down because a is not
+// positive definite, or if it doesn't converge within maxIterations.
+func ConjugateGradient(a *CSR[float64], b vector.Vector[float64], tolerance float64, maxIterations int) (vector.Vector[float64], int, error) {
+	if a.rows != a.cols || len(b) != a.rows {
+		return nil, 0, fmt.Errorf("cannot solve %dx%d system with vector of length %d", a.rows, a.cols, len(b))
+	}
+	x := vector.NewFromValue(0.0, len(b))
+	r := b.Clone()
+	p := b.Clone()
+	rr, _ := r.DotProduct(r)
+	threshold := tolerance * b.Magnitude()
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		if math.Sqrt(rr) <= threshold {
+			return x, iteration, nil
+		}
+		ap, _ := a.MatVec(p)
+		pap, _ := p.DotProduct(ap)
+		if !(pap > 0) {
+			return nil, iteration, errors.New("matrix is not positive definite")
+		}
+		alpha := rr / pap
+		x.Add(p.Clone().Scale(alpha), 0)
+		r.Subtract(ap.Scale(alpha), 0)
+		next, _ := r.DotProduct(r)
+		p = p.Scale(next/rr).Add(r, 0)
+		rr = next
+	}
+	if math.Sqrt(rr) <= threshold {
+		return x, maxIterations, nil
+	}
+	return nil, maxIterations, fmt.Errorf("no convergence after %d iterations", maxIterations)
+}
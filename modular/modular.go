@@ -0,0 +1,323 @@
+// Package modular provides a ModInt type: an integer in the ring Z/nZ of
+// integers modulo n. All arithmetic is reduced automatically, so code can be
+// written without scattering % n and modular inverse calls everywhere.
+//
+// Poly builds on ModInt: a polynomial over the finite field Z/pZ for a prime
+// p, with long division, GCD and irreducibility testing, the foundation for
+// e.g. CRC and Reed-Solomon experiments.
+//
+// Important details:
+//
+// (*) ModInt is a small value type: methods never modify the receiver but
+// return a new value.
+//
+// (*) Moduli up to math.MaxInt are supported. Multiplication uses 128-bit
+// intermediates, so it never overflows.
+//
+// (*) Combining two ModInts with different moduli is a programming error and
+// panics, just like indexing a slice out of range. The zero value of ModInt
+// has no modulus and must not be used for arithmetic.
+//
+// (*) Inverse and Divide return an error if the value has no inverse (i.e.
+// when it is not coprime to the modulus).
+package modular
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ModInt represents an integer modulo a fixed modulus. The value is always
+// kept in the range [0, modulus).
+type ModInt struct {
+	value   uint64
+	modulus uint64
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the ModInt value mod modulus.
+// Negative values are mapped to their non-negative representative (-1 mod 7
+// becomes 6). Returns an error if the modulus is smaller than 1.
+func New(value int, modulus int) (ModInt, error) {
+	if modulus < 1 {
+		return ModInt{}, errors.New("modulus must be positive")
+	}
+	m := uint64(modulus)
+	r := value % modulus
+	if r < 0 {
+		r += modulus
+	}
+	return ModInt{value: uint64(r) % m, modulus: m}, nil
+}
+
+// MustNew is a constructor identical to New but which panics if an error
+// occurs.
+func MustNew(value int, modulus int) ModInt {
+	a, err := New(value, modulus)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// NewSlice is a constructor function that converts a slice of integers to a
+// slice of ModInts with the same modulus. Returns an error if the modulus is
+// smaller than 1.
+func NewSlice(values []int, modulus int) ([]ModInt, error) {
+	result := make([]ModInt, len(values))
+	for i, value := range values {
+		a, err := New(value, modulus)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = a
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Accessors
+// ============================================================================
+
+// Value returns the value of the ModInt in the range [0, modulus).
+func (a ModInt) Value() int {
+	return int(a.value)
+}
+
+// Modulus returns the modulus of the ModInt.
+func (a ModInt) Modulus() int {
+	return int(a.modulus)
+}
+
+// Signed returns the representative of the ModInt closest to zero, in the
+// range (-modulus/2, modulus/2]. For example 6 mod 7 gives -1.
+func (a ModInt) Signed() int {
+	if a.value > a.modulus/2 {
+		return int(a.value) - int(a.modulus)
+	}
+	return int(a.value)
+}
+
+// IsZero checks if the ModInt is congruent to zero.
+func (a ModInt) IsZero() bool {
+	return a.value == 0
+}
+
+// Equals checks if two ModInts have the same value and modulus.
+func (a ModInt) Equals(other ModInt) bool {
+	return a == other
+}
+
+// String implements the fmt.Stringer interface and returns the ModInt
+// formatted as "value (mod modulus)".
+func (a ModInt) String() string {
+	return fmt.Sprintf("%d (mod %d)", a.value, a.modulus)
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns a + other. Panics if the moduli differ.
+func (a ModInt) Add(other ModInt) ModInt {
+	a.mustMatch(other)
+	sum, carry := bits.Add64(a.value, other.value, 0)
+	if carry != 0 || sum >= a.modulus {
+		sum -= a.modulus
+	}
+	return ModInt{value: sum, modulus: a.modulus}
+}
+
+// AddInt returns a + value.
+func (a ModInt) AddInt(value int) ModInt {
+	return a.Add(MustNew(value, int(a.modulus)))
+}
+
+// Subtract returns a - other. Panics if the moduli differ.
+func (a ModInt) Subtract(other ModInt) ModInt {
+	a.mustMatch(other)
+	return a.Add(other.Negate())
+}
+
+// SubtractInt returns a - value.
+func (a ModInt) SubtractInt(value int) ModInt {
+	return a.Subtract(MustNew(value, int(a.modulus)))
+}
+
+// Negate returns -a.
+func (a ModInt) Negate() ModInt {
+	if a.value == 0 {
+		return a
+	}
+	return ModInt{value: a.modulus - a.value, modulus: a.modulus}
+}
+
+// Multiply returns a * other. Panics if the moduli differ.
+func (a ModInt) Multiply(other ModInt) ModInt {
+	a.mustMatch(other)
+	return ModInt{value: mulMod(a.value, other.value, a.modulus), modulus: a.modulus}
+}
+
+// MultiplyInt returns a * value.
+func (a ModInt) MultiplyInt(value int) ModInt {
+	return a.Multiply(MustNew(value, int(a.modulus)))
+}
+
+// Pow returns a^exponent using binary exponentiation. By convention 0^0 = 1.
+func (a ModInt) Pow(exponent uint) ModInt {
+	result := uint64(1) % a.modulus
+	base := a.value
+	for exponent > 0 {
+		if exponent&1 != 0 {
+			result = mulMod(result, base, a.modulus)
+		}
+		base = mulMod(base, base, a.modulus)
+		exponent >>= 1
+	}
+	return ModInt{value: result, modulus: a.modulus}
+}
+
+// PowInt returns a^exponent for a signed exponent: negative exponents use the
+// inverse of a. Returns an error if the exponent is negative and a has no
+// inverse.
+func (a ModInt) PowInt(exponent int) (ModInt, error) {
+	if exponent >= 0 {
+		return a.Pow(uint(exponent)), nil
+	}
+	inverse, err := a.Inverse()
+	if err != nil {
+		return ModInt{}, err
+	}
+	return inverse.Pow(uint(-exponent)), nil
+}
+
+// Inverse returns the multiplicative inverse of a: the value x for which
+// a * x = 1 (mod n). Uses the extended Euclidean algorithm. Returns an error
+// if a and the modulus are not coprime.
+func (a ModInt) Inverse() (ModInt, error) {
+	// Invariants: oldR = oldS * a (mod n) and r = s * a (mod n). The moduli
+	// are at most math.MaxInt, so all intermediates fit in an int64.
+	oldR, r := int64(a.value), int64(a.modulus)
+	oldS, s := int64(1), int64(0)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, oldS-q*s
+	}
+	if oldR != 1 {
+		return ModInt{}, fmt.Errorf("%d has no inverse modulo %d", a.value, a.modulus)
+	}
+	return MustNew(int(oldS), int(a.modulus)), nil
+}
+
+// Divide returns a / other, i.e. a multiplied by the inverse of other. Panics
+// if the moduli differ. Returns an error if other has no inverse.
+func (a ModInt) Divide(other ModInt) (ModInt, error) {
+	a.mustMatch(other)
+	inverse, err := other.Inverse()
+	if err != nil {
+		return ModInt{}, err
+	}
+	return a.Multiply(inverse), nil
+}
+
+// ============================================================================
+// Batch operations
+// ============================================================================
+
+// Sum returns the sum of the specified ModInts. Returns an error if no values
+// are specified or if the moduli differ.
+func Sum(values ...ModInt) (ModInt, error) {
+	if err := checkBatch(values); err != nil {
+		return ModInt{}, err
+	}
+	result := ModInt{value: 0, modulus: values[0].modulus}
+	for _, value := range values {
+		result = result.Add(value)
+	}
+	return result, nil
+}
+
+// Product returns the product of the specified ModInts. Returns an error if
+// no values are specified or if the moduli differ.
+func Product(values ...ModInt) (ModInt, error) {
+	if err := checkBatch(values); err != nil {
+		return ModInt{}, err
+	}
+	result := ModInt{value: 1 % values[0].modulus, modulus: values[0].modulus}
+	for _, value := range values {
+		result = result.Multiply(value)
+	}
+	return result, nil
+}
+
+// BatchInverse returns the inverses of all specified ModInts using
+// Montgomery's trick: only a single modular inverse is computed, the rest is
+// done with 3(n-1) multiplications. Returns an error if the moduli differ or
+// if any value has no inverse.
+func BatchInverse(values []ModInt) ([]ModInt, error) {
+	if len(values) == 0 {
+		return []ModInt{}, nil
+	}
+	if err := checkBatch(values); err != nil {
+		return nil, err
+	}
+	// prefix[i] = values[0] * ... * values[i]
+	prefix := make([]ModInt, len(values))
+	prefix[0] = values[0]
+	for i := 1; i < len(values); i++ {
+		prefix[i] = prefix[i-1].Multiply(values[i])
+	}
+	inverse, err := prefix[len(values)-1].Inverse()
+	if err != nil {
+		// Report which value is the culprit.
+		for _, value := range values {
+			if _, err := value.Inverse(); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	result := make([]ModInt, len(values))
+	for i := len(values) - 1; i > 0; i-- {
+		result[i] = inverse.Multiply(prefix[i-1])
+		inverse = inverse.Multiply(values[i])
+	}
+	result[0] = inverse
+	return result, nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// mustMatch panics if the moduli of a and other differ.
+func (a ModInt) mustMatch(other ModInt) {
+	if a.modulus == 0 || a.modulus != other.modulus {
+		panic(fmt.Sprintf("modular: mismatched moduli %d and %d", a.modulus, other.modulus))
+	}
+}
+
+// checkBatch returns an error if values is empty or contains different moduli.
+func checkBatch(values []ModInt) error {
+	if len(values) == 0 {
+		return errors.New("no values specified")
+	}
+	for _, value := range values {
+		if value.modulus == 0 || value.modulus != values[0].modulus {
+			return errors.New("all values must have the same modulus")
+		}
+	}
+	return nil
+}
+
+// mulMod returns a*b mod m without overflow.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%m, lo, m)
+	return rem
+}
@@ -0,0 +1,173 @@
+package modular
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewAndAccessors(t *testing.T) {
+	a := MustNew(-1, 7)
+	if a.Value() != 6 || a.Modulus() != 7 || a.Signed() != -1 {
+		t.Fatalf("MustNew(-1, 7) = %v (signed %d); want 6 (mod 7), -1", a, a.Signed())
+	}
+	if _, err := New(3, 0); err == nil {
+		t.Fatalf("New with modulus 0 should return error")
+	}
+	if s := a.String(); s != "6 (mod 7)" {
+		t.Fatalf("String() = %q; want \"6 (mod 7)\"", s)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustNew(5, 7)
+	b := MustNew(4, 7)
+	cases := []struct {
+		name string
+		got  ModInt
+		want int
+	}{
+		{"Add", a.Add(b), 2},
+		{"Subtract", b.Subtract(a), 6},
+		{"Multiply", a.Multiply(b), 6},
+		{"Negate", a.Negate(), 2},
+		{"Pow", MustNew(3, 1000000007).Pow(1000000006), 1},
+		{"AddInt", a.AddInt(-12), 0},
+		{"MultiplyInt", a.MultiplyInt(3), 1},
+	}
+	for _, c := range cases {
+		if c.got.Value() != c.want {
+			t.Fatalf("%s = %v; want %d", c.name, c.got, c.want)
+		}
+	}
+	// Large moduli must not overflow
+	large := MustNew(math.MaxInt-1, math.MaxInt)
+	if got := large.Multiply(large); got.Value() != 1 {
+		t.Fatalf("(-1)*(-1) mod MaxInt = %v; want 1", got)
+	}
+	if got := large.Add(large); got.Value() != math.MaxInt-2 {
+		t.Fatalf("(-1)+(-1) mod MaxInt = %v; want MaxInt-2", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Add with mismatched moduli should panic")
+		}
+	}()
+	a.Add(MustNew(1, 5))
+}
+
+func TestInverseAndDivide(t *testing.T) {
+	inverse, err := MustNew(3, 11).Inverse()
+	if err != nil || inverse.Value() != 4 {
+		t.Fatalf("Inverse(3 mod 11) = %v, %v; want 4", inverse, err)
+	}
+	if _, err := MustNew(4, 12).Inverse(); err == nil {
+		t.Fatalf("Inverse(4 mod 12) should return error")
+	}
+	quotient, err := MustNew(1, 11).Divide(MustNew(2, 11))
+	if err != nil || quotient.Value() != 6 {
+		t.Fatalf("1/2 mod 11 = %v, %v; want 6", quotient, err)
+	}
+	power, err := MustNew(2, 11).PowInt(-1)
+	if err != nil || power.Value() != 6 {
+		t.Fatalf("2^-1 mod 11 = %v, %v; want 6", power, err)
+	}
+	large, err := MustNew(2, math.MaxInt).Inverse()
+	if err != nil || large.MultiplyInt(2).Value() != 1 {
+		t.Fatalf("Inverse(2 mod MaxInt) = %v, %v", large, err)
+	}
+}
+
+func TestBatchOperations(t *testing.T) {
+	values, err := NewSlice([]int{1, 2, 3, 4, 5, 6}, 7)
+	if err != nil {
+		t.Fatalf("NewSlice returned error: %v", err)
+	}
+	if sum, _ := Sum(values...); sum.Value() != 0 {
+		t.Fatalf("Sum = %v; want 0", sum)
+	}
+	// Wilson's theorem: (p-1)! = -1 (mod p)
+	if product, _ := Product(values...); product.Value() != 6 {
+		t.Fatalf("Product = %v; want 6", product)
+	}
+	inverses, err := BatchInverse(values)
+	if err != nil {
+		t.Fatalf("BatchInverse returned error: %v", err)
+	}
+	for i, inverse := range inverses {
+		if values[i].Multiply(inverse).Value() != 1 {
+			t.Fatalf("BatchInverse[%d] = %v is not the inverse of %v", i, inverse, values[i])
+		}
+	}
+	if _, err := BatchInverse([]ModInt{MustNew(2, 8), MustNew(3, 8)}); err == nil {
+		t.Fatalf("BatchInverse with non-invertible value should return error")
+	}
+	if _, err := Sum(MustNew(1, 7), MustNew(1, 5)); err == nil {
+		t.Fatalf("Sum with mismatched moduli should return error")
+	}
+}
+
+func TestPolyArithmetic(t *testing.T) {
+	p := MustNewPoly(5, 1, 2, 0, 1) // x^3 + 2x + 1
+	q := MustNewPoly(5, 4, 1)       // x + 4
+	if s := p.String(); s != "x^3 + 2x + 1 (mod 5)" {
+		t.Fatalf("String() = %q; want \"x^3 + 2x + 1 (mod 5)\"", s)
+	}
+	if _, err := NewPoly(6, 1, 1); err == nil {
+		t.Fatalf("NewPoly with modulus 6 should return error")
+	}
+	if got := p.Add(MustNewPoly(5, 4, 3, 0, 4)); !got.Equals(MustNewPoly(5, 0, 0, 0, 0)) || got.Degree() != -1 {
+		t.Fatalf("Add = %v; want 0 (mod 5)", got)
+	}
+	if got, want := p.Multiply(q), MustNewPoly(5, 4, 4, 2, 4, 1); !got.Equals(want) {
+		t.Fatalf("Multiply = %v; want %v", got, want)
+	}
+	quotient, remainder, err := p.DivMod(q)
+	if err != nil {
+		t.Fatalf("DivMod returned error: %v", err)
+	}
+	if got := quotient.Multiply(q).Add(remainder); !got.Equals(p) || remainder.Degree() >= q.Degree() {
+		t.Fatalf("DivMod = %v, %v; quotient*q + remainder = %v", quotient, remainder, got)
+	}
+	// p(1) = 1 + 2 + 1 = 4, which is also the remainder of p / (x - 1).
+	if got := p.Evaluate(MustNew(1, 5)); got.Value() != 4 || remainder.Coefficient(0).Value() != 4 {
+		t.Fatalf("Evaluate(1) = %v, remainder = %v; want 4", got, remainder)
+	}
+	if _, _, err := p.DivMod(MustNewPoly(5)); err == nil {
+		t.Fatalf("DivMod by zero polynomial should return error")
+	}
+	if got, want := p.Derivative(), MustNewPoly(5, 2, 0, 3); !got.Equals(want) {
+		t.Fatalf("Derivative = %v; want %v", got, want)
+	}
+}
+
+func TestPolyGcdAndIrreducible(t *testing.T) {
+	// (x + 1)(x + 2) and (x + 1)(x + 3) have gcd x + 1.
+	a := MustNewPoly(7, 1, 1).Multiply(MustNewPoly(7, 2, 1)).Scale(MustNew(3, 7))
+	b := MustNewPoly(7, 1, 1).Multiply(MustNewPoly(7, 3, 1))
+	if got, want := PolyGcd(a, b), MustNewPoly(7, 1, 1); !got.Equals(want) {
+		t.Fatalf("PolyGcd = %v; want %v", got, want)
+	}
+	cases := []struct {
+		p    Poly
+		want bool
+	}{
+		{MustNewPoly(2, 1, 1, 1), true},          // x^2 + x + 1
+		{MustNewPoly(2, 1, 0, 1), false},         // x^2 + 1 = (x + 1)^2
+		{MustNewPoly(2, 1, 1, 0, 0, 1), true},    // x^4 + x + 1
+		{MustNewPoly(2, 1, 1, 0, 0, 0, 0), true}, // x + 1
+		{MustNewPoly(2, 1, 0, 1, 0, 1), false},   // x^4 + x^2 + 1 = (x^2 + x + 1)^2
+		{MustNewPoly(2, 1, 1, 0, 1, 1), false},   // x^4 + x^3 + x + 1
+		{MustNewPoly(3, 1, 0, 1), true},          // x^2 + 1 has no root mod 3
+		{MustNewPoly(5, 1, 0, 1), false},         // 2^2 + 1 = 0 mod 5
+		{MustNewPoly(5, 3), false},
+	}
+	for _, c := range cases {
+		if got := c.p.IsIrreducible(); got != c.want {
+			t.Fatalf("%v.IsIrreducible() = %v; want %v", c.p, got, c.want)
+		}
+	}
+	x := MustNewPoly(3, 0, 1)
+	if got, _ := x.PowMod(9, MustNewPoly(3, 1, 0, 1)); !got.Equals(x) {
+		t.Fatalf("x^9 mod x^2 + 1 = %v; want x (mod 3)", got)
+	}
+}
@@ -0,0 +1,358 @@
+package modular
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bogersw/wbmath/primes"
+)
+
+// Poly represents a polynomial with coefficients in the finite field Z/pZ for
+// a prime p. Like ModInt it is a value type: methods never modify the
+// receiver but return a new Poly. Coefficients are stored from the constant
+// term upwards without trailing zeros, so the zero polynomial has no
+// coefficients and degree -1.
+type Poly struct {
+	coefficients []ModInt
+	modulus      int
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// NewPoly is a constructor function that returns the polynomial with the
+// specified coefficients mod modulus, starting with the constant term:
+// NewPoly(5, 1, 2, 0, 1) is x^3 + 2x + 1 (mod 5). Returns an error if the
+// modulus is not prime.
+func NewPoly(modulus int, coefficients ...int) (Poly, error) {
+	if !primes.IsPrime(modulus) {
+		return Poly{}, fmt.Errorf("modulus %d is not prime", modulus)
+	}
+	values, _ := NewSlice(coefficients, modulus)
+	return newPoly(values, modulus), nil
+}
+
+// MustNewPoly is a constructor identical to NewPoly but which panics if an
+// error occurs.
+func MustNewPoly(modulus int, coefficients ...int) Poly {
+	p, err := NewPoly(modulus, coefficients...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewPolyFromModInts is a constructor function that returns the polynomial
+// with the specified coefficients, starting with the constant term. Returns
+// an error if no coefficients are specified, if the moduli differ or if the
+// modulus is not prime.
+func NewPolyFromModInts(coefficients ...ModInt) (Poly, error) {
+	if err := checkBatch(coefficients); err != nil {
+		return Poly{}, err
+	}
+	modulus := coefficients[0].Modulus()
+	if !primes.IsPrime(modulus) {
+		return Poly{}, fmt.Errorf("modulus %d is not prime", modulus)
+	}
+	return newPoly(append([]ModInt(nil), coefficients...), modulus), nil
+}
+
+// ============================================================================
+// Accessors
+// ============================================================================
+
+// Degree returns the degree of the polynomial, or -1 for the zero
+// polynomial.
+func (p Poly) Degree() int {
+	return len(p.coefficients) - 1
+}
+
+// Modulus returns the (prime) modulus of the coefficients.
+func (p Poly) Modulus() int {
+	return p.modulus
+}
+
+// Coefficient returns the coefficient of x^i, which is zero if i is larger
+// than the degree. Panics if i is negative.
+func (p Poly) Coefficient(i int) ModInt {
+	if i < 0 {
+		panic(fmt.Sprintf("modular: negative coefficient index %d", i))
+	}
+	if i >= len(p.coefficients) {
+		return MustNew(0, p.modulus)
+	}
+	return p.coefficients[i]
+}
+
+// Coefficients returns a copy of the coefficients, starting with the
+// constant term.
+func (p Poly) Coefficients() []ModInt {
+	return append([]ModInt(nil), p.coefficients...)
+}
+
+// IsZero checks if p is the zero polynomial.
+func (p Poly) IsZero() bool {
+	return len(p.coefficients) == 0
+}
+
+// IsMonic checks if the leading coefficient of p is 1.
+func (p Poly) IsMonic() bool {
+	return len(p.coefficients) > 0 && p.lead().Value() == 1
+}
+
+// Equals checks if two polynomials have the same coefficients and modulus.
+func (p Poly) Equals(other Poly) bool {
+	if p.modulus != other.modulus || len(p.coefficients) != len(other.coefficients) {
+		return false
+	}
+	for i, c := range p.coefficients {
+		if c != other.coefficients[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the fmt.Stringer interface and returns the polynomial
+// formatted as e.g. "x^3 + 2x + 1 (mod 5)".
+func (p Poly) String() string {
+	var terms []string
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		c := p.coefficients[i].Value()
+		if c == 0 {
+			continue
+		}
+		var term string
+		switch {
+		case i == 0:
+			term = fmt.Sprint(c)
+		case c == 1:
+			term = "x"
+		default:
+			term = fmt.Sprintf("%dx", c)
+		}
+		if i > 1 {
+			term += fmt.Sprintf("^%d", i)
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		terms = []string{"0"}
+	}
+	return fmt.Sprintf("%s (mod %d)", strings.Join(terms, " + "), p.modulus)
+}
+
+// Evaluate returns p(x) using Horner's method. Panics if the moduli differ.
+func (p Poly) Evaluate(x ModInt) ModInt {
+	result := MustNew(0, p.modulus)
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		result = result.Multiply(x).Add(p.coefficients[i])
+	}
+	return result
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns p + other. Panics if the moduli differ.
+func (p Poly) Add(other Poly) Poly {
+	p.mustMatch(other)
+	result := make([]ModInt, max(len(p.coefficients), len(other.coefficients)))
+	for i := range result {
+		result[i] = p.Coefficient(i).Add(other.Coefficient(i))
+	}
+	return newPoly(result, p.modulus)
+}
+
+// Subtract returns p - other. Panics if the moduli differ.
+func (p Poly) Subtract(other Poly) Poly {
+	p.mustMatch(other)
+	return p.Add(other.Negate())
+}
+
+// Negate returns -p.
+func (p Poly) Negate() Poly {
+	return p.Scale(MustNew(-1, p.modulus))
+}
+
+// Scale returns p multiplied by the constant factor. Panics if the moduli
+// differ.
+func (p Poly) Scale(factor ModInt) Poly {
+	result := make([]ModInt, len(p.coefficients))
+	for i, c := range p.coefficients {
+		result[i] = c.Multiply(factor)
+	}
+	return newPoly(result, p.modulus)
+}
+
+// Multiply returns p * other. Panics if the moduli differ.
+func (p Poly) Multiply(other Poly) Poly {
+	p.mustMatch(other)
+	if p.IsZero() || other.IsZero() {
+		return Poly{modulus: p.modulus}
+	}
+	result := make([]ModInt, len(p.coefficients)+len(other.coefficients)-1)
+	for i := range result {
+		result[i] = MustNew(0, p.modulus)
+	}
+	for i, a := range p.coefficients {
+		for j, b := range other.coefficients {
+			result[i+j] = result[i+j].Add(a.Multiply(b))
+		}
+	}
+	return newPoly(result, p.modulus)
+}
+
+// DivMod returns the quotient and remainder of the polynomial long division
+// p / other, so that p = quotient * other + remainder with the degree of the
+// remainder smaller than the degree of other. Panics if the moduli differ.
+// Returns an error if other is the zero polynomial.
+func (p Poly) DivMod(other Poly) (Poly, Poly, error) {
+	p.mustMatch(other)
+	if other.IsZero() {
+		return Poly{}, Poly{}, errors.New("division by the zero polynomial")
+	}
+	// The modulus is prime, so the non-zero leading coefficient is invertible.
+	inverse, _ := other.lead().Inverse()
+	remainder := p.Coefficients()
+	quotient := make([]ModInt, max(len(remainder)-len(other.coefficients)+1, 0))
+	for i := len(quotient) - 1; i >= 0; i-- {
+		factor := remainder[i+other.Degree()].Multiply(inverse)
+		quotient[i] = factor
+		for j, c := range other.coefficients {
+			remainder[i+j] = remainder[i+j].Subtract(factor.Multiply(c))
+		}
+	}
+	return newPoly(quotient, p.modulus), newPoly(remainder, p.modulus), nil
+}
+
+// Mod returns the remainder of p / other. Panics if the moduli differ.
+// Returns an error if other is the zero polynomial.
+func (p Poly) Mod(other Poly) (Poly, error) {
+	_, remainder, err := p.DivMod(other)
+	return remainder, err
+}
+
+// Monic returns p divided by its leading coefficient. The zero polynomial is
+// returned unchanged.
+func (p Poly) Monic() Poly {
+	if p.IsZero() {
+		return p
+	}
+	inverse, _ := p.lead().Inverse()
+	return p.Scale(inverse)
+}
+
+// Derivative returns the formal derivative of p.
+func (p Poly) Derivative() Poly {
+	if len(p.coefficients) <= 1 {
+		return Poly{modulus: p.modulus}
+	}
+	result := make([]ModInt, len(p.coefficients)-1)
+	for i := range result {
+		result[i] = p.coefficients[i+1].MultiplyInt(i + 1)
+	}
+	return newPoly(result, p.modulus)
+}
+
+// PowMod returns p^exponent mod m using binary exponentiation. Panics if the
+// moduli differ. Returns an error if m is the zero polynomial.
+func (p Poly) PowMod(exponent uint, m Poly) (Poly, error) {
+	p.mustMatch(m)
+	if m.IsZero() {
+		return Poly{}, errors.New("division by the zero polynomial")
+	}
+	result, _ := MustNewPoly(p.modulus, 1).Mod(m)
+	base, _ := p.Mod(m)
+	for exponent > 0 {
+		if exponent&1 != 0 {
+			result, _ = result.Multiply(base).Mod(m)
+		}
+		base, _ = base.Multiply(base).Mod(m)
+		exponent >>= 1
+	}
+	return result, nil
+}
+
+// ============================================================================
+// GCD and irreducibility
+// ============================================================================
+
+// PolyGcd returns the monic greatest common divisor of a and b, computed with
+// the Euclidean algorithm. The GCD of two zero polynomials is the zero
+// polynomial. Panics if the moduli differ.
+func PolyGcd(a, b Poly) Poly {
+	a.mustMatch(b)
+	for !b.IsZero() {
+		a, b = b, mustMod(a, b)
+	}
+	return a.Monic()
+}
+
+// IsIrreducible checks if p cannot be written as the product of two
+// polynomials of lower degree, using Rabin's test: a polynomial f of degree
+// n > 0 is irreducible if and only if f divides x^(p^n) - x and
+// gcd(x^(p^(n/q)) - x, f) = 1 for every prime factor q of n. Constants
+// (including the zero polynomial) are not irreducible.
+func (p Poly) IsIrreducible() bool {
+	n := p.Degree()
+	if n < 1 {
+		return false
+	}
+	x := MustNewPoly(p.modulus, 0, 1)
+	factors, _ := primes.Factorize(n)
+	for _, factor := range factors {
+		h := p.frobenius(n / factor.Prime).Subtract(x)
+		if PolyGcd(h, p).Degree() != 0 {
+			return false
+		}
+	}
+	return mustMod(p.frobenius(n).Subtract(x), p).IsZero()
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// newPoly returns a Poly with the trailing zero coefficients removed.
+func newPoly(coefficients []ModInt, modulus int) Poly {
+	n := len(coefficients)
+	for n > 0 && coefficients[n-1].IsZero() {
+		n--
+	}
+	return Poly{coefficients: coefficients[:n:n], modulus: modulus}
+}
+
+// lead returns the leading coefficient of a non-zero polynomial.
+func (p Poly) lead() ModInt {
+	return p.coefficients[len(p.coefficients)-1]
+}
+
+// mustMatch panics if the moduli of p and other differ.
+func (p Poly) mustMatch(other Poly) {
+	if p.modulus == 0 || p.modulus != other.modulus {
+		panic(fmt.Sprintf("modular: mismatched moduli %d and %d", p.modulus, other.modulus))
+	}
+}
+
+// frobenius returns x^(p^k) mod the polynomial, where p is the modulus, by
+// raising x to the power p k times.
+func (p Poly) frobenius(k int) Poly {
+	result := MustNewPoly(p.modulus, 0, 1)
+	for range k {
+		result, _ = result.PowMod(uint(p.modulus), p)
+	}
+	return result
+}
+
+// mustMod returns a mod b for a non-zero b.
+func mustMod(a, b Poly) Poly {
+	remainder, err := a.Mod(b)
+	if err != nil {
+		panic(err)
+	}
+	return remainder
+}
@@ -0,0 +1,335 @@
+// Package money provides a Money type: an exact Fraction amount in a given
+// currency. Arithmetic is exact; rounding to the minor unit of the currency
+// (e.g. cents) only happens when explicitly requested with Round, when
+// formatting, or when splitting an amount with Split and Allocate.
+//
+// Split and Allocate never lose or create a cent: the parts always add up to
+// the (rounded) total. "Divide €10 among 3 people" gives €3.34, €3.33 and
+// €3.33.
+//
+// Important details:
+//
+// (*) Money is an immutable value type: methods return a new Money.
+//
+// (*) Combining amounts in different currencies returns an error.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/decimal"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// ============================================================================
+// Currency
+// ============================================================================
+
+// Currency describes a currency: its ISO 4217 code, the number of decimal
+// places of its minor unit and its symbol.
+type Currency struct {
+	Code       string
+	MinorUnits int
+	Symbol     string
+}
+
+// Common currencies.
+var (
+	EUR = Currency{Code: "EUR", MinorUnits: 2, Symbol: "€"}
+	USD = Currency{Code: "USD", MinorUnits: 2, Symbol: "$"}
+	GBP = Currency{Code: "GBP", MinorUnits: 2, Symbol: "£"}
+	CHF = Currency{Code: "CHF", MinorUnits: 2, Symbol: "CHF"}
+	JPY = Currency{Code: "JPY", MinorUnits: 0, Symbol: "¥"}
+	KWD = Currency{Code: "KWD", MinorUnits: 3, Symbol: "KD"}
+)
+
+var currencies = map[string]Currency{
+	"EUR": EUR, "USD": USD, "GBP": GBP, "CHF": CHF, "JPY": JPY, "KWD": KWD,
+}
+
+// LookupCurrency returns the predefined Currency for the specified ISO 4217
+// code (case-insensitive). Returns an error for unknown codes.
+func LookupCurrency(code string) (Currency, error) {
+	currency, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("unknown currency %q", code)
+	}
+	return currency, nil
+}
+
+// ============================================================================
+// Money
+// ============================================================================
+
+// Money represents an exact amount in a currency.
+type Money struct {
+	amount   *fraction.Fraction
+	currency Currency
+}
+
+// New is a constructor function that returns Money with the specified exact
+// amount. Returns an error if the amount is nil.
+func New(amount *fraction.Fraction, currency Currency) (Money, error) {
+	if amount == nil {
+		return Money{}, fraction.ErrNilFraction
+	}
+	return Money{amount: amount.Clone().Simplify(), currency: currency}, nil
+}
+
+// NewFromMinor is a constructor function that returns Money for an amount in
+// minor units, e.g. NewFromMinor(1050, EUR) is €10.50.
+func NewFromMinor(minor int64, currency Currency) Money {
+	return Money{
+		amount:   fraction.MustNew(int(minor), wbmath.PowInt(10, uint(currency.MinorUnits))).Simplify(),
+		currency: currency,
+	}
+}
+
+// Parse is a constructor function that parses a decimal amount like "10.50"
+// or "-0.005" exactly. Returns an error if the amount is invalid.
+func Parse(amount string, currency Currency) (Money, error) {
+	d, err := decimal.Parse(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: d.Fraction(), currency: currency}, nil
+}
+
+// MustParse is a constructor identical to Parse but which panics if an error
+// occurs.
+func MustParse(amount string, currency Currency) Money {
+	m, err := Parse(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Amount returns a copy of the exact amount.
+func (m Money) Amount() *fraction.Fraction {
+	return m.amount.Clone()
+}
+
+// Currency returns the currency of the amount.
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the amount.
+func (m Money) Sign() int {
+	numerator, _ := m.amount.Numerator()
+	switch {
+	case numerator < 0:
+		return -1
+	case numerator > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero checks if the amount equals zero.
+func (m Money) IsZero() bool {
+	return m.Sign() == 0
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns m + other. Returns an error if the currencies differ.
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, currencyMismatch(m.currency, other.currency)
+	}
+	return Money{amount: m.amount.Clone().Add(other.amount).Simplify(), currency: m.currency}, nil
+}
+
+// Subtract returns m - other. Returns an error if the currencies differ.
+func (m Money) Subtract(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, currencyMismatch(m.currency, other.currency)
+	}
+	return Money{amount: m.amount.Clone().Subtract(other.amount).Simplify(), currency: m.currency}, nil
+}
+
+// Multiply returns m * factor, exactly. Returns an error if the factor is
+// nil.
+func (m Money) Multiply(factor *fraction.Fraction) (Money, error) {
+	if factor == nil {
+		return Money{}, fraction.ErrNilFraction
+	}
+	return Money{amount: m.amount.Clone().Multiply(factor).Simplify(), currency: m.currency}, nil
+}
+
+// MultiplyInt returns m * factor.
+func (m Money) MultiplyInt(factor int) Money {
+	return Money{amount: m.amount.Clone().MultiplyInt(factor).Simplify(), currency: m.currency}
+}
+
+// Compare returns -1 if m < other, 0 if m == other and 1 if m > other.
+// Returns an error if the currencies differ.
+func (m Money) Compare(other Money) (int, error) {
+	difference, err := m.Subtract(other)
+	if err != nil {
+		return 0, err
+	}
+	return difference.Sign(), nil
+}
+
+// ============================================================================
+// Rounding, splitting and formatting
+// ============================================================================
+
+// Round returns m rounded to the minor unit of its currency with the
+// specified rounding mode. Returns an error if the amount doesn't fit.
+func (m Money) Round(mode decimal.RoundingMode) (Money, error) {
+	d, err := decimal.NewFromFraction(m.amount, m.currency.MinorUnits, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: d.Fraction(), currency: m.currency}, nil
+}
+
+// Minor returns the amount in minor units (e.g. cents), rounded with the
+// specified mode. Returns an error if the amount doesn't fit.
+func (m Money) Minor(mode decimal.RoundingMode) (int64, error) {
+	d, err := decimal.NewFromFraction(m.amount, m.currency.MinorUnits, mode)
+	if err != nil {
+		return 0, err
+	}
+	return d.Mantissa(), nil
+}
+
+// Split divides m into n parts that differ by at most one minor unit and add
+// up to the total rounded to minor units (half-even). The larger parts come
+// first. Returns an error if n is smaller than 1.
+func (m Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, errors.New("number of parts must be at least 1")
+	}
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// Allocate divides m proportionally to the specified (non-negative) ratios,
+// e.g. Allocate(70, 30). Every part is rounded to minor units; the minor
+// units that remain after rounding down are handed out one by one to the
+// parts with the largest remainders (the earliest part wins ties), so the
+// parts add up exactly to the total rounded to minor units (half-even).
+// The products of the amount and the ratios are computed in 128 bits, so
+// large amounts don't overflow. Returns an error if no ratios are specified,
+// if any ratio is negative, if all ratios are zero or if the sum of the
+// ratios doesn't fit in 64 bits.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("at least one ratio is required")
+	}
+	var total, carry uint64
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, errors.New("ratios must not be negative")
+		}
+		total, carry = bits.Add64(total, uint64(ratio), 0)
+		if carry != 0 {
+			return nil, errors.New("sum of the ratios overflows")
+		}
+	}
+	if total == 0 {
+		return nil, errors.New("at least one ratio must be positive")
+	}
+	minor, err := m.Minor(decimal.HalfEven)
+	if err != nil {
+		return nil, err
+	}
+	// Work with |minor| and restore the sign at the end, so negative amounts
+	// are split symmetrically. As a uint64, |math.MinInt64| fits as well.
+	negative, magnitude := minor < 0, uint64(minor)
+	if negative {
+		magnitude = -magnitude
+	}
+	shares := make([]uint64, len(ratios))
+	remainders := make([]uint64, len(ratios))
+	allocated := uint64(0)
+	for i, ratio := range ratios {
+		// magnitude·ratio/total <= magnitude, so the quotient fits.
+		hi, lo := bits.Mul64(magnitude, uint64(ratio))
+		shares[i], remainders[i] = bits.Div64(hi, lo, total)
+		allocated += shares[i]
+	}
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := uint64(0); i < magnitude-allocated; i++ {
+		shares[order[i]]++
+	}
+	result := make([]Money, len(ratios))
+	for i, share := range shares {
+		value := int64(share)
+		if negative {
+			value = -value
+		}
+		result[i] = NewFromMinor(value, m.currency)
+	}
+	return result, nil
+}
+
+// String implements the fmt.Stringer interface and returns the amount
+// rounded to minor units (half-even) followed by the currency code, e.g.
+// "10.50 EUR".
+func (m Money) String() string {
+	if m.amount == nil {
+		return "NaN"
+	}
+	d, err := decimal.NewFromFraction(m.amount, m.currency.MinorUnits, decimal.HalfEven)
+	if err != nil {
+		return "NaN"
+	}
+	return d.String() + " " + m.currency.Code
+}
+
+// Format returns the amount rounded to minor units (half-even) with the
+// currency symbol and thousands separators, e.g. "-€1,234.50".
+func (m Money) Format() string {
+	if m.amount == nil {
+		return "NaN"
+	}
+	d, err := decimal.NewFromFraction(m.amount, m.currency.MinorUnits, decimal.HalfEven)
+	if err != nil {
+		return "NaN"
+	}
+	digits := d.Abs().String()
+	integer, fractional, _ := strings.Cut(digits, ".")
+	var sb strings.Builder
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(c)
+	}
+	result := m.currency.Symbol + sb.String()
+	if fractional != "" {
+		result += "." + fractional
+	}
+	if d.Sign() < 0 {
+		return "-" + result
+	}
+	return result
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+func currencyMismatch(a, b Currency) error {
+	return fmt.Errorf("currency mismatch: %s and %s", a.Code, b.Code)
+}
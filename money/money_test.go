@@ -0,0 +1,112 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/bogersw/wbmath/decimal"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func formatAll(ms []Money) []string {
+	result := make([]string, len(ms))
+	for i, m := range ms {
+		result[i] = m.String()
+	}
+	return result
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("0.10", EUR)
+	b := MustParse("0.20", EUR)
+	sum, err := a.Add(b)
+	if err != nil || sum.String() != "0.30 EUR" {
+		t.Fatalf("0.10 + 0.20 = %v, %v; want 0.30 EUR", sum, err)
+	}
+	if _, err := a.Add(MustParse("1", USD)); err == nil {
+		t.Fatalf("Add with different currencies should return error")
+	}
+	third, _ := MustParse("10", EUR).Multiply(fraction.MustNew(1, 3))
+	if got := third.MultiplyInt(3); got.String() != "10.00 EUR" {
+		t.Fatalf("10 * 1/3 * 3 = %v; want exactly 10.00 EUR", got)
+	}
+	if c, _ := a.Compare(b); c != -1 {
+		t.Fatalf("Compare = %d; want -1", c)
+	}
+	rounded, _ := third.Round(decimal.HalfUp)
+	if minor, _ := rounded.Minor(decimal.HalfUp); minor != 333 {
+		t.Fatalf("Round(10/3) = %v; want 3.33", rounded)
+	}
+	if _, err := New(nil, EUR); !errors.Is(err, fraction.ErrNilFraction) {
+		t.Fatalf("New(nil) error = %v; want %v", err, fraction.ErrNilFraction)
+	}
+	if _, err := a.Multiply(nil); !errors.Is(err, fraction.ErrNilFraction) {
+		t.Fatalf("Multiply(nil) error = %v; want %v", err, fraction.ErrNilFraction)
+	}
+}
+
+func TestSplitAndAllocate(t *testing.T) {
+	parts, err := MustParse("10", EUR).Split(3)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+	if got := formatAll(parts); !reflect.DeepEqual(got, []string{"3.34 EUR", "3.33 EUR", "3.33 EUR"}) {
+		t.Fatalf("Split(3) = %v", got)
+	}
+	parts, _ = MustParse("0.05", USD).Allocate(70, 30)
+	if got := formatAll(parts); !reflect.DeepEqual(got, []string{"0.04 USD", "0.01 USD"}) {
+		t.Fatalf("Allocate(70, 30) = %v", got)
+	}
+	parts, _ = MustParse("-1", EUR).Split(3)
+	if got := formatAll(parts); !reflect.DeepEqual(got, []string{"-0.34 EUR", "-0.33 EUR", "-0.33 EUR"}) {
+		t.Fatalf("Split(-1, 3) = %v", got)
+	}
+	total := NewFromMinor(0, EUR)
+	parts, _ = MustParse("100", EUR).Allocate(1, 1, 1, 1, 1, 1, 1)
+	for _, part := range parts {
+		total, _ = total.Add(part)
+	}
+	if total.String() != "100.00 EUR" {
+		t.Fatalf("sum of allocated parts = %v; want 100.00 EUR", total)
+	}
+	if _, err := MustParse("1", EUR).Allocate(0, 0); err == nil {
+		t.Fatalf("Allocate with zero ratios should return error")
+	}
+	// The products of large amounts and ratios don't fit in an int64.
+	largest := NewFromMinor(math.MaxInt64, EUR)
+	cases := []struct {
+		ratios []int
+		want   []int64
+	}{
+		{[]int{1, 1, 1}, []int64{3074457345618258603, 3074457345618258602, 3074457345618258602}},
+		{[]int{7000, 3000}, []int64{6456360425798343065, 2767011611056432742}},
+	}
+	for _, c := range cases {
+		parts, err := largest.Allocate(c.ratios...)
+		if err != nil {
+			t.Fatalf("Allocate%v of MaxInt64 minor units returned error: %v", c.ratios, err)
+		}
+		for i, part := range parts {
+			if got, _ := part.Minor(decimal.HalfEven); got != c.want[i] {
+				t.Fatalf("Allocate%v of MaxInt64 minor units = %v; want %v minor units at %d", c.ratios, formatAll(parts), c.want[i], i)
+			}
+		}
+	}
+	if _, err := largest.Allocate(math.MaxInt, math.MaxInt, math.MaxInt); err == nil {
+		t.Fatalf("Allocate with ratios summing beyond 64 bits should return error")
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	if got := MustParse("-1234567.5", EUR).Format(); got != "-€1,234,567.50" {
+		t.Fatalf("Format() = %q; want \"-€1,234,567.50\"", got)
+	}
+	if got := MustParse("1234", JPY).Format(); got != "¥1,234" {
+		t.Fatalf("Format() = %q; want \"¥1,234\"", got)
+	}
+	if c, err := LookupCurrency("usd"); err != nil || c != USD {
+		t.Fatalf("LookupCurrency(usd) = %v, %v", c, err)
+	}
+}
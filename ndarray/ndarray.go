@@ -0,0 +1,558 @@
+// Package ndarray provides a generic N-dimensional array (tensor) type,
+// NDArray[T], which generalizes vector.Vector to multiple dimensions.
+//
+// An NDArray is a view on a flat backing slice described by a shape, strides
+// and an offset. Reshape (of contiguous arrays), Transpose and Slice return
+// views that share data with the original; Copy returns an independent,
+// contiguous array.
+//
+// Available functionality includes constructors (New, NewFromSlice,
+// NewFromValue, NewFromVector), element access (At, Set), views (Reshape,
+// Transpose, Slice), broadcasting element-wise arithmetic (Add, Subtract,
+// Multiply, Divide), scalar operations (AddScalar, Scale, Map) and
+// reductions (Sum, SumAxis, ProductAxis, MinAxis, MaxAxis, MeanAxis).
+//
+// Important details:
+//
+// (*) Broadcasting follows the NumPy rules: shapes are aligned on their last
+// dimension and two dimensions are compatible when they are equal or one of
+// them is 1.
+//
+// (*) Element-wise arithmetic returns a new array; views are never modified.
+// Set and the scalar operations modify the array (and therefore every view
+// on the same data) in-place.
+//
+// (*) Integer division by zero panics, as it does for the built-in types.
+package ndarray
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// NDArray is an N-dimensional array of signed numbers.
+type NDArray[T wbmath.SignedNumber] struct {
+	data    []T
+	shape   []int
+	strides []int
+	offset  int
+}
+
+// Range selects the indices Start, Start+Step, ... up to (excluding) Stop
+// along one axis. Negative Start and Stop count from the end of the axis; a
+// Stop of 0 means "up to the end". A Step of 0 is treated as 1. Negative
+// steps are not supported.
+type Range struct {
+	Start, Stop, Step int
+}
+
+// All selects every index along an axis.
+var All = Range{}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns a zero-filled NDArray with the
+// specified shape. Returns an error if a dimension is negative.
+func New[T wbmath.SignedNumber](shape ...int) (*NDArray[T], error) {
+	size, err := shapeSize(shape)
+	if err != nil {
+		return nil, err
+	}
+	return newContiguous(make([]T, size), shape), nil
+}
+
+// NewFromValue is a constructor function that returns an NDArray with the
+// specified shape in which every element equals `value`. Returns an error if
+// a dimension is negative.
+func NewFromValue[T wbmath.SignedNumber](value T, shape ...int) (*NDArray[T], error) {
+	a, err := New[T](shape...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range a.data {
+		a.data[i] = value
+	}
+	return a, nil
+}
+
+// NewFromSlice is a constructor function that returns an NDArray with the
+// specified shape, filled with a copy of `data` in row-major order. Returns
+// an error if the length of the data doesn't match the shape.
+func NewFromSlice[T wbmath.SignedNumber](data []T, shape ...int) (*NDArray[T], error) {
+	size, err := shapeSize(shape)
+	if err != nil {
+		return nil, err
+	}
+	if size != len(data) {
+		return nil, fmt.Errorf("cannot create array of shape %v from %d elements", shape, len(data))
+	}
+	return newContiguous(append([]T(nil), data...), shape), nil
+}
+
+// NewFromVector is a constructor function that returns a one-dimensional
+// NDArray with a copy of the elements of the Vector.
+func NewFromVector[T wbmath.SignedNumber](v vector.Vector[T]) *NDArray[T] {
+	return newContiguous(append([]T(nil), v...), []int{len(v)})
+}
+
+// ============================================================================
+// Metadata and element access
+// ============================================================================
+
+// Shape returns a copy of the shape of the array.
+func (a *NDArray[T]) Shape() []int {
+	return append([]int(nil), a.shape...)
+}
+
+// Strides returns a copy of the strides of the array, in elements.
+func (a *NDArray[T]) Strides() []int {
+	return append([]int(nil), a.strides...)
+}
+
+// Ndim returns the number of dimensions of the array.
+func (a *NDArray[T]) Ndim() int {
+	return len(a.shape)
+}
+
+// Size returns the total number of elements of the array.
+func (a *NDArray[T]) Size() int {
+	size, _ := shapeSize(a.shape)
+	return size
+}
+
+// IsContiguous checks if the elements of the array are laid out in row-major
+// order without gaps.
+func (a *NDArray[T]) IsContiguous() bool {
+	stride := 1
+	for i := len(a.shape) - 1; i >= 0; i-- {
+		if a.shape[i] != 1 && a.strides[i] != stride {
+			return false
+		}
+		stride *= a.shape[i]
+	}
+	return true
+}
+
+// At returns the element at the specified index. Returns an error if the
+// index is out of range or has the wrong number of dimensions.
+func (a *NDArray[T]) At(index ...int) (T, error) {
+	position, err := a.position(index)
+	if err != nil {
+		return 0, err
+	}
+	return a.data[position], nil
+}
+
+// Set sets the element at the specified index to `value`. Returns an error
+// if the index is out of range or has the wrong number of dimensions.
+func (a *NDArray[T]) Set(value T, index ...int) error {
+	position, err := a.position(index)
+	if err != nil {
+		return err
+	}
+	a.data[position] = value
+	return nil
+}
+
+// Data returns a copy of the elements of the array in row-major order.
+func (a *NDArray[T]) Data() []T {
+	data := make([]T, 0, a.Size())
+	a.forEach(func(_ []int, position int) {
+		data = append(data, a.data[position])
+	})
+	return data
+}
+
+// Vector returns a copy of the elements of the array in row-major order as a
+// Vector.
+func (a *NDArray[T]) Vector() vector.Vector[T] {
+	return vector.New(a.Data()...)
+}
+
+// Copy returns an independent, contiguous copy of the array.
+func (a *NDArray[T]) Copy() *NDArray[T] {
+	return newContiguous(a.Data(), a.shape)
+}
+
+// ============================================================================
+// Views
+// ============================================================================
+
+// Reshape returns the array with a new shape and the same number of
+// elements. One dimension may be -1, in which case it is inferred. The result
+// is a view if the array is contiguous and a copy otherwise. Returns an error
+// if the shapes are incompatible.
+func (a *NDArray[T]) Reshape(shape ...int) (*NDArray[T], error) {
+	shape = append([]int(nil), shape...)
+	size := a.Size()
+	inferred, known := -1, 1
+	for i, n := range shape {
+		switch {
+		case n == -1 && inferred == -1:
+			inferred = i
+		case n < 0:
+			return nil, fmt.Errorf("invalid shape %v", shape)
+		default:
+			known *= n
+		}
+	}
+	if inferred >= 0 {
+		if known == 0 || size%known != 0 {
+			return nil, fmt.Errorf("cannot reshape array of size %d into shape %v", size, shape)
+		}
+		shape[inferred] = size / known
+		known = size
+	}
+	if known != size {
+		return nil, fmt.Errorf("cannot reshape array of size %d into shape %v", size, shape)
+	}
+	if !a.IsContiguous() {
+		return newContiguous(a.Data(), shape), nil
+	}
+	view := newContiguous(a.data, shape)
+	view.offset = a.offset
+	return view, nil
+}
+
+// Transpose returns a view with the axes permuted: axis i of the result is
+// axis axes[i] of the array. Without arguments the order of the axes is
+// reversed. Returns an error if `axes` is not a permutation of the axes.
+func (a *NDArray[T]) Transpose(axes ...int) (*NDArray[T], error) {
+	n := len(a.shape)
+	if len(axes) == 0 {
+		axes = make([]int, n)
+		for i := range axes {
+			axes[i] = n - 1 - i
+		}
+	}
+	if len(axes) != n {
+		return nil, fmt.Errorf("axes %v don't match array with %d dimensions", axes, n)
+	}
+	seen := make([]bool, n)
+	shape, strides := make([]int, n), make([]int, n)
+	for i, axis := range axes {
+		if axis < 0 || axis >= n || seen[axis] {
+			return nil, fmt.Errorf("axes %v are not a permutation", axes)
+		}
+		seen[axis] = true
+		shape[i], strides[i] = a.shape[axis], a.strides[axis]
+	}
+	return &NDArray[T]{data: a.data, shape: shape, strides: strides, offset: a.offset}, nil
+}
+
+// Slice returns a view with the specified ranges applied to the leading axes;
+// axes without a range are kept whole. Returns an error if there are more
+// ranges than axes or if a range is invalid.
+func (a *NDArray[T]) Slice(ranges ...Range) (*NDArray[T], error) {
+	if len(ranges) > len(a.shape) {
+		return nil, fmt.Errorf("too many ranges (%d) for array with %d dimensions", len(ranges), len(a.shape))
+	}
+	view := &NDArray[T]{data: a.data, shape: a.Shape(), strides: a.Strides(), offset: a.offset}
+	for axis, r := range ranges {
+		n := a.shape[axis]
+		start, stop, step := r.Start, r.Stop, r.Step
+		if start < 0 {
+			start += n
+		}
+		if stop <= 0 {
+			stop += n
+		}
+		if step == 0 {
+			step = 1
+		}
+		if step < 0 || start < 0 || stop > n || start > stop {
+			return nil, fmt.Errorf("invalid range %+v for axis %d with length %d", r, axis, n)
+		}
+		view.offset += start * a.strides[axis]
+		view.shape[axis] = (stop - start + step - 1) / step
+		view.strides[axis] = a.strides[axis] * step
+	}
+	return view, nil
+}
+
+// ============================================================================
+// Element-wise arithmetic
+// ============================================================================
+
+// Add returns a new array with the element-wise sum of the two arrays after
+// broadcasting. Returns an error if the shapes cannot be broadcast.
+func (a *NDArray[T]) Add(other *NDArray[T]) (*NDArray[T], error) {
+	return a.broadcast(other, func(x, y T) T { return x + y })
+}
+
+// Subtract returns a new array with the element-wise difference of the two
+// arrays after broadcasting. Returns an error if the shapes cannot be
+// broadcast.
+func (a *NDArray[T]) Subtract(other *NDArray[T]) (*NDArray[T], error) {
+	return a.broadcast(other, func(x, y T) T { return x - y })
+}
+
+// Multiply returns a new array with the element-wise product of the two
+// arrays after broadcasting. Returns an error if the shapes cannot be
+// broadcast.
+func (a *NDArray[T]) Multiply(other *NDArray[T]) (*NDArray[T], error) {
+	return a.broadcast(other, func(x, y T) T { return x * y })
+}
+
+// Divide returns a new array with the element-wise quotient of the two
+// arrays after broadcasting. Returns an error if the shapes cannot be
+// broadcast.
+func (a *NDArray[T]) Divide(other *NDArray[T]) (*NDArray[T], error) {
+	return a.broadcast(other, func(x, y T) T { return x / y })
+}
+
+// AddScalar adds `value` to every element in-place and returns the array.
+func (a *NDArray[T]) AddScalar(value T) *NDArray[T] {
+	return a.Map(func(x T) T { return x + value })
+}
+
+// Scale multiplies every element by `factor` in-place and returns the array.
+func (a *NDArray[T]) Scale(factor T) *NDArray[T] {
+	return a.Map(func(x T) T { return x * factor })
+}
+
+// Map replaces every element by transform(element) in-place and returns the
+// array.
+func (a *NDArray[T]) Map(transform func(T) T) *NDArray[T] {
+	a.forEach(func(_ []int, position int) {
+		a.data[position] = transform(a.data[position])
+	})
+	return a
+}
+
+// BroadcastShape returns the shape that results from broadcasting arrays
+// with the specified shapes. Returns an error if the shapes are
+// incompatible.
+func BroadcastShape(a, b []int) ([]int, error) {
+	n := max(len(a), len(b))
+	shape := make([]int, n)
+	for i := 1; i <= n; i++ {
+		x, y := 1, 1
+		if i <= len(a) {
+			x = a[len(a)-i]
+		}
+		if i <= len(b) {
+			y = b[len(b)-i]
+		}
+		switch {
+		case x == y || y == 1:
+			shape[n-i] = x
+		case x == 1:
+			shape[n-i] = y
+		default:
+			return nil, fmt.Errorf("shapes %v and %v cannot be broadcast", a, b)
+		}
+	}
+	return shape, nil
+}
+
+// ============================================================================
+// Reductions
+// ============================================================================
+
+// Sum returns the sum of all elements.
+func (a *NDArray[T]) Sum() T {
+	var sum T
+	a.forEach(func(_ []int, position int) { sum += a.data[position] })
+	return sum
+}
+
+// SumAxis returns the sums along the specified axis; the axis is removed
+// from the shape. Returns an error if the axis is out of range.
+func (a *NDArray[T]) SumAxis(axis int) (*NDArray[T], error) {
+	return a.reduce(axis, func(acc, x T) T { return acc + x })
+}
+
+// ProductAxis returns the products along the specified axis. Returns an
+// error if the axis is out of range.
+func (a *NDArray[T]) ProductAxis(axis int) (*NDArray[T], error) {
+	return a.reduce(axis, func(acc, x T) T { return acc * x })
+}
+
+// MinAxis returns the minima along the specified axis. Returns an error if
+// the axis is out of range or has length zero.
+func (a *NDArray[T]) MinAxis(axis int) (*NDArray[T], error) {
+	return a.reduce(axis, func(acc, x T) T { return min(acc, x) })
+}
+
+// MaxAxis returns the maxima along the specified axis. Returns an error if
+// the axis is out of range or has length zero.
+func (a *NDArray[T]) MaxAxis(axis int) (*NDArray[T], error) {
+	return a.reduce(axis, func(acc, x T) T { return max(acc, x) })
+}
+
+// MeanAxis returns the arithmetic means along the specified axis as float64.
+// Returns an error if the axis is out of range or has length zero.
+func (a *NDArray[T]) MeanAxis(axis int) (*NDArray[float64], error) {
+	sums, err := a.SumAxis(axis)
+	if err != nil {
+		return nil, err
+	}
+	if a.shape[axis] == 0 {
+		return nil, errors.New("mean of empty axis")
+	}
+	data := sums.Data()
+	means := make([]float64, len(data))
+	for i, sum := range data {
+		means[i] = float64(sum) / float64(a.shape[axis])
+	}
+	return newContiguous(means, sums.shape), nil
+}
+
+// String implements the fmt.Stringer interface and returns the elements as
+// nested brackets, e.g. "[[1 2] [3 4]]".
+func (a *NDArray[T]) String() string {
+	var sb strings.Builder
+	a.format(&sb, 0, a.offset)
+	return sb.String()
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// newContiguous wraps `data` in a row-major array of the specified shape.
+func newContiguous[T wbmath.SignedNumber](data []T, shape []int) *NDArray[T] {
+	shape = append([]int(nil), shape...)
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= shape[i]
+	}
+	return &NDArray[T]{data: data, shape: shape, strides: strides}
+}
+
+func shapeSize(shape []int) (int, error) {
+	size := 1
+	for _, n := range shape {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid shape %v", shape)
+		}
+		size *= n
+	}
+	return size, nil
+}
+
+// position converts a multi-index into a position in the backing slice.
+func (a *NDArray[T]) position(index []int) (int, error) {
+	if len(index) != len(a.shape) {
+		return 0, fmt.Errorf("index %v doesn't match array with %d dimensions", index, len(a.shape))
+	}
+	position := a.offset
+	for i, n := range index {
+		if n < 0 || n >= a.shape[i] {
+			return 0, fmt.Errorf("index %v out of range for shape %v", index, a.shape)
+		}
+		position += n * a.strides[i]
+	}
+	return position, nil
+}
+
+// forEach calls fn for every multi-index of the array in row-major order,
+// together with the corresponding position in the backing slice. The index
+// slice is reused between calls.
+func (a *NDArray[T]) forEach(fn func(index []int, position int)) {
+	if a.Size() == 0 {
+		return
+	}
+	index := make([]int, len(a.shape))
+	position := a.offset
+	for {
+		fn(index, position)
+		axis := len(index) - 1
+		for ; axis >= 0; axis-- {
+			index[axis]++
+			position += a.strides[axis]
+			if index[axis] < a.shape[axis] {
+				break
+			}
+			position -= index[axis] * a.strides[axis]
+			index[axis] = 0
+		}
+		if axis < 0 {
+			return
+		}
+	}
+}
+
+// broadcastStrides returns the strides with which the array is read when it
+// is broadcast to `shape`: broadcast dimensions get stride 0.
+func (a *NDArray[T]) broadcastStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	shift := len(shape) - len(a.shape)
+	for i := range a.shape {
+		if a.shape[i] != 1 {
+			strides[i+shift] = a.strides[i]
+		}
+	}
+	return strides
+}
+
+func (a *NDArray[T]) broadcast(other *NDArray[T], op func(x, y T) T) (*NDArray[T], error) {
+	shape, err := BroadcastShape(a.shape, other.shape)
+	if err != nil {
+		return nil, err
+	}
+	result, _ := New[T](shape...)
+	aStrides, bStrides := a.broadcastStrides(shape), other.broadcastStrides(shape)
+	i := 0
+	result.forEach(func(index []int, _ int) {
+		x, y := a.offset, other.offset
+		for axis, n := range index {
+			x += n * aStrides[axis]
+			y += n * bStrides[axis]
+		}
+		result.data[i] = op(a.data[x], other.data[y])
+		i++
+	})
+	return result, nil
+}
+
+// reduce folds the elements along `axis` with `op`, starting from the first
+// element along the axis.
+func (a *NDArray[T]) reduce(axis int, op func(acc, x T) T) (*NDArray[T], error) {
+	if axis < 0 || axis >= len(a.shape) {
+		return nil, fmt.Errorf("axis %d out of range for array with %d dimensions", axis, len(a.shape))
+	}
+	if a.shape[axis] == 0 {
+		return nil, errors.New("reduction of empty axis")
+	}
+	shape := append(append([]int(nil), a.shape[:axis]...), a.shape[axis+1:]...)
+	result, _ := New[T](shape...)
+	// Iterate over the array with the reduced axis removed, then fold along
+	// the axis for each result element.
+	outer := &NDArray[T]{data: a.data, shape: shape, offset: a.offset,
+		strides: append(append([]int(nil), a.strides[:axis]...), a.strides[axis+1:]...)}
+	i := 0
+	outer.forEach(func(_ []int, position int) {
+		acc := a.data[position]
+		for k := 1; k < a.shape[axis]; k++ {
+			acc = op(acc, a.data[position+k*a.strides[axis]])
+		}
+		result.data[i] = acc
+		i++
+	})
+	return result, nil
+}
+
+func (a *NDArray[T]) format(sb *strings.Builder, axis, position int) {
+	if axis == len(a.shape) {
+		fmt.Fprint(sb, a.data[position])
+		return
+	}
+	sb.WriteByte('[')
+	for i := 0; i < a.shape[axis]; i++ {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		a.format(sb, axis+1, position+i*a.strides[axis])
+	}
+	sb.WriteByte(']')
+}
@@ -0,0 +1,102 @@
+package ndarray
+
+import (
+	"reflect"
+	"testing"
+)
+
+func arange(n int, shape ...int) *NDArray[int] {
+	data := make([]int, n)
+	for i := range data {
+		data[i] = i
+	}
+	a, err := NewFromSlice(data, shape...)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func TestAccessAndViews(t *testing.T) {
+	a := arange(6, 2, 3)
+	if v, _ := a.At(1, 2); v != 5 {
+		t.Fatalf("At(1, 2) = %d; want 5", v)
+	}
+	if _, err := a.At(2, 0); err == nil {
+		t.Fatalf("At(2, 0) should return error")
+	}
+	tr, _ := a.Transpose()
+	if got := tr.String(); got != "[[0 3] [1 4] [2 5]]" {
+		t.Fatalf("Transpose() = %s", got)
+	}
+	if tr.IsContiguous() {
+		t.Fatalf("transposed array should not be contiguous")
+	}
+	r, err := tr.Reshape(-1)
+	if err != nil || !reflect.DeepEqual(r.Data(), []int{0, 3, 1, 4, 2, 5}) {
+		t.Fatalf("Reshape(-1) = %v, %v", r, err)
+	}
+	s, _ := arange(12, 3, 4).Slice(Range{Start: 1}, Range{Start: 0, Stop: 4, Step: 2})
+	if got := s.String(); got != "[[4 6] [8 10]]" {
+		t.Fatalf("Slice() = %s", got)
+	}
+	// Views share data with the original.
+	_ = s.Set(-1, 0, 0)
+	if got, _ := s.At(0, 0); got != -1 {
+		t.Fatalf("Set on view failed")
+	}
+	view, _ := a.Reshape(3, 2)
+	_ = view.Set(42, 0, 0)
+	if got, _ := a.At(0, 0); got != 42 {
+		t.Fatalf("Reshape of contiguous array should be a view")
+	}
+	if _, err := a.Reshape(4, 2); err == nil {
+		t.Fatalf("Reshape(4, 2) should return error")
+	}
+}
+
+func TestBroadcasting(t *testing.T) {
+	a := arange(6, 2, 3)
+	row, _ := NewFromSlice([]int{10, 20, 30}, 3)
+	sum, err := a.Add(row)
+	if err != nil || sum.String() != "[[10 21 32] [13 24 35]]" {
+		t.Fatalf("Add(row) = %v, %v", sum, err)
+	}
+	col, _ := NewFromSlice([]int{1, 2}, 2, 1)
+	product, _ := col.Multiply(row)
+	if got := product.String(); got != "[[10 20 30] [20 40 60]]" {
+		t.Fatalf("outer product = %s", got)
+	}
+	if _, err := a.Add(col.Copy().Scale(1)); err != nil {
+		t.Fatalf("(2,3) + (2,1) should broadcast: %v", err)
+	}
+	other, _ := New[int](2)
+	if _, err := a.Add(other); err == nil {
+		t.Fatalf("(2,3) + (2) should return error")
+	}
+}
+
+func TestReductions(t *testing.T) {
+	a := arange(6, 2, 3)
+	if a.Sum() != 15 {
+		t.Fatalf("Sum() = %d; want 15", a.Sum())
+	}
+	s0, _ := a.SumAxis(0)
+	s1, _ := a.SumAxis(1)
+	if !reflect.DeepEqual(s0.Data(), []int{3, 5, 7}) || !reflect.DeepEqual(s1.Data(), []int{3, 12}) {
+		t.Fatalf("SumAxis = %v, %v", s0, s1)
+	}
+	tr, _ := a.Transpose()
+	m, _ := tr.MaxAxis(1)
+	if !reflect.DeepEqual(m.Data(), []int{3, 4, 5}) {
+		t.Fatalf("MaxAxis(1) of transpose = %v", m)
+	}
+	mean, _ := a.MeanAxis(0)
+	if !reflect.DeepEqual(mean.Data(), []float64{1.5, 2.5, 3.5}) {
+		t.Fatalf("MeanAxis(0) = %v", mean)
+	}
+	total, _ := s1.SumAxis(0)
+	if total.Ndim() != 0 || total.Sum() != 15 {
+		t.Fatalf("reducing a 1D array should give a 0D array, got %v", total)
+	}
+}
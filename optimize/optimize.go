@@ -0,0 +1,365 @@
+// Package optimize provides function minimization: one-dimensional methods
+// (golden-section search and Brent's method) on a bracketing interval, and
+// multi-dimensional methods (gradient descent with a backtracking line search
+// and the Nelder-Mead simplex method) over vector.Vector[float64].
+//
+// All methods return a result with the minimizer, the function value there
+// and diagnostics (iterations, function evaluations, convergence). Failing to
+// converge within the maximum number of iterations is not an error: check the
+// Converged field of the result.
+//
+// Important details:
+//
+// (*) All methods find a local minimum. Maximize f by minimizing -f.
+//
+// (*) Settings can be nil: zero fields of Settings are replaced by defaults.
+//
+// (*) The starting Vector passed to the multi-dimensional methods is never
+// modified.
+package optimize
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Settings controls the stopping criteria of the minimization methods.
+type Settings struct {
+	// Tolerance is the convergence tolerance on the position (1-D methods,
+	// Nelder-Mead) or on the gradient norm (gradient descent). Default 1e-8.
+	Tolerance float64
+	// MaxIterations is the maximum number of iterations. Default 1000.
+	MaxIterations int
+	// StepSize is the initial step size of gradient descent and the initial
+	// simplex size of Nelder-Mead. Default 1.
+	StepSize float64
+}
+
+// Result1D is the result of a one-dimensional minimization.
+type Result1D struct {
+	X           float64
+	Value       float64
+	Iterations  int
+	Evaluations int
+	Converged   bool
+}
+
+// Result is the result of a multi-dimensional minimization.
+type Result struct {
+	X           vector.Vector[float64]
+	Value       float64
+	Iterations  int
+	Evaluations int
+	Converged   bool
+}
+
+// withDefaults returns a copy of the settings with zero fields replaced by
+// their defaults.
+func (s *Settings) withDefaults() Settings {
+	result := Settings{Tolerance: 1e-8, MaxIterations: 1000, StepSize: 1}
+	if s == nil {
+		return result
+	}
+	if s.Tolerance > 0 {
+		result.Tolerance = s.Tolerance
+	}
+	if s.MaxIterations > 0 {
+		result.MaxIterations = s.MaxIterations
+	}
+	if s.StepSize > 0 {
+		result.StepSize = s.StepSize
+	}
+	return result
+}
+
+// ============================================================================
+// One-dimensional minimization
+// ============================================================================
+
+// goldenRatio is 1/phi: the factor by which the bracket shrinks per step.
+var goldenRatio = (math.Sqrt(5) - 1) / 2
+
+// GoldenSection minimizes f on the interval [a, b] with golden-section
+// search. f should be unimodal on the interval. Returns an error if a >= b.
+func GoldenSection(f func(float64) float64, a, b float64, settings *Settings) (Result1D, error) {
+	if !(a < b) {
+		return Result1D{}, errors.New("interval must satisfy a < b")
+	}
+	s := settings.withDefaults()
+	c := b - goldenRatio*(b-a)
+	d := a + goldenRatio*(b-a)
+	fc, fd := f(c), f(d)
+	result := Result1D{Evaluations: 2}
+	for result.Iterations < s.MaxIterations {
+		if b-a <= s.Tolerance*(1+math.Abs(a)+math.Abs(b)) {
+			result.Converged = true
+			break
+		}
+		result.Iterations++
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - goldenRatio*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + goldenRatio*(b-a)
+			fd = f(d)
+		}
+		result.Evaluations++
+	}
+	if fc < fd {
+		result.X, result.Value = c, fc
+	} else {
+		result.X, result.Value = d, fd
+	}
+	return result, nil
+}
+
+// Brent minimizes f on the interval [a, b] with Brent's method: a
+// combination of golden-section steps and parabolic interpolation, which
+// converges superlinearly for smooth functions. Returns an error if a >= b.
+func Brent(f func(float64) float64, a, b float64, settings *Settings) (Result1D, error) {
+	if !(a < b) {
+		return Result1D{}, errors.New("interval must satisfy a < b")
+	}
+	s := settings.withDefaults()
+	const cgold = 0.3819660112501051 // 1 - 1/phi
+	// x: best point so far, w: second best, v: previous value of w.
+	x := a + cgold*(b-a)
+	w, v := x, x
+	fx := f(x)
+	fw, fv := fx, fx
+	var d, e float64
+	result := Result1D{Evaluations: 1}
+	for result.Iterations < s.MaxIterations {
+		m := (a + b) / 2
+		tol1 := s.Tolerance*math.Abs(x) + 1e-12
+		tol2 := 2 * tol1
+		if math.Abs(x-m) <= tol2-(b-a)/2 {
+			result.Converged = true
+			break
+		}
+		result.Iterations++
+		useGolden := true
+		if math.Abs(e) > tol1 {
+			// Try a parabolic fit through x, w and v.
+			r := (x - w) * (fx - fv)
+			q := (x - v) * (fx - fw)
+			p := (x-v)*q - (x-w)*r
+			q = 2 * (q - r)
+			if q > 0 {
+				p = -p
+			}
+			q = math.Abs(q)
+			if math.Abs(p) < math.Abs(q*e/2) && p > q*(a-x) && p < q*(b-x) {
+				e = d
+				d = p / q
+				u := x + d
+				if u-a < tol2 || b-u < tol2 {
+					d = math.Copysign(tol1, m-x)
+				}
+				useGolden = false
+			}
+		}
+		if useGolden {
+			if x < m {
+				e = b - x
+			} else {
+				e = a - x
+			}
+			d = cgold * e
+		}
+		u := x + d
+		if math.Abs(d) < tol1 {
+			u = x + math.Copysign(tol1, d)
+		}
+		fu := f(u)
+		result.Evaluations++
+		if fu <= fx {
+			if u < x {
+				b = x
+			} else {
+				a = x
+			}
+			v, fv, w, fw, x, fx = w, fw, x, fx, u, fu
+		} else {
+			if u < x {
+				a = u
+			} else {
+				b = u
+			}
+			if fu <= fw || w == x {
+				v, fv, w, fw = w, fw, u, fu
+			} else if fu <= fv || v == x || v == w {
+				v, fv = u, fu
+			}
+		}
+	}
+	result.X, result.Value = x, fx
+	return result, nil
+}
+
+// ============================================================================
+// Multi-dimensional minimization
+// ============================================================================
+
+// GradientDescent minimizes f starting at x0 with steepest descent and a
+// backtracking (Armijo) line search. If gradient is nil, the gradient is
+// approximated with central differences. Converges when the norm of the
+// gradient drops below the tolerance. Returns an error if x0 is empty.
+func GradientDescent(
+	f func(vector.Vector[float64]) float64,
+	gradient func(vector.Vector[float64]) vector.Vector[float64],
+	x0 vector.Vector[float64],
+	settings *Settings,
+) (Result, error) {
+	if len(x0) == 0 {
+		return Result{}, errors.New("starting point must not be empty")
+	}
+	s := settings.withDefaults()
+	result := Result{}
+	evaluate := func(x vector.Vector[float64]) float64 {
+		result.Evaluations++
+		return f(x)
+	}
+	if gradient == nil {
+		gradient = func(x vector.Vector[float64]) vector.Vector[float64] {
+			return numericGradient(evaluate, x)
+		}
+	}
+	x := x0.Clone()
+	fx := evaluate(x)
+	step := s.StepSize
+	for result.Iterations < s.MaxIterations {
+		g := gradient(x)
+		norm := g.Magnitude()
+		if norm <= s.Tolerance {
+			result.Converged = true
+			break
+		}
+		result.Iterations++
+		// Backtracking line search: halve the step until the Armijo
+		// condition f(x - t*g) <= f(x) - t/2 * |g|^2 holds.
+		for {
+			candidate := x.Clone().Subtract(g.Clone().Scale(step), 0)
+			fCandidate := evaluate(candidate)
+			if fCandidate <= fx-step/2*norm*norm {
+				x, fx = candidate, fCandidate
+				// Allow the step to grow again after a successful step.
+				step *= 2
+				break
+			}
+			step /= 2
+			if step < 1e-20 {
+				result.X, result.Value = x, fx
+				return result, nil
+			}
+		}
+	}
+	result.X, result.Value = x, fx
+	return result, nil
+}
+
+// NelderMead minimizes f starting at x0 with the Nelder-Mead (downhill
+// simplex) method, which doesn't need derivatives. The initial simplex
+// consists of x0 and x0 moved by StepSize along each axis. Converges when
+// the simplex has shrunk below the tolerance. Returns an error if x0 is
+// empty.
+func NelderMead(f func(vector.Vector[float64]) float64, x0 vector.Vector[float64], settings *Settings) (Result, error) {
+	if len(x0) == 0 {
+		return Result{}, errors.New("starting point must not be empty")
+	}
+	s := settings.withDefaults()
+	const (
+		alpha = 1.0 // reflection
+		gamma = 2.0 // expansion
+		rho   = 0.5 // contraction
+		sigma = 0.5 // shrink
+	)
+	n := len(x0)
+	result := Result{}
+	type vertex struct {
+		x     vector.Vector[float64]
+		value float64
+	}
+	newVertex := func(x vector.Vector[float64]) vertex {
+		result.Evaluations++
+		return vertex{x: x, value: f(x)}
+	}
+	simplex := make([]vertex, n+1)
+	simplex[0] = newVertex(x0.Clone())
+	for i := 0; i < n; i++ {
+		x := x0.Clone()
+		x[i] += s.StepSize
+		simplex[i+1] = newVertex(x)
+	}
+	// along returns centroid + t * (centroid - worst).
+	along := func(centroid, worst vector.Vector[float64], t float64) vector.Vector[float64] {
+		direction := centroid.Clone().Subtract(worst, 0)
+		return centroid.Clone().Add(direction.Scale(t), 0)
+	}
+	for result.Iterations < s.MaxIterations {
+		sort.Slice(simplex, func(i, j int) bool { return simplex[i].value < simplex[j].value })
+		// Converged when all vertices are within the tolerance of the best.
+		size := 0.0
+		for _, v := range simplex[1:] {
+			size = math.Max(size, v.x.Clone().Subtract(simplex[0].x, 0).Magnitude())
+		}
+		if size <= s.Tolerance {
+			result.Converged = true
+			break
+		}
+		result.Iterations++
+		centroid := vector.NewFromValue(0.0, n)
+		for _, v := range simplex[:n] {
+			centroid.Add(v.x, 0)
+		}
+		centroid.Scale(1 / float64(n))
+		worst := simplex[n]
+		reflected := newVertex(along(centroid, worst.x, alpha))
+		switch {
+		case reflected.value < simplex[0].value:
+			expanded := newVertex(along(centroid, worst.x, gamma))
+			if expanded.value < reflected.value {
+				simplex[n] = expanded
+			} else {
+				simplex[n] = reflected
+			}
+		case reflected.value < simplex[n-1].value:
+			simplex[n] = reflected
+		default:
+			contracted := newVertex(along(centroid, worst.x, -rho))
+			if contracted.value < worst.value {
+				simplex[n] = contracted
+				continue
+			}
+			// Shrink all vertices towards the best one.
+			best := simplex[0].x
+			for i := 1; i <= n; i++ {
+				x := best.Clone().Add(simplex[i].x.Clone().Subtract(best, 0).Scale(sigma), 0)
+				simplex[i] = newVertex(x)
+			}
+		}
+	}
+	sort.Slice(simplex, func(i, j int) bool { return simplex[i].value < simplex[j].value })
+	result.X, result.Value = simplex[0].x, simplex[0].value
+	return result, nil
+}
+
+// numericGradient approximates the gradient of f at x with central
+// differences.
+func numericGradient(f func(vector.Vector[float64]) float64, x vector.Vector[float64]) vector.Vector[float64] {
+	gradient := vector.NewFromValue(0.0, len(x))
+	for i := range x {
+		h := 1e-6 * math.Max(1, math.Abs(x[i]))
+		forward := x.Clone()
+		forward[i] += h
+		backward := x.Clone()
+		backward[i] -= h
+		gradient[i] = (f(forward) - f(backward)) / (2 * h)
+	}
+	return gradient
+}
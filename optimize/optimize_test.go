@@ -0,0 +1,75 @@
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+func TestOneDimensional(t *testing.T) {
+	f := func(x float64) float64 { return (x-2)*(x-2) + 1 }
+	methods := map[string]func(func(float64) float64, float64, float64, *Settings) (Result1D, error){
+		"GoldenSection": GoldenSection,
+		"Brent":         Brent,
+	}
+	for name, method := range methods {
+		result, err := method(f, -10, 10, nil)
+		if err != nil {
+			t.Fatalf("%s returned error: %v", name, err)
+		}
+		if !result.Converged || math.Abs(result.X-2) > 1e-6 || math.Abs(result.Value-1) > 1e-12 {
+			t.Fatalf("%s = %+v; want X = 2, Value = 1", name, result)
+		}
+		if _, err := method(f, 1, 1, nil); err == nil {
+			t.Fatalf("%s with an empty interval should return error", name)
+		}
+	}
+	golden, _ := GoldenSection(math.Cos, 0, 2*math.Pi, nil)
+	brent, _ := Brent(math.Cos, 0, 2*math.Pi, nil)
+	if math.Abs(brent.X-math.Pi) > 1e-6 || brent.Evaluations >= golden.Evaluations {
+		t.Fatalf("Brent = %+v should converge to pi faster than golden section (%d evaluations)",
+			brent, golden.Evaluations)
+	}
+}
+
+func rosenbrock(x vector.Vector[float64]) float64 {
+	return (1-x[0])*(1-x[0]) + 100*(x[1]-x[0]*x[0])*(x[1]-x[0]*x[0])
+}
+
+func TestGradientDescent(t *testing.T) {
+	f := func(x vector.Vector[float64]) float64 { return (x[0]-1)*(x[0]-1) + 4*(x[1]+2)*(x[1]+2) }
+	gradient := func(x vector.Vector[float64]) vector.Vector[float64] {
+		return vector.New(2*(x[0]-1), 8*(x[1]+2))
+	}
+	x0 := vector.New(5.0, 5.0)
+	for _, g := range []func(vector.Vector[float64]) vector.Vector[float64]{gradient, nil} {
+		result, err := GradientDescent(f, g, x0, &Settings{Tolerance: 1e-6})
+		if err != nil {
+			t.Fatalf("GradientDescent returned error: %v", err)
+		}
+		if !result.Converged || math.Abs(result.X[0]-1) > 1e-5 || math.Abs(result.X[1]+2) > 1e-5 {
+			t.Fatalf("GradientDescent = %+v; want X = [1 -2]", result)
+		}
+	}
+	if x0[0] != 5 || x0[1] != 5 {
+		t.Fatalf("GradientDescent modified the starting point: %v", x0)
+	}
+	if _, err := GradientDescent(f, nil, vector.New[float64](), nil); err == nil {
+		t.Fatalf("GradientDescent with an empty starting point should return error")
+	}
+}
+
+func TestNelderMead(t *testing.T) {
+	result, err := NelderMead(rosenbrock, vector.New(-1.2, 1.0), &Settings{Tolerance: 1e-10, MaxIterations: 5000})
+	if err != nil {
+		t.Fatalf("NelderMead returned error: %v", err)
+	}
+	if !result.Converged || math.Abs(result.X[0]-1) > 1e-6 || math.Abs(result.X[1]-1) > 1e-6 {
+		t.Fatalf("NelderMead = %+v; want X = [1 1]", result)
+	}
+	limited, _ := NelderMead(rosenbrock, vector.New(-1.2, 1.0), &Settings{MaxIterations: 3})
+	if limited.Converged || limited.Iterations != 3 {
+		t.Fatalf("NelderMead with 3 iterations = %+v; want not converged", limited)
+	}
+}
@@ -0,0 +1,360 @@
+// Package pade approximates functions by rational functions P(x)/Q(x): Padé
+// approximants built from the Taylor coefficients of a function at x = 0,
+// with float64 or exact Fraction coefficients, and minimax-style rational
+// fits of a function on an interval.
+//
+// A rational approximation of degree (m, n) has a numerator of degree m and a
+// denominator of degree n. Rational functions can model poles and
+// asymptotes, and are often far more accurate than a polynomial with the
+// same number of coefficients.
+//
+// Important details:
+//
+// (*) Coefficients are stored in increasing order of the power, like in the
+// fit package. The denominator is normalized so that its constant term is 1.
+//
+// (*) The Padé approximant of degree (m, n) needs the Taylor coefficients
+// c0, ..., c(m+n). With one more coefficient the leading term of the error,
+// f(x) - P(x)/Q(x) ≈ e·x^(m+n+1), is estimated as well.
+//
+// (*) Fit iteratively solves weighted linear least squares problems on
+// Chebyshev nodes (Loeb's linearization with Lawson's weight updates). It is
+// not a full Remez exchange, but in practice it gets close to the minimax
+// approximation. The reported error is the maximum absolute error measured
+// on a dense grid over the interval.
+package pade
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/fit"
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/realnum"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Rational is a rational function with float64 coefficients.
+type Rational struct {
+	// Numerator holds the coefficients p0, ..., pm of P(x).
+	Numerator vector.Vector[float64]
+	// Denominator holds the coefficients q0 = 1, q1, ..., qn of Q(x).
+	Denominator vector.Vector[float64]
+	// Error estimates the approximation error. For Pade it is the
+	// coefficient e of the leading error term e·x^(m+n+1) (NaN if there were
+	// too few Taylor coefficients); for Fit it is the maximum absolute error
+	// on the interval.
+	Error float64
+}
+
+// ExactRational is a rational function with Fraction coefficients.
+type ExactRational struct {
+	// Numerator holds the coefficients p0, ..., pm of P(x).
+	Numerator []*fraction.Fraction
+	// Denominator holds the coefficients q0 = 1, q1, ..., qn of Q(x).
+	Denominator []*fraction.Fraction
+	// Error is the coefficient e of the leading error term e·x^(m+n+1), or
+	// nil if there were too few Taylor coefficients.
+	Error *fraction.Fraction
+}
+
+// ============================================================================
+// Padé approximants
+// ============================================================================
+
+// Pade returns the Padé approximant of degree (m, n) of the function with the
+// specified Taylor coefficients at x = 0 (taylor[i] belongs to x^i). Returns
+// an error if m or n is negative, if there are fewer than m+n+1 coefficients
+// or if the approximant doesn't exist because the linear system for the
+// denominator is singular.
+func Pade(taylor vector.Vector[float64], m, n int) (*Rational, error) {
+	c := make([]realnum.Float, len(taylor))
+	for i, value := range taylor {
+		c[i] = realnum.Float(value)
+	}
+	p, q, e, err := pade(c, m, n, realnum.Float(0), realnum.Float(1))
+	if err != nil {
+		return nil, err
+	}
+	r := &Rational{
+		Numerator:   vector.NewFromValue(0.0, len(p)),
+		Denominator: vector.NewFromValue(0.0, len(q)),
+		Error:       math.NaN(),
+	}
+	for i, value := range p {
+		r.Numerator[i] = float64(value)
+	}
+	for i, value := range q {
+		r.Denominator[i] = float64(value)
+	}
+	if e != nil {
+		r.Error = float64(*e)
+	}
+	return r, nil
+}
+
+// PadeExact returns the Padé approximant of degree (m, n) with exact Fraction
+// coefficients. The input is not modified. Returns an error for the same
+// reasons as Pade, or if a coefficient is nil or invalid.
+func PadeExact(taylor []*fraction.Fraction, m, n int) (*ExactRational, error) {
+	c := make([]realnum.Fraction, len(taylor))
+	for i, value := range taylor {
+		if value == nil {
+			return nil, fmt.Errorf("Taylor coefficient %d is nil", i)
+		}
+		var err error
+		if c[i], err = realnum.NewFraction(value); err != nil {
+			return nil, err
+		}
+	}
+	zero, _ := realnum.NewFractionFromInts(0, 1)
+	one, _ := realnum.NewFractionFromInts(1, 1)
+	p, q, e, err := pade(c, m, n, zero, one)
+	if err != nil {
+		return nil, err
+	}
+	r := &ExactRational{
+		Numerator:   make([]*fraction.Fraction, len(p)),
+		Denominator: make([]*fraction.Fraction, len(q)),
+	}
+	for i, value := range p {
+		r.Numerator[i] = value.Value()
+	}
+	for i, value := range q {
+		r.Denominator[i] = value.Value()
+	}
+	if e != nil {
+		r.Error = e.Value()
+	}
+	return r, nil
+}
+
+// ============================================================================
+// Rational fits
+// ============================================================================
+
+// Fit returns a rational approximation of degree (m, n) of f on the interval
+// [a, b] that approximately minimizes the maximum absolute error. Returns an
+// error if m or n is negative, if the interval is empty or not finite, or if
+// f is not finite at one of the sample points.
+func Fit(f func(float64) float64, a, b float64, m, n int) (*Rational, error) {
+	if m < 0 || n < 0 {
+		return nil, errors.New("degrees must not be negative")
+	}
+	if !(a < b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return nil, errors.New("interval must be finite and non-empty")
+	}
+	// Chebyshev nodes cluster near the endpoints, where the error of a
+	// least squares fit tends to be largest.
+	k := 8 * (m + n + 1)
+	if k < 32 {
+		k = 32
+	}
+	x := vector.NewFromValue(0.0, k)
+	y := vector.NewFromValue(0.0, k)
+	for i := range x {
+		x[i] = (a+b)/2 + (b-a)/2*math.Cos(math.Pi*(float64(i)+0.5)/float64(k))
+		y[i] = f(x[i])
+		if math.IsNaN(y[i]) || math.IsInf(y[i], 0) {
+			return nil, fmt.Errorf("function is not finite at %v", x[i])
+		}
+	}
+	// The dense grid for the error estimate.
+	grid := vector.NewFromRange(a, b, uint(20*k))
+	values := grid.Clone().Map(f)
+
+	weights := vector.NewFromValue(1.0/float64(k), k)
+	var best *Rational
+	current := &Rational{Numerator: vector.NewFromValue(0.0, m+1), Denominator: vector.NewFromValue(0.0, n+1)}
+	current.Denominator[0] = 1
+	for iteration := 0; iteration < 50; iteration++ {
+		// Loeb's linearization: minimize Σ w·(P(x) - f(x)·Q(x))² / Q'(x)²
+		// with Q' the denominator of the previous iteration.
+		scale := vector.NewFromValue(0.0, k)
+		for i := range x {
+			scale[i] = math.Sqrt(weights[i]) / horner(current.Denominator, x[i])
+		}
+		columns := make([]vector.Vector[float64], 0, m+n+1)
+		for j := 0; j <= m; j++ {
+			column := vector.NewFromValue(0.0, k)
+			for i := range x {
+				column[i] = scale[i] * math.Pow(x[i], float64(j))
+			}
+			columns = append(columns, column)
+		}
+		for j := 1; j <= n; j++ {
+			column := vector.NewFromValue(0.0, k)
+			for i := range x {
+				column[i] = -scale[i] * y[i] * math.Pow(x[i], float64(j))
+			}
+			columns = append(columns, column)
+		}
+		model, err := fit.FitDesign(columns, y.Clone().Multiply(scale, 0))
+		if err != nil {
+			if best == nil {
+				return nil, err
+			}
+			break
+		}
+		next := &Rational{
+			Numerator:   model.Coefficients[:m+1].Clone(),
+			Denominator: append(vector.Vector[float64]{1}, model.Coefficients[m+1:]...),
+		}
+		next.Error = maxError(next, grid, values)
+		if best == nil || next.Error < best.Error {
+			best = next
+		}
+		if best.Error == 0 || math.IsNaN(next.Error) || math.IsInf(next.Error, 0) {
+			break
+		}
+		// Lawson's update: increase the weights where the error is large.
+		total := 0.0
+		for i := range x {
+			weights[i] *= math.Abs(y[i] - next.Evaluate(x[i]))
+			total += weights[i]
+		}
+		if !(total > 0) {
+			break
+		}
+		weights.Scale(1 / total)
+		current = next
+	}
+	return best, nil
+}
+
+// ============================================================================
+// Evaluation
+// ============================================================================
+
+// Evaluate returns P(x)/Q(x).
+func (r *Rational) Evaluate(x float64) float64 {
+	return horner(r.Numerator, x) / horner(r.Denominator, x)
+}
+
+// Evaluate returns P(x)/Q(x) as a new Fraction. Returns an error if x is nil
+// or invalid, or if Q(x) is zero.
+func (r *ExactRational) Evaluate(x *fraction.Fraction) (*fraction.Fraction, error) {
+	value, err := realnum.NewFraction(x)
+	if err != nil {
+		return nil, err
+	}
+	p := realnum.PolyEval(wrap(r.Numerator), value)
+	q := realnum.PolyEval(wrap(r.Denominator), value)
+	inverse, err := q.Inverse()
+	if err != nil {
+		return nil, errors.New("denominator is zero")
+	}
+	return p.Mul(inverse).Value(), nil
+}
+
+// Float returns the rational function with float64 coefficients.
+func (r *ExactRational) Float() *Rational {
+	result := &Rational{
+		Numerator:   vector.NewFromValue(0.0, len(r.Numerator)),
+		Denominator: vector.NewFromValue(0.0, len(r.Denominator)),
+		Error:       math.NaN(),
+	}
+	for i, value := range r.Numerator {
+		result.Numerator[i] = value.Evaluate()
+	}
+	for i, value := range r.Denominator {
+		result.Denominator[i] = value.Evaluate()
+	}
+	if r.Error != nil {
+		result.Error = r.Error.Evaluate()
+	}
+	return result
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// pade computes the numerator and denominator coefficients of the Padé
+// approximant, and the leading error coefficient if enough Taylor
+// coefficients are available (nil otherwise).
+func pade[T wbmath.Real[T]](c []T, m, n int, zero, one T) ([]T, []T, *T, error) {
+	if m < 0 || n < 0 {
+		return nil, nil, nil, errors.New("degrees must not be negative")
+	}
+	if len(c) < m+n+1 {
+		return nil, nil, nil, fmt.Errorf("degree (%d, %d) requires %d Taylor coefficients, got %d", m, n, m+n+1, len(c))
+	}
+	coefficient := func(i int) T {
+		if i < 0 {
+			return zero
+		}
+		return c[i]
+	}
+	// The denominator q0 = 1, q1, ..., qn makes the coefficients of x^(m+1),
+	// ..., x^(m+n) of f(x)·Q(x) vanish:
+	// Σ_{j=1..n} qj·c(m+i-j) = -c(m+i) for i = 1, ..., n.
+	q := []T{one}
+	if n > 0 {
+		a := make([][]T, n)
+		b := make([]T, n)
+		for i := range a {
+			a[i] = make([]T, n)
+			for j := range a[i] {
+				a[i][j] = coefficient(m + i - j)
+			}
+			b[i] = c[m+i+1].Neg()
+		}
+		solution, err := realnum.Solve(a, b)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Padé approximant of degree (%d, %d) doesn't exist: %w", m, n, err)
+		}
+		q = append(q, solution...)
+	}
+	// The numerator holds the first m+1 coefficients of f(x)·Q(x).
+	product := func(i int) T {
+		sum := zero
+		for j := 0; j <= n && j <= i; j++ {
+			sum = sum.Add(q[j].Mul(coefficient(i - j)))
+		}
+		return sum
+	}
+	p := make([]T, m+1)
+	for i := range p {
+		p[i] = product(i)
+	}
+	if len(c) < m+n+2 {
+		return p, q, nil, nil
+	}
+	// f - P/Q = (f·Q - P)/Q and Q(0) = 1, so the error starts with the
+	// coefficient of x^(m+n+1) of f·Q.
+	e := product(m + n + 1)
+	return p, q, &e, nil
+}
+
+// maxError returns the maximum absolute error of r on the grid.
+func maxError(r *Rational, grid, values vector.Vector[float64]) float64 {
+	result := 0.0
+	for i, x := range grid {
+		e := math.Abs(values[i] - r.Evaluate(x))
+		if math.IsNaN(e) {
+			return math.Inf(1)
+		}
+		result = math.Max(result, e)
+	}
+	return result
+}
+
+// horner evaluates the polynomial with the specified coefficients at x.
+func horner(coefficients vector.Vector[float64], x float64) float64 {
+	result := 0.0
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = result*x + coefficients[i]
+	}
+	return result
+}
+
+func wrap(coefficients []*fraction.Fraction) []realnum.Fraction {
+	result := make([]realnum.Fraction, len(coefficients))
+	for i, value := range coefficients {
+		result[i], _ = realnum.NewFraction(value)
+	}
+	return result
+}
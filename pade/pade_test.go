@@ -0,0 +1,115 @@
+package pade
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// expTaylor returns the first n Taylor coefficients 1/k! of exp.
+func expTaylor(n int) []*fraction.Fraction {
+	result := make([]*fraction.Fraction, n)
+	factorial := 1
+	for k := range result {
+		if k > 0 {
+			factorial *= k
+		}
+		result[k] = fraction.MustNew(1, factorial)
+	}
+	return result
+}
+
+func ratios(fs []*fraction.Fraction) []string {
+	result := make([]string, len(fs))
+	for i, f := range fs {
+		result[i] = f.AsIntegerRatio()
+	}
+	return result
+}
+
+func TestPadeExact(t *testing.T) {
+	r, err := PadeExact(expTaylor(6), 2, 2)
+	if err != nil {
+		t.Fatalf("PadeExact error: %v", err)
+	}
+	want := []string{"1/1", "1/2", "1/12"}
+	if got := ratios(r.Numerator); len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Numerator = %v; want %v", got, want)
+	}
+	want = []string{"1/1", "-1/2", "1/12"}
+	if got := ratios(r.Denominator); len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Denominator = %v; want %v", got, want)
+	}
+	// exp(x) - (12 + 6x + x²)/(12 - 6x + x²) ≈ x^5/720
+	if r.Error == nil || r.Error.AsIntegerRatio() != "1/720" {
+		t.Fatalf("Error = %v; want 1/720", r.Error)
+	}
+	value, err := r.Evaluate(fraction.MustNew(1, 1))
+	if err != nil || value.AsIntegerRatio() != "19/7" {
+		t.Fatalf("Evaluate(1) = %v, %v; want 19/7", value, err)
+	}
+	r, err = PadeExact(expTaylor(3), 1, 1)
+	if err != nil || r.Error != nil {
+		t.Fatalf("PadeExact without extra coefficient = %v, %v; want nil error term", r.Error, err)
+	}
+	if _, err := PadeExact(expTaylor(3), 2, 2); err == nil {
+		t.Fatalf("PadeExact with too few coefficients should return error")
+	}
+	// 1 + x³: the system for the [1/1] denominator is singular.
+	cubic := []*fraction.Fraction{fraction.MustNew(1, 1), fraction.MustNew(0, 1), fraction.MustNew(0, 1)}
+	if _, err := PadeExact(cubic, 1, 1); err == nil {
+		t.Fatalf("PadeExact with singular system should return error")
+	}
+}
+
+func TestPade(t *testing.T) {
+	taylor := vector.New(1.0, 1, 1.0/2, 1.0/6, 1.0/24, 1.0/120)
+	r, err := Pade(taylor, 2, 2)
+	if err != nil {
+		t.Fatalf("Pade error: %v", err)
+	}
+	if got := r.Evaluate(0.5); math.Abs(got-math.Exp(0.5)) > 1e-4 {
+		t.Fatalf("Evaluate(0.5) = %v; want %v", got, math.Exp(0.5))
+	}
+	if math.Abs(r.Error-1.0/720) > 1e-15 {
+		t.Fatalf("Error = %v; want %v", r.Error, 1.0/720)
+	}
+	exact, _ := PadeExact(expTaylor(6), 2, 2)
+	if got := exact.Float(); math.Abs(got.Evaluate(0.5)-r.Evaluate(0.5)) > 1e-15 {
+		t.Fatalf("Float().Evaluate(0.5) = %v; want %v", got.Evaluate(0.5), r.Evaluate(0.5))
+	}
+	// Degree (m, 0) is the truncated Taylor polynomial.
+	r, _ = Pade(taylor, 3, 0)
+	if len(r.Denominator) != 1 || r.Numerator[3] != 1.0/6 {
+		t.Fatalf("Pade(3, 0) = %v / %v", r.Numerator, r.Denominator)
+	}
+	if _, err := Pade(taylor, -1, 2); err == nil {
+		t.Fatalf("Pade with negative degree should return error")
+	}
+}
+
+func TestFit(t *testing.T) {
+	// A rational function is reproduced (almost) exactly.
+	r, err := Fit(func(x float64) float64 { return 1 / (1 + x*x) }, -1, 1, 0, 2)
+	if err != nil || r.Error > 1e-10 {
+		t.Fatalf("Fit(1/(1+x²)) error = %v, %v", r, err)
+	}
+	// exp on [0, 1]: the fit spreads the error over the interval, so it
+	// beats the Padé approximant, which is accurate only near x = 0.
+	r, err = Fit(math.Exp, 0, 1, 2, 2)
+	if err != nil || r.Error > 2e-5 {
+		t.Fatalf("Fit(exp) error = %v, %v", r, err)
+	}
+	pade, _ := Pade(vector.New(1.0, 1, 1.0/2, 1.0/6, 1.0/24), 2, 2)
+	if padeError := math.Abs(pade.Evaluate(1) - math.E); r.Error >= padeError {
+		t.Fatalf("Fit(exp) error = %v; want less than Padé error %v", r.Error, padeError)
+	}
+	if _, err := Fit(math.Exp, 1, 0, 2, 2); err == nil {
+		t.Fatalf("Fit on empty interval should return error")
+	}
+	if _, err := Fit(math.Log, -1, 1, 1, 1); err == nil {
+		t.Fatalf("Fit of non-finite function should return error")
+	}
+}
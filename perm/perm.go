@@ -0,0 +1,252 @@
+// Package perm provides a Permutation type in (zero-based) one-line notation:
+// the permutation p maps index i to p[i]. Supported are composition, inverse,
+// powers, cycle decomposition, parity/sign, order and application to slices
+// and Vectors.
+//
+// Important details:
+//
+// (*) Apply and ApplyVector gather: result[i] = items[p[i]]. This matches the
+// semantics of "argsort" indices: applying the permutation of indices that
+// sorts a slice yields the sorted slice.
+//
+// (*) Composition follows the usual convention for functions: p.Compose(q)
+// is the permutation i -> p[q[i]] (first q, then p).
+//
+// (*) Methods never modify the receiver but return a new Permutation.
+package perm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Permutation is a permutation of 0..n-1 in one-line notation.
+type Permutation []int
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the Permutation with the
+// specified images: index i is mapped to images[i]. Returns an error if the
+// images are not a permutation of 0..n-1.
+func New(images ...int) (Permutation, error) {
+	seen := make([]bool, len(images))
+	for _, image := range images {
+		if image < 0 || image >= len(images) || seen[image] {
+			return nil, errors.New("images must be a permutation of 0..n-1")
+		}
+		seen[image] = true
+	}
+	return append(Permutation{}, images...), nil
+}
+
+// MustNew is a constructor identical to New but which panics if an error
+// occurs.
+func MustNew(images ...int) Permutation {
+	p, err := New(images...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Identity is a constructor function that returns the identity permutation
+// of size n.
+func Identity(n int) Permutation {
+	p := make(Permutation, n)
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+// NewFromCycles is a constructor function that returns the Permutation of
+// size n defined by the specified disjoint cycles: the cycle [a b c] maps a
+// to b, b to c and c to a. Returns an error if an element is out of range or
+// appears more than once.
+func NewFromCycles(n int, cycles ...[]int) (Permutation, error) {
+	p := Identity(n)
+	seen := make([]bool, n)
+	for _, cycle := range cycles {
+		for i, element := range cycle {
+			if element < 0 || element >= n || seen[element] {
+				return nil, errors.New("cycles must be disjoint and contain elements of 0..n-1")
+			}
+			seen[element] = true
+			p[element] = cycle[(i+1)%len(cycle)]
+		}
+	}
+	return p, nil
+}
+
+// ============================================================================
+// Group operations
+// ============================================================================
+
+// Compose returns the composition p∘other: the permutation that first applies
+// `other` and then p. Returns an error if the sizes differ.
+func (p Permutation) Compose(other Permutation) (Permutation, error) {
+	if len(p) != len(other) {
+		return nil, errors.New("permutations must have the same size")
+	}
+	result := make(Permutation, len(p))
+	for i, image := range other {
+		result[i] = p[image]
+	}
+	return result, nil
+}
+
+// Inverse returns the inverse permutation: composing it with p gives the
+// identity.
+func (p Permutation) Inverse() Permutation {
+	inverse := make(Permutation, len(p))
+	for i, image := range p {
+		inverse[image] = i
+	}
+	return inverse
+}
+
+// Power returns p composed with itself k times. Negative powers use the
+// inverse.
+func (p Permutation) Power(k int) Permutation {
+	base := p
+	if k < 0 {
+		base = p.Inverse()
+		k = -k
+	}
+	result := Identity(len(p))
+	for k > 0 {
+		if k&1 != 0 {
+			result, _ = result.Compose(base)
+		}
+		base, _ = base.Compose(base)
+		k >>= 1
+	}
+	return result
+}
+
+// IsIdentity checks if p maps every index to itself.
+func (p Permutation) IsIdentity() bool {
+	for i, image := range p {
+		if i != image {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks if two permutations are identical.
+func (p Permutation) Equal(other Permutation) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================================================
+// Cycle structure
+// ============================================================================
+
+// Cycles returns the decomposition of p into disjoint cycles. Fixed points
+// (cycles of length 1) are omitted. Each cycle starts with its smallest
+// element and the cycles are ordered by their first element.
+func (p Permutation) Cycles() [][]int {
+	visited := make([]bool, len(p))
+	var cycles [][]int
+	for start := range p {
+		if visited[start] || p[start] == start {
+			visited[start] = true
+			continue
+		}
+		var cycle []int
+		for i := start; !visited[i]; i = p[i] {
+			visited[i] = true
+			cycle = append(cycle, i)
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// Sign returns the sign of p: 1 for even permutations and -1 for odd ones.
+// A cycle of length k is the product of k-1 transpositions.
+func (p Permutation) Sign() int {
+	sign := 1
+	for _, cycle := range p.Cycles() {
+		if len(cycle)%2 == 0 {
+			sign = -sign
+		}
+	}
+	return sign
+}
+
+// IsEven checks if p is an even permutation (a product of an even number of
+// transpositions).
+func (p Permutation) IsEven() bool {
+	return p.Sign() == 1
+}
+
+// Order returns the order of p: the smallest k > 0 for which p^k is the
+// identity. It is equal to the least common multiple of the cycle lengths.
+func (p Permutation) Order() int {
+	order := 1
+	for _, cycle := range p.Cycles() {
+		order = order / wbmath.Gcd(order, len(cycle)) * len(cycle)
+	}
+	return order
+}
+
+// String implements the fmt.Stringer interface and returns p in cycle
+// notation, e.g. "(0 2 1)(3 4)". The identity is formatted as "()".
+func (p Permutation) String() string {
+	cycles := p.Cycles()
+	if len(cycles) == 0 {
+		return "()"
+	}
+	var sb strings.Builder
+	for _, cycle := range cycles {
+		elements := make([]string, len(cycle))
+		for i, element := range cycle {
+			elements[i] = fmt.Sprintf("%d", element)
+		}
+		sb.WriteString("(" + strings.Join(elements, " ") + ")")
+	}
+	return sb.String()
+}
+
+// ============================================================================
+// Application
+// ============================================================================
+
+// Apply returns a new slice with result[i] = items[p[i]]. Returns an error if
+// the length of items differs from the size of p.
+func Apply[T any](p Permutation, items []T) ([]T, error) {
+	if len(items) != len(p) {
+		return nil, errors.New("slice length must equal permutation size")
+	}
+	result := make([]T, len(items))
+	for i, image := range p {
+		result[i] = items[image]
+	}
+	return result, nil
+}
+
+// ApplyVector returns a new Vector with result[i] = v[p[i]]. Returns an error
+// if the length of the Vector differs from the size of p.
+func ApplyVector[T wbmath.SignedNumber](p Permutation, v vector.Vector[T]) (vector.Vector[T], error) {
+	result, err := Apply(p, v)
+	if err != nil {
+		return nil, err
+	}
+	return vector.New(result...), nil
+}
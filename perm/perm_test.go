@@ -0,0 +1,72 @@
+package perm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+func TestConstructors(t *testing.T) {
+	if _, err := New(0, 2, 2); err == nil {
+		t.Fatalf("New with duplicate images should return error")
+	}
+	p, err := NewFromCycles(5, []int{0, 2, 1}, []int{3, 4})
+	if err != nil {
+		t.Fatalf("NewFromCycles returned error: %v", err)
+	}
+	if want := MustNew(2, 0, 1, 4, 3); !p.Equal(want) {
+		t.Fatalf("NewFromCycles = %v; want %v", []int(p), []int(want))
+	}
+	if s := p.String(); s != "(0 2 1)(3 4)" {
+		t.Fatalf("String() = %q; want \"(0 2 1)(3 4)\"", s)
+	}
+	if s := Identity(3).String(); s != "()" {
+		t.Fatalf("Identity.String() = %q; want \"()\"", s)
+	}
+	if _, err := NewFromCycles(3, []int{0, 1}, []int{1, 2}); err == nil {
+		t.Fatalf("NewFromCycles with overlapping cycles should return error")
+	}
+}
+
+func TestGroupOperations(t *testing.T) {
+	p := MustNew(1, 2, 0, 4, 3)
+	q := MustNew(0, 1, 2, 4, 3)
+	composed, err := p.Compose(q)
+	if err != nil {
+		t.Fatalf("Compose returned error: %v", err)
+	}
+	if want := MustNew(1, 2, 0, 3, 4); !composed.Equal(want) {
+		t.Fatalf("Compose = %v; want %v", []int(composed), []int(want))
+	}
+	if identity, _ := p.Compose(p.Inverse()); !identity.IsIdentity() {
+		t.Fatalf("p * p^-1 = %v; want identity", []int(identity))
+	}
+	if p.Order() != 6 || !p.Power(6).IsIdentity() || p.Power(3).IsIdentity() {
+		t.Fatalf("Order() = %d; want 6", p.Order())
+	}
+	if !p.Power(-1).Equal(p.Inverse()) {
+		t.Fatalf("Power(-1) = %v; want inverse", []int(p.Power(-1)))
+	}
+	if p.Sign() != -1 || p.IsEven() {
+		t.Fatalf("Sign() = %d; want -1", p.Sign())
+	}
+	if _, err := p.Compose(Identity(3)); err == nil {
+		t.Fatalf("Compose with different sizes should return error")
+	}
+}
+
+func TestApply(t *testing.T) {
+	p := MustNew(2, 0, 1)
+	got, err := Apply(p, []string{"a", "b", "c"})
+	if err != nil || !reflect.DeepEqual(got, []string{"c", "a", "b"}) {
+		t.Fatalf("Apply = %v, %v; want [c a b]", got, err)
+	}
+	v, err := ApplyVector(p, vector.New(1.5, 2.5, 3.5))
+	if err != nil || !reflect.DeepEqual(v, vector.New(3.5, 1.5, 2.5)) {
+		t.Fatalf("ApplyVector = %v, %v; want [3.5 1.5 2.5]", v, err)
+	}
+	if _, err := Apply(p, []int{1}); err == nil {
+		t.Fatalf("Apply with wrong length should return error")
+	}
+}
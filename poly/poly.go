@@ -0,0 +1,462 @@
+// Package poly provides a generic Polynomial[T] type for polynomials with
+// real coefficients, where T is any implementation of wbmath.Real: compute
+// fast with realnum.Float or exactly with realnum.Fraction or realnum.Rat.
+//
+// Available functionality includes constructors (New, NewFloat,
+// NewFromVector, NewFraction), accessors (Degree, Coefficient, Coefficients,
+// IsZero, Equals), evaluation with Horner's method (Evaluate), arithmetic
+// (Add, Subtract, Negate, Scale, Multiply, DivMod, Mod, Monic), calculus
+// (Derivative, Integral), the greatest common divisor (Gcd), roots of
+// polynomials of degree 1 to 3 (Roots) and string formatting (String).
+//
+// Important details:
+//
+// (*) Like modular.Poly, a Polynomial is a value type: methods never modify
+// the receiver but return a new Polynomial. Coefficients are stored from the
+// constant term upwards (coefficients[i] belongs to x^i, like in the fit and
+// realnum packages) without trailing zeros, so the zero polynomial has no
+// coefficients and degree -1.
+//
+// (*) The algorithms that decide if a coefficient is zero (DivMod, Gcd) are
+// exact for the exact backends. With realnum.Float, rounding errors can
+// leave tiny non-zero remainders, so Gcd is only reliable with exact
+// coefficients.
+package poly
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/realnum"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Polynomial represents a polynomial with coefficients of type T.
+type Polynomial[T wbmath.Real[T]] struct {
+	coefficients []T
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the polynomial with the
+// specified coefficients, starting with the constant term: New(1, -2, 3) is
+// 3x^2 - 2x + 1. Trailing zero coefficients are dropped.
+func New[T wbmath.Real[T]](coefficients ...T) Polynomial[T] {
+	return newPolynomial(append([]T(nil), coefficients...))
+}
+
+// NewFloat is a constructor function that returns the polynomial with the
+// specified float64 coefficients, starting with the constant term.
+func NewFloat(coefficients ...float64) Polynomial[realnum.Float] {
+	values := make([]realnum.Float, len(coefficients))
+	for i, c := range coefficients {
+		values[i] = realnum.Float(c)
+	}
+	return newPolynomial(values)
+}
+
+// NewFromVector is a constructor function that returns the polynomial with
+// the coefficients of the specified Vector, starting with the constant term,
+// e.g. the result of vector.PolyFit.
+func NewFromVector(coefficients vector.Vector[float64]) Polynomial[realnum.Float] {
+	return NewFloat(coefficients...)
+}
+
+// NewFraction is a constructor function that returns the polynomial with the
+// specified exact coefficients, starting with the constant term. Returns an
+// error if a coefficient is nil.
+func NewFraction(coefficients ...*fraction.Fraction) (Polynomial[realnum.Fraction], error) {
+	values := make([]realnum.Fraction, len(coefficients))
+	for i, c := range coefficients {
+		value, err := realnum.NewFraction(c)
+		if err != nil {
+			return Polynomial[realnum.Fraction]{}, fmt.Errorf("coefficient %d: %w", i, err)
+		}
+		values[i] = value
+	}
+	return newPolynomial(values), nil
+}
+
+// ============================================================================
+// Accessors
+// ============================================================================
+
+// Degree returns the degree of the polynomial, or -1 for the zero
+// polynomial.
+func (p Polynomial[T]) Degree() int {
+	return len(p.coefficients) - 1
+}
+
+// Coefficient returns the coefficient of x^i, which is zero if i is larger
+// than the degree, and false for the zero polynomial (which has no
+// coefficient to derive a zero of type T from). Panics if i is negative.
+func (p Polynomial[T]) Coefficient(i int) (T, bool) {
+	if i < 0 {
+		panic(fmt.Sprintf("negative index %d", i))
+	}
+	if p.IsZero() {
+		var zero T
+		return zero, false
+	}
+	if i >= len(p.coefficients) {
+		return p.zero(), true
+	}
+	return p.coefficients[i], true
+}
+
+// Coefficients returns a copy of the coefficients, starting with the
+// constant term.
+func (p Polynomial[T]) Coefficients() []T {
+	return append([]T(nil), p.coefficients...)
+}
+
+// IsZero checks if p is the zero polynomial.
+func (p Polynomial[T]) IsZero() bool {
+	return len(p.coefficients) == 0
+}
+
+// Equals checks if p and other have the same coefficients.
+func (p Polynomial[T]) Equals(other Polynomial[T]) bool {
+	if len(p.coefficients) != len(other.coefficients) {
+		return false
+	}
+	for i, c := range p.coefficients {
+		if c.Cmp(other.coefficients[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the fmt.Stringer interface and returns the polynomial
+// formatted as e.g. "3x^2 - 2x + 1". Coefficients that are not a single
+// number (e.g. "1/2" or "1 1/2") are put between parentheses: "(1/2)x - 1".
+func (p Polynomial[T]) String() string {
+	var b strings.Builder
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		c := p.coefficients[i]
+		if isZero(c) {
+			continue
+		}
+		negative := c.Cmp(p.zero()) < 0
+		if negative {
+			c = c.Neg()
+		}
+		switch {
+		case b.Len() == 0 && negative:
+			b.WriteString("-")
+		case b.Len() > 0 && negative:
+			b.WriteString(" - ")
+		case b.Len() > 0:
+			b.WriteString(" + ")
+		}
+		value := fmt.Sprint(c)
+		if strings.ContainsAny(value, " /") {
+			value = "(" + value + ")"
+		}
+		if i == 0 || value != "1" {
+			b.WriteString(value)
+		}
+		if i > 0 {
+			b.WriteString("x")
+		}
+		if i > 1 {
+			fmt.Fprintf(&b, "^%d", i)
+		}
+	}
+	if b.Len() == 0 {
+		return "0"
+	}
+	return b.String()
+}
+
+// Evaluate returns p(x) using Horner's method.
+func (p Polynomial[T]) Evaluate(x T) T {
+	return realnum.PolyEval(p.coefficients, x)
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns p + other.
+func (p Polynomial[T]) Add(other Polynomial[T]) Polynomial[T] {
+	result := p.Coefficients()
+	for i, c := range other.coefficients {
+		if i < len(result) {
+			result[i] = result[i].Add(c)
+		} else {
+			result = append(result, c)
+		}
+	}
+	return newPolynomial(result)
+}
+
+// Subtract returns p - other.
+func (p Polynomial[T]) Subtract(other Polynomial[T]) Polynomial[T] {
+	return p.Add(other.Negate())
+}
+
+// Negate returns -p.
+func (p Polynomial[T]) Negate() Polynomial[T] {
+	result := make([]T, len(p.coefficients))
+	for i, c := range p.coefficients {
+		result[i] = c.Neg()
+	}
+	return Polynomial[T]{coefficients: result}
+}
+
+// Scale returns p multiplied by the specified factor.
+func (p Polynomial[T]) Scale(factor T) Polynomial[T] {
+	result := make([]T, len(p.coefficients))
+	for i, c := range p.coefficients {
+		result[i] = c.Mul(factor)
+	}
+	return newPolynomial(result)
+}
+
+// Multiply returns p * other.
+func (p Polynomial[T]) Multiply(other Polynomial[T]) Polynomial[T] {
+	if p.IsZero() || other.IsZero() {
+		return Polynomial[T]{}
+	}
+	result := make([]T, len(p.coefficients)+len(other.coefficients)-1)
+	for i := range result {
+		result[i] = p.zero()
+	}
+	for i, a := range p.coefficients {
+		for j, b := range other.coefficients {
+			result[i+j] = result[i+j].Add(a.Mul(b))
+		}
+	}
+	return newPolynomial(result)
+}
+
+// DivMod returns the quotient and remainder of the polynomial long division
+// p / other, so that p = quotient * other + remainder with the degree of the
+// remainder smaller than the degree of other. Returns an error if other is
+// the zero polynomial.
+func (p Polynomial[T]) DivMod(other Polynomial[T]) (Polynomial[T], Polynomial[T], error) {
+	if other.IsZero() {
+		return Polynomial[T]{}, Polynomial[T]{}, errors.New("division by the zero polynomial")
+	}
+	inverse, err := other.lead().Inverse()
+	if err != nil {
+		return Polynomial[T]{}, Polynomial[T]{}, err
+	}
+	remainder := p.Coefficients()
+	quotient := make([]T, max(len(remainder)-len(other.coefficients)+1, 0))
+	for i := len(quotient) - 1; i >= 0; i-- {
+		factor := remainder[i+other.Degree()].Mul(inverse)
+		quotient[i] = factor
+		for j, c := range other.coefficients {
+			remainder[i+j] = remainder[i+j].Add(factor.Mul(c).Neg())
+		}
+		// The leading term cancels by construction; set it to zero exactly so
+		// rounding errors don't leave it behind.
+		remainder[i+other.Degree()] = p.zero()
+	}
+	return newPolynomial(quotient), newPolynomial(remainder), nil
+}
+
+// Mod returns the remainder of p / other. Returns an error if other is the
+// zero polynomial.
+func (p Polynomial[T]) Mod(other Polynomial[T]) (Polynomial[T], error) {
+	_, remainder, err := p.DivMod(other)
+	return remainder, err
+}
+
+// Monic returns p divided by its leading coefficient. The zero polynomial is
+// returned unchanged.
+func (p Polynomial[T]) Monic() Polynomial[T] {
+	if p.IsZero() {
+		return p
+	}
+	inverse, _ := p.lead().Inverse()
+	return p.Scale(inverse)
+}
+
+// ============================================================================
+// Calculus
+// ============================================================================
+
+// Derivative returns the derivative of p.
+func (p Polynomial[T]) Derivative() Polynomial[T] {
+	if len(p.coefficients) <= 1 {
+		return Polynomial[T]{}
+	}
+	result := make([]T, len(p.coefficients)-1)
+	for i := range result {
+		result[i] = p.coefficients[i+1].Mul(p.integer(i + 1))
+	}
+	return newPolynomial(result)
+}
+
+// Integral returns the antiderivative of p with a zero constant term, so
+// Integral().Derivative() equals p.
+func (p Polynomial[T]) Integral() Polynomial[T] {
+	if p.IsZero() {
+		return p
+	}
+	result := make([]T, len(p.coefficients)+1)
+	result[0] = p.zero()
+	for i, c := range p.coefficients {
+		inverse, _ := p.integer(i + 1).Inverse()
+		result[i+1] = c.Mul(inverse)
+	}
+	return newPolynomial(result)
+}
+
+// ============================================================================
+// GCD and roots
+// ============================================================================
+
+// Gcd returns the monic greatest common divisor of a and b, computed with the
+// Euclidean algorithm. The GCD of two zero polynomials is the zero
+// polynomial.
+func Gcd[T wbmath.Real[T]](a, b Polynomial[T]) Polynomial[T] {
+	for !b.IsZero() {
+		_, remainder, _ := a.DivMod(b)
+		a, b = b, remainder
+	}
+	return a.Monic()
+}
+
+// Roots returns the (complex) roots of a polynomial of degree 1, 2 or 3 with
+// the closed-form formulas, repeated according to their multiplicity. Roots
+// that are real to working precision have a zero imaginary part. Returns an
+// error for other degrees.
+func Roots(p Polynomial[realnum.Float]) ([]complex128, error) {
+	c := make([]float64, len(p.coefficients))
+	for i, value := range p.coefficients {
+		c[i] = float64(value)
+	}
+	switch p.Degree() {
+	case 1:
+		return []complex128{complex(-c[0]/c[1], 0)}, nil
+	case 2:
+		return quadraticRoots(c[2], c[1], c[0]), nil
+	case 3:
+		return cubicRoots(c[3], c[2], c[1], c[0]), nil
+	default:
+		return nil, fmt.Errorf("roots of a polynomial of degree %d are not supported", p.Degree())
+	}
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// newPolynomial returns the polynomial with the specified coefficients (not
+// copied) after dropping trailing zeros.
+func newPolynomial[T wbmath.Real[T]](coefficients []T) Polynomial[T] {
+	n := len(coefficients)
+	for n > 0 && isZero(coefficients[n-1]) {
+		n--
+	}
+	if n == 0 {
+		return Polynomial[T]{}
+	}
+	return Polynomial[T]{coefficients: coefficients[:n]}
+}
+
+// isZero checks if the value is zero. A generic algorithm cannot create a
+// zero, but value - value is one.
+func isZero[T wbmath.Real[T]](value T) bool {
+	return value.Cmp(value.Add(value.Neg())) == 0
+}
+
+// zero returns the zero of type T. Panics for the zero polynomial.
+func (p Polynomial[T]) zero() T {
+	c := p.coefficients[0]
+	return c.Add(c.Neg())
+}
+
+// integer returns n (> 0) as a value of type T, built from the leading
+// coefficient (which is non-zero) by double-and-add. Panics for the zero
+// polynomial.
+func (p Polynomial[T]) integer(n int) T {
+	inverse, _ := p.lead().Inverse()
+	one := p.lead().Mul(inverse)
+	result, power := p.zero(), one
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result = result.Add(power)
+		}
+		power = power.Add(power)
+	}
+	return result
+}
+
+// lead returns the leading coefficient. Panics for the zero polynomial.
+func (p Polynomial[T]) lead() T {
+	return p.coefficients[len(p.coefficients)-1]
+}
+
+// quadraticRoots returns the roots of a·x² + b·x + c with a ≠ 0. The root
+// with the larger magnitude is computed first, and the other one from the
+// product of the roots (c/a) to avoid cancellation.
+func quadraticRoots(a, b, c float64) []complex128 {
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		re, im := -b/(2*a), math.Sqrt(-discriminant)/(2*math.Abs(a))
+		return []complex128{complex(re, -im), complex(re, im)}
+	}
+	q := -(b + math.Copysign(math.Sqrt(discriminant), b)) / 2
+	if q == 0 {
+		// b = c = 0: a double root at zero.
+		return []complex128{0, 0}
+	}
+	x1, x2 := q/a, c/q
+	return []complex128{complex(min(x1, x2), 0), complex(max(x1, x2), 0)}
+}
+
+// cubicRoots returns the roots of a·x³ + b·x² + c·x + d with a ≠ 0 using
+// Cardano's formula in complex arithmetic, followed by two Newton steps per
+// root to polish the result.
+func cubicRoots(a, b, c, d float64) []complex128 {
+	delta0 := b*b - 3*a*c
+	delta1 := 2*b*b*b - 9*a*b*c + 27*a*a*d
+	root := cmplx.Sqrt(complex(delta1*delta1-4*delta0*delta0*delta0, 0))
+	// Take the sign that avoids cancellation.
+	s := complex(delta1, 0) + root
+	if cmplx.Abs(complex(delta1, 0)-root) > cmplx.Abs(s) {
+		s = complex(delta1, 0) - root
+	}
+	k := cmplx.Pow(s/2, 1.0/3)
+	xi := complex(-0.5, math.Sqrt(3)/2)
+	p := func(x complex128) complex128 {
+		return ((complex(a, 0)*x+complex(b, 0))*x+complex(c, 0))*x + complex(d, 0)
+	}
+	dp := func(x complex128) complex128 {
+		return (complex(3*a, 0)*x+complex(2*b, 0))*x + complex(c, 0)
+	}
+	roots := make([]complex128, 3)
+	for i := range roots {
+		var x complex128
+		if k == 0 {
+			// delta0 = delta1 = 0: a triple root.
+			x = complex(-b/(3*a), 0)
+		} else {
+			x = -(complex(b, 0) + k + complex(delta0, 0)/k) / complex(3*a, 0)
+		}
+		for range 2 {
+			if derivative := dp(x); derivative != 0 {
+				x -= p(x) / derivative
+			}
+		}
+		if math.Abs(imag(x)) <= 1e-12*math.Max(1, cmplx.Abs(x)) {
+			x = complex(real(x), 0)
+		}
+		roots[i] = x
+		k *= xi
+	}
+	return roots
+}
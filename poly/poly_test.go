@@ -0,0 +1,132 @@
+package poly
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/realnum"
+)
+
+func exact(t *testing.T, coefficients ...string) Polynomial[realnum.Fraction] {
+	fractions := make([]*fraction.Fraction, len(coefficients))
+	for i, c := range coefficients {
+		fractions[i] = fraction.MustNewFromString(c)
+	}
+	p, err := NewFraction(fractions...)
+	if err != nil {
+		t.Fatalf("NewFraction returned error: %v", err)
+	}
+	return p
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		p    Polynomial[realnum.Float]
+		want string
+	}{
+		{NewFloat(1, -2, 3), "3x^2 - 2x + 1"},
+		{NewFloat(0, 1), "x"},
+		{NewFloat(-1, 0, -1, 0), "-x^2 - 1"},
+		{NewFloat(2.5), "2.5"},
+		{NewFloat(0, 0), "0"},
+	}
+	for _, test := range tests {
+		if got := test.p.String(); got != test.want {
+			t.Fatalf("String() = %q; want %q", got, test.want)
+		}
+	}
+	if got := exact(t, "-1", "1/2").String(); got != "(1/2)x - 1" {
+		t.Fatalf("String() = %q; want %q", got, "(1/2)x - 1")
+	}
+	if NewFloat(0, 0).Degree() != -1 || NewFloat(1, 2, 0).Degree() != 1 {
+		t.Fatalf("Degree doesn't drop trailing zeros")
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	p := NewFloat(1, -2, 3) // 3x^2 - 2x + 1
+	q := NewFloat(-1, 1)    // x - 1
+	if got := p.Evaluate(2); got != 9 {
+		t.Fatalf("Evaluate(2) = %v; want 9", got)
+	}
+	if got := p.Add(q); !got.Equals(NewFloat(0, -1, 3)) {
+		t.Fatalf("Add = %v", got)
+	}
+	if got := p.Subtract(p); !got.IsZero() {
+		t.Fatalf("p - p = %v; want 0", got)
+	}
+	if got := p.Multiply(q); !got.Equals(NewFloat(-1, 3, -5, 3)) {
+		t.Fatalf("Multiply = %v", got)
+	}
+	quotient, remainder, err := p.DivMod(q)
+	if err != nil || !quotient.Equals(NewFloat(1, 3)) || !remainder.Equals(NewFloat(2)) {
+		t.Fatalf("DivMod = %v, %v, %v; want 3x + 1, 2", quotient, remainder, err)
+	}
+	if _, _, err := p.DivMod(NewFloat()); err == nil {
+		t.Fatalf("DivMod by the zero polynomial should return error")
+	}
+	if got := p.Derivative(); !got.Equals(NewFloat(-2, 6)) {
+		t.Fatalf("Derivative = %v", got)
+	}
+	if got := p.Integral(); !got.Equals(NewFloat(0, 1, -1, 1)) || !got.Derivative().Equals(p) {
+		t.Fatalf("Integral = %v", got)
+	}
+}
+
+func TestExact(t *testing.T) {
+	// (x - 1/2)(x + 3) = x^2 + 5/2 x - 3/2 and (x - 1/2)(2x - 1/3)
+	a := exact(t, "-3/2", "5/2", "1")
+	b := exact(t, "1/6", "-4/3", "2")
+	if got := Gcd(a, b); !got.Equals(exact(t, "-1/2", "1")) {
+		t.Fatalf("Gcd = %v; want x - 1/2", got)
+	}
+	if got := Gcd(a, exact(t, "1", "1")); !got.Equals(exact(t, "1")) {
+		t.Fatalf("Gcd of coprime polynomials = %v; want 1", got)
+	}
+	// The integral of x^2 + 5/2 x - 3/2 has coefficients 1/3 and 5/4.
+	if got := a.Integral(); !got.Equals(exact(t, "0", "-3/2", "5/4", "1/3")) {
+		t.Fatalf("Integral = %v", got)
+	}
+	x, _ := realnum.NewFractionFromInts(1, 2)
+	if got := a.Evaluate(x); got.Cmp(realnum.Fraction{}) != 0 {
+		t.Fatalf("Evaluate(1/2) = %v; want 0", got)
+	}
+	if _, err := NewFraction(nil); err == nil {
+		t.Fatalf("NewFraction(nil) should return error")
+	}
+}
+
+func TestRoots(t *testing.T) {
+	tests := []struct {
+		p    Polynomial[realnum.Float]
+		want []complex128
+	}{
+		{NewFloat(-4, 2), []complex128{2}},
+		{NewFloat(6, -5, 1), []complex128{2, 3}},
+		{NewFloat(1, 0, 1), []complex128{-1i, 1i}},
+		{NewFloat(-6, 11, -6, 1), []complex128{1, 2, 3}},
+		{NewFloat(-1, 3, -3, 1), []complex128{1, 1, 1}},
+		{NewFloat(-1, 0, 0, 1), []complex128{1, complex(-0.5, math.Sqrt(3)/2), complex(-0.5, -math.Sqrt(3)/2)}},
+	}
+	for _, test := range tests {
+		roots, err := Roots(test.p)
+		if err != nil || len(roots) != len(test.want) {
+			t.Fatalf("Roots(%v) = %v, %v; want %v", test.p, roots, err, test.want)
+		}
+		// Every expected root must be found (in any order).
+		for _, want := range test.want {
+			found := false
+			for _, root := range roots {
+				found = found || cmplx.Abs(root-want) < 1e-6
+			}
+			if !found {
+				t.Fatalf("Roots(%v) = %v; want %v", test.p, roots, test.want)
+			}
+		}
+	}
+	if _, err := Roots(NewFloat(1, 0, 0, 0, 1)); err == nil {
+		t.Fatalf("Roots of a quartic should return error")
+	}
+}
@@ -0,0 +1,586 @@
+// Package primes provides prime number machinery: an unbounded prime
+// iterator, a (segmented) sieve of Eratosthenes over arbitrary ranges, a
+// deterministic primality test for all int values, prime counting, integer
+// factorization and a Factored type that does arithmetic on prime exponents.
+//
+// Important details:
+//
+// (*) IsPrime is a deterministic Miller-Rabin test: it is exact for every
+// 64-bit integer and doesn't need a sieve.
+//
+// (*) Sieve and SieveRange only keep the base primes up to sqrt(hi) and one
+// segment of at most segmentSize numbers in memory, so large ranges can be
+// processed with little memory.
+package primes
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/big"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// segmentSize is the number of values that is sieved at once.
+const segmentSize = 1 << 15
+
+// ============================================================================
+// Primality
+// ============================================================================
+
+// IsPrime checks if n is a prime number. Uses trial division by small primes
+// followed by a deterministic Miller-Rabin test.
+func IsPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37} {
+		if n == p {
+			return true
+		}
+		if n%p == 0 {
+			return false
+		}
+	}
+	// d * 2^s = n - 1 with d odd
+	m := uint64(n)
+	d := m - 1
+	s := bits.TrailingZeros64(d)
+	d >>= uint(s)
+	// These bases make the test deterministic for all n < 2^64.
+	for _, a := range []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37} {
+		x := powMod(a, d, m)
+		if x == 1 || x == m-1 {
+			continue
+		}
+		composite := true
+		for r := 1; r < s; r++ {
+			x = mulMod(x, x, m)
+			if x == m-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// NextPrime returns the smallest prime larger than n. Returns 0 if that prime
+// doesn't fit in an int (n >= 9223372036854775783, the largest prime below
+// 2^63, for a 64-bit int).
+func NextPrime(n int) int {
+	if n < 2 {
+		return 2
+	}
+	candidate := n + 1
+	if candidate%2 == 0 && candidate != 2 {
+		candidate++
+	}
+	// The candidate turns negative when it overflows.
+	for candidate > 0 && !IsPrime(candidate) {
+		candidate += 2
+	}
+	return max(candidate, 0)
+}
+
+// ============================================================================
+// Sieves and iteration
+// ============================================================================
+
+// Sieve returns all primes less than or equal to n, in increasing order.
+func Sieve(n int) []int {
+	primes, _ := SieveRange(2, n)
+	return primes
+}
+
+// SieveRange returns all primes p with lo <= p <= hi, in increasing order,
+// using a segmented sieve of Eratosthenes. Returns an error if lo > hi.
+func SieveRange(lo, hi int) ([]int, error) {
+	if lo > hi {
+		return nil, errors.New("lower bound must not exceed upper bound")
+	}
+	var result []int
+	for p := range primesInRange(lo, hi) {
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// All returns an unbounded iterator over the primes 2, 3, 5, 7, ... The
+// primes are generated segment by segment, so breaking out of the loop early
+// is cheap.
+func All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for lo := 2; ; lo += segmentSize {
+			for p := range primesInRange(lo, lo+segmentSize-1) {
+				if !yield(p) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the primes p with lo <= p <= hi.
+func Range(lo, hi int) iter.Seq[int] {
+	return primesInRange(lo, hi)
+}
+
+// primesInRange sieves [lo, hi] segment by segment and yields the primes.
+// Sieving needs the primes up to sqrt(hi), which take gigabytes near
+// math.MaxInt, so a range shorter than sqrt(hi) is tested number by number
+// instead: the memory use never exceeds O(min(hi - lo, sqrt(hi))).
+func primesInRange(lo, hi int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if lo < 2 {
+			lo = 2
+		}
+		if hi < lo {
+			return
+		}
+		if hi-lo < isqrt(hi) {
+			for n := lo; ; n++ {
+				if IsPrime(n) && !yield(n) {
+					return
+				}
+				if n == hi {
+					return
+				}
+			}
+		}
+		base := simpleSieve(isqrt(hi))
+		composite := make([]bool, segmentSize)
+		// The loop ends when a segment reaches hi, so start and end never
+		// overflow, even if hi is math.MaxInt.
+		for start := lo; ; start += segmentSize {
+			end := start + min(segmentSize-1, hi-start)
+			segment := composite[:end-start+1]
+			clear(segment)
+			for _, p := range base {
+				if p*p > end {
+					break
+				}
+				// First multiple of p in the segment, but never p itself.
+				offset := (p - start%p) % p
+				if offset > end-start {
+					continue
+				}
+				first := max(p*p, start+offset)
+				for multiple := first; multiple <= end; multiple += p {
+					segment[multiple-start] = true
+					if multiple > end-p {
+						break
+					}
+				}
+			}
+			for i, isComposite := range segment {
+				if !isComposite && !yield(start+i) {
+					return
+				}
+			}
+			if end == hi {
+				return
+			}
+		}
+	}
+}
+
+// simpleSieve returns the primes up to n with a plain sieve of Eratosthenes.
+func simpleSieve(n int) []int {
+	if n < 2 {
+		return nil
+	}
+	composite := make([]bool, n+1)
+	var primes []int
+	for i := 2; i <= n; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for multiple := i * i; multiple <= n; multiple += i {
+			composite[multiple] = true
+		}
+	}
+	return primes
+}
+
+// Pi returns the prime-counting function π(n): the number of primes less
+// than or equal to n.
+func Pi(n int) int {
+	count := 0
+	for range primesInRange(2, n) {
+		count++
+	}
+	return count
+}
+
+// ============================================================================
+// Factorization
+// ============================================================================
+
+// Factor is a prime factor with its multiplicity.
+type Factor struct {
+	Prime    int
+	Exponent int
+}
+
+// Factorize returns the prime factorization of n as a list of factors in
+// increasing order of the primes. Returns an error if n < 1. The
+// factorization of 1 is empty.
+func Factorize(n int) ([]Factor, error) {
+	if n < 1 {
+		return nil, errors.New("only positive integers can be factorized")
+	}
+	counts := make(map[int]int)
+	factorize(uint64(n), counts)
+	factors := make([]Factor, 0, len(counts))
+	for p, e := range counts {
+		factors = append(factors, Factor{Prime: p, Exponent: e})
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Prime < factors[j].Prime })
+	return factors, nil
+}
+
+// PrimeFactors returns the prime factors of n in increasing order, repeated
+// according to their multiplicity (e.g. 12 gives [2 2 3]). Returns an error
+// if n < 1.
+func PrimeFactors(n int) ([]int, error) {
+	factors, err := Factorize(n)
+	if err != nil {
+		return nil, err
+	}
+	var result []int
+	for _, factor := range factors {
+		for i := 0; i < factor.Exponent; i++ {
+			result = append(result, factor.Prime)
+		}
+	}
+	return result, nil
+}
+
+// Divisors returns all positive divisors of n in increasing order. Returns
+// an error if n < 1.
+func Divisors(n int) ([]int, error) {
+	factors, err := Factorize(n)
+	if err != nil {
+		return nil, err
+	}
+	divisors := []int{1}
+	for _, factor := range factors {
+		count := len(divisors)
+		power := 1
+		for e := 1; e <= factor.Exponent; e++ {
+			power *= factor.Prime
+			for i := 0; i < count; i++ {
+				divisors = append(divisors, divisors[i]*power)
+			}
+		}
+	}
+	sort.Ints(divisors)
+	return divisors, nil
+}
+
+// Totient returns Euler's totient function φ(n): the number of integers in
+// [1, n] that are coprime to n. Returns an error if n < 1.
+func Totient(n int) (int, error) {
+	factors, err := Factorize(n)
+	if err != nil {
+		return 0, err
+	}
+	result := n
+	for _, factor := range factors {
+		result = result / factor.Prime * (factor.Prime - 1)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Factored numbers
+// ============================================================================
+
+// Factored is a positive number stored as its prime-exponent map, e.g. 360 is
+// stored as {2: 3, 3: 2, 5: 1}. Multiplication, division, gcd, lcm, powers
+// and roots operate on the exponents, so they never overflow: only Int can.
+// Division may produce negative exponents, i.e. positive rationals.
+// A Factored value is immutable: methods return a new value.
+type Factored struct {
+	exponents map[int]int
+}
+
+// NewFactored is a constructor function that factorizes n. Returns an error
+// if n < 1.
+func NewFactored(n int) (Factored, error) {
+	if n < 1 {
+		return Factored{}, errors.New("only positive integers can be factorized")
+	}
+	exponents := make(map[int]int)
+	factorize(uint64(n), exponents)
+	return Factored{exponents: exponents}, nil
+}
+
+// NewFactoredFromFactors is a constructor function that returns the product
+// of the factors (exponents may be negative). Returns an error if a factor
+// is not prime.
+func NewFactoredFromFactors(factors ...Factor) (Factored, error) {
+	exponents := make(map[int]int)
+	for _, factor := range factors {
+		if !IsPrime(factor.Prime) {
+			return Factored{}, fmt.Errorf("%d is not prime", factor.Prime)
+		}
+		exponents[factor.Prime] += factor.Exponent
+	}
+	return Factored{exponents: exponents}.normalized(), nil
+}
+
+// Factors returns the factors in increasing order of the primes.
+func (f Factored) Factors() []Factor {
+	factors := make([]Factor, 0, len(f.exponents))
+	for p, e := range f.exponents {
+		factors = append(factors, Factor{Prime: p, Exponent: e})
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Prime < factors[j].Prime })
+	return factors
+}
+
+// Exponent returns the exponent of the prime p (0 if p doesn't occur).
+func (f Factored) Exponent(p int) int {
+	return f.exponents[p]
+}
+
+// IsInteger checks if all exponents are non-negative.
+func (f Factored) IsInteger() bool {
+	for _, e := range f.exponents {
+		if e < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Mul returns f · other.
+func (f Factored) Mul(other Factored) Factored {
+	return f.combine(other, func(a, b int) int { return a + b })
+}
+
+// Div returns f / other. The result has negative exponents if other doesn't
+// divide f.
+func (f Factored) Div(other Factored) Factored {
+	return f.combine(other, func(a, b int) int { return a - b })
+}
+
+// Divides checks if f divides other, i.e. if other / f is an integer.
+func (f Factored) Divides(other Factored) bool {
+	return other.Div(f).IsInteger()
+}
+
+// Gcd returns the greatest common divisor: the minimum of the exponents.
+func (f Factored) Gcd(other Factored) Factored {
+	return f.combine(other, func(a, b int) int { return min(a, b) })
+}
+
+// Lcm returns the least common multiple: the maximum of the exponents.
+func (f Factored) Lcm(other Factored) Factored {
+	return f.combine(other, func(a, b int) int { return max(a, b) })
+}
+
+// Pow returns f raised to the specified power.
+func (f Factored) Pow(exponent uint) Factored {
+	result := make(map[int]int, len(f.exponents))
+	for p, e := range f.exponents {
+		result[p] = e * int(exponent)
+	}
+	return Factored{exponents: result}.normalized()
+}
+
+// NthRoot returns the nth root of f. Returns an error if degree is 0 or if
+// the root is not exact.
+func (f Factored) NthRoot(degree uint) (Factored, error) {
+	if degree == 0 {
+		return Factored{}, errors.New("degree must be positive")
+	}
+	result := make(map[int]int, len(f.exponents))
+	for p, e := range f.exponents {
+		if e%int(degree) != 0 {
+			return Factored{}, errors.New("the nth-root is not exact")
+		}
+		result[p] = e / int(degree)
+	}
+	return Factored{exponents: result}, nil
+}
+
+// NumDivisors returns the number of positive divisors of an integer f.
+// Returns an error if f is not an integer.
+func (f Factored) NumDivisors() (int, error) {
+	if !f.IsInteger() {
+		return 0, errors.New("not an integer")
+	}
+	count := 1
+	for _, e := range f.exponents {
+		count *= e + 1
+	}
+	return count, nil
+}
+
+// Int returns f as an int. Returns an error if f is not an integer or if it
+// overflows.
+func (f Factored) Int() (int, error) {
+	n, err := f.BigInt()
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() || n.Int64() > math.MaxInt {
+		return 0, errors.New("value overflows int")
+	}
+	return int(n.Int64()), nil
+}
+
+// BigInt returns f as a big.Int. Returns an error if f is not an integer.
+func (f Factored) BigInt() (*big.Int, error) {
+	if !f.IsInteger() {
+		return nil, errors.New("not an integer")
+	}
+	result := big.NewInt(1)
+	power := new(big.Int)
+	for p, e := range f.exponents {
+		result.Mul(result, power.Exp(big.NewInt(int64(p)), big.NewInt(int64(e)), nil))
+	}
+	return result, nil
+}
+
+// String implements the fmt.Stringer interface, e.g. "2^3 · 3^2 · 5" or
+// "2^-1 · 3". The number 1 is shown as "1".
+func (f Factored) String() string {
+	factors := f.Factors()
+	if len(factors) == 0 {
+		return "1"
+	}
+	parts := make([]string, len(factors))
+	for i, factor := range factors {
+		parts[i] = strconv.Itoa(factor.Prime)
+		if factor.Exponent != 1 {
+			parts[i] += "^" + strconv.Itoa(factor.Exponent)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// combine applies op to the exponents of every prime of f and other.
+func (f Factored) combine(other Factored, op func(a, b int) int) Factored {
+	result := make(map[int]int, len(f.exponents)+len(other.exponents))
+	for p, e := range f.exponents {
+		result[p] = op(e, other.exponents[p])
+	}
+	for p, e := range other.exponents {
+		if _, ok := f.exponents[p]; !ok {
+			result[p] = op(0, e)
+		}
+	}
+	return Factored{exponents: result}.normalized()
+}
+
+// normalized removes zero exponents.
+func (f Factored) normalized() Factored {
+	for p, e := range f.exponents {
+		if e == 0 {
+			delete(f.exponents, p)
+		}
+	}
+	return f
+}
+
+// factorize adds the prime factors of n to counts. Small factors are removed
+// by trial division, large composite cofactors are split with Pollard's rho.
+func factorize(n uint64, counts map[int]int) {
+	for _, p := range []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37} {
+		for n%p == 0 {
+			counts[int(p)]++
+			n /= p
+		}
+	}
+	if n == 1 {
+		return
+	}
+	if n <= math.MaxInt && IsPrime(int(n)) {
+		counts[int(n)]++
+		return
+	}
+	d := pollardRho(n)
+	factorize(d, counts)
+	factorize(n/d, counts)
+}
+
+// pollardRho returns a non-trivial divisor of the composite number n (which
+// has no factors below 41).
+func pollardRho(n uint64) uint64 {
+	for c := uint64(1); ; c++ {
+		x, y, d := uint64(2), uint64(2), uint64(1)
+		f := func(v uint64) uint64 { return (mulMod(v, v, n) + c) % n }
+		for d == 1 {
+			x = f(x)
+			y = f(f(y))
+			if x > y {
+				d = gcd(x-y, n)
+			} else {
+				d = gcd(y-x, n)
+			}
+		}
+		if d != n {
+			return d
+		}
+	}
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// mulMod returns a*b mod m without overflow.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%m, lo, m)
+	return rem
+}
+
+// powMod returns base^exponent mod m.
+func powMod(base, exponent, m uint64) uint64 {
+	result := uint64(1)
+	base %= m
+	for exponent > 0 {
+		if exponent&1 != 0 {
+			result = mulMod(result, base, m)
+		}
+		base = mulMod(base, base, m)
+		exponent >>= 1
+	}
+	return result
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// isqrt returns floor(sqrt(n)) for n >= 0.
+func isqrt(n int) int {
+	if n < 0 {
+		return 0
+	}
+	r := int(math.Sqrt(float64(n)))
+	// Compare by division: r·r overflows near math.MaxInt.
+	for r > 0 && r > n/r {
+		r--
+	}
+	for r+1 <= n/(r+1) {
+		r++
+	}
+	return r
+}
@@ -0,0 +1,175 @@
+package primes
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestIsPrime(t *testing.T) {
+	sieve := Sieve(10000)
+	isPrime := make(map[int]bool, len(sieve))
+	for _, p := range sieve {
+		isPrime[p] = true
+	}
+	for n := -1; n <= 10000; n++ {
+		if IsPrime(n) != isPrime[n] {
+			t.Fatalf("IsPrime(%d) = %v; want %v", n, IsPrime(n), isPrime[n])
+		}
+	}
+	if !IsPrime(2147483647) {
+		t.Fatalf("IsPrime(2^31-1) = false; want true")
+	}
+	if IsPrime(3215031751) {
+		t.Fatalf("IsPrime(3215031751) = true; want false (strong pseudoprime to bases 2..7)")
+	}
+	if got := NextPrime(100); got != 101 {
+		t.Fatalf("NextPrime(100) = %d; want 101", got)
+	}
+	if math.MaxInt == math.MaxInt64 {
+		// 2^63 - 25 is the largest prime that fits in an int64.
+		largest := math.MaxInt - 24
+		if got := NextPrime(largest - 1); got != largest {
+			t.Fatalf("NextPrime(2^63 - 26) = %d; want %d", got, largest)
+		}
+		if got := NextPrime(largest); got != 0 {
+			t.Fatalf("NextPrime(2^63 - 25) = %d; want 0", got)
+		}
+		if got := NextPrime(math.MaxInt); got != 0 {
+			t.Fatalf("NextPrime(MaxInt) = %d; want 0", got)
+		}
+	}
+}
+
+func TestSieveAndIteration(t *testing.T) {
+	if got := Sieve(30); !reflect.DeepEqual(got, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}) {
+		t.Fatalf("Sieve(30) = %v", got)
+	}
+	got, err := SieveRange(1000000000, 1000000100)
+	if err != nil {
+		t.Fatalf("SieveRange returned error: %v", err)
+	}
+	want := []int{1000000007, 1000000009, 1000000021, 1000000033, 1000000087, 1000000093, 1000000097}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SieveRange = %v; want %v", got, want)
+	}
+	if math.MaxInt == math.MaxInt64 {
+		// A range ending at math.MaxInt neither overflows nor sieves the
+		// primes up to 2^31.5.
+		got, err := SieveRange(math.MaxInt-100, math.MaxInt)
+		if err != nil || len(got) == 0 || got[len(got)-1] != math.MaxInt-24 {
+			t.Fatalf("SieveRange(MaxInt - 100, MaxInt) = %v, %v; want the largest prime 2^63 - 25 last", got, err)
+		}
+		for _, p := range got {
+			if !IsPrime(p) {
+				t.Fatalf("SieveRange(MaxInt - 100, MaxInt) contains composite %d", p)
+			}
+		}
+		count := 0
+		for range Range(math.MaxInt-24, math.MaxInt) {
+			count++
+		}
+		if count != 1 {
+			t.Fatalf("Range(2^63 - 25, MaxInt) yielded %d primes; want 1", count)
+		}
+	}
+	// Segments that end before hi and ranges wider than sqrt(hi) are sieved.
+	wide, _ := SieveRange(100000, 200000)
+	for i, p := range wide {
+		if !IsPrime(p) || (i > 0 && NextPrime(wide[i-1]) != p) {
+			t.Fatalf("SieveRange(100000, 200000) is wrong at %d", p)
+		}
+	}
+	if _, err := SieveRange(10, 1); err == nil {
+		t.Fatalf("SieveRange with lo > hi should return error")
+	}
+	count := 0
+	last := 0
+	for p := range All() {
+		count++
+		last = p
+		if count == 10001 {
+			break
+		}
+	}
+	if last != 104743 {
+		t.Fatalf("10001st prime = %d; want 104743", last)
+	}
+	if got := Pi(1000000); got != 78498 {
+		t.Fatalf("Pi(1e6) = %d; want 78498", got)
+	}
+}
+
+func TestFactorization(t *testing.T) {
+	factors, err := Factorize(360)
+	if err != nil {
+		t.Fatalf("Factorize returned error: %v", err)
+	}
+	if want := []Factor{{2, 3}, {3, 2}, {5, 1}}; !reflect.DeepEqual(factors, want) {
+		t.Fatalf("Factorize(360) = %v; want %v", factors, want)
+	}
+	if got, _ := PrimeFactors(600851475143); !reflect.DeepEqual(got, []int{71, 839, 1471, 6857}) {
+		t.Fatalf("PrimeFactors(600851475143) = %v", got)
+	}
+	if got, _ := PrimeFactors(1000000007 * 998244353); !reflect.DeepEqual(got, []int{998244353, 1000000007}) {
+		t.Fatalf("PrimeFactors(large semiprime) = %v", got)
+	}
+	if got, _ := Divisors(28); !reflect.DeepEqual(got, []int{1, 2, 4, 7, 14, 28}) {
+		t.Fatalf("Divisors(28) = %v", got)
+	}
+	if got, _ := Totient(36); got != 12 {
+		t.Fatalf("Totient(36) = %d; want 12", got)
+	}
+	if _, err := Factorize(0); err == nil {
+		t.Fatalf("Factorize(0) should return error")
+	}
+}
+
+func TestFactored(t *testing.T) {
+	a, _ := NewFactored(360) // 2^3 · 3^2 · 5
+	b, _ := NewFactored(84)  // 2^2 · 3 · 7
+	if a.String() != "2^3 · 3^2 · 5" {
+		t.Fatalf("NewFactored(360) = %v", a)
+	}
+	if got, _ := a.Gcd(b).Int(); got != 12 {
+		t.Fatalf("Gcd(360, 84) = %d; want 12", got)
+	}
+	if got, _ := a.Lcm(b).Int(); got != 2520 {
+		t.Fatalf("Lcm(360, 84) = %d; want 2520", got)
+	}
+	quotient := a.Div(b)
+	if quotient.IsInteger() || quotient.String() != "2 · 3 · 5 · 7^-1" {
+		t.Fatalf("360 / 84 = %v", quotient)
+	}
+	if _, err := quotient.Int(); err == nil {
+		t.Fatalf("Int() of non-integer should return error")
+	}
+	if got, _ := quotient.Mul(b).Int(); got != 360 {
+		t.Fatalf("(360 / 84) · 84 = %d; want 360", got)
+	}
+	if count, _ := a.NumDivisors(); count != 24 {
+		t.Fatalf("NumDivisors(360) = %d; want 24", count)
+	}
+	// 360^20 overflows int but not Factored.
+	power := a.Pow(20)
+	if _, err := power.Int(); err == nil {
+		t.Fatalf("Int() of 360^20 should overflow")
+	}
+	big, _ := power.BigInt()
+	if big.String() != "1336749453884373406783884597657600000000000000000000" {
+		t.Fatalf("BigInt(360^20) = %v", big)
+	}
+	root, err := power.NthRoot(20)
+	if err != nil || root.String() != a.String() {
+		t.Fatalf("NthRoot(360^20, 20) = %v, %v", root, err)
+	}
+	if _, err := a.NthRoot(2); err == nil {
+		t.Fatalf("NthRoot(360, 2) should return error")
+	}
+	if !b.Divides(b.Pow(2)) || a.Divides(b) {
+		t.Fatalf("Divides returned wrong result")
+	}
+	if _, err := NewFactoredFromFactors(Factor{Prime: 4, Exponent: 1}); err == nil {
+		t.Fatalf("NewFactoredFromFactors with composite should return error")
+	}
+}
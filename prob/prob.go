@@ -0,0 +1,229 @@
+// Package prob computes exact probabilities of discrete distributions with
+// finite support: binomial, hypergeometric, dice and custom weights. All
+// probabilities are rational and returned as Fractions, e.g. the probability
+// of rolling a total of 7 with two dice is exactly 1/6.
+//
+// Distributions can be added (the distribution of the sum of independent
+// variables), so "2d6 + 1d4" is Dice(2, 6) added to Dice(1, 4).
+//
+// Important details:
+//
+// (*) Probabilities are computed with big.Rat internally, so intermediate
+// values never overflow. Methods that return a Fraction return an error if
+// the result doesn't fit in a Fraction (int numerator and denominator).
+//
+// (*) A Distribution is immutable after construction.
+package prob
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// Distribution is a discrete probability distribution on the integers
+// min, min+1, ..., min+len(probabilities)-1.
+type Distribution struct {
+	min           int
+	probabilities []*big.Rat
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// Binomial is a constructor function that returns the distribution of the
+// number of successes in n independent trials with success probability p:
+// P(X = k) = C(n, k)·p^k·(1-p)^(n-k). Returns an error if n is negative or
+// if p is nil or not in [0, 1].
+func Binomial(n int, p *fraction.Fraction) (*Distribution, error) {
+	if n < 0 {
+		return nil, errors.New("n must not be negative")
+	}
+	success := p.Rat()
+	if success == nil {
+		return nil, fraction.ErrNilFraction
+	}
+	if success.Sign() < 0 || success.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, errors.New("p must be in [0, 1]")
+	}
+	failure := new(big.Rat).Sub(big.NewRat(1, 1), success)
+	probabilities := make([]*big.Rat, n+1)
+	binomial := new(big.Int)
+	for k := 0; k <= n; k++ {
+		probability := new(big.Rat).SetInt(binomial.Binomial(int64(n), int64(k)))
+		probability.Mul(probability, ratPow(success, k))
+		probability.Mul(probability, ratPow(failure, n-k))
+		probabilities[k] = probability
+	}
+	return &Distribution{min: 0, probabilities: probabilities}, nil
+}
+
+// Hypergeometric is a constructor function that returns the distribution of
+// the number of successes when drawing `draws` items without replacement
+// from a population of `population` items of which `successes` are
+// successes: P(X = k) = C(K, k)·C(N-K, n-k) / C(N, n). Returns an error if
+// the parameters are inconsistent.
+func Hypergeometric(population, successes, draws int) (*Distribution, error) {
+	if population < 0 || successes < 0 || draws < 0 || successes > population || draws > population {
+		return nil, errors.New("invalid hypergeometric parameters")
+	}
+	low := max(0, draws-(population-successes))
+	high := min(draws, successes)
+	total := new(big.Int).Binomial(int64(population), int64(draws))
+	probabilities := make([]*big.Rat, high-low+1)
+	for k := low; k <= high; k++ {
+		ways := new(big.Int).Binomial(int64(successes), int64(k))
+		ways.Mul(ways, new(big.Int).Binomial(int64(population-successes), int64(draws-k)))
+		probabilities[k-low] = new(big.Rat).SetFrac(ways, total)
+	}
+	return &Distribution{min: low, probabilities: probabilities}, nil
+}
+
+// Dice is a constructor function that returns the distribution of the total
+// of `count` fair dice with `sides` sides (numbered 1 to sides). Dice(2, 6)
+// is the classic 2d6. Returns an error if count is negative or sides < 1.
+func Dice(count, sides int) (*Distribution, error) {
+	if count < 0 || sides < 1 {
+		return nil, errors.New("count must not be negative and sides must be positive")
+	}
+	weights := make(map[int]int, sides)
+	for side := 1; side <= sides; side++ {
+		weights[side] = 1
+	}
+	die, _ := NewFromWeights(weights)
+	result := &Distribution{min: 0, probabilities: []*big.Rat{big.NewRat(1, 1)}}
+	for i := 0; i < count; i++ {
+		result = result.Add(die)
+	}
+	return result, nil
+}
+
+// NewFromWeights is a constructor function that returns the distribution in
+// which every value has a probability proportional to its weight, e.g. a
+// loaded die. Returns an error if a weight is negative or if all weights are
+// zero.
+func NewFromWeights(weights map[int]int) (*Distribution, error) {
+	total := 0
+	low, high := 0, -1
+	for value, weight := range weights {
+		if weight < 0 {
+			return nil, errors.New("weights must not be negative")
+		}
+		if weight == 0 {
+			continue
+		}
+		total += weight
+		if high < low {
+			low, high = value, value
+		}
+		low, high = min(low, value), max(high, value)
+	}
+	if total == 0 {
+		return nil, errors.New("at least one weight must be positive")
+	}
+	probabilities := make([]*big.Rat, high-low+1)
+	for i := range probabilities {
+		probabilities[i] = big.NewRat(int64(weights[low+i]), int64(total))
+	}
+	return &Distribution{min: low, probabilities: probabilities}, nil
+}
+
+// ============================================================================
+// Probabilities
+// ============================================================================
+
+// Support returns the smallest and the largest value with a possibly
+// non-zero probability.
+func (d *Distribution) Support() (int, int) {
+	return d.min, d.min + len(d.probabilities) - 1
+}
+
+// PMF returns the probability P(X = k). Returns an error if the result
+// doesn't fit in a Fraction.
+func (d *Distribution) PMF(k int) (*fraction.Fraction, error) {
+	return fraction.NewFromRat(d.pmf(k))
+}
+
+// CDF returns the probability P(X <= k). Returns an error if the result
+// doesn't fit in a Fraction.
+func (d *Distribution) CDF(k int) (*fraction.Fraction, error) {
+	sum := new(big.Rat)
+	for i := d.min; i <= k && i-d.min < len(d.probabilities); i++ {
+		sum.Add(sum, d.pmf(i))
+	}
+	return fraction.NewFromRat(sum)
+}
+
+// Range returns the probability P(low <= X <= high). Returns an error if the
+// result doesn't fit in a Fraction.
+func (d *Distribution) Range(low, high int) (*fraction.Fraction, error) {
+	sum := new(big.Rat)
+	for i := max(low, d.min); i <= high && i-d.min < len(d.probabilities); i++ {
+		sum.Add(sum, d.pmf(i))
+	}
+	return fraction.NewFromRat(sum)
+}
+
+// Mean returns the expected value E[X]. Returns an error if the result
+// doesn't fit in a Fraction.
+func (d *Distribution) Mean() (*fraction.Fraction, error) {
+	return fraction.NewFromRat(d.mean())
+}
+
+// Variance returns E[(X - E[X])²]. Returns an error if the result doesn't
+// fit in a Fraction.
+func (d *Distribution) Variance() (*fraction.Fraction, error) {
+	mean := d.mean()
+	variance := new(big.Rat)
+	deviation := new(big.Rat)
+	for i, probability := range d.probabilities {
+		deviation.Sub(new(big.Rat).SetInt64(int64(d.min+i)), mean)
+		deviation.Mul(deviation, deviation)
+		variance.Add(variance, deviation.Mul(deviation, probability))
+	}
+	return fraction.NewFromRat(variance)
+}
+
+// Add returns the distribution of X + Y for independent X (distributed as d)
+// and Y (distributed as other).
+func (d *Distribution) Add(other *Distribution) *Distribution {
+	probabilities := make([]*big.Rat, len(d.probabilities)+len(other.probabilities)-1)
+	for i := range probabilities {
+		probabilities[i] = new(big.Rat)
+	}
+	term := new(big.Rat)
+	for i, p := range d.probabilities {
+		for j, q := range other.probabilities {
+			probabilities[i+j].Add(probabilities[i+j], term.Mul(p, q))
+		}
+	}
+	return &Distribution{min: d.min + other.min, probabilities: probabilities}
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+func (d *Distribution) pmf(k int) *big.Rat {
+	if k < d.min || k-d.min >= len(d.probabilities) {
+		return new(big.Rat)
+	}
+	return d.probabilities[k-d.min]
+}
+
+func (d *Distribution) mean() *big.Rat {
+	mean := new(big.Rat)
+	term := new(big.Rat)
+	for i, probability := range d.probabilities {
+		mean.Add(mean, term.Mul(new(big.Rat).SetInt64(int64(d.min+i)), probability))
+	}
+	return mean
+}
+
+func ratPow(r *big.Rat, exponent int) *big.Rat {
+	numerator := new(big.Int).Exp(r.Num(), big.NewInt(int64(exponent)), nil)
+	denominator := new(big.Int).Exp(r.Denom(), big.NewInt(int64(exponent)), nil)
+	return new(big.Rat).SetFrac(numerator, denominator)
+}
@@ -0,0 +1,79 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func ratio(f *fraction.Fraction, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return f.AsIntegerRatio()
+}
+
+func TestBinomial(t *testing.T) {
+	d, err := Binomial(4, fraction.MustNew(1, 3))
+	if err != nil {
+		t.Fatalf("Binomial returned error: %v", err)
+	}
+	// P(X = 2) = 6 · 1/9 · 4/9 = 24/81 = 8/27
+	if got := ratio(d.PMF(2)); got != "8/27" {
+		t.Fatalf("PMF(2) = %s; want 8/27", got)
+	}
+	if got := ratio(d.CDF(4)); got != "1/1" {
+		t.Fatalf("CDF(4) = %s; want 1/1", got)
+	}
+	if got := ratio(d.Mean()); got != "4/3" {
+		t.Fatalf("Mean() = %s; want 4/3", got)
+	}
+	if got := ratio(d.Variance()); got != "8/9" {
+		t.Fatalf("Variance() = %s; want 8/9", got)
+	}
+	if _, err := Binomial(3, fraction.MustNew(3, 2)); err == nil {
+		t.Fatalf("Binomial with p > 1 should return error")
+	}
+}
+
+func TestHypergeometric(t *testing.T) {
+	// Drawing 5 cards: probability of exactly 2 aces.
+	d, _ := Hypergeometric(52, 4, 5)
+	if got := ratio(d.PMF(2)); got != "2162/54145" {
+		t.Fatalf("PMF(2) = %s; want 2162/54145", got)
+	}
+	if low, high := d.Support(); low != 0 || high != 4 {
+		t.Fatalf("Support() = %d, %d; want 0, 4", low, high)
+	}
+	if got := ratio(d.Mean()); got != "5/13" {
+		t.Fatalf("Mean() = %s; want 5/13", got)
+	}
+}
+
+func TestDice(t *testing.T) {
+	d, _ := Dice(2, 6)
+	if got := ratio(d.PMF(7)); got != "1/6" {
+		t.Fatalf("P(2d6 = 7) = %s; want 1/6", got)
+	}
+	if got := ratio(d.Range(10, 12)); got != "1/6" {
+		t.Fatalf("P(2d6 >= 10) = %s; want 1/6", got)
+	}
+	if got := ratio(d.PMF(1)); got != "0/1" {
+		t.Fatalf("P(2d6 = 1) = %s; want 0/1", got)
+	}
+	// 2d6 + 1d4 has mean 7 + 5/2.
+	d4, _ := Dice(1, 4)
+	if got := ratio(d.Add(d4).Mean()); got != "19/2" {
+		t.Fatalf("Mean(2d6 + 1d4) = %s; want 19/2", got)
+	}
+	loaded, _ := NewFromWeights(map[int]int{1: 1, 6: 3, 3: 0})
+	if low, high := loaded.Support(); low != 1 || high != 6 {
+		t.Fatalf("Support() = %d, %d; want 1, 6", low, high)
+	}
+	if got := ratio(loaded.PMF(6)); got != "3/4" {
+		t.Fatalf("PMF(6) = %s; want 3/4", got)
+	}
+	if _, err := NewFromWeights(map[int]int{1: 0}); err == nil {
+		t.Fatalf("NewFromWeights with zero weights should return error")
+	}
+}
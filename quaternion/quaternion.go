@@ -0,0 +1,247 @@
+// Package quaternion provides a Quaternion type for 3D orientation math:
+// quaternion arithmetic, spherical linear interpolation (Slerp), conversion
+// to and from rotation matrices, axis-angle pairs and Euler angles, and the
+// rotation of geom3d vectors.
+//
+// Important details:
+//
+// (*) Quaternion is a small value type: methods never modify the receiver
+// but return a new value.
+//
+// (*) Only unit quaternions represent rotations. The constructors for
+// rotations always return unit quaternions; use Normalize after accumulating
+// many multiplications to counter rounding drift.
+//
+// (*) Euler angles use the aerospace (Z-Y-X, intrinsic) convention: first
+// yaw around z, then pitch around the new y, then roll around the new x.
+// Angles are in radians.
+package quaternion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bogersw/wbmath/geom3d"
+)
+
+// Quaternion represents w + xi + yj + zk.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the quaternion w + xi + yj + zk.
+func New(w, x, y, z float64) Quaternion {
+	return Quaternion{W: w, X: x, Y: y, Z: z}
+}
+
+// Identity returns the identity quaternion 1 (no rotation).
+func Identity() Quaternion {
+	return Quaternion{W: 1}
+}
+
+// NewFromAxisAngle is a constructor function that returns the unit quaternion
+// that rotates by `angle` radians around `axis` (right-hand rule). The axis
+// doesn't have to be a unit vector. Returns an error if the axis is the zero
+// vector.
+func NewFromAxisAngle(axis geom3d.Vec3, angle float64) (Quaternion, error) {
+	if axis.Magnitude() == 0 {
+		return Quaternion{}, errors.New("rotation axis must not be the zero vector")
+	}
+	u := axis.Normalize()
+	sin, cos := math.Sincos(angle / 2)
+	return Quaternion{W: cos, X: u.X * sin, Y: u.Y * sin, Z: u.Z * sin}, nil
+}
+
+// NewFromEuler is a constructor function that returns the unit quaternion
+// for the specified Euler angles (roll around x, pitch around y, yaw around
+// z; applied as yaw, then pitch, then roll).
+func NewFromEuler(roll, pitch, yaw float64) Quaternion {
+	sr, cr := math.Sincos(roll / 2)
+	sp, cp := math.Sincos(pitch / 2)
+	sy, cy := math.Sincos(yaw / 2)
+	return Quaternion{
+		W: cr*cp*cy + sr*sp*sy,
+		X: sr*cp*cy - cr*sp*sy,
+		Y: cr*sp*cy + sr*cp*sy,
+		Z: cr*cp*sy - sr*sp*cy,
+	}
+}
+
+// NewFromMatrix is a constructor function that returns the unit quaternion
+// for the specified rotation matrix. The matrix must be orthonormal with
+// determinant 1; other matrices give meaningless results.
+func NewFromMatrix(m geom3d.Mat3) Quaternion {
+	// Shepperd's method: pick the largest diagonal term to avoid dividing by
+	// a small number.
+	trace := m[0][0] + m[1][1] + m[2][2]
+	var q Quaternion
+	switch {
+	case trace > 0:
+		s := 2 * math.Sqrt(trace+1)
+		q = Quaternion{W: s / 4, X: (m[2][1] - m[1][2]) / s, Y: (m[0][2] - m[2][0]) / s, Z: (m[1][0] - m[0][1]) / s}
+	case m[0][0] > m[1][1] && m[0][0] > m[2][2]:
+		s := 2 * math.Sqrt(1+m[0][0]-m[1][1]-m[2][2])
+		q = Quaternion{W: (m[2][1] - m[1][2]) / s, X: s / 4, Y: (m[0][1] + m[1][0]) / s, Z: (m[0][2] + m[2][0]) / s}
+	case m[1][1] > m[2][2]:
+		s := 2 * math.Sqrt(1+m[1][1]-m[0][0]-m[2][2])
+		q = Quaternion{W: (m[0][2] - m[2][0]) / s, X: (m[0][1] + m[1][0]) / s, Y: s / 4, Z: (m[1][2] + m[2][1]) / s}
+	default:
+		s := 2 * math.Sqrt(1+m[2][2]-m[0][0]-m[1][1])
+		q = Quaternion{W: (m[1][0] - m[0][1]) / s, X: (m[0][2] + m[2][0]) / s, Y: (m[1][2] + m[2][1]) / s, Z: s / 4}
+	}
+	return q.Normalize()
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns q + other.
+func (q Quaternion) Add(other Quaternion) Quaternion {
+	return Quaternion{W: q.W + other.W, X: q.X + other.X, Y: q.Y + other.Y, Z: q.Z + other.Z}
+}
+
+// Subtract returns q - other.
+func (q Quaternion) Subtract(other Quaternion) Quaternion {
+	return Quaternion{W: q.W - other.W, X: q.X - other.X, Y: q.Y - other.Y, Z: q.Z - other.Z}
+}
+
+// Scale returns q with every component multiplied by `factor`.
+func (q Quaternion) Scale(factor float64) Quaternion {
+	return Quaternion{W: q.W * factor, X: q.X * factor, Y: q.Y * factor, Z: q.Z * factor}
+}
+
+// Multiply returns the Hamilton product q * other. For unit quaternions the
+// result represents the rotation `other` followed by q.
+func (q Quaternion) Multiply(other Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*other.W - q.X*other.X - q.Y*other.Y - q.Z*other.Z,
+		X: q.W*other.X + q.X*other.W + q.Y*other.Z - q.Z*other.Y,
+		Y: q.W*other.Y - q.X*other.Z + q.Y*other.W + q.Z*other.X,
+		Z: q.W*other.Z + q.X*other.Y - q.Y*other.X + q.Z*other.W,
+	}
+}
+
+// Conjugate returns the conjugate w - xi - yj - zk. For unit quaternions this
+// is the inverse rotation.
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// Dot returns the four-dimensional dot product of q and other.
+func (q Quaternion) Dot(other Quaternion) float64 {
+	return q.W*other.W + q.X*other.X + q.Y*other.Y + q.Z*other.Z
+}
+
+// Norm returns the length of q.
+func (q Quaternion) Norm() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+// Normalize returns q scaled to unit length. The zero quaternion is returned
+// unchanged.
+func (q Quaternion) Normalize() Quaternion {
+	norm := q.Norm()
+	if norm == 0 {
+		return q
+	}
+	return q.Scale(1 / norm)
+}
+
+// Inverse returns the multiplicative inverse of q. Returns an error for the
+// zero quaternion.
+func (q Quaternion) Inverse() (Quaternion, error) {
+	normSquared := q.Dot(q)
+	if normSquared == 0 {
+		return Quaternion{}, errors.New("the zero quaternion has no inverse")
+	}
+	return q.Conjugate().Scale(1 / normSquared), nil
+}
+
+// Slerp returns the spherical linear interpolation between the unit
+// quaternions q (t = 0) and other (t = 1): the rotation moves at constant
+// angular speed along the shortest path.
+func (q Quaternion) Slerp(other Quaternion, t float64) Quaternion {
+	cos := q.Dot(other)
+	// q and -q represent the same rotation: take the shortest path.
+	if cos < 0 {
+		other = other.Scale(-1)
+		cos = -cos
+	}
+	if cos > 0.9995 {
+		// Nearly identical: linear interpolation avoids dividing by ~0.
+		return q.Add(other.Subtract(q).Scale(t)).Normalize()
+	}
+	theta := math.Acos(cos)
+	sin := math.Sin(theta)
+	a := math.Sin((1-t)*theta) / sin
+	b := math.Sin(t*theta) / sin
+	return q.Scale(a).Add(other.Scale(b))
+}
+
+// AlmostEqual checks if all components of q and other differ by at most
+// `tolerance`.
+func (q Quaternion) AlmostEqual(other Quaternion, tolerance float64) bool {
+	return math.Abs(q.W-other.W) <= tolerance && math.Abs(q.X-other.X) <= tolerance &&
+		math.Abs(q.Y-other.Y) <= tolerance && math.Abs(q.Z-other.Z) <= tolerance
+}
+
+// String implements the fmt.Stringer interface and returns q formatted as
+// "w + xi + yj + zk".
+func (q Quaternion) String() string {
+	return fmt.Sprintf("%g %+gi %+gj %+gk", q.W, q.X, q.Y, q.Z)
+}
+
+// ============================================================================
+// Rotations
+// ============================================================================
+
+// Rotate returns the vector v rotated by the unit quaternion q (q v q*).
+func (q Quaternion) Rotate(v geom3d.Vec3) geom3d.Vec3 {
+	// Optimized form of q * (0, v) * q*: v + 2w(u x v) + 2u x (u x v)
+	u := geom3d.NewVec3(q.X, q.Y, q.Z)
+	t := u.Cross(v).Scale(2)
+	return v.Add(t.Scale(q.W)).Add(u.Cross(t))
+}
+
+// Matrix returns the rotation matrix of the unit quaternion q.
+func (q Quaternion) Matrix() geom3d.Mat3 {
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	return geom3d.Mat3{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y)},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x)},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y)},
+	}
+}
+
+// AxisAngle returns the rotation axis (a unit vector) and angle (in [0, 2π))
+// of the unit quaternion q. For the identity rotation the axis is (1, 0, 0).
+func (q Quaternion) AxisAngle() (geom3d.Vec3, float64) {
+	q = q.Normalize()
+	sin := math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	if sin < 1e-12 {
+		return geom3d.NewVec3(1, 0, 0), 0
+	}
+	angle := 2 * math.Atan2(sin, q.W)
+	return geom3d.NewVec3(q.X/sin, q.Y/sin, q.Z/sin), angle
+}
+
+// Euler returns the Euler angles (roll, pitch, yaw) of the unit quaternion q.
+// At pitch = ±π/2 (gimbal lock) roll is set to zero.
+func (q Quaternion) Euler() (roll, pitch, yaw float64) {
+	sinPitch := 2 * (q.W*q.Y - q.Z*q.X)
+	if math.Abs(sinPitch) >= 1-1e-12 {
+		pitch = math.Copysign(math.Pi/2, sinPitch)
+		yaw = -2 * math.Atan2(q.X, q.W) * math.Copysign(1, sinPitch)
+		return 0, pitch, yaw
+	}
+	roll = math.Atan2(2*(q.W*q.X+q.Y*q.Z), 1-2*(q.X*q.X+q.Y*q.Y))
+	pitch = math.Asin(sinPitch)
+	yaw = math.Atan2(2*(q.W*q.Z+q.X*q.Y), 1-2*(q.Y*q.Y+q.Z*q.Z))
+	return roll, pitch, yaw
+}
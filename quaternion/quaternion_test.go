@@ -0,0 +1,84 @@
+package quaternion
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/geom3d"
+)
+
+const tolerance = 1e-9
+
+func TestArithmetic(t *testing.T) {
+	i := New(0, 1, 0, 0)
+	j := New(0, 0, 1, 0)
+	k := New(0, 0, 0, 1)
+	if got := i.Multiply(j); !got.AlmostEqual(k, tolerance) {
+		t.Fatalf("i * j = %v; want k", got)
+	}
+	if got := j.Multiply(i); !got.AlmostEqual(k.Scale(-1), tolerance) {
+		t.Fatalf("j * i = %v; want -k", got)
+	}
+	q := New(1, 2, 3, 4)
+	inverse, err := q.Inverse()
+	if err != nil || !q.Multiply(inverse).AlmostEqual(Identity(), tolerance) {
+		t.Fatalf("q * q^-1 = %v, %v; want 1", q.Multiply(inverse), err)
+	}
+	if math.Abs(q.Normalize().Norm()-1) > tolerance {
+		t.Fatalf("Normalize().Norm() = %v; want 1", q.Normalize().Norm())
+	}
+	if _, err := (Quaternion{}).Inverse(); err == nil {
+		t.Fatalf("Inverse of zero should return error")
+	}
+}
+
+func TestRotations(t *testing.T) {
+	q, err := NewFromAxisAngle(geom3d.NewVec3(0, 0, 1), math.Pi/2)
+	if err != nil {
+		t.Fatalf("NewFromAxisAngle returned error: %v", err)
+	}
+	x := geom3d.NewVec3(1, 0, 0)
+	if got := q.Rotate(x); !got.AlmostEqual(geom3d.NewVec3(0, 1, 0), tolerance) {
+		t.Fatalf("Rotate = %v; want (0, 1, 0)", got)
+	}
+	m, _ := geom3d.AxisAngle(geom3d.NewVec3(1, 2, 3), 0.7)
+	fromMatrix := NewFromMatrix(m)
+	v := geom3d.NewVec3(-1, 0.5, 2)
+	if !fromMatrix.Rotate(v).AlmostEqual(m.Apply(v), tolerance) {
+		t.Fatalf("NewFromMatrix rotation mismatch")
+	}
+	if !fromMatrix.Matrix().Apply(v).AlmostEqual(m.Apply(v), tolerance) {
+		t.Fatalf("Matrix() mismatch")
+	}
+	axis, angle := fromMatrix.AxisAngle()
+	if !axis.AlmostEqual(geom3d.NewVec3(1, 2, 3).Normalize(), tolerance) || math.Abs(angle-0.7) > tolerance {
+		t.Fatalf("AxisAngle = %v, %v; want (1,2,3)/|.|, 0.7", axis, angle)
+	}
+}
+
+func TestEuler(t *testing.T) {
+	roll, pitch, yaw := 0.1, -0.4, 2.0
+	q := NewFromEuler(roll, pitch, yaw)
+	expected := geom3d.RotationZ(yaw).Multiply(geom3d.RotationY(pitch)).Multiply(geom3d.RotationX(roll))
+	v := geom3d.NewVec3(1, 2, 3)
+	if !q.Rotate(v).AlmostEqual(expected.Apply(v), tolerance) {
+		t.Fatalf("NewFromEuler does not match Rz*Ry*Rx")
+	}
+	r, p, y := q.Euler()
+	if math.Abs(r-roll) > tolerance || math.Abs(p-pitch) > tolerance || math.Abs(y-yaw) > tolerance {
+		t.Fatalf("Euler() = %v, %v, %v; want %v, %v, %v", r, p, y, roll, pitch, yaw)
+	}
+}
+
+func TestSlerp(t *testing.T) {
+	a := Identity()
+	b, _ := NewFromAxisAngle(geom3d.NewVec3(0, 1, 0), math.Pi/2)
+	half := a.Slerp(b, 0.5)
+	want, _ := NewFromAxisAngle(geom3d.NewVec3(0, 1, 0), math.Pi/4)
+	if !half.AlmostEqual(want, tolerance) {
+		t.Fatalf("Slerp(0.5) = %v; want %v", half, want)
+	}
+	if !a.Slerp(b, 1).AlmostEqual(b, tolerance) || !a.Slerp(b.Scale(-1), 0).AlmostEqual(a, tolerance) {
+		t.Fatalf("Slerp endpoints mismatch")
+	}
+}
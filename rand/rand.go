@@ -0,0 +1,268 @@
+// Package rand provides pseudo-random number generators that can be plugged
+// into every part of wbmath that takes a rand.Source: the dist and sample
+// packages and the Shuffle helper in this package.
+//
+// Two generators are available:
+//
+// (*) PCG64, the 128-bit permuted congruential generator with the XSL-RR
+// output function (the PCG64 of NumPy). Different stream numbers give
+// independent sequences and Advance jumps ahead any number of steps.
+//
+// (*) Xoshiro256, the xoshiro256** generator of Blackman and Vigna: very fast,
+// with Jump and LongJump to split the sequence into non-overlapping blocks of
+// 2^128 and 2^192 numbers.
+//
+// Important details:
+//
+// (*) Both generators implement rand.Source of math/rand/v2 and rand.Source64
+// of math/rand, so they can also be used with rand.New of either package.
+//
+// (*) For parallel simulations, use NewPCG64Streams or NewXoshiro256Streams to
+// create one generator per worker. The result depends only on the seed and
+// the number of streams, not on the scheduling of the workers.
+//
+// (*) The generators are not safe for concurrent use and are not suitable for
+// cryptographic purposes.
+package rand
+
+import (
+	"errors"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// PCG64 is a 128-bit PCG generator with XSL-RR output.
+type PCG64 struct {
+	hi, lo       uint64 // state
+	incHi, incLo uint64 // increment (odd)
+}
+
+// Xoshiro256 is a xoshiro256** generator.
+type Xoshiro256 struct {
+	s [4]uint64
+}
+
+// The 128-bit multiplier of PCG64.
+const (
+	pcgMulHi = 0x2360ed051fc65da4
+	pcgMulLo = 0x4385df649fccf645
+)
+
+// ============================================================================
+// PCG64
+// ============================================================================
+
+// NewPCG64 is a constructor function that returns a PCG64 generator with the
+// specified seed and stream number. Generators with different streams
+// produce different sequences, also for the same seed.
+func NewPCG64(seed, stream uint64) *PCG64 {
+	p := &PCG64{}
+	p.seed(seed, stream)
+	return p
+}
+
+// NewPCG64Streams is a constructor function that returns n PCG64 generators
+// with the same seed and the streams 0, ..., n-1. Returns an error if n is
+// negative.
+func NewPCG64Streams(seed uint64, n int) ([]*PCG64, error) {
+	if n < 0 {
+		return nil, errors.New("number of streams must not be negative")
+	}
+	result := make([]*PCG64, n)
+	for i := range result {
+		result[i] = NewPCG64(seed, uint64(i))
+	}
+	return result, nil
+}
+
+// Uint64 returns the next pseudo-random number.
+func (p *PCG64) Uint64() uint64 {
+	p.step()
+	return bits.RotateLeft64(p.hi^p.lo, -int(p.hi>>58))
+}
+
+// Int63 returns a non-negative pseudo-random int64 (for math/rand).
+func (p *PCG64) Int63() int64 {
+	return int64(p.Uint64() >> 1)
+}
+
+// Seed resets the generator to the specified seed, keeping the stream (for
+// math/rand).
+func (p *PCG64) Seed(seed int64) {
+	p.seed(uint64(seed), p.incLo>>1|p.incHi<<63)
+}
+
+// Advance moves the generator delta steps ahead in O(log delta) time, as if
+// Uint64 was called delta times.
+func (p *PCG64) Advance(delta uint64) {
+	// Compose the affine steps x -> a·x + c by repeated squaring (Brown's
+	// algorithm): after the loop x -> accMul·x + accAdd is the delta-step map.
+	accMulHi, accMulLo := uint64(0), uint64(1)
+	accAddHi, accAddLo := uint64(0), uint64(0)
+	curMulHi, curMulLo := uint64(pcgMulHi), uint64(pcgMulLo)
+	curAddHi, curAddLo := p.incHi, p.incLo
+	for ; delta > 0; delta >>= 1 {
+		if delta&1 == 1 {
+			accMulHi, accMulLo = mul128(accMulHi, accMulLo, curMulHi, curMulLo)
+			accAddHi, accAddLo = mul128(accAddHi, accAddLo, curMulHi, curMulLo)
+			accAddHi, accAddLo = add128(accAddHi, accAddLo, curAddHi, curAddLo)
+		}
+		// c -> (a + 1)·c, a -> a²
+		aPlusOneHi, aPlusOneLo := add128(curMulHi, curMulLo, 0, 1)
+		curAddHi, curAddLo = mul128(aPlusOneHi, aPlusOneLo, curAddHi, curAddLo)
+		curMulHi, curMulLo = mul128(curMulHi, curMulLo, curMulHi, curMulLo)
+	}
+	p.hi, p.lo = mul128(accMulHi, accMulLo, p.hi, p.lo)
+	p.hi, p.lo = add128(p.hi, p.lo, accAddHi, accAddLo)
+}
+
+// Clone returns an independent copy of the generator in its current state.
+func (p *PCG64) Clone() *PCG64 {
+	clone := *p
+	return &clone
+}
+
+func (p *PCG64) seed(seed, stream uint64) {
+	// The initialization of the reference implementation (pcg_setseq_128).
+	p.hi, p.lo = 0, 0
+	p.incHi, p.incLo = stream>>63, stream<<1|1
+	p.step()
+	p.hi, p.lo = add128(p.hi, p.lo, 0, seed)
+	p.step()
+}
+
+func (p *PCG64) step() {
+	p.hi, p.lo = mul128(p.hi, p.lo, pcgMulHi, pcgMulLo)
+	p.hi, p.lo = add128(p.hi, p.lo, p.incHi, p.incLo)
+}
+
+// ============================================================================
+// Xoshiro256
+// ============================================================================
+
+// NewXoshiro256 is a constructor function that returns a xoshiro256**
+// generator whose state is derived from the seed with SplitMix64.
+func NewXoshiro256(seed uint64) *Xoshiro256 {
+	x := &Xoshiro256{}
+	x.Seed(int64(seed))
+	return x
+}
+
+// NewXoshiro256FromState is a constructor function that returns a
+// xoshiro256** generator with the specified internal state. Returns an error
+// if the state is all zeros, which is a fixed point of the generator.
+func NewXoshiro256FromState(state [4]uint64) (*Xoshiro256, error) {
+	if state == [4]uint64{} {
+		return nil, errors.New("state must not be all zeros")
+	}
+	return &Xoshiro256{s: state}, nil
+}
+
+// NewXoshiro256Streams is a constructor function that returns n xoshiro256**
+// generators: the first one is seeded with seed and every next one starts
+// 2^128 steps (one Jump) further. Returns an error if n is negative.
+func NewXoshiro256Streams(seed uint64, n int) ([]*Xoshiro256, error) {
+	if n < 0 {
+		return nil, errors.New("number of streams must not be negative")
+	}
+	result := make([]*Xoshiro256, n)
+	current := NewXoshiro256(seed)
+	for i := range result {
+		result[i] = current.Clone()
+		current.Jump()
+	}
+	return result, nil
+}
+
+// Uint64 returns the next pseudo-random number.
+func (x *Xoshiro256) Uint64() uint64 {
+	s := &x.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+// Int63 returns a non-negative pseudo-random int64 (for math/rand).
+func (x *Xoshiro256) Int63() int64 {
+	return int64(x.Uint64() >> 1)
+}
+
+// Seed resets the generator to the state derived from the seed with
+// SplitMix64 (for math/rand).
+func (x *Xoshiro256) Seed(seed int64) {
+	state := uint64(seed)
+	for i := range x.s {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		x.s[i] = z ^ z>>31
+	}
+}
+
+// Jump advances the generator 2^128 steps. It is used to create 2^128
+// non-overlapping subsequences for parallel computations.
+func (x *Xoshiro256) Jump() {
+	x.jump([4]uint64{0x180ec6d33cfd0aba, 0xd5a61266f0c9392c, 0xa9582618e03fc9aa, 0x39abdc4529b1661c})
+}
+
+// LongJump advances the generator 2^192 steps. It is used to create 2^64
+// starting points, from each of which Jump creates further subsequences.
+func (x *Xoshiro256) LongJump() {
+	x.jump([4]uint64{0x76e15d3efefdcbbf, 0xc5004e441c522fb3, 0x77710069854ee241, 0x39109bb02acbe635})
+}
+
+// State returns the internal state of the generator.
+func (x *Xoshiro256) State() [4]uint64 {
+	return x.s
+}
+
+// Clone returns an independent copy of the generator in its current state.
+func (x *Xoshiro256) Clone() *Xoshiro256 {
+	clone := *x
+	return &clone
+}
+
+func (x *Xoshiro256) jump(polynomial [4]uint64) {
+	var s [4]uint64
+	for _, word := range polynomial {
+		for b := 0; b < 64; b++ {
+			if word&(1<<b) != 0 {
+				for i := range s {
+					s[i] ^= x.s[i]
+				}
+			}
+			x.Uint64()
+		}
+	}
+	x.s = s
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// Shuffle randomly permutes the elements of s in place (Fisher-Yates), using
+// the specified source.
+func Shuffle[T any](s []T, src rand.Source) {
+	rand.New(src).Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
+
+// mul128 returns the lower 128 bits of the product of two 128-bit numbers.
+func mul128(aHi, aLo, bHi, bLo uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(aLo, bLo)
+	return hi + aHi*bLo + aLo*bHi, lo
+}
+
+// add128 returns the sum of two 128-bit numbers modulo 2^128.
+func add128(aHi, aLo, bHi, bLo uint64) (uint64, uint64) {
+	lo, carry := bits.Add64(aLo, bLo, 0)
+	hi, _ := bits.Add64(aHi, bHi, carry)
+	return hi, lo
+}
@@ -0,0 +1,111 @@
+package rand
+
+import (
+	mathrand "math/rand"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/bogersw/wbmath/dist"
+	"github.com/bogersw/wbmath/sample"
+)
+
+// Interface checks for math/rand/v2 and math/rand.
+var (
+	_ rand.Source       = (*PCG64)(nil)
+	_ rand.Source       = (*Xoshiro256)(nil)
+	_ mathrand.Source64 = (*PCG64)(nil)
+	_ mathrand.Source64 = (*Xoshiro256)(nil)
+)
+
+func TestPCG64(t *testing.T) {
+	// Reference output of pcg64 (XSL-RR 128/64) with seed 42 and stream 54.
+	p := NewPCG64(42, 54)
+	for _, want := range []uint64{0x86b1da1d72062b68, 0x1304aa46c9853d39, 0xa3670e9e0dd50358, 0xf9090e529a7dae00} {
+		if got := p.Uint64(); got != want {
+			t.Fatalf("Uint64() = %#x; want %#x", got, want)
+		}
+	}
+	// Advance(n) is equivalent to n calls of Uint64.
+	a, b := NewPCG64(7, 3), NewPCG64(7, 3)
+	for i := 0; i < 1000; i++ {
+		a.Uint64()
+	}
+	b.Advance(1000)
+	if x, y := a.Uint64(), b.Uint64(); x != y {
+		t.Fatalf("Advance(1000) gives %#x; want %#x", y, x)
+	}
+	// Seed keeps the stream.
+	c := a.Clone()
+	a.Seed(7)
+	if x, y := a.Uint64(), NewPCG64(7, 3).Uint64(); x != y {
+		t.Fatalf("Seed(7) gives %#x; want %#x", x, y)
+	}
+	if c.Uint64() != b.Uint64() {
+		t.Fatalf("Clone should continue the original sequence")
+	}
+	streams, _ := NewPCG64Streams(1, 3)
+	if x, y := streams[0].Uint64(), streams[1].Uint64(); x == y {
+		t.Fatalf("streams 0 and 1 start with the same value %#x", x)
+	}
+	if _, err := NewPCG64Streams(1, -1); err == nil {
+		t.Fatalf("NewPCG64Streams(-1) should return error")
+	}
+}
+
+func TestXoshiro256(t *testing.T) {
+	// Reference output of xoshiro256** with state {1, 2, 3, 4}.
+	x, err := NewXoshiro256FromState([4]uint64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("NewXoshiro256FromState error: %v", err)
+	}
+	for _, want := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		if got := x.Uint64(); got != want {
+			t.Fatalf("Uint64() = %v; want %v", got, want)
+		}
+	}
+	if _, err := NewXoshiro256FromState([4]uint64{}); err == nil {
+		t.Fatalf("NewXoshiro256FromState(zeros) should return error")
+	}
+	streams, _ := NewXoshiro256Streams(5, 3)
+	jumped := NewXoshiro256(5)
+	jumped.Jump()
+	jumped.Jump()
+	if streams[2].State() != jumped.State() {
+		t.Fatalf("stream 2 should start two jumps after the seed")
+	}
+	long := NewXoshiro256(5)
+	long.LongJump()
+	if long.State() == NewXoshiro256(5).State() || long.State() == streams[1].State() {
+		t.Fatalf("LongJump should give a different state")
+	}
+	if x := NewXoshiro256(5).Int63(); x < 0 {
+		t.Fatalf("Int63() = %v; want non-negative", x)
+	}
+}
+
+func TestPlugIn(t *testing.T) {
+	// The same seed gives the same results in dist, sample and Shuffle.
+	normal, _ := dist.NewNormal(0, 1)
+	a := normal.Sample(5, NewXoshiro256(1))
+	b := normal.Sample(5, NewXoshiro256(1))
+	if !slices.Equal(a, b) {
+		t.Fatalf("Sample with equal seeds = %v and %v", a, b)
+	}
+	chosen, err := sample.Choose(10, 3, NewPCG64(1, 0))
+	if err != nil || len(chosen) != 3 {
+		t.Fatalf("Choose = %v, %v", chosen, err)
+	}
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	Shuffle(s, NewPCG64(1, 0))
+	sorted := slices.Clone(s)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Fatalf("Shuffle = %v; want a permutation", s)
+	}
+	u := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	Shuffle(u, NewPCG64(1, 0))
+	if !slices.Equal(s, u) {
+		t.Fatalf("Shuffle with equal seeds = %v and %v", s, u)
+	}
+}
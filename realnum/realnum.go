@@ -0,0 +1,479 @@
+// Package realnum provides implementations of the wbmath.Real interface for
+// several number backends and generic algorithms that run on any of them:
+// write the algorithm once, then compute fast with Float or exactly with
+// Fraction or Rat.
+//
+// Backends: Float (float64), Fraction (*fraction.Fraction), Rat (*big.Rat)
+// and Decimal (decimal.Decimal with a fixed working scale).
+// Algorithms: Sum, PolyEval, Solve, Determinant and Inverse.
+//
+// Important details:
+//
+// (*) All backends are immutable value types: arithmetic returns new values
+// and never modifies the operands, unlike the in-place Fraction methods.
+//
+// (*) Decimal rounds products and inverses to its working scale (half-even)
+// and panics if a result doesn't fit in a Decimal. Fraction arithmetic
+// overflows silently for large numerators or denominators; use Rat when the
+// numbers can grow large.
+package realnum
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/decimal"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// ErrNilRat is returned by NewRat for a nil *big.Rat.
+var ErrNilRat = errors.New("invalid Rat instance")
+
+// Compile-time checks that the backends implement wbmath.Real.
+var (
+	_ wbmath.Real[Float]    = Float(0)
+	_ wbmath.Real[Fraction] = Fraction{}
+	_ wbmath.Real[Rat]      = Rat{}
+	_ wbmath.Real[Decimal]  = Decimal{}
+)
+
+// ============================================================================
+// Float
+// ============================================================================
+
+// Float is a float64 that implements wbmath.Real.
+type Float float64
+
+// Add returns x + other.
+func (x Float) Add(other Float) Float { return x + other }
+
+// Mul returns x * other.
+func (x Float) Mul(other Float) Float { return x * other }
+
+// Neg returns -x.
+func (x Float) Neg() Float { return -x }
+
+// Inverse returns 1 / x. Returns fraction.ErrDivisionByZero if x is zero.
+func (x Float) Inverse() (Float, error) {
+	if x == 0 {
+		return 0, fraction.ErrDivisionByZero
+	}
+	return 1 / x, nil
+}
+
+// Cmp compares x and other.
+func (x Float) Cmp(other Float) int {
+	switch {
+	case x < other:
+		return -1
+	case x > other:
+		return 1
+	}
+	return 0
+}
+
+// ============================================================================
+// Fraction
+// ============================================================================
+
+// Fraction wraps a *fraction.Fraction as an immutable wbmath.Real. The zero
+// value is 0.
+type Fraction struct {
+	value *fraction.Fraction
+}
+
+// NewFraction is a constructor function that wraps a copy of f. Returns an
+// error if f is nil.
+func NewFraction(f *fraction.Fraction) (Fraction, error) {
+	if f == nil {
+		return Fraction{}, fraction.ErrNilFraction
+	}
+	return Fraction{value: f.Clone().Simplify()}, nil
+}
+
+// NewFractionFromInts is a constructor function that returns the fraction
+// numerator / denominator. Returns an error if the denominator is zero.
+func NewFractionFromInts(numerator, denominator int) (Fraction, error) {
+	f, err := fraction.New(numerator, denominator)
+	if err != nil {
+		return Fraction{}, err
+	}
+	return Fraction{value: f.Simplify()}, nil
+}
+
+// Value returns a copy of the wrapped Fraction.
+func (x Fraction) Value() *fraction.Fraction { return x.fraction().Clone() }
+
+// String implements the fmt.Stringer interface.
+func (x Fraction) String() string { return x.fraction().String() }
+
+// Add returns x + other.
+func (x Fraction) Add(other Fraction) Fraction {
+	return Fraction{value: x.Value().Add(other.fraction()).Simplify()}
+}
+
+// Mul returns x * other.
+func (x Fraction) Mul(other Fraction) Fraction {
+	return Fraction{value: x.Value().Multiply(other.fraction()).Simplify()}
+}
+
+// Neg returns -x.
+func (x Fraction) Neg() Fraction {
+	return Fraction{value: x.Value().MultiplyInt(-1)}
+}
+
+// Inverse returns 1 / x. Returns fraction.ErrDivisionByZero if x is zero.
+func (x Fraction) Inverse() (Fraction, error) {
+	numerator, _ := x.fraction().Numerator()
+	if numerator == 0 {
+		return Fraction{}, fraction.ErrDivisionByZero
+	}
+	denominator, _ := x.fraction().Denominator()
+	return NewFractionFromInts(denominator, numerator)
+}
+
+// Cmp compares x and other exactly.
+func (x Fraction) Cmp(other Fraction) int {
+	return x.fraction().Compare(other.fraction())
+}
+
+func (x Fraction) fraction() *fraction.Fraction {
+	if x.value == nil {
+		return fraction.MustNew(0, 1)
+	}
+	return x.value
+}
+
+// ============================================================================
+// Rat
+// ============================================================================
+
+// Rat wraps a *big.Rat as an immutable wbmath.Real: arbitrary precision
+// without overflow. The zero value is 0.
+type Rat struct {
+	value *big.Rat
+}
+
+// NewRat is a constructor function that wraps a copy of r. Returns ErrNilRat
+// if r is nil.
+func NewRat(r *big.Rat) (Rat, error) {
+	if r == nil {
+		return Rat{}, ErrNilRat
+	}
+	return Rat{value: new(big.Rat).Set(r)}, nil
+}
+
+// NewRatFromInts is a constructor function that returns the rational number
+// numerator / denominator. Returns fraction.ErrDivisionByZero if the
+// denominator is zero.
+func NewRatFromInts(numerator, denominator int64) (Rat, error) {
+	if denominator == 0 {
+		return Rat{}, fraction.ErrDivisionByZero
+	}
+	return Rat{value: big.NewRat(numerator, denominator)}, nil
+}
+
+// Value returns a copy of the wrapped big.Rat.
+func (x Rat) Value() *big.Rat { return new(big.Rat).Set(x.rat()) }
+
+// String implements the fmt.Stringer interface.
+func (x Rat) String() string { return x.rat().RatString() }
+
+// Add returns x + other.
+func (x Rat) Add(other Rat) Rat { return Rat{value: new(big.Rat).Add(x.rat(), other.rat())} }
+
+// Mul returns x * other.
+func (x Rat) Mul(other Rat) Rat { return Rat{value: new(big.Rat).Mul(x.rat(), other.rat())} }
+
+// Neg returns -x.
+func (x Rat) Neg() Rat { return Rat{value: new(big.Rat).Neg(x.rat())} }
+
+// Inverse returns 1 / x. Returns fraction.ErrDivisionByZero if x is zero.
+func (x Rat) Inverse() (Rat, error) {
+	if x.rat().Sign() == 0 {
+		return Rat{}, fraction.ErrDivisionByZero
+	}
+	return Rat{value: new(big.Rat).Inv(x.rat())}, nil
+}
+
+// Cmp compares x and other.
+func (x Rat) Cmp(other Rat) int { return x.rat().Cmp(other.rat()) }
+
+func (x Rat) rat() *big.Rat {
+	if x.value == nil {
+		return new(big.Rat)
+	}
+	return x.value
+}
+
+// ============================================================================
+// Decimal
+// ============================================================================
+
+// Decimal wraps a decimal.Decimal as a wbmath.Real with a fixed working
+// scale: products and inverses are rounded half-even to that scale. The
+// zero value is 0 with scale 0.
+type Decimal struct {
+	value decimal.Decimal
+	scale int
+}
+
+// NewDecimal is a constructor function that wraps d with the specified
+// working scale. Returns an error if the scale is invalid or if d doesn't
+// fit at that scale.
+func NewDecimal(d decimal.Decimal, scale int) (Decimal, error) {
+	value, err := d.Rescale(scale, decimal.HalfEven)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{value: value, scale: scale}, nil
+}
+
+// Value returns the wrapped Decimal.
+func (x Decimal) Value() decimal.Decimal { return x.value }
+
+// String implements the fmt.Stringer interface.
+func (x Decimal) String() string { return x.value.String() }
+
+// Add returns x + other, at the larger of the two working scales. Panics if
+// the result doesn't fit.
+func (x Decimal) Add(other Decimal) Decimal {
+	sum, err := x.value.Add(other.value)
+	if err != nil {
+		panic(err)
+	}
+	return Decimal{value: sum, scale: max(x.scale, other.scale)}
+}
+
+// Mul returns x * other, rounded to the larger of the two working scales.
+// Panics if the result doesn't fit.
+func (x Decimal) Mul(other Decimal) Decimal {
+	scale := max(x.scale, other.scale)
+	product, err := x.value.MultiplyRound(other.value, scale, decimal.HalfEven)
+	if err != nil {
+		panic(err)
+	}
+	return Decimal{value: product, scale: scale}
+}
+
+// Neg returns -x.
+func (x Decimal) Neg() Decimal { return Decimal{value: x.value.Negate(), scale: x.scale} }
+
+// Inverse returns 1 / x rounded to the working scale. Returns
+// fraction.ErrDivisionByZero if x is zero, or an error if the result doesn't
+// fit.
+func (x Decimal) Inverse() (Decimal, error) {
+	if x.value.IsZero() {
+		return Decimal{}, fraction.ErrDivisionByZero
+	}
+	inverse, err := decimal.NewFromInt(1).Divide(x.value, x.scale, decimal.HalfEven)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{value: inverse, scale: x.scale}, nil
+}
+
+// Cmp compares x and other.
+func (x Decimal) Cmp(other Decimal) int { return x.value.Compare(other.value) }
+
+// ============================================================================
+// Generic algorithms
+// ============================================================================
+
+// Sum returns zero + values[0] + values[1] + ... The zero value is passed
+// explicitly because a generic algorithm cannot create one.
+func Sum[T wbmath.Real[T]](zero T, values ...T) T {
+	sum := zero
+	for _, value := range values {
+		sum = sum.Add(value)
+	}
+	return sum
+}
+
+// PolyEval evaluates the polynomial with the specified coefficients at x
+// using Horner's method; coefficients[i] belongs to x^i. An empty list of
+// coefficients is the zero polynomial.
+func PolyEval[T wbmath.Real[T]](coefficients []T, x T) T {
+	if len(coefficients) == 0 {
+		return x.Add(x.Neg())
+	}
+	result := coefficients[len(coefficients)-1]
+	for i := len(coefficients) - 2; i >= 0; i-- {
+		result = result.Mul(x).Add(coefficients[i])
+	}
+	return result
+}
+
+// Solve solves the square linear system a·x = b with Gaussian elimination
+// and partial pivoting (the pivot with the largest absolute value). The
+// inputs are not modified. Returns an error if the dimensions don't match, or
+// wbmath.ErrSingular if the matrix is singular. With Float values,
+// singularity is only detected for exactly zero pivots.
+func Solve[T wbmath.Real[T]](a [][]T, b []T) ([]T, error) {
+	if len(a) == 0 || len(b) != len(a) {
+		return nil, errors.New("dimensions of matrix and right-hand side don't match")
+	}
+	if err := validateSquare(a); err != nil {
+		return nil, err
+	}
+	columns := make([][]T, len(b))
+	for i, value := range b {
+		columns[i] = []T{value}
+	}
+	m := augment(a, columns)
+	if _, err := eliminate(m); err != nil {
+		return nil, err
+	}
+	x := make([]T, len(b))
+	for i, row := range substitute(m) {
+		x[i] = row[0]
+	}
+	return x, nil
+}
+
+// Determinant returns the determinant of the square matrix a, computed with
+// Gaussian elimination and partial pivoting. The input is not modified.
+// Returns an error if the matrix is empty or not square.
+func Determinant[T wbmath.Real[T]](a [][]T) (T, error) {
+	if err := validateSquare(a); err != nil {
+		var zero T
+		return zero, err
+	}
+	determinant, err := eliminate(augment(a, nil))
+	if err != nil {
+		// Singular: the determinant is zero.
+		return a[0][0].Add(a[0][0].Neg()), nil
+	}
+	return determinant, nil
+}
+
+// Inverse returns the inverse of the square matrix a, computed with Gaussian
+// elimination of [a | I] and partial pivoting. The input is not modified.
+// Returns an error if the matrix is empty or not square, or
+// wbmath.ErrSingular if it is singular.
+func Inverse[T wbmath.Real[T]](a [][]T) ([][]T, error) {
+	if err := validateSquare(a); err != nil {
+		return nil, err
+	}
+	// The identity is built from 1 = v·(1/v) for a non-zero element v of the
+	// first column; if there is none, the matrix is singular.
+	var one T
+	found := false
+	for _, row := range a {
+		if inverse, err := row[0].Inverse(); err == nil {
+			one, found = row[0].Mul(inverse), true
+			break
+		}
+	}
+	if !found {
+		return nil, wbmath.ErrSingular
+	}
+	zero := one.Add(one.Neg())
+	identity := make([][]T, len(a))
+	for i := range identity {
+		identity[i] = make([]T, len(a))
+		for j := range identity[i] {
+			identity[i][j] = zero
+		}
+		identity[i][i] = one
+	}
+	m := augment(a, identity)
+	if _, err := eliminate(m); err != nil {
+		return nil, err
+	}
+	return substitute(m), nil
+}
+
+// validateSquare checks that a is a non-empty square matrix.
+func validateSquare[T any](a [][]T) error {
+	if len(a) == 0 {
+		return errors.New("matrix must not be empty")
+	}
+	for _, row := range a {
+		if len(row) != len(a) {
+			return errors.New("matrix must be square")
+		}
+	}
+	return nil
+}
+
+// augment returns a copy of the square matrix a with the row extra[i]
+// appended to row i, e.g. the right-hand side of a linear system.
+func augment[T any](a, extra [][]T) [][]T {
+	m := make([][]T, len(a))
+	for i, row := range a {
+		m[i] = append(make([]T, 0, len(row)+len(extra)), row...)
+		if extra != nil {
+			m[i] = append(m[i], extra[i]...)
+		}
+	}
+	return m
+}
+
+// eliminate reduces the augmented matrix m, whose first len(m) columns form a
+// square matrix, to upper triangular form in place, with partial pivoting.
+// Returns the determinant of the square part, or wbmath.ErrSingular if a
+// pivot is zero.
+func eliminate[T wbmath.Real[T]](m [][]T) (T, error) {
+	n := len(m)
+	zero := m[0][0].Add(m[0][0].Neg())
+	abs := func(v T) T {
+		if v.Cmp(zero) < 0 {
+			return v.Neg()
+		}
+		return v
+	}
+	determinant, negate := zero, false
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if abs(m[row][col]).Cmp(abs(m[pivot][col])) > 0 {
+				pivot = row
+			}
+		}
+		inverse, err := m[pivot][col].Inverse()
+		if err != nil {
+			return zero, wbmath.ErrSingular
+		}
+		if pivot != col {
+			m[col], m[pivot] = m[pivot], m[col]
+			// A row swap changes the sign of the determinant.
+			negate = !negate
+		}
+		if col == 0 {
+			determinant = m[0][0]
+		} else {
+			determinant = determinant.Mul(m[col][col])
+		}
+		for row := col + 1; row < n; row++ {
+			factor := m[row][col].Mul(inverse).Neg()
+			for k := col; k < len(m[row]); k++ {
+				m[row][k] = m[row][k].Add(factor.Mul(m[col][k]))
+			}
+		}
+	}
+	if negate {
+		determinant = determinant.Neg()
+	}
+	return determinant, nil
+}
+
+// substitute returns the solutions of the upper triangular systems produced
+// by eliminate: one row per unknown, one column per appended column of m.
+func substitute[T wbmath.Real[T]](m [][]T) [][]T {
+	n := len(m)
+	x := make([][]T, n)
+	for row := n - 1; row >= 0; row-- {
+		inverse, _ := m[row][row].Inverse()
+		x[row] = make([]T, len(m[row])-n)
+		for c := range x[row] {
+			sum := m[row][n+c]
+			for k := row + 1; k < n; k++ {
+				sum = sum.Add(m[row][k].Mul(x[k][c]).Neg())
+			}
+			x[row][c] = sum.Mul(inverse)
+		}
+	}
+	return x
+}
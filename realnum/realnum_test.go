@@ -0,0 +1,152 @@
+package realnum
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/decimal"
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func fractions(values ...[2]int) []Fraction {
+	result := make([]Fraction, len(values))
+	for i, v := range values {
+		result[i], _ = NewFractionFromInts(v[0], v[1])
+	}
+	return result
+}
+
+func TestSum(t *testing.T) {
+	tenth, _ := NewFractionFromInts(1, 10)
+	values := make([]Fraction, 10)
+	floats := make([]Float, 10)
+	for i := range values {
+		values[i], floats[i] = tenth, 0.1
+	}
+	if got := Sum(Fraction{}, values...); got.String() != "1" {
+		t.Fatalf("Sum of ten 1/10 = %v; want exactly 1", got)
+	}
+	if got := Sum(0, floats...); got == 1 {
+		t.Fatalf("float sum of ten 0.1 is exactly 1; expected rounding error")
+	}
+}
+
+func TestPolyEval(t *testing.T) {
+	// p(x) = 1 - 3x + 2x², p(1/2) = 0
+	p := fractions([2]int{1, 1}, [2]int{-3, 1}, [2]int{2, 1})
+	if got := PolyEval(p, fractions([2]int{1, 2})[0]); got.String() != "0" {
+		t.Fatalf("p(1/2) = %v; want 0", got)
+	}
+	r1, _ := NewRatFromInts(1, 1)
+	r2, _ := NewRatFromInts(-3, 1)
+	r3, _ := NewRatFromInts(2, 1)
+	x, _ := NewRatFromInts(3, 1)
+	if got := PolyEval([]Rat{r1, r2, r3}, x); got.String() != "10" {
+		t.Fatalf("p(3) = %v; want 10", got)
+	}
+	if got := PolyEval(nil, Float(2)); got != 0 {
+		t.Fatalf("empty polynomial = %v; want 0", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := Float(0).Inverse(); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("Float(0).Inverse() error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := (Fraction{}).Inverse(); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("Fraction{}.Inverse() error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := (Rat{}).Inverse(); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("Rat{}.Inverse() error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := (Decimal{}).Inverse(); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("Decimal{}.Inverse() error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := NewRatFromInts(1, 0); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("NewRatFromInts(1, 0) error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if _, err := NewRat(nil); !errors.Is(err, ErrNilRat) {
+		t.Fatalf("NewRat(nil) error = %v; want %v", err, ErrNilRat)
+	}
+	if _, err := NewFraction(nil); !errors.Is(err, fraction.ErrNilFraction) {
+		t.Fatalf("NewFraction(nil) error = %v; want %v", err, fraction.ErrNilFraction)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	// 2x + y = 3, x + 3y = 5 => x = 4/5, y = 7/5
+	a := [][]Fraction{fractions([2]int{2, 1}, [2]int{1, 1}), fractions([2]int{1, 1}, [2]int{3, 1})}
+	b := fractions([2]int{3, 1}, [2]int{5, 1})
+	x, err := Solve(a, b)
+	if err != nil || x[0].String() != "4/5" || x[1].String() != "1 2/5" {
+		t.Fatalf("Solve = %v, %v; want [4/5 1 2/5]", x, err)
+	}
+	xf, _ := Solve([][]Float{{2, 1}, {1, 3}}, []Float{3, 5})
+	if math.Abs(float64(xf[0])-0.8) > 1e-12 || math.Abs(float64(xf[1])-1.4) > 1e-12 {
+		t.Fatalf("Solve with floats = %v", xf)
+	}
+	d := func(s string) Decimal {
+		value, _ := NewDecimal(decimal.MustParse(s), 6)
+		return value
+	}
+	xd, _ := Solve([][]Decimal{{d("2"), d("1")}, {d("1"), d("3")}}, []Decimal{d("3"), d("5")})
+	if xd[0].String() != "0.800000" || xd[1].String() != "1.400000" {
+		t.Fatalf("Solve with decimals = %v", xd)
+	}
+	if _, err := Solve([][]Float{{1, 2}, {2, 4}}, []Float{1, 2}); !errors.Is(err, wbmath.ErrSingular) {
+		t.Fatalf("Solve of singular system returned %v; want ErrSingular", err)
+	}
+}
+
+func TestDeterminantAndInverse(t *testing.T) {
+	// The first pivot requires a row swap.
+	a := [][]Fraction{
+		fractions([2]int{0, 1}, [2]int{2, 1}, [2]int{1, 1}),
+		fractions([2]int{1, 1}, [2]int{1, 1}, [2]int{0, 1}),
+		fractions([2]int{3, 1}, [2]int{0, 1}, [2]int{1, 1}),
+	}
+	if det, err := Determinant(a); err != nil || det.String() != "-5" {
+		t.Fatalf("Determinant = %v, %v; want -5", det, err)
+	}
+	inverse, err := Inverse(a)
+	if err != nil {
+		t.Fatalf("Inverse returned error: %v", err)
+	}
+	want := "[[-1/5 2/5 1/5] [1/5 3/5 -1/5] [3/5 -1 1/5 2/5]]"
+	if got := fmt.Sprint(inverse); got != want {
+		t.Fatalf("Inverse = %v; want %v", got, want)
+	}
+	if got := fmt.Sprint(a[0]); got != "[0 2 1]" {
+		t.Fatalf("Inverse modified its input: %v", got)
+	}
+	r := func(n int64) Rat {
+		value, _ := NewRatFromInts(n, 1)
+		return value
+	}
+	if det, err := Determinant([][]Rat{{r(4), r(7)}, {r(2), r(6)}}); err != nil || det.String() != "10" {
+		t.Fatalf("Determinant with rats = %v, %v; want 10", det, err)
+	}
+	inverseFloat, err := Inverse([][]Float{{4, 7}, {2, 6}})
+	if err != nil || math.Abs(float64(inverseFloat[0][1])+0.7) > 1e-12 || math.Abs(float64(inverseFloat[1][0])+0.2) > 1e-12 {
+		t.Fatalf("Inverse with floats = %v, %v", inverseFloat, err)
+	}
+	singular := [][]Float{{1, 2}, {2, 4}}
+	if det, err := Determinant(singular); err != nil || det != 0 {
+		t.Fatalf("Determinant of singular matrix = %v, %v; want 0", det, err)
+	}
+	if _, err := Inverse(singular); !errors.Is(err, wbmath.ErrSingular) {
+		t.Fatalf("Inverse of singular matrix returned %v; want ErrSingular", err)
+	}
+	if _, err := Inverse([][]Float{{0, 1}, {0, 2}}); !errors.Is(err, wbmath.ErrSingular) {
+		t.Fatalf("Inverse with zero column returned %v; want ErrSingular", err)
+	}
+	if _, err := Determinant([][]Float{{1, 2}}); err == nil {
+		t.Fatalf("Determinant of non-square matrix should return error")
+	}
+	if _, err := Inverse([][]Float{}); err == nil {
+		t.Fatalf("Inverse of empty matrix should return error")
+	}
+}
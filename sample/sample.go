@@ -0,0 +1,252 @@
+// Package sample provides random sampling algorithms: uniform sampling of
+// indices, reservoir sampling from an iterator of unknown length, weighted
+// sampling with and without replacement and stratified train/test splits.
+//
+// Important details:
+//
+// (*) Every function draws from a caller-supplied rand.Source (math/rand/v2),
+// like the dist package, so results are reproducible: the same seed always
+// gives the same sample.
+//
+// (*) The sampling functions return indices, so the same sample can be taken
+// from several aligned Vectors (e.g. features and labels). Use Take to
+// collect the elements of a Vector at the sampled indices.
+//
+// (*) The input Vectors are never modified.
+package sample
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// ============================================================================
+// Uniform sampling
+// ============================================================================
+
+// Choose returns k distinct indices drawn uniformly from 0, ..., n-1 in
+// random order, using Floyd's algorithm (which needs only O(k) memory).
+// Returns an error if k is negative or larger than n.
+func Choose(n, k int, src rand.Source) ([]int, error) {
+	if k < 0 || k > n {
+		return nil, fmt.Errorf("cannot choose %d of %d indices", k, n)
+	}
+	rng := rand.New(src)
+	chosen := make(map[int]bool, k)
+	result := make([]int, 0, k)
+	for j := n - k; j < n; j++ {
+		i := rng.IntN(j + 1)
+		if chosen[i] {
+			i = j
+		}
+		chosen[i] = true
+		result = append(result, i)
+	}
+	rng.Shuffle(len(result), func(a, b int) { result[a], result[b] = result[b], result[a] })
+	return result, nil
+}
+
+// Reservoir returns k elements drawn uniformly without replacement from the
+// sequence in a single pass, without knowing its length in advance (reservoir
+// sampling, Li's algorithm L). If the sequence has fewer than k elements all
+// of them are returned. Returns an error if k is negative.
+func Reservoir[T any](seq iter.Seq[T], k int, src rand.Source) ([]T, error) {
+	if k < 0 {
+		return nil, errors.New("sample size must not be negative")
+	}
+	reservoir := make([]T, 0, k)
+	if k == 0 {
+		return reservoir, nil
+	}
+	rng := rand.New(src)
+	// w is the largest of k uniform keys; skip is the number of elements to
+	// pass over before the next replacement.
+	w := math.Exp(math.Log(uniform(rng)) / float64(k))
+	skip := nextSkip(rng, w)
+	for element := range seq {
+		if len(reservoir) < k {
+			reservoir = append(reservoir, element)
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		reservoir[rng.IntN(k)] = element
+		w *= math.Exp(math.Log(uniform(rng)) / float64(k))
+		skip = nextSkip(rng, w)
+	}
+	return reservoir, nil
+}
+
+// ============================================================================
+// Weighted sampling
+// ============================================================================
+
+// WeightedWithReplacement returns k indices drawn independently, index i with
+// probability proportional to weights[i]. Returns an error if k is negative,
+// if a weight is negative or not finite, or if all weights are zero.
+func WeightedWithReplacement[T wbmath.SignedNumber](weights vector.Vector[T], k int, src rand.Source) ([]int, error) {
+	if k < 0 {
+		return nil, errors.New("sample size must not be negative")
+	}
+	cumulative, err := cumulativeWeights(weights)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(src)
+	total := cumulative[len(cumulative)-1]
+	result := make([]int, k)
+	for j := range result {
+		target := rng.Float64() * total
+		// The first index whose cumulative weight exceeds the target; indices
+		// with zero weight are never selected.
+		result[j] = sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > target })
+	}
+	return result, nil
+}
+
+// WeightedWithoutReplacement returns k distinct indices, drawn one after the
+// other with probabilities proportional to the weights of the remaining
+// indices (the algorithm of Efraimidis and Spirakis). The indices are
+// returned in the order they were drawn. Returns an error if k is negative or
+// larger than the number of positive weights, or if a weight is negative or
+// not finite.
+func WeightedWithoutReplacement[T wbmath.SignedNumber](weights vector.Vector[T], k int, src rand.Source) ([]int, error) {
+	if k < 0 {
+		return nil, errors.New("sample size must not be negative")
+	}
+	if err := checkWeights(weights); err != nil {
+		return nil, err
+	}
+	rng := rand.New(src)
+	// Every index with a positive weight w gets the key log(u)/w; the k
+	// largest keys form the sample.
+	type keyed struct {
+		index int
+		key   float64
+	}
+	candidates := make([]keyed, 0, len(weights))
+	for i, weight := range weights {
+		if w := float64(weight); w > 0 {
+			candidates = append(candidates, keyed{i, math.Log(uniform(rng)) / w})
+		}
+	}
+	if k > len(candidates) {
+		return nil, fmt.Errorf("cannot draw %d indices from %d positive weights", k, len(candidates))
+	}
+	slices.SortFunc(candidates, func(a, b keyed) int { return cmp.Compare(b.key, a.key) })
+	result := make([]int, k)
+	for j := range result {
+		result[j] = candidates[j].index
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Stratified splitting
+// ============================================================================
+
+// StratifiedSplit splits the indices 0, ..., len(strata)-1 into a training
+// and a test set such that every stratum (the observations with equal
+// labels) contributes the same proportion, testFraction, to the test set
+// (rounded to the nearest integer per stratum). Both sets are sorted.
+// Returns an error if testFraction is not in [0, 1].
+func StratifiedSplit[K comparable](strata []K, testFraction float64, src rand.Source) ([]int, []int, error) {
+	if !(testFraction >= 0 && testFraction <= 1) {
+		return nil, nil, errors.New("test fraction must be in [0, 1]")
+	}
+	groups := make(map[K][]int)
+	var labels []K // in order of first appearance, for reproducibility
+	for i, label := range strata {
+		if _, ok := groups[label]; !ok {
+			labels = append(labels, label)
+		}
+		groups[label] = append(groups[label], i)
+	}
+	rng := rand.New(src)
+	train, test := []int{}, []int{}
+	for _, label := range labels {
+		group := groups[label]
+		rng.Shuffle(len(group), func(a, b int) { group[a], group[b] = group[b], group[a] })
+		n := int(math.Round(testFraction * float64(len(group))))
+		test = append(test, group[:n]...)
+		train = append(train, group[n:]...)
+	}
+	slices.Sort(train)
+	slices.Sort(test)
+	return train, test, nil
+}
+
+// Take returns a new Vector with the elements of v at the specified indices.
+// Returns an error if an index is out of range.
+func Take[T wbmath.SignedNumber](v vector.Vector[T], indices []int) (vector.Vector[T], error) {
+	result := vector.NewFromValue(T(0), len(indices))
+	for j, i := range indices {
+		if i < 0 || i >= len(v) {
+			return nil, fmt.Errorf("index %d out of range for vector of length %d", i, len(v))
+		}
+		result[j] = v[i]
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// uniform returns a uniform variate in (0, 1), which is safe to take the
+// logarithm of.
+func uniform(rng *rand.Rand) float64 {
+	for {
+		if u := rng.Float64(); u > 0 {
+			return u
+		}
+	}
+}
+
+// nextSkip returns the number of elements algorithm L skips before the next
+// replacement: a geometric variate with success probability 1 - w.
+func nextSkip(rng *rand.Rand, w float64) int {
+	skip := math.Floor(math.Log(uniform(rng)) / math.Log1p(-w))
+	if skip > math.MaxInt32 || math.IsNaN(skip) {
+		return math.MaxInt32
+	}
+	return int(skip)
+}
+
+// checkWeights returns an error if a weight is negative or not finite.
+func checkWeights[T wbmath.SignedNumber](weights vector.Vector[T]) error {
+	for _, weight := range weights {
+		if w := float64(weight); !(w >= 0) || math.IsInf(w, 1) {
+			return fmt.Errorf("weight %v must be non-negative and finite", w)
+		}
+	}
+	return nil
+}
+
+// cumulativeWeights validates the weights and returns their running sums.
+func cumulativeWeights[T wbmath.SignedNumber](weights vector.Vector[T]) ([]float64, error) {
+	if err := checkWeights(weights); err != nil {
+		return nil, err
+	}
+	cumulative := make([]float64, len(weights))
+	total := 0.0
+	for i, weight := range weights {
+		total += float64(weight)
+		cumulative[i] = total
+	}
+	if total == 0 {
+		return nil, errors.New("at least one weight must be positive")
+	}
+	return cumulative, nil
+}
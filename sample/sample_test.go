@@ -0,0 +1,113 @@
+package sample
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+func TestChoose(t *testing.T) {
+	indices, err := Choose(10, 4, rand.NewPCG(1, 2))
+	if err != nil {
+		t.Fatalf("Choose returned error: %v", err)
+	}
+	sorted := slices.Clone(indices)
+	slices.Sort(sorted)
+	if len(sorted) != 4 || sorted[0] < 0 || sorted[3] >= 10 || len(slices.Compact(sorted)) != 4 {
+		t.Fatalf("Choose(10, 4) = %v; want 4 distinct indices in [0, 10)", indices)
+	}
+	if _, err := Choose(3, 4, rand.NewPCG(1, 2)); err == nil {
+		t.Fatalf("Choose(3, 4) should return error")
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	src := rand.NewPCG(3, 4)
+	// Every element of 0..99 should be selected with probability 10/100.
+	elements := make([]int, 100)
+	for i := range elements {
+		elements[i] = i
+	}
+	counts := make([]int, 100)
+	for trial := 0; trial < 2000; trial++ {
+		reservoir, _ := Reservoir(slices.Values(elements), 10, src)
+		if len(reservoir) != 10 {
+			t.Fatalf("Reservoir returned %d elements; want 10", len(reservoir))
+		}
+		for _, element := range reservoir {
+			counts[element]++
+		}
+	}
+	for _, i := range []int{0, 9, 10, 50, 99} {
+		if counts[i] < 130 || counts[i] > 270 {
+			t.Fatalf("element %d selected %d times in 2000 trials; want about 200", i, counts[i])
+		}
+	}
+	short, _ := Reservoir(slices.Values([]string{"a", "b"}), 5, src)
+	if len(short) != 2 {
+		t.Fatalf("Reservoir of a short sequence = %v; want all elements", short)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	weights := vector.New(1.0, 0, 3)
+	indices, err := WeightedWithReplacement(weights, 4000, rand.NewPCG(5, 6))
+	if err != nil {
+		t.Fatalf("WeightedWithReplacement returned error: %v", err)
+	}
+	counts := make([]int, 3)
+	for _, i := range indices {
+		counts[i]++
+	}
+	if counts[1] != 0 || math.Abs(float64(counts[2])/4000-0.75) > 0.03 {
+		t.Fatalf("WeightedWithReplacement counts = %v; want about [1000 0 3000]", counts)
+	}
+	if _, err := WeightedWithReplacement(vector.New(0, 0), 1, rand.NewPCG(5, 6)); err == nil {
+		t.Fatalf("WeightedWithReplacement with zero weights should return error")
+	}
+
+	first := 0
+	for trial := 0; trial < 1000; trial++ {
+		drawn, _ := WeightedWithoutReplacement(vector.New(1, 0, 3, 1), 3, rand.NewPCG(uint64(trial), 7))
+		if slices.Contains(drawn, 1) || len(drawn) != 3 || drawn[0] == drawn[1] {
+			t.Fatalf("WeightedWithoutReplacement = %v; want 3 distinct indices without 1", drawn)
+		}
+		if drawn[0] == 2 {
+			first++
+		}
+	}
+	if first < 540 || first > 660 {
+		t.Fatalf("index 2 drawn first %d times in 1000 trials; want about 600", first)
+	}
+	if _, err := WeightedWithoutReplacement(vector.New(1, 0, 3), 3, rand.NewPCG(1, 2)); err == nil {
+		t.Fatalf("WeightedWithoutReplacement of more than the positive weights should return error")
+	}
+	if _, err := WeightedWithoutReplacement(vector.New(1, -1), 1, rand.NewPCG(1, 2)); err == nil {
+		t.Fatalf("WeightedWithoutReplacement with negative weights should return error")
+	}
+}
+
+func TestStratifiedSplit(t *testing.T) {
+	strata := []string{"a", "a", "a", "a", "b", "b", "b", "b", "b", "b", "b", "b"}
+	train, test, err := StratifiedSplit(strata, 0.25, rand.NewPCG(8, 9))
+	if err != nil {
+		t.Fatalf("StratifiedSplit returned error: %v", err)
+	}
+	if len(train) != 9 || len(test) != 3 {
+		t.Fatalf("StratifiedSplit = %v, %v; want 9 training and 3 test indices", train, test)
+	}
+	if test[0] >= 4 || test[1] < 4 {
+		t.Fatalf("test = %v; want one index of stratum a and two of stratum b", test)
+	}
+	values := vector.New(10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21)
+	taken, _ := Take(values, test)
+	if taken[0] != 10+test[0] || taken[2] != 10+test[2] {
+		t.Fatalf("Take = %v; want the values at %v", taken, test)
+	}
+	if _, err := Take(values, []int{12}); err == nil {
+		t.Fatalf("Take with an index out of range should return error")
+	}
+}
@@ -0,0 +1,216 @@
+// Package seq provides lazy generators for common mathematical sequences
+// (arithmetic and geometric progressions, primes, Fibonacci numbers, figurate
+// numbers) as iter.Seq values, plus combinators (Take, TakeWhile, Filter, Map,
+// Sum, Collect) to consume them.
+//
+// Important details:
+//
+// (*) Most generators are infinite: always limit them with Take or TakeWhile
+// (or break out of the range loop) before calling Sum or Collect.
+//
+// (*) Integer generators use int and will silently overflow for large terms
+// (e.g. Fibonacci beyond the 92nd term on 64-bit platforms).
+package seq
+
+import (
+	"iter"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// ============================================================================
+// Generators
+// ============================================================================
+
+// Arithmetic returns the infinite arithmetic progression start, start+step,
+// start+2*step, ...
+func Arithmetic[T wbmath.Number](start, step T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := start; ; value += step {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Geometric returns the infinite geometric progression start, start*ratio,
+// start*ratio^2, ...
+func Geometric[T wbmath.Number](start, ratio T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := start; ; value *= ratio {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Naturals returns the infinite sequence of natural numbers 1, 2, 3, ...
+func Naturals() iter.Seq[int] {
+	return Arithmetic(1, 1)
+}
+
+// Fibonacci returns the infinite Fibonacci sequence 0, 1, 1, 2, 3, 5, ...
+func Fibonacci() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for a, b := 0, 1; ; a, b = b, a+b {
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// Triangular returns the infinite sequence of triangular numbers n(n+1)/2:
+// 1, 3, 6, 10, ...
+func Triangular() iter.Seq[int] {
+	return Map(Naturals(), func(n int) int { return n * (n + 1) / 2 })
+}
+
+// Squares returns the infinite sequence of square numbers 1, 4, 9, 16, ...
+func Squares() iter.Seq[int] {
+	return Map(Naturals(), func(n int) int { return n * n })
+}
+
+// Factorials returns the infinite sequence of factorials 1, 1, 2, 6, 24, ...
+// starting at 0!.
+func Factorials() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for n, value := 1, 1; ; n++ {
+			if !yield(value) {
+				return
+			}
+			value *= n
+		}
+	}
+}
+
+// Primes returns the infinite sequence of prime numbers 2, 3, 5, 7, ...
+// It uses an incremental sieve: every composite number is crossed off by
+// its smallest prime factor, so memory grows with the number of primes
+// generated so far rather than with a fixed upper bound.
+func Primes() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if !yield(2) {
+			return
+		}
+		// composites maps the next odd composite to the step (2*p) at which
+		// the prime p that generated it crosses off further multiples.
+		composites := make(map[int]int)
+		for n := 3; ; n += 2 {
+			step, isComposite := composites[n]
+			if !isComposite {
+				if !yield(n) {
+					return
+				}
+				composites[n*n] = 2 * n
+				continue
+			}
+			delete(composites, n)
+			next := n + step
+			for composites[next] != 0 {
+				next += step
+			}
+			composites[next] = step
+		}
+	}
+}
+
+// ============================================================================
+// Combinators
+// ============================================================================
+
+// Take returns a sequence with at most the first n values of seq.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		count := 0
+		for value := range seq {
+			if !yield(value) {
+				return
+			}
+			count++
+			if count >= n {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns a sequence with the values of seq up to (but not
+// including) the first value for which `predicate` returns false.
+func TakeWhile[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range seq {
+			if !predicate(value) || !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns a sequence without the first n values of seq.
+func Skip[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		count := 0
+		for value := range seq {
+			if count < n {
+				count++
+				continue
+			}
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns a sequence with only the values of seq for which
+// `predicate` returns true.
+func Filter[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range seq {
+			if predicate(value) && !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Map returns a sequence with `transform` applied to every value of seq.
+func Map[T, U any](seq iter.Seq[T], transform func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for value := range seq {
+			if !yield(transform(value)) {
+				return
+			}
+		}
+	}
+}
+
+// Sum returns the sum of all values of a (finite) sequence.
+func Sum[T wbmath.Number](seq iter.Seq[T]) T {
+	var sum T
+	for value := range seq {
+		sum += value
+	}
+	return sum
+}
+
+// Collect returns all values of a (finite) sequence as a slice.
+func Collect[T any](seq iter.Seq[T]) []T {
+	var values []T
+	for value := range seq {
+		values = append(values, value)
+	}
+	return values
+}
+
+// CollectVector returns all values of a (finite) sequence as a Vector.
+func CollectVector[T wbmath.SignedNumber](seq iter.Seq[T]) vector.Vector[T] {
+	return vector.New(Collect(seq)...)
+}
@@ -0,0 +1,50 @@
+package seq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerators(t *testing.T) {
+	cases := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Arithmetic", Collect(Take(Arithmetic(3, 4), 4)), []int{3, 7, 11, 15}},
+		{"Geometric", Collect(Take(Geometric(1, 3), 4)), []int{1, 3, 9, 27}},
+		{"Fibonacci", Collect(Take(Fibonacci(), 8)), []int{0, 1, 1, 2, 3, 5, 8, 13}},
+		{"Triangular", Collect(Take(Triangular(), 5)), []int{1, 3, 6, 10, 15}},
+		{"Squares", Collect(Take(Squares(), 4)), []int{1, 4, 9, 16}},
+		{"Factorials", Collect(Take(Factorials(), 6)), []int{1, 1, 2, 6, 24, 120}},
+		{"Primes", Collect(Take(Primes(), 10)), []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+	}
+	for _, c := range cases {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Fatalf("%s = %v; want %v", c.name, c.got, c.want)
+		}
+	}
+	// The 1000th prime is 7919
+	if got := Collect(Skip(Take(Primes(), 1000), 999)); len(got) != 1 || got[0] != 7919 {
+		t.Fatalf("1000th prime = %v; want 7919", got)
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	// Project Euler 2: sum of the even Fibonacci numbers below 4 million
+	evenFib := Filter(TakeWhile(Fibonacci(), func(n int) bool { return n < 4000000 }),
+		func(n int) bool { return n%2 == 0 })
+	if got := Sum(evenFib); got != 4613732 {
+		t.Fatalf("Sum(even Fibonacci < 4e6) = %d; want 4613732", got)
+	}
+	halves := Map(Take(Naturals(), 3), func(n int) float64 { return float64(n) / 2 })
+	if got := Collect(halves); !reflect.DeepEqual(got, []float64{0.5, 1, 1.5}) {
+		t.Fatalf("Map = %v; want [0.5 1 1.5]", got)
+	}
+	if got := CollectVector(Take(Geometric(2.0, 0.5), 3)); got.Sum() != 3.5 {
+		t.Fatalf("CollectVector sum = %v; want 3.5", got.Sum())
+	}
+	if got := Collect(Take(Naturals(), 0)); len(got) != 0 {
+		t.Fatalf("Take(0) = %v; want empty", got)
+	}
+}
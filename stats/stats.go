@@ -0,0 +1,251 @@
+// Package stats provides multivariate descriptive statistics over a
+// collection of Vectors (one Vector per variable) or over the columns of a
+// Matrix: column means and standard deviations and the full covariance,
+// Pearson correlation and Spearman rank correlation matrices, computed in one
+// call instead of pairing up the variables by hand.
+//
+// Important details:
+//
+// (*) All variables must have the same number of observations, at least two.
+// Covariances and standard deviations use the sample (n - 1) denominator.
+//
+// (*) The correlation of a variable with zero variance is undefined (NaN),
+// except on the diagonal, which is always 1.
+//
+// (*) Spearman correlations rank the observations first, giving tied
+// observations the average of their ranks.
+//
+// (*) The input Vectors and Matrix are never modified.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/matrix"
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Summary holds the descriptive statistics of a collection of variables.
+// Element (i, j) of a matrix relates variable i and variable j.
+type Summary struct {
+	// Observations is the number of observations per variable.
+	Observations int
+	// Means holds the mean of every variable.
+	Means vector.Vector[float64]
+	// StdDevs holds the sample standard deviation of every variable.
+	StdDevs vector.Vector[float64]
+	// Covariance is the sample covariance matrix.
+	Covariance *matrix.Matrix[float64]
+	// Correlation is the Pearson correlation matrix.
+	Correlation *matrix.Matrix[float64]
+	// Spearman is the Spearman rank correlation matrix.
+	Spearman *matrix.Matrix[float64]
+}
+
+// ============================================================================
+// Summaries
+// ============================================================================
+
+// Describe returns the Summary of the specified variables. Returns an error
+// if fewer than one variable or two observations are specified, or if the
+// variables have different lengths.
+func Describe[T wbmath.SignedNumber](variables ...vector.Vector[T]) (*Summary, error) {
+	columns, err := toColumns(variables)
+	if err != nil {
+		return nil, err
+	}
+	means, covariance := covariances(columns)
+	ranks := make([]vector.Vector[float64], len(columns))
+	for i, column := range columns {
+		ranks[i] = rank(column)
+	}
+	_, rankCovariance := covariances(ranks)
+	stdDevs := vector.NewFromValue(0.0, len(columns))
+	for i := range stdDevs {
+		stdDevs[i] = math.Sqrt(covariance[i][i])
+	}
+	return &Summary{
+		Observations: len(columns[0]),
+		Means:        means,
+		StdDevs:      stdDevs,
+		Covariance:   mustMatrix(covariance),
+		Correlation:  mustMatrix(correlations(covariance)),
+		Spearman:     mustMatrix(correlations(rankCovariance)),
+	}, nil
+}
+
+// DescribeMatrix returns the Summary of the columns of m: every column is a
+// variable and every row an observation. Returns an error if m has no
+// columns or fewer than two rows.
+func DescribeMatrix[T wbmath.SignedNumber](m *matrix.Matrix[T]) (*Summary, error) {
+	return Describe(columnsOf(m)...)
+}
+
+// ============================================================================
+// Individual statistics
+// ============================================================================
+
+// Means returns the mean of every variable. Returns an error under the same
+// conditions as Describe.
+func Means[T wbmath.SignedNumber](variables ...vector.Vector[T]) (vector.Vector[float64], error) {
+	columns, err := toColumns(variables)
+	if err != nil {
+		return nil, err
+	}
+	means := vector.NewFromValue(0.0, len(columns))
+	for i, column := range columns {
+		means[i] = mean(column)
+	}
+	return means, nil
+}
+
+// StdDevs returns the sample standard deviation of every variable. Returns
+// an error under the same conditions as Describe.
+func StdDevs[T wbmath.SignedNumber](variables ...vector.Vector[T]) (vector.Vector[float64], error) {
+	columns, err := toColumns(variables)
+	if err != nil {
+		return nil, err
+	}
+	_, covariance := covariances(columns)
+	stdDevs := vector.NewFromValue(0.0, len(columns))
+	for i := range stdDevs {
+		stdDevs[i] = math.Sqrt(covariance[i][i])
+	}
+	return stdDevs, nil
+}
+
+// Covariance returns the sample covariance matrix of the variables. Returns
+// an error under the same conditions as Describe.
+func Covariance[T wbmath.SignedNumber](variables ...vector.Vector[T]) (*matrix.Matrix[float64], error) {
+	columns, err := toColumns(variables)
+	if err != nil {
+		return nil, err
+	}
+	_, covariance := covariances(columns)
+	return mustMatrix(covariance), nil
+}
+
+// Correlation returns the Pearson correlation matrix of the variables.
+// Returns an error under the same conditions as Describe.
+func Correlation[T wbmath.SignedNumber](variables ...vector.Vector[T]) (*matrix.Matrix[float64], error) {
+	columns, err := toColumns(variables)
+	if err != nil {
+		return nil, err
+	}
+	_, covariance := covariances(columns)
+	return mustMatrix(correlations(covariance)), nil
+}
+
+// Spearman returns the Spearman rank correlation matrix of the variables:
+// the Pearson correlations of their ranks. Returns an error under the same
+// conditions as Describe.
+func Spearman[T wbmath.SignedNumber](variables ...vector.Vector[T]) (*matrix.Matrix[float64], error) {
+	columns, err := toColumns(variables)
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range columns {
+		columns[i] = rank(column)
+	}
+	_, covariance := covariances(columns)
+	return mustMatrix(correlations(covariance)), nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// toColumns validates the variables and converts them to float64.
+func toColumns[T wbmath.SignedNumber](variables []vector.Vector[T]) ([]vector.Vector[float64], error) {
+	if len(variables) == 0 {
+		return nil, errors.New("no variables specified")
+	}
+	n := len(variables[0])
+	if n < 2 {
+		return nil, errors.New("at least two observations are required")
+	}
+	columns := make([]vector.Vector[float64], len(variables))
+	for i, variable := range variables {
+		if len(variable) != n {
+			return nil, fmt.Errorf("variable %d has %d observations; want %d", i, len(variable), n)
+		}
+		columns[i] = variable.CloneAsFloat64()
+	}
+	return columns, nil
+}
+
+// columnsOf returns copies of the columns of m.
+func columnsOf[T wbmath.SignedNumber](m *matrix.Matrix[T]) []vector.Vector[T] {
+	_, cols := m.Dims()
+	columns := make([]vector.Vector[T], cols)
+	for j := range columns {
+		columns[j], _ = m.Col(j)
+	}
+	return columns
+}
+
+func mean(column vector.Vector[float64]) float64 {
+	return column.Sum() / float64(len(column))
+}
+
+// covariances returns the means and the sample covariance matrix of the
+// columns, computed from the centered data.
+func covariances(columns []vector.Vector[float64]) (vector.Vector[float64], [][]float64) {
+	means := vector.NewFromValue(0.0, len(columns))
+	centered := make([]vector.Vector[float64], len(columns))
+	for i, column := range columns {
+		means[i] = mean(column)
+		centered[i] = column.Clone().Map(func(x float64) float64 { return x - means[i] })
+	}
+	denominator := float64(len(columns[0]) - 1)
+	covariance := make([][]float64, len(columns))
+	for i := range covariance {
+		covariance[i] = make([]float64, len(columns))
+	}
+	for i := range centered {
+		for j := i; j < len(centered); j++ {
+			dot, _ := centered[i].DotProduct(centered[j])
+			covariance[i][j] = dot / denominator
+			covariance[j][i] = covariance[i][j]
+		}
+	}
+	return means, covariance
+}
+
+// correlations normalizes a covariance matrix to a correlation matrix.
+func correlations(covariance [][]float64) [][]float64 {
+	correlation := make([][]float64, len(covariance))
+	for i := range correlation {
+		correlation[i] = make([]float64, len(covariance))
+		for j := range correlation[i] {
+			switch {
+			case i == j:
+				correlation[i][j] = 1
+			case covariance[i][i] == 0 || covariance[j][j] == 0:
+				correlation[i][j] = math.NaN()
+			default:
+				r := covariance[i][j] / math.Sqrt(covariance[i][i]*covariance[j][j])
+				// Clamp rounding errors, e.g. 1.0000000000000002.
+				correlation[i][j] = max(-1, min(1, r))
+			}
+		}
+	}
+	return correlation
+}
+
+// rank returns the 1-based ranks of the observations, averaging the ranks of
+// ties: [10 30 20 20] gives [1 4 2.5 2.5].
+func rank(column vector.Vector[float64]) vector.Vector[float64] {
+	return column.Rank()
+}
+
+func mustMatrix(rows [][]float64) *matrix.Matrix[float64] {
+	m, err := matrix.NewFromRows(rows...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/matrix"
+	"github.com/bogersw/wbmath/vector"
+)
+
+func TestDescribe(t *testing.T) {
+	x := vector.New(1, 2, 3, 4, 5)
+	y := vector.New(2, 4, 6, 8, 10)
+	z := vector.New(5, 3, 4, 1, 2)
+	s, err := Describe(x, y, z)
+	if err != nil {
+		t.Fatalf("Describe returned error: %v", err)
+	}
+	if s.Observations != 5 || s.Means[0] != 3 || s.Means[1] != 6 || s.Means[2] != 3 {
+		t.Fatalf("Observations, Means = %d, %v; want 5, [3 6 3]", s.Observations, s.Means)
+	}
+	if got := s.StdDevs[0]; math.Abs(got-math.Sqrt(2.5)) > 1e-12 {
+		t.Fatalf("StdDevs[0] = %v; want %v", got, math.Sqrt(2.5))
+	}
+	cases := []struct {
+		name string
+		m    *matrix.Matrix[float64]
+		i, j int
+		want float64
+	}{
+		{"Covariance", s.Covariance, 0, 0, 2.5},
+		{"Covariance", s.Covariance, 0, 1, 5},
+		{"Covariance", s.Covariance, 2, 0, -2},
+		{"Correlation", s.Correlation, 0, 1, 1},
+		{"Correlation", s.Correlation, 0, 2, -0.8},
+		{"Spearman", s.Spearman, 1, 2, -0.8},
+		{"Spearman", s.Spearman, 2, 2, 1},
+	}
+	for _, c := range cases {
+		got, _ := c.m.At(c.i, c.j)
+		if math.Abs(got-c.want) > 1e-12 {
+			t.Fatalf("%s(%d, %d) = %v; want %v", c.name, c.i, c.j, got, c.want)
+		}
+	}
+	if _, err := Describe(x, vector.New(1, 2)); err == nil {
+		t.Fatalf("Describe with different lengths should return error")
+	}
+	if _, err := Describe(vector.New(1)); err == nil {
+		t.Fatalf("Describe with one observation should return error")
+	}
+}
+
+func TestSpearmanTiesAndConstant(t *testing.T) {
+	if got := rank(vector.New(10.0, 30, 20, 20)); got[0] != 1 || got[1] != 4 || got[2] != 2.5 || got[3] != 2.5 {
+		t.Fatalf("rank = %v; want [1 4 2.5 2.5]", got)
+	}
+	// Spearman only sees the order: a monotone transform has correlation 1.
+	x := vector.New(1.0, 2, 3, 4)
+	spearman, _ := Spearman(x, vector.New(1.0, 8, 27, 64), vector.New(7.0, 7, 7, 7))
+	if got, _ := spearman.At(0, 1); got != 1 {
+		t.Fatalf("Spearman(x, x³) = %v; want 1", got)
+	}
+	if got, _ := spearman.At(0, 2); !math.IsNaN(got) {
+		t.Fatalf("Spearman with a constant variable = %v; want NaN", got)
+	}
+}
+
+func TestDescribeMatrix(t *testing.T) {
+	m, _ := matrix.NewFromRows([]int{1, 5}, []int{2, 3}, []int{3, 4}, []int{4, 1}, []int{5, 2})
+	s, err := DescribeMatrix(m)
+	if err != nil {
+		t.Fatalf("DescribeMatrix returned error: %v", err)
+	}
+	if got, _ := s.Correlation.At(1, 0); math.Abs(got+0.8) > 1e-12 {
+		t.Fatalf("Correlation(1, 0) = %v; want -0.8", got)
+	}
+	means, _ := Means(vector.New(1, 2), vector.New(3, 5))
+	stdDevs, _ := StdDevs(vector.New(1, 3))
+	if means[1] != 4 || stdDevs[0] != math.Sqrt(2) {
+		t.Fatalf("Means, StdDevs = %v, %v; want [1.5 4], [%v]", means, stdDevs, math.Sqrt(2))
+	}
+}
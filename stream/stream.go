@@ -0,0 +1,273 @@
+// Package stream provides online (single-pass) statistics for data that is
+// too large to hold in a Vector, or that arrives one value at a time.
+//
+// Accumulator keeps the count, mean, variance (Welford's algorithm), minimum
+// and maximum in constant memory. Accumulators can be merged, so a data set
+// can be split into shards that are processed in parallel and combined
+// afterwards. Quantile estimates a single quantile (e.g. the median or the
+// 99th percentile) with the P² algorithm of Jain and Chlamtac, using five
+// markers instead of storing the observations.
+//
+// Important details:
+//
+// (*) Statistics that are undefined for the observations seen so far (e.g.
+// the mean of zero observations or the sample variance of one) are NaN.
+//
+// (*) Add operates in-place and returns the receiver to allow chaining. An
+// Accumulator or Quantile is not safe for concurrent use: use one per
+// goroutine and Merge the accumulators afterwards.
+//
+// (*) P² sketches cannot be merged; the estimate is exact for fewer than
+// five observations.
+package stream
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// ============================================================================
+// Accumulator
+// ============================================================================
+
+// Accumulator computes the count, mean, variance, minimum and maximum of a
+// stream of values. The zero value is an empty Accumulator ready to use.
+type Accumulator struct {
+	count    int
+	mean     float64
+	m2       float64 // sum of squared deviations from the mean
+	min, max float64
+}
+
+// New is a constructor function that returns an empty Accumulator.
+func New() *Accumulator {
+	return &Accumulator{}
+}
+
+// Add adds the values to the Accumulator using Welford's update, which
+// avoids the cancellation of the naive sum-of-squares formula.
+func (a *Accumulator) Add(values ...float64) *Accumulator {
+	for _, x := range values {
+		if a.count == 0 {
+			a.min, a.max = x, x
+		}
+		a.count++
+		delta := x - a.mean
+		a.mean += delta / float64(a.count)
+		a.m2 += delta * (x - a.mean)
+		a.min = math.Min(a.min, x)
+		a.max = math.Max(a.max, x)
+	}
+	return a
+}
+
+// Merge combines the state of other into a, as if all values added to other
+// had been added to a (Chan's parallel algorithm). other is not modified.
+func (a *Accumulator) Merge(other *Accumulator) *Accumulator {
+	if other.count == 0 {
+		return a
+	}
+	if a.count == 0 {
+		*a = *other
+		return a
+	}
+	n := float64(a.count + other.count)
+	delta := other.mean - a.mean
+	a.mean += delta * float64(other.count) / n
+	a.m2 += other.m2 + delta*delta*float64(a.count)*float64(other.count)/n
+	a.count += other.count
+	a.min = math.Min(a.min, other.min)
+	a.max = math.Max(a.max, other.max)
+	return a
+}
+
+// Reset empties the Accumulator.
+func (a *Accumulator) Reset() *Accumulator {
+	*a = Accumulator{}
+	return a
+}
+
+// Count returns the number of values added.
+func (a *Accumulator) Count() int {
+	return a.count
+}
+
+// Mean returns the arithmetic mean, or NaN if no values were added.
+func (a *Accumulator) Mean() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.mean
+}
+
+// Variance returns the sample variance (with n - 1 in the denominator), or
+// NaN if fewer than two values were added.
+func (a *Accumulator) Variance() float64 {
+	if a.count < 2 {
+		return math.NaN()
+	}
+	return a.m2 / float64(a.count-1)
+}
+
+// PopulationVariance returns the population variance (with n in the
+// denominator), or NaN if no values were added.
+func (a *Accumulator) PopulationVariance() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.m2 / float64(a.count)
+}
+
+// StdDev returns the sample standard deviation, or NaN if fewer than two
+// values were added.
+func (a *Accumulator) StdDev() float64 {
+	return math.Sqrt(a.Variance())
+}
+
+// Min returns the smallest value, or NaN if no values were added.
+func (a *Accumulator) Min() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.min
+}
+
+// Max returns the largest value, or NaN if no values were added.
+func (a *Accumulator) Max() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.max
+}
+
+// ============================================================================
+// P² quantile sketch
+// ============================================================================
+
+// Quantile estimates the p-quantile of a stream of values with the P²
+// algorithm in constant memory.
+type Quantile struct {
+	p         float64
+	count     int
+	heights   [5]float64 // marker heights
+	positions [5]float64 // actual marker positions (1-based)
+	desired   [5]float64 // desired marker positions
+	increment [5]float64 // increments of the desired positions
+}
+
+// NewQuantile is a constructor function that returns an empty sketch for the
+// p-quantile, e.g. 0.5 for the median. Returns an error if p is not in
+// (0, 1).
+func NewQuantile(p float64) (*Quantile, error) {
+	if !(p > 0 && p < 1) {
+		return nil, errors.New("p must be in (0, 1)")
+	}
+	return &Quantile{
+		p:         p,
+		positions: [5]float64{1, 2, 3, 4, 5},
+		desired:   [5]float64{1, 1 + 2*p, 1 + 4*p, 3 + 2*p, 5},
+		increment: [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}, nil
+}
+
+// Add adds the values to the sketch.
+func (q *Quantile) Add(values ...float64) *Quantile {
+	for _, x := range values {
+		q.add(x)
+	}
+	return q
+}
+
+// P returns the quantile that is estimated.
+func (q *Quantile) P() float64 {
+	return q.p
+}
+
+// Count returns the number of values added.
+func (q *Quantile) Count() int {
+	return q.count
+}
+
+// Value returns the estimate of the p-quantile, or NaN if no values were
+// added. For fewer than five values the exact quantile is returned
+// (interpolating linearly between the order statistics).
+func (q *Quantile) Value() float64 {
+	if q.count == 0 {
+		return math.NaN()
+	}
+	if q.count < 5 {
+		sorted := slices.Clone(q.heights[:q.count])
+		slices.Sort(sorted)
+		position := q.p * float64(q.count-1)
+		i := int(position)
+		if i == q.count-1 {
+			return sorted[i]
+		}
+		return sorted[i] + (position-float64(i))*(sorted[i+1]-sorted[i])
+	}
+	return q.heights[2]
+}
+
+func (q *Quantile) add(x float64) {
+	if q.count < 5 {
+		q.heights[q.count] = x
+		q.count++
+		if q.count == 5 {
+			slices.Sort(q.heights[:])
+		}
+		return
+	}
+	q.count++
+
+	// Find the cell k with heights[k] <= x < heights[k+1], extending the
+	// extreme markers if necessary.
+	var k int
+	switch {
+	case x < q.heights[0]:
+		q.heights[0] = x
+		k = 0
+	case x >= q.heights[4]:
+		q.heights[4] = x
+		k = 3
+	default:
+		for k = 0; x >= q.heights[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		q.positions[i]++
+	}
+	for i := range q.desired {
+		q.desired[i] += q.increment[i]
+	}
+
+	// Adjust the heights of the middle markers if they are off by one or
+	// more positions.
+	for i := 1; i <= 3; i++ {
+		d := q.desired[i] - q.positions[i]
+		if (d >= 1 && q.positions[i+1]-q.positions[i] > 1) || (d <= -1 && q.positions[i-1]-q.positions[i] < -1) {
+			sign := math.Copysign(1, d)
+			height := q.parabolic(i, sign)
+			if !(q.heights[i-1] < height && height < q.heights[i+1]) {
+				height = q.linear(i, sign)
+			}
+			q.heights[i] = height
+			q.positions[i] += sign
+		}
+	}
+}
+
+// parabolic returns the piecewise-parabolic (P²) prediction of the height of
+// marker i after moving it by d (±1) positions.
+func (q *Quantile) parabolic(i int, d float64) float64 {
+	n, h := q.positions, q.heights
+	return h[i] + d/(n[i+1]-n[i-1])*((n[i]-n[i-1]+d)*(h[i+1]-h[i])/(n[i+1]-n[i])+
+		(n[i+1]-n[i]-d)*(h[i]-h[i-1])/(n[i]-n[i-1]))
+}
+
+// linear returns the linear prediction of the height of marker i after
+// moving it by d (±1) positions.
+func (q *Quantile) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return q.heights[i] + d*(q.heights[j]-q.heights[i])/(q.positions[j]-q.positions[i])
+}
@@ -0,0 +1,75 @@
+package stream
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAccumulator(t *testing.T) {
+	a := New().Add(2, 4, 4, 4, 5, 5, 7, 9)
+	if a.Count() != 8 || a.Mean() != 5 || a.PopulationVariance() != 4 {
+		t.Fatalf("count, mean, variance = %d, %v, %v; want 8, 5, 4", a.Count(), a.Mean(), a.PopulationVariance())
+	}
+	if got := a.Variance(); math.Abs(got-32.0/7) > 1e-12 {
+		t.Fatalf("Variance() = %v; want %v", got, 32.0/7)
+	}
+	if a.Min() != 2 || a.Max() != 9 {
+		t.Fatalf("Min(), Max() = %v, %v; want 2, 9", a.Min(), a.Max())
+	}
+	if empty := New(); !math.IsNaN(empty.Mean()) || !math.IsNaN(empty.Min()) || !math.IsNaN(New().Add(1).Variance()) {
+		t.Fatalf("statistics of too few values should be NaN")
+	}
+	// Welford's update doesn't suffer from cancellation with a large offset.
+	shifted := New().Add(1e9+4, 1e9+7, 1e9+13, 1e9+16)
+	if got := shifted.Variance(); got != 30 {
+		t.Fatalf("Variance() with offset = %v; want 30", got)
+	}
+}
+
+func TestAccumulatorMerge(t *testing.T) {
+	whole := New()
+	shards := []*Accumulator{New(), New(), New()}
+	for i := 0; i < 1000; i++ {
+		x := math.Sin(float64(i)) * float64(i%17)
+		whole.Add(x)
+		shards[i%3].Add(x)
+	}
+	merged := New().Merge(shards[0]).Merge(shards[1]).Merge(shards[2]).Merge(New())
+	if merged.Count() != whole.Count() || merged.Min() != whole.Min() || merged.Max() != whole.Max() {
+		t.Fatalf("Merge = %d [%v, %v]; want %d [%v, %v]",
+			merged.Count(), merged.Min(), merged.Max(), whole.Count(), whole.Min(), whole.Max())
+	}
+	if math.Abs(merged.Mean()-whole.Mean()) > 1e-12 || math.Abs(merged.Variance()-whole.Variance()) > 1e-9 {
+		t.Fatalf("Merge mean, variance = %v, %v; want %v, %v", merged.Mean(), merged.Variance(), whole.Mean(), whole.Variance())
+	}
+	if merged.Reset().Count() != 0 {
+		t.Fatalf("Reset() should empty the accumulator")
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	if _, err := NewQuantile(1); err == nil {
+		t.Fatalf("NewQuantile(1) should return error")
+	}
+	median, _ := NewQuantile(0.5)
+	if !math.IsNaN(median.Value()) {
+		t.Fatalf("Value() of an empty sketch should be NaN")
+	}
+	if got := median.Add(4, 1, 3).Value(); got != 3 {
+		t.Fatalf("Value() of 3 values = %v; want 3", got)
+	}
+	// A permutation of 0..9999: the exact quantiles are known.
+	cases := []float64{0.1, 0.5, 0.9, 0.99}
+	for _, p := range cases {
+		q, _ := NewQuantile(p)
+		for i := 0; i < 10000; i++ {
+			q.Add(float64(i * 7919 % 10000))
+		}
+		if got, want := q.Value(), p*9999; math.Abs(got-want) > 100 {
+			t.Fatalf("Quantile(%v) = %v; want about %v", p, got, want)
+		}
+		if q.Count() != 10000 || q.P() != p {
+			t.Fatalf("Count(), P() = %d, %v; want 10000, %v", q.Count(), q.P(), p)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Package surd provides exact arithmetic on quadratic surds: numbers of the
+// form a + b·√n with rational a and b and a square-free integer n > 1.
+//
+// Where Fraction.NthRoot returns an error for square roots that aren't
+// rational, Sqrt returns a Surd: √(8/3) becomes 2/3·√6, which can be carried
+// through further calculations exactly.
+//
+// Important details:
+//
+// (*) A Surd is an immutable value type: methods return a new Surd.
+//
+// (*) Surds are always kept in canonical form: n is square-free, and a
+// rational Surd has b = 0 and n = 1.
+//
+// (*) Sums and products of surds with different radicands (like √2 + √3)
+// cannot be represented and return an error.
+package surd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/primes"
+)
+
+// Surd represents the number a + b·√n.
+type Surd struct {
+	a, b *fraction.Fraction
+	n    int
+}
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// New is a constructor function that returns the Surd a + b·√n in canonical
+// form, e.g. New(1, 2, 12) is 1 + 4·√3. Returns an error if a or b is nil or
+// if n is negative.
+func New(a, b *fraction.Fraction, n int) (Surd, error) {
+	if a == nil || b == nil {
+		return Surd{}, fraction.ErrNilFraction
+	}
+	if n < 0 {
+		return Surd{}, errors.New("the square root of a negative number is not real")
+	}
+	square, free := splitSquare(n)
+	return canonical(a.Clone(), b.Clone().MultiplyInt(square), free), nil
+}
+
+// MustNew is a constructor identical to New but which panics if an error
+// occurs.
+func MustNew(a, b *fraction.Fraction, n int) Surd {
+	s, err := New(a, b, n)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewFromFraction is a constructor function that returns the rational number
+// f as a Surd. Returns an error if f is nil.
+func NewFromFraction(f *fraction.Fraction) (Surd, error) {
+	return New(f, fraction.MustNew(0, 1), 1)
+}
+
+// Sqrt is a constructor function that returns the square root of f, using
+// √(p/q) = √(p·q)/q and extracting square factors: Sqrt(8/3) = 2/3·√6.
+// Returns an error if f is nil or negative.
+func Sqrt(f *fraction.Fraction) (Surd, error) {
+	numerator, ok := f.Numerator()
+	if !ok {
+		return Surd{}, fraction.ErrNilFraction
+	}
+	denominator, _ := f.Denominator()
+	if numerator < 0 {
+		return Surd{}, errors.New("the square root of a negative number is not real")
+	}
+	// Extract squares from numerator and denominator separately to keep the
+	// intermediate product small.
+	pSquare, pFree := splitSquare(numerator)
+	qSquare, qFree := splitSquare(denominator)
+	square, free := splitSquare(pFree * qFree)
+	b := fraction.MustNew(pSquare*square, qSquare*qFree).Simplify()
+	return canonical(fraction.MustNew(0, 1), b, free), nil
+}
+
+// ============================================================================
+// Accessors
+// ============================================================================
+
+// A returns a copy of the rational part a.
+func (s Surd) A() *fraction.Fraction { return s.a.Clone() }
+
+// B returns a copy of the coefficient b of the root.
+func (s Surd) B() *fraction.Fraction { return s.b.Clone() }
+
+// N returns the square-free radicand n (1 for rational values).
+func (s Surd) N() int { return s.n }
+
+// IsRational checks if the Surd is a rational number (b = 0).
+func (s Surd) IsRational() bool { return isZero(s.b) }
+
+// Equals checks if two surds are equal.
+func (s Surd) Equals(other Surd) bool {
+	return s.n == other.n && equal(s.a, other.a) && equal(s.b, other.b)
+}
+
+// Float64 returns the value of the Surd as a float64.
+func (s Surd) Float64() float64 {
+	return s.a.Evaluate() + s.b.Evaluate()*math.Sqrt(float64(s.n))
+}
+
+// String implements the fmt.Stringer interface, e.g. "1/2 + 3√2", "-√5" or
+// "2/3√6".
+func (s Surd) String() string {
+	if s.a == nil || s.b == nil {
+		return "NaN"
+	}
+	if s.IsRational() {
+		return s.a.String()
+	}
+	root := fmt.Sprintf("√%d", s.n)
+	b := s.b.Clone()
+	sign := "+"
+	if numerator, _ := b.Numerator(); numerator < 0 {
+		sign = "-"
+		b.MultiplyInt(-1)
+	}
+	if numerator, _ := b.Numerator(); numerator != 1 || !isInteger(b) {
+		root = b.String() + root
+	}
+	switch {
+	case isZero(s.a) && sign == "-":
+		return "-" + root
+	case isZero(s.a):
+		return root
+	}
+	return fmt.Sprintf("%s %s %s", s.a, sign, root)
+}
+
+// ============================================================================
+// Arithmetic
+// ============================================================================
+
+// Add returns s + other. Returns an error if both are irrational with
+// different radicands.
+func (s Surd) Add(other Surd) (Surd, error) {
+	n, err := commonRadicand(s, other)
+	if err != nil {
+		return Surd{}, err
+	}
+	return canonical(s.a.Clone().Add(other.a), s.b.Clone().Add(other.b), n), nil
+}
+
+// Subtract returns s - other. Returns an error if both are irrational with
+// different radicands.
+func (s Surd) Subtract(other Surd) (Surd, error) {
+	return s.Add(other.Negate())
+}
+
+// Multiply returns s · other, using
+// (a + b√n)(c + d√n) = (ac + bdn) + (ad + bc)√n. Returns an error if both
+// are irrational with different radicands.
+func (s Surd) Multiply(other Surd) (Surd, error) {
+	n, err := commonRadicand(s, other)
+	if err != nil {
+		return Surd{}, err
+	}
+	a := s.a.Clone().Multiply(other.a).Add(s.b.Clone().Multiply(other.b).MultiplyInt(n))
+	b := s.a.Clone().Multiply(other.b).Add(s.b.Clone().Multiply(other.a))
+	return canonical(a, b, n), nil
+}
+
+// Divide returns s / other. Returns an error if other is zero or if both are
+// irrational with different radicands.
+func (s Surd) Divide(other Surd) (Surd, error) {
+	inverse, err := other.Inverse()
+	if err != nil {
+		return Surd{}, err
+	}
+	return s.Multiply(inverse)
+}
+
+// Scale returns f · s. Returns an error if f is nil.
+func (s Surd) Scale(f *fraction.Fraction) (Surd, error) {
+	if f == nil {
+		return Surd{}, fraction.ErrNilFraction
+	}
+	return canonical(s.a.Clone().Multiply(f), s.b.Clone().Multiply(f), s.n), nil
+}
+
+// Negate returns -s.
+func (s Surd) Negate() Surd {
+	return canonical(s.a.Clone().MultiplyInt(-1), s.b.Clone().MultiplyInt(-1), s.n)
+}
+
+// Conjugate returns a - b·√n.
+func (s Surd) Conjugate() Surd {
+	return canonical(s.a.Clone(), s.b.Clone().MultiplyInt(-1), s.n)
+}
+
+// Norm returns s · Conjugate() = a² - n·b², which is always rational.
+func (s Surd) Norm() *fraction.Fraction {
+	return s.a.Clone().Multiply(s.a).Subtract(s.b.Clone().Multiply(s.b).MultiplyInt(s.n)).Simplify()
+}
+
+// Inverse returns 1 / s = Conjugate() / Norm(). Returns an error if s is
+// zero.
+func (s Surd) Inverse() (Surd, error) {
+	norm := s.Norm()
+	if isZero(norm) {
+		return Surd{}, errors.New("division by zero")
+	}
+	inverse := fraction.MustNew(1, 1).Divide(norm)
+	return s.Conjugate().Scale(inverse)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// splitSquare writes n = square² · free with free square-free.
+func splitSquare(n int) (int, int) {
+	if n < 2 {
+		return 1, n
+	}
+	factors, _ := primes.Factorize(n)
+	square, free := 1, 1
+	for _, factor := range factors {
+		for i := 0; i < factor.Exponent/2; i++ {
+			square *= factor.Prime
+		}
+		if factor.Exponent%2 == 1 {
+			free *= factor.Prime
+		}
+	}
+	return square, free
+}
+
+// canonical builds a Surd from a simplified radicand: rational values get
+// b = 0 and n = 1.
+func canonical(a, b *fraction.Fraction, n int) Surd {
+	a.Simplify()
+	b.Simplify()
+	switch {
+	case n == 0:
+		b = fraction.MustNew(0, 1)
+		n = 1
+	case n == 1:
+		a.Add(b).Simplify()
+		b = fraction.MustNew(0, 1)
+	case isZero(b):
+		n = 1
+	}
+	return Surd{a: a, b: b, n: n}
+}
+
+func commonRadicand(s, other Surd) (int, error) {
+	switch {
+	case s.IsRational():
+		return other.n, nil
+	case other.IsRational() || s.n == other.n:
+		return s.n, nil
+	}
+	return 0, fmt.Errorf("cannot combine √%d and √%d", s.n, other.n)
+}
+
+func isZero(f *fraction.Fraction) bool {
+	numerator, _ := f.Numerator()
+	return numerator == 0
+}
+
+func isInteger(f *fraction.Fraction) bool {
+	denominator, _ := f.Denominator()
+	return denominator == 1
+}
+
+// equal compares two simplified fractions.
+func equal(x, y *fraction.Fraction) bool {
+	return x.AsIntegerRatio() == y.AsIntegerRatio()
+}
@@ -0,0 +1,71 @@
+package surd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func f(numerator, denominator int) *fraction.Fraction {
+	return fraction.MustNew(numerator, denominator)
+}
+
+func TestSqrt(t *testing.T) {
+	tests := []struct {
+		input *fraction.Fraction
+		want  string
+	}{
+		{f(8, 3), "2/3√6"},
+		{f(9, 4), "1 1/2"},
+		{f(12, 1), "2√3"},
+		{f(1, 2), "1/2√2"},
+		{f(0, 1), "0"},
+	}
+	for _, test := range tests {
+		s, err := Sqrt(test.input)
+		if err != nil || s.String() != test.want {
+			t.Fatalf("Sqrt(%v) = %v, %v; want %s", test.input, s, err, test.want)
+		}
+		if got := s.Float64(); math.Abs(got-math.Sqrt(test.input.Evaluate())) > 1e-12 {
+			t.Fatalf("Sqrt(%v).Float64() = %v", test.input, got)
+		}
+	}
+	if _, err := Sqrt(f(-1, 1)); err == nil {
+		t.Fatalf("Sqrt(-1) should return error")
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	x := MustNew(f(1, 1), f(1, 1), 2) // 1 + √2
+	square, _ := x.Multiply(x)
+	if square.String() != "3 + 2√2" {
+		t.Fatalf("(1 + √2)² = %v; want 3 + 2√2", square)
+	}
+	if norm := x.Norm(); norm.AsIntegerRatio() != "-1/1" {
+		t.Fatalf("Norm(1 + √2) = %v; want -1", norm)
+	}
+	inverse, _ := x.Inverse()
+	if inverse.String() != "-1 + √2" {
+		t.Fatalf("1 / (1 + √2) = %v; want -1 + √2", inverse)
+	}
+	one, _ := x.Multiply(inverse)
+	if !one.IsRational() || one.String() != "1" {
+		t.Fatalf("x · x⁻¹ = %v; want 1", one)
+	}
+	difference, _ := x.Subtract(MustNew(f(0, 1), f(1, 1), 8))
+	if difference.String() != "1 - √2" {
+		t.Fatalf("(1 + √2) - √8 = %v; want 1 - √2", difference)
+	}
+	sqrt3, _ := Sqrt(f(3, 1))
+	if _, err := x.Add(sqrt3); err == nil {
+		t.Fatalf("√2 + √3 should return error")
+	}
+	three, _ := sqrt3.Multiply(sqrt3)
+	if !three.Equals(MustNew(f(3, 1), f(0, 1), 1)) {
+		t.Fatalf("√3 · √3 = %v; want 3", three)
+	}
+	if _, err := MustNew(f(0, 1), f(0, 1), 5).Inverse(); err == nil {
+		t.Fatalf("Inverse of zero should return error")
+	}
+}
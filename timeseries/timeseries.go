@@ -0,0 +1,315 @@
+// Package timeseries provides building blocks for analysing and forecasting
+// time series stored in a Vector[float64] of equally spaced observations:
+// exponential smoothing (simple, Holt's linear trend and Holt-Winters
+// seasonal), classical seasonal decomposition, differencing and lag/lead
+// shifts.
+//
+// Important details:
+//
+// (*) The input Vectors are never modified; every function returns new
+// Vectors.
+//
+// (*) Values that are undefined are NaN: the first lag values of a lagged
+// series, and the trend (and thus the residual) at both ends of a
+// decomposition, where the centered moving average doesn't fit.
+//
+// (*) The smoothing parameters alpha, beta and gamma must be in [0, 1]. They
+// are not optimized: use e.g. the optimize package to minimize Model.SSE.
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+// Seasonality determines how the seasonal component is combined with the
+// level and trend.
+type Seasonality int
+
+const (
+	// Additive seasonality adds a constant seasonal pattern: y = trend +
+	// seasonal + residual.
+	Additive Seasonality = iota
+	// Multiplicative seasonality scales the trend by a seasonal factor, for
+	// patterns that grow with the level: y = trend · seasonal · residual.
+	Multiplicative
+)
+
+// Model is a fitted exponential smoothing model.
+type Model struct {
+	// Fitted holds the one-step-ahead forecast of every observation.
+	Fitted vector.Vector[float64]
+	// SSE is the sum of the squared one-step-ahead forecast errors.
+	SSE float64
+
+	level, trend float64
+	seasonal     []float64 // seasonal components indexed by time mod period
+	seasonality  Seasonality
+	n            int // number of observations
+}
+
+// ============================================================================
+// Exponential smoothing
+// ============================================================================
+
+// SimpleSmoothing fits simple exponential smoothing (no trend, no season)
+// with smoothing factor alpha: level = alpha·y + (1 - alpha)·level. Returns
+// an error if the series is empty or if alpha is not in [0, 1].
+func SimpleSmoothing(series vector.Vector[float64], alpha float64) (*Model, error) {
+	if len(series) == 0 {
+		return nil, errors.New("series must not be empty")
+	}
+	if err := checkParameters(alpha); err != nil {
+		return nil, err
+	}
+	m := &Model{Fitted: vector.NewFromValue(0.0, len(series)), level: series[0], n: len(series)}
+	for t, y := range series {
+		m.observe(t, y, m.level)
+		m.level = alpha*y + (1-alpha)*m.level
+	}
+	return m, nil
+}
+
+// Holt fits Holt's linear trend method with level smoothing factor alpha and
+// trend smoothing factor beta. The level starts at the first observation and
+// the trend at the difference of the first two. Returns an error if the
+// series has fewer than two observations or if a parameter is not in [0, 1].
+func Holt(series vector.Vector[float64], alpha, beta float64) (*Model, error) {
+	if len(series) < 2 {
+		return nil, errors.New("series must have at least two observations")
+	}
+	if err := checkParameters(alpha, beta); err != nil {
+		return nil, err
+	}
+	m := &Model{Fitted: vector.NewFromValue(0.0, len(series)), n: len(series)}
+	m.level, m.trend = series[0], series[1]-series[0]
+	m.observe(0, series[0], series[0])
+	for t := 1; t < len(series); t++ {
+		y := series[t]
+		m.observe(t, y, m.level+m.trend)
+		level := alpha*y + (1-alpha)*(m.level+m.trend)
+		m.trend = beta*(level-m.level) + (1-beta)*m.trend
+		m.level = level
+	}
+	return m, nil
+}
+
+// HoltWinters fits the Holt-Winters seasonal method with smoothing factors
+// alpha (level), beta (trend) and gamma (season) and the specified period,
+// e.g. 12 for monthly data with a yearly pattern. The level starts at the
+// mean of the first season, the trend at the average change between the
+// first two seasons. Returns an error if the series is shorter than two
+// periods, if a parameter is not in [0, 1] or if multiplicative seasonality
+// is used with non-positive observations.
+func HoltWinters(series vector.Vector[float64], alpha, beta, gamma float64, period int, seasonality Seasonality) (*Model, error) {
+	if err := checkSeason(series, period, seasonality); err != nil {
+		return nil, err
+	}
+	if err := checkParameters(alpha, beta, gamma); err != nil {
+		return nil, err
+	}
+	first := series[:period].Sum() / float64(period)
+	second := series[period:2*period].Sum() / float64(period)
+	m := &Model{
+		Fitted:      vector.NewFromValue(0.0, len(series)),
+		level:       first,
+		trend:       (second - first) / float64(period),
+		seasonal:    make([]float64, period),
+		seasonality: seasonality,
+		n:           len(series),
+	}
+	for i := range m.seasonal {
+		m.seasonal[i] = combine(seasonality, series[i]-first, series[i]/first)
+	}
+	for t, y := range series {
+		s := m.seasonal[t%period]
+		trended := m.level + m.trend
+		m.observe(t, y, combine(seasonality, trended+s, trended*s))
+		level := alpha*combine(seasonality, y-s, y/s) + (1-alpha)*trended
+		m.trend = beta*(level-m.level) + (1-beta)*m.trend
+		m.level = level
+		m.seasonal[t%period] = gamma*combine(seasonality, y-level, y/level) + (1-gamma)*s
+	}
+	return m, nil
+}
+
+// Forecast returns the forecasts for the next h time steps after the last
+// observation. Returns an error if h is negative.
+func (m *Model) Forecast(h int) (vector.Vector[float64], error) {
+	if h < 0 {
+		return nil, errors.New("horizon must not be negative")
+	}
+	result := vector.NewFromValue(0.0, h)
+	for k := range result {
+		trended := m.level + float64(k+1)*m.trend
+		if m.seasonal == nil {
+			result[k] = trended
+			continue
+		}
+		s := m.seasonal[(m.n+k)%len(m.seasonal)]
+		result[k] = combine(m.seasonality, trended+s, trended*s)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Seasonal decomposition
+// ============================================================================
+
+// Decomposition is the result of a classical seasonal decomposition.
+type Decomposition struct {
+	// Trend is the centered moving average of the series (NaN at both ends).
+	Trend vector.Vector[float64]
+	// Seasonal repeats the seasonal indices, which sum to zero (additive) or
+	// average to one (multiplicative) over a period.
+	Seasonal vector.Vector[float64]
+	// Residual is what remains after removing trend and season.
+	Residual vector.Vector[float64]
+}
+
+// Decompose splits the series into trend, seasonal and residual components
+// with classical decomposition: the trend is a centered moving average over
+// one period and the seasonal index of every position in the period is the
+// average detrended value. Returns an error if the series is shorter than
+// two periods or if multiplicative seasonality is used with non-positive
+// observations.
+func Decompose(series vector.Vector[float64], period int, seasonality Seasonality) (*Decomposition, error) {
+	if err := checkSeason(series, period, seasonality); err != nil {
+		return nil, err
+	}
+	trend := centeredMovingAverage(series, period)
+	sums := make([]float64, period)
+	counts := make([]int, period)
+	for t, y := range series {
+		if !math.IsNaN(trend[t]) {
+			sums[t%period] += combine(seasonality, y-trend[t], y/trend[t])
+			counts[t%period]++
+		}
+	}
+	// Normalize the indices so that they don't absorb part of the level.
+	indices := make([]float64, period)
+	total := 0.0
+	for i := range indices {
+		indices[i] = sums[i] / float64(counts[i])
+		total += indices[i]
+	}
+	for i := range indices {
+		indices[i] = combine(seasonality, indices[i]-total/float64(period), indices[i]*float64(period)/total)
+	}
+	d := &Decomposition{
+		Trend:    trend,
+		Seasonal: vector.NewFromValue(0.0, len(series)),
+		Residual: vector.NewFromValue(0.0, len(series)),
+	}
+	for t, y := range series {
+		s := indices[t%period]
+		d.Seasonal[t] = s
+		d.Residual[t] = combine(seasonality, y-trend[t]-s, y/(trend[t]*s))
+	}
+	return d, nil
+}
+
+// ============================================================================
+// Differencing and shifts
+// ============================================================================
+
+// Diff returns the lag-differenced series y[t] - y[t-lag], which is lag
+// elements shorter than the series. Use lag 1 to remove a linear trend and
+// the period to remove a season; apply Diff repeatedly for higher orders.
+// Returns an error if lag is not positive or not smaller than the length of
+// the series.
+func Diff(series vector.Vector[float64], lag int) (vector.Vector[float64], error) {
+	if lag < 1 || lag >= len(series) {
+		return nil, fmt.Errorf("lag %d out of range for series of length %d", lag, len(series))
+	}
+	result := vector.NewFromValue(0.0, len(series)-lag)
+	for t := range result {
+		result[t] = series[t+lag] - series[t]
+	}
+	return result, nil
+}
+
+// Lag returns the series shifted k steps forward in time: element t of the
+// result is y[t-k]. The first k elements (the last -k elements for a
+// negative k) are NaN. The result has the same length as the series.
+func Lag(series vector.Vector[float64], k int) vector.Vector[float64] {
+	result := vector.NewFromValue(math.NaN(), len(series))
+	for t := range result {
+		if source := t - k; source >= 0 && source < len(series) {
+			result[t] = series[source]
+		}
+	}
+	return result
+}
+
+// Lead returns the series shifted k steps backward in time: element t of the
+// result is y[t+k]. Lead(series, k) is Lag(series, -k).
+func Lead(series vector.Vector[float64], k int) vector.Vector[float64] {
+	return Lag(series, -k)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// observe stores the one-step-ahead forecast of observation t.
+func (m *Model) observe(t int, y, forecast float64) {
+	m.Fitted[t] = forecast
+	m.SSE += (y - forecast) * (y - forecast)
+}
+
+// combine returns the additive or the multiplicative variant of a formula.
+func combine(seasonality Seasonality, additive, multiplicative float64) float64 {
+	if seasonality == Multiplicative {
+		return multiplicative
+	}
+	return additive
+}
+
+func checkParameters(parameters ...float64) error {
+	for _, p := range parameters {
+		if !(p >= 0 && p <= 1) {
+			return fmt.Errorf("smoothing parameter %v must be in [0, 1]", p)
+		}
+	}
+	return nil
+}
+
+func checkSeason(series vector.Vector[float64], period int, seasonality Seasonality) error {
+	if period < 2 {
+		return errors.New("period must be at least 2")
+	}
+	if len(series) < 2*period {
+		return fmt.Errorf("series must have at least two periods (%d observations)", 2*period)
+	}
+	if seasonality == Multiplicative {
+		for _, y := range series {
+			if !(y > 0) {
+				return errors.New("multiplicative seasonality requires positive observations")
+			}
+		}
+	}
+	return nil
+}
+
+// centeredMovingAverage returns the moving average over one period centered
+// on every observation. For an even period the window has period + 1
+// elements with half weights at both ends (a 2×m moving average).
+func centeredMovingAverage(series vector.Vector[float64], period int) vector.Vector[float64] {
+	result := vector.NewFromValue(math.NaN(), len(series))
+	half := period / 2
+	for t := half; t < len(series)-half; t++ {
+		sum := 0.0
+		for i := t - half; i <= t+half; i++ {
+			sum += series[i]
+		}
+		if period%2 == 0 {
+			sum -= (series[t-half] + series[t+half]) / 2
+		}
+		result[t] = sum / float64(period)
+	}
+	return result
+}
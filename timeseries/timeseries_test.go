@@ -0,0 +1,115 @@
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/vector"
+)
+
+// seasonalSeries returns a linear trend plus a repeating pattern.
+func seasonalSeries(periods int) vector.Vector[float64] {
+	pattern := []float64{3, -1, -4, 2}
+	series := vector.NewFromValue(0.0, periods*len(pattern))
+	for t := range series {
+		series[t] = 10 + 0.5*float64(t) + pattern[t%len(pattern)]
+	}
+	return series
+}
+
+func TestSimpleSmoothingAndHolt(t *testing.T) {
+	series := vector.New(3.0, 5, 4)
+	m, err := SimpleSmoothing(series, 0.5)
+	if err != nil {
+		t.Fatalf("SimpleSmoothing returned error: %v", err)
+	}
+	// Levels: 3, 4, 4; the fitted values lag one step behind.
+	if m.Fitted[0] != 3 || m.Fitted[1] != 3 || m.Fitted[2] != 4 || m.SSE != 4 {
+		t.Fatalf("Fitted, SSE = %v, %v; want [3 3 4], 4", m.Fitted, m.SSE)
+	}
+	if forecast, _ := m.Forecast(2); forecast[0] != 4 || forecast[1] != 4 {
+		t.Fatalf("Forecast(2) = %v; want [4 4]", forecast)
+	}
+	if _, err := SimpleSmoothing(series, 1.5); err == nil {
+		t.Fatalf("SimpleSmoothing with alpha 1.5 should return error")
+	}
+	// A straight line is forecast exactly by Holt's method.
+	line := vector.New(1.0, 3, 5, 7, 9)
+	holt, _ := Holt(line, 0.3, 0.1)
+	forecast, _ := holt.Forecast(3)
+	for k, want := range []float64{11, 13, 15} {
+		if math.Abs(forecast[k]-want) > 1e-12 {
+			t.Fatalf("Holt Forecast(3) = %v; want [11 13 15]", forecast)
+		}
+	}
+	if holt.SSE > 1e-20 {
+		t.Fatalf("Holt SSE on a line = %v; want 0", holt.SSE)
+	}
+}
+
+func TestHoltWinters(t *testing.T) {
+	series := seasonalSeries(6)
+	m, err := HoltWinters(series, 0.3, 0.1, 0.2, 4, Additive)
+	if err != nil {
+		t.Fatalf("HoltWinters returned error: %v", err)
+	}
+	forecast, _ := m.Forecast(4)
+	for k := range forecast {
+		want := 10 + 0.5*float64(len(series)+k) + []float64{3, -1, -4, 2}[k]
+		if math.Abs(forecast[k]-want) > 0.5 {
+			t.Fatalf("Forecast(4) = %v; want about %v at step %d", forecast, want, k)
+		}
+	}
+	if _, err := HoltWinters(series[:7], 0.3, 0.1, 0.2, 4, Additive); err == nil {
+		t.Fatalf("HoltWinters with less than two periods should return error")
+	}
+	negative := vector.New(1.0, -1, 1, -1)
+	if _, err := HoltWinters(negative, 0.3, 0.1, 0.2, 2, Multiplicative); err == nil {
+		t.Fatalf("multiplicative HoltWinters with negative values should return error")
+	}
+	// A purely multiplicative pattern without trend is reproduced exactly.
+	multiplicative := vector.New(10.0, 20, 5, 10, 20, 5, 10, 20, 5)
+	mm, _ := HoltWinters(multiplicative, 0.5, 0.5, 0.5, 3, Multiplicative)
+	if forecast, _ := mm.Forecast(3); math.Abs(forecast[0]-10) > 1e-9 || math.Abs(forecast[2]-5) > 1e-9 {
+		t.Fatalf("multiplicative Forecast(3) = %v; want [10 20 5]", forecast)
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	series := seasonalSeries(5)
+	d, err := Decompose(series, 4, Additive)
+	if err != nil {
+		t.Fatalf("Decompose returned error: %v", err)
+	}
+	if !math.IsNaN(d.Trend[0]) || !math.IsNaN(d.Trend[1]) || math.Abs(d.Trend[2]-11) > 1e-12 {
+		t.Fatalf("Trend = %v; want [NaN NaN 11 ...]", d.Trend)
+	}
+	for t0, want := range []float64{3, -1, -4, 2} {
+		if math.Abs(d.Seasonal[t0]-want) > 1e-12 || math.Abs(d.Seasonal[t0+4]-want) > 1e-12 {
+			t.Fatalf("Seasonal = %v; want the pattern [3 -1 -4 2]", d.Seasonal)
+		}
+	}
+	if math.Abs(d.Residual[5]) > 1e-12 {
+		t.Fatalf("Residual = %v; want 0 where the trend is defined", d.Residual)
+	}
+}
+
+func TestDiffAndShifts(t *testing.T) {
+	series := vector.New(1.0, 4, 9, 16)
+	diff, _ := Diff(series, 1)
+	second, _ := Diff(diff, 1)
+	if len(diff) != 3 || diff[2] != 7 || len(second) != 2 || second[0] != 2 || second[1] != 2 {
+		t.Fatalf("Diff = %v, %v; want [3 5 7], [2 2]", diff, second)
+	}
+	if _, err := Diff(series, 4); err == nil {
+		t.Fatalf("Diff with lag 4 should return error")
+	}
+	lag := Lag(series, 1)
+	lead := Lead(series, 2)
+	if !math.IsNaN(lag[0]) || lag[1] != 1 || lag[3] != 9 {
+		t.Fatalf("Lag(1) = %v; want [NaN 1 4 9]", lag)
+	}
+	if lead[0] != 9 || lead[1] != 16 || !math.IsNaN(lead[2]) {
+		t.Fatalf("Lead(2) = %v; want [9 16 NaN NaN]", lead)
+	}
+}
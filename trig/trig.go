@@ -0,0 +1,189 @@
+// Package trig returns exact values of the sine, cosine and tangent of
+// rational multiples of π, e.g. sin(π/3) = ½√3 and tan(π/12) = 2 - √3, as
+// Surds (numbers of the form a + b·√n). Rational results, like cos(π/3) =
+// 1/2, are Surds with IsRational() true.
+//
+// Angles are specified as the Fraction r in the angle r·π, so π/4 is
+// fraction.MustNew(1, 4) and -3π/2 is fraction.MustNew(-3, 2).
+//
+// Important details:
+//
+// (*) Only values with a closed form a + b·√n are exact: the multiples of
+// π/6 and π/4, the cosines of the multiples of π/5 (and the sines of the
+// odd multiples of π/10) and the tangents of the multiples of π/8 and π/12.
+// Other angles, like sin(π/12) = (√6 - √2)/4, return an *InexactError that
+// carries the float64 approximation.
+//
+// (*) Tan returns an error (that is not an *InexactError) at odd multiples of
+// π/2, where the tangent is undefined.
+package trig
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/bogersw/wbmath"
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/surd"
+)
+
+// InexactError is returned when a trigonometric value has no exact closed
+// form in this package.
+type InexactError struct {
+	// Function is "sin", "cos" or "tan".
+	Function string
+	// Angle is the angle as a multiple of π, e.g. "1/12".
+	Angle string
+	// Approximation is the float64 value of the function at the angle.
+	Approximation float64
+}
+
+// Error implements the error interface.
+func (e *InexactError) Error() string {
+	return fmt.Sprintf("%s(%sπ) has no exact value (≈ %v)", e.Function, e.Angle, e.Approximation)
+}
+
+// ============================================================================
+// Trigonometric functions
+// ============================================================================
+
+// Sin returns the exact value of sin(angle·π). Returns an *InexactError if
+// the value has no exact closed form, or an error if angle is nil.
+func Sin(angle *fraction.Fraction) (surd.Surd, error) {
+	p, q, err := parts(angle)
+	if err != nil {
+		return surd.Surd{}, err
+	}
+	value, ok := sine(p, q)
+	if !ok {
+		return surd.Surd{}, inexact("sin", p, q, math.Sin)
+	}
+	return value, nil
+}
+
+// Cos returns the exact value of cos(angle·π). Returns an *InexactError if
+// the value has no exact closed form, or an error if angle is nil.
+func Cos(angle *fraction.Fraction) (surd.Surd, error) {
+	p, q, err := parts(angle)
+	if err != nil {
+		return surd.Surd{}, err
+	}
+	value, ok := cosine(p, q)
+	if !ok {
+		return surd.Surd{}, inexact("cos", p, q, math.Cos)
+	}
+	return value, nil
+}
+
+// Tan returns the exact value of tan(angle·π). Returns an error if the
+// tangent is undefined (at odd multiples of π/2), an *InexactError if the
+// value has no exact closed form, or an error if angle is nil.
+func Tan(angle *fraction.Fraction) (surd.Surd, error) {
+	p, q, err := parts(angle)
+	if err != nil {
+		return surd.Surd{}, err
+	}
+	sin, sinOk := sine(p, q)
+	cos, cosOk := cosine(p, q)
+	if sinOk && cosOk {
+		if cos.Float64() == 0 {
+			return surd.Surd{}, fmt.Errorf("tan(%sπ) is undefined", ratio(p, q))
+		}
+		return sin.Divide(cos)
+	}
+	// Half-angle formula: tan(x) = (1 - cos(2x)) / sin(2x). sin(2x) is not
+	// zero here, otherwise x would be a multiple of π/2 with exact values.
+	sin2, sinOk := sine(2*p, q)
+	cos2, cosOk := cosine(2*p, q)
+	if sinOk && cosOk {
+		numerator, err := rational(1, 1).Subtract(cos2)
+		if err == nil {
+			return numerator.Divide(sin2)
+		}
+	}
+	return surd.Surd{}, inexact("tan", p, q, math.Tan)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// parts returns the numerator and the (positive) denominator of the angle.
+func parts(angle *fraction.Fraction) (int, int, error) {
+	p, ok := angle.Numerator()
+	if !ok {
+		return 0, 0, fraction.ErrNilFraction
+	}
+	q, _ := angle.Denominator()
+	return p, q, nil
+}
+
+// sine returns sin(p/q·π) = cos((q - 2p)/(2q)·π) if it has an exact value.
+func sine(p, q int) (surd.Surd, bool) {
+	return cosine(q-2*p, 2*q)
+}
+
+// cosine returns cos(p/q·π) if it has an exact value.
+func cosine(p, q int) (surd.Surd, bool) {
+	// Reduce the angle to [0, 2π), then use the symmetries cos(2π - x) =
+	// cos(x) and cos(π - x) = -cos(x) to reduce it to [0, π/2].
+	p %= 2 * q
+	if p < 0 {
+		p += 2 * q
+	}
+	if p > q {
+		p = 2*q - p
+	}
+	sign := 1
+	if 2*p > q {
+		p, sign = q-p, -1
+	}
+	if g := wbmath.Gcd(p, q); g > 1 {
+		p, q = p/g, q/g
+	}
+	var value surd.Surd
+	switch {
+	case p == 0:
+		value = rational(1, 1)
+	case p == 1 && q == 2:
+		value = rational(0, 1)
+	case p == 1 && q == 3:
+		value = rational(1, 2)
+	case p == 1 && q == 4:
+		value = root(0, 1, 2, 2) // ½√2
+	case p == 1 && q == 6:
+		value = root(0, 1, 2, 3) // ½√3
+	case p == 1 && q == 5:
+		value = root(1, 1, 4, 5) // (1 + √5)/4
+	case p == 2 && q == 5:
+		value = root(-1, 1, 4, 5) // (√5 - 1)/4
+	default:
+		return surd.Surd{}, false
+	}
+	if sign < 0 {
+		value = value.Negate()
+	}
+	return value, true
+}
+
+// rational returns the Surd p/q.
+func rational(p, q int) surd.Surd {
+	return surd.MustNew(fraction.MustNew(p, q), fraction.MustNew(0, 1), 1)
+}
+
+// root returns the Surd (a + b·√n)/d.
+func root(a, b, d, n int) surd.Surd {
+	return surd.MustNew(fraction.MustNew(a, d), fraction.MustNew(b, d), n)
+}
+
+func ratio(p, q int) string {
+	return fraction.MustNew(p, q).AsIntegerRatio()
+}
+
+func inexact(function string, p, q int, f func(float64) float64) *InexactError {
+	return &InexactError{
+		Function:      function,
+		Angle:         ratio(p, q),
+		Approximation: f(float64(p) / float64(q) * math.Pi),
+	}
+}
@@ -0,0 +1,72 @@
+package trig
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+	"github.com/bogersw/wbmath/surd"
+)
+
+func TestExactValues(t *testing.T) {
+	cases := []struct {
+		name string
+		f    func(*fraction.Fraction) (surd.Surd, error)
+		p, q int
+		want string
+	}{
+		{"sin", Sin, 1, 6, "1/2"},
+		{"sin", Sin, 1, 3, "1/2√3"},
+		{"sin", Sin, 3, 4, "1/2√2"},
+		{"sin", Sin, -1, 2, "-1"},
+		{"sin", Sin, 7, 1, "0"},
+		{"sin", Sin, 1, 10, "-1/4 + 1/4√5"},
+		{"cos", Cos, 1, 4, "1/2√2"},
+		{"cos", Cos, 2, 3, "-1/2"},
+		{"cos", Cos, 11, 6, "1/2√3"},
+		{"cos", Cos, 1, 5, "1/4 + 1/4√5"},
+		{"tan", Tan, 1, 3, "√3"},
+		{"tan", Tan, 3, 4, "-1"},
+		{"tan", Tan, 1, 6, "1/3√3"},
+		{"tan", Tan, 1, 12, "2 - √3"},
+		{"tan", Tan, 3, 8, "1 + √2"},
+	}
+	for _, c := range cases {
+		got, err := c.f(fraction.MustNew(c.p, c.q))
+		if err != nil {
+			t.Fatalf("%s(%d/%dπ) returned error: %v", c.name, c.p, c.q, err)
+		}
+		if got.String() != c.want {
+			t.Fatalf("%s(%d/%dπ) = %v; want %v", c.name, c.p, c.q, got, c.want)
+		}
+		want := map[string]func(float64) float64{"sin": math.Sin, "cos": math.Cos, "tan": math.Tan}[c.name]
+		if x := float64(c.p) / float64(c.q) * math.Pi; math.Abs(got.Float64()-want(x)) > 1e-12 {
+			t.Fatalf("%s(%d/%dπ) = %v ≈ %v; want %v", c.name, c.p, c.q, got, got.Float64(), want(x))
+		}
+	}
+}
+
+func TestInexactAndUndefined(t *testing.T) {
+	_, err := Sin(fraction.MustNew(1, 12))
+	var inexact *InexactError
+	if !errors.As(err, &inexact) {
+		t.Fatalf("Sin(π/12) error = %v; want *InexactError", err)
+	}
+	if inexact.Function != "sin" || inexact.Angle != "1/12" || math.Abs(inexact.Approximation-math.Sin(math.Pi/12)) > 1e-15 {
+		t.Fatalf("InexactError = %+v; want sin, 1/12, %v", inexact, math.Sin(math.Pi/12))
+	}
+	if _, err := Cos(fraction.MustNew(1, 7)); !errors.As(err, &inexact) {
+		t.Fatalf("Cos(π/7) error = %v; want *InexactError", err)
+	}
+	if _, err := Tan(fraction.MustNew(1, 10)); !errors.As(err, &inexact) {
+		t.Fatalf("Tan(π/10) error = %v; want *InexactError", err)
+	}
+	_, err = Tan(fraction.MustNew(3, 2))
+	if err == nil || errors.As(err, &inexact) {
+		t.Fatalf("Tan(3π/2) error = %v; want an undefined error", err)
+	}
+	if _, err := Cos(nil); err == nil {
+		t.Fatalf("Cos(nil) should return error")
+	}
+}
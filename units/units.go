@@ -0,0 +1,351 @@
+// Package units provides a Quantity type that carries a value together with
+// its physical dimension (expressed as exponents of the SI base dimensions),
+// and a set of units with exact, Fraction-based conversion factors.
+//
+// Important details:
+//
+// (*) A Quantity always stores its value in SI base units (m, kg, s, A, K,
+// mol, cd). Units are only used when creating a Quantity and when reading
+// its value back with In.
+//
+// (*) Conversion factors are stored as fractions, so conversions like m to ft
+// (1 ft = 381/1250 m) are exact until the final float64 evaluation.
+//
+// (*) Units with an offset (°C, °F) are affine: Convert handles them, but
+// ConversionFactor returns an error because no single factor exists.
+//
+// (*) Adding or subtracting quantities with different dimensions returns an
+// error instead of silently producing a meaningless result.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+// ============================================================================
+// Dimension
+// ============================================================================
+
+// Dimension holds the exponents of the seven SI base dimensions, in the order
+// length, mass, time, electric current, temperature, amount of substance and
+// luminous intensity. For example: a velocity has dimension {1, 0, -1, ...}.
+type Dimension [7]int
+
+// The SI base dimensions.
+var (
+	Dimensionless = Dimension{}
+	Length        = Dimension{1, 0, 0, 0, 0, 0, 0}
+	Mass          = Dimension{0, 1, 0, 0, 0, 0, 0}
+	Time          = Dimension{0, 0, 1, 0, 0, 0, 0}
+	Current       = Dimension{0, 0, 0, 1, 0, 0, 0}
+	Temperature   = Dimension{0, 0, 0, 0, 1, 0, 0}
+	Amount        = Dimension{0, 0, 0, 0, 0, 1, 0}
+	Luminosity    = Dimension{0, 0, 0, 0, 0, 0, 1}
+)
+
+// baseSymbols holds the SI base unit symbols in the same order as Dimension.
+var baseSymbols = [7]string{"m", "kg", "s", "A", "K", "mol", "cd"}
+
+// Multiply returns the dimension of the product of two quantities: the
+// exponents are added.
+func (d Dimension) Multiply(other Dimension) Dimension {
+	for i := range d {
+		d[i] += other[i]
+	}
+	return d
+}
+
+// Divide returns the dimension of the quotient of two quantities: the
+// exponents are subtracted.
+func (d Dimension) Divide(other Dimension) Dimension {
+	for i := range d {
+		d[i] -= other[i]
+	}
+	return d
+}
+
+// Pow returns the dimension raised to the specified power: the exponents are
+// multiplied.
+func (d Dimension) Pow(exponent int) Dimension {
+	for i := range d {
+		d[i] *= exponent
+	}
+	return d
+}
+
+// String implements the fmt.Stringer interface and returns the dimension in
+// terms of the SI base units, e.g. "m kg s^-2". Returns an empty string for a
+// dimensionless value.
+func (d Dimension) String() string {
+	parts := make([]string, 0, len(d))
+	for i, exponent := range d {
+		switch exponent {
+		case 0:
+			continue
+		case 1:
+			parts = append(parts, baseSymbols[i])
+		default:
+			parts = append(parts, fmt.Sprintf("%s^%d", baseSymbols[i], exponent))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ============================================================================
+// Unit
+// ============================================================================
+
+// Unit represents a unit of measurement. A value x expressed in the unit
+// corresponds to x * factor + offset in SI base units.
+type Unit struct {
+	symbol    string
+	dimension Dimension
+	factor    *fraction.Fraction
+	offset    *fraction.Fraction
+}
+
+// NewUnit is a constructor function that returns a linear Unit with the
+// specified symbol, dimension and (exact) factor to SI base units. Returns an
+// error if the factor is nil or zero.
+func NewUnit(symbol string, dimension Dimension, factor *fraction.Fraction) (*Unit, error) {
+	return NewAffineUnit(symbol, dimension, factor, fraction.NewFromNumber(0))
+}
+
+// MustNewUnit is a constructor identical to NewUnit but which panics if an
+// error occurs.
+func MustNewUnit(symbol string, dimension Dimension, factor *fraction.Fraction) *Unit {
+	unit, err := NewUnit(symbol, dimension, factor)
+	if err != nil {
+		panic(err)
+	}
+	return unit
+}
+
+// NewAffineUnit is a constructor function that returns a Unit with both a
+// factor and an offset to SI base units, like degrees Celsius. Returns an
+// error if the factor is nil or zero, or if the offset is nil.
+func NewAffineUnit(symbol string, dimension Dimension, factor, offset *fraction.Fraction) (*Unit, error) {
+	if factor == nil || offset == nil {
+		return nil, fraction.ErrNilFraction
+	}
+	if numerator, _ := factor.Numerator(); numerator == 0 {
+		return nil, errors.New("unit factor must not be zero")
+	}
+	return &Unit{
+			symbol:    symbol,
+			dimension: dimension,
+			factor:    factor.Clone(),
+			offset:    offset.Clone()},
+		nil
+}
+
+// MustNewAffineUnit is a constructor identical to NewAffineUnit but which
+// panics if an error occurs.
+func MustNewAffineUnit(symbol string, dimension Dimension, factor, offset *fraction.Fraction) *Unit {
+	unit, err := NewAffineUnit(symbol, dimension, factor, offset)
+	if err != nil {
+		panic(err)
+	}
+	return unit
+}
+
+// Predefined units. The factors are exact by definition of the units.
+var (
+	Meter      = MustNewUnit("m", Length, fraction.MustNew(1, 1))
+	Kilometer  = MustNewUnit("km", Length, fraction.MustNew(1000, 1))
+	Centimeter = MustNewUnit("cm", Length, fraction.MustNew(1, 100))
+	Millimeter = MustNewUnit("mm", Length, fraction.MustNew(1, 1000))
+	Inch       = MustNewUnit("in", Length, fraction.MustNew(127, 5000))
+	Foot       = MustNewUnit("ft", Length, fraction.MustNew(381, 1250))
+	Yard       = MustNewUnit("yd", Length, fraction.MustNew(1143, 1250))
+	Mile       = MustNewUnit("mi", Length, fraction.MustNew(201168, 125))
+
+	Kilogram = MustNewUnit("kg", Mass, fraction.MustNew(1, 1))
+	Gram     = MustNewUnit("g", Mass, fraction.MustNew(1, 1000))
+	Pound    = MustNewUnit("lb", Mass, fraction.MustNew(45359237, 100000000))
+
+	Second = MustNewUnit("s", Time, fraction.MustNew(1, 1))
+	Minute = MustNewUnit("min", Time, fraction.MustNew(60, 1))
+	Hour   = MustNewUnit("h", Time, fraction.MustNew(3600, 1))
+
+	Ampere  = MustNewUnit("A", Current, fraction.MustNew(1, 1))
+	Mole    = MustNewUnit("mol", Amount, fraction.MustNew(1, 1))
+	Candela = MustNewUnit("cd", Luminosity, fraction.MustNew(1, 1))
+
+	Kelvin     = MustNewUnit("K", Temperature, fraction.MustNew(1, 1))
+	Celsius    = MustNewAffineUnit("°C", Temperature, fraction.MustNew(1, 1), fraction.MustNew(5463, 20))
+	Fahrenheit = MustNewAffineUnit("°F", Temperature, fraction.MustNew(5, 9), fraction.MustNew(45967, 180))
+
+	Newton = MustNewUnit("N", Dimension{1, 1, -2, 0, 0, 0, 0}, fraction.MustNew(1, 1))
+	Joule  = MustNewUnit("J", Dimension{2, 1, -2, 0, 0, 0, 0}, fraction.MustNew(1, 1))
+	Watt   = MustNewUnit("W", Dimension{2, 1, -3, 0, 0, 0, 0}, fraction.MustNew(1, 1))
+)
+
+// Symbol returns the symbol of the Unit.
+func (u *Unit) Symbol() string {
+	return u.symbol
+}
+
+// Dimension returns the dimension of the Unit.
+func (u *Unit) Dimension() Dimension {
+	return u.dimension
+}
+
+// IsAffine checks if the Unit has a non-zero offset to SI base units.
+func (u *Unit) IsAffine() bool {
+	numerator, _ := u.offset.Numerator()
+	return numerator != 0
+}
+
+// String implements the fmt.Stringer interface and returns the symbol of the
+// Unit.
+func (u *Unit) String() string {
+	return u.symbol
+}
+
+// ConversionFactor returns the exact factor by which a value in unit `from`
+// has to be multiplied to express it in unit `to`. Returns an error if the
+// dimensions differ or if either unit is affine.
+func ConversionFactor(from, to *Unit) (*fraction.Fraction, error) {
+	if from.dimension != to.dimension {
+		return nil, dimensionMismatch(from.dimension, to.dimension)
+	}
+	if from.IsAffine() || to.IsAffine() {
+		return nil, errors.New("affine units have no single conversion factor")
+	}
+	return from.factor.Clone().Divide(to.factor).Simplify(), nil
+}
+
+// Convert converts a value from unit `from` to unit `to`, taking offsets into
+// account (e.g. °C to °F). The conversion coefficients are computed exactly;
+// only the final result is a float64. Returns an error if the dimensions
+// differ.
+func Convert(value float64, from, to *Unit) (float64, error) {
+	if from.dimension != to.dimension {
+		return 0, dimensionMismatch(from.dimension, to.dimension)
+	}
+	// value_to = value_from * (f_from / f_to) + (o_from - o_to) / f_to
+	scale := from.factor.Clone().Divide(to.factor).Simplify()
+	shift := from.offset.Clone().Subtract(to.offset).Divide(to.factor).Simplify()
+	return value*scale.Evaluate() + shift.Evaluate(), nil
+}
+
+// ============================================================================
+// Quantity
+// ============================================================================
+
+// Quantity represents a value with a physical dimension. The value is stored
+// in SI base units.
+type Quantity struct {
+	value     float64
+	dimension Dimension
+}
+
+// New is a constructor function that returns a Quantity for the specified
+// value expressed in the specified unit.
+func New(value float64, unit *Unit) Quantity {
+	return Quantity{
+		value:     value*unit.factor.Evaluate() + unit.offset.Evaluate(),
+		dimension: unit.dimension,
+	}
+}
+
+// NewFromDimension is a constructor function that returns a Quantity for the
+// specified value in SI base units with the specified dimension.
+func NewFromDimension(value float64, dimension Dimension) Quantity {
+	return Quantity{value: value, dimension: dimension}
+}
+
+// Value returns the value of the Quantity in SI base units.
+func (q Quantity) Value() float64 {
+	return q.value
+}
+
+// Dimension returns the dimension of the Quantity.
+func (q Quantity) Dimension() Dimension {
+	return q.dimension
+}
+
+// In returns the value of the Quantity expressed in the specified unit.
+// Returns an error if the dimension of the unit doesn't match.
+func (q Quantity) In(unit *Unit) (float64, error) {
+	if q.dimension != unit.dimension {
+		return 0, dimensionMismatch(q.dimension, unit.dimension)
+	}
+	return (q.value - unit.offset.Evaluate()) / unit.factor.Evaluate(), nil
+}
+
+// Add returns the sum of the current Quantity and the specified Quantity.
+// Returns an error if the dimensions differ.
+func (q Quantity) Add(other Quantity) (Quantity, error) {
+	if q.dimension != other.dimension {
+		return Quantity{}, dimensionMismatch(q.dimension, other.dimension)
+	}
+	return Quantity{value: q.value + other.value, dimension: q.dimension}, nil
+}
+
+// Subtract returns the difference of the current Quantity and the specified
+// Quantity. Returns an error if the dimensions differ.
+func (q Quantity) Subtract(other Quantity) (Quantity, error) {
+	if q.dimension != other.dimension {
+		return Quantity{}, dimensionMismatch(q.dimension, other.dimension)
+	}
+	return Quantity{value: q.value - other.value, dimension: q.dimension}, nil
+}
+
+// Multiply returns the product of the current Quantity and the specified
+// Quantity. The dimensions are combined, so this never fails.
+func (q Quantity) Multiply(other Quantity) Quantity {
+	return Quantity{value: q.value * other.value, dimension: q.dimension.Multiply(other.dimension)}
+}
+
+// Divide returns the quotient of the current Quantity and the specified
+// Quantity. Returns fraction.ErrDivisionByZero if the value of the specified
+// Quantity is zero.
+func (q Quantity) Divide(other Quantity) (Quantity, error) {
+	if other.value == 0 {
+		return Quantity{}, fraction.ErrDivisionByZero
+	}
+	return Quantity{value: q.value / other.value, dimension: q.dimension.Divide(other.dimension)}, nil
+}
+
+// Scale returns the current Quantity with its value multiplied by `factor`.
+func (q Quantity) Scale(factor float64) Quantity {
+	return Quantity{value: q.value * factor, dimension: q.dimension}
+}
+
+// Pow returns the current Quantity raised to the specified integer power.
+func (q Quantity) Pow(exponent int) Quantity {
+	value := 1.0
+	base := q.value
+	if exponent < 0 {
+		base = 1 / base
+	}
+	for i := 0; i < exponent || i < -exponent; i++ {
+		value *= base
+	}
+	return Quantity{value: value, dimension: q.dimension.Pow(exponent)}
+}
+
+// String implements the fmt.Stringer interface and returns the value of the
+// Quantity in SI base units, e.g. "9.81 m s^-2".
+func (q Quantity) String() string {
+	if q.dimension == Dimensionless {
+		return fmt.Sprintf("%g", q.value)
+	}
+	return fmt.Sprintf("%g %s", q.value, q.dimension)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// dimensionMismatch returns the error used when two dimensions should match
+// but don't.
+func dimensionMismatch(a, b Dimension) error {
+	return fmt.Errorf("dimension mismatch: [%s] and [%s]", a, b)
+}
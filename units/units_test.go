@@ -0,0 +1,95 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bogersw/wbmath/fraction"
+)
+
+func almostEqual(a, b float64) bool {
+	const eps = 1e-9
+	return math.Abs(a-b) <= eps
+}
+
+func TestConversionFactor(t *testing.T) {
+	factor, err := ConversionFactor(Foot, Meter)
+	if err != nil {
+		t.Fatalf("ConversionFactor returned error: %v", err)
+	}
+	if s := factor.AsIntegerRatio(); s != "381/1250" {
+		t.Fatalf("ConversionFactor(ft, m) = %q; want \"381/1250\"", s)
+	}
+	factor, _ = ConversionFactor(Mile, Foot)
+	if s := factor.AsIntegerRatio(); s != "5280/1" {
+		t.Fatalf("ConversionFactor(mi, ft) = %q; want \"5280/1\"", s)
+	}
+	if _, err := ConversionFactor(Meter, Second); err == nil {
+		t.Fatalf("ConversionFactor(m, s) should return error")
+	}
+	if _, err := ConversionFactor(Celsius, Fahrenheit); err == nil {
+		t.Fatalf("ConversionFactor(°C, °F) should return error")
+	}
+}
+
+func TestConvertTemperature(t *testing.T) {
+	cases := []struct {
+		value    float64
+		from, to *Unit
+		want     float64
+	}{
+		{100, Celsius, Fahrenheit, 212},
+		{-40, Celsius, Fahrenheit, -40},
+		{32, Fahrenheit, Celsius, 0},
+		{0, Celsius, Kelvin, 273.15},
+	}
+	for _, c := range cases {
+		got, err := Convert(c.value, c.from, c.to)
+		if err != nil {
+			t.Fatalf("Convert returned error: %v", err)
+		}
+		if !almostEqual(got, c.want) {
+			t.Fatalf("Convert(%v, %s, %s) = %v; want %v", c.value, c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestQuantityArithmetic(t *testing.T) {
+	distance := New(1, Kilometer)
+	sum, err := distance.Add(New(500, Meter))
+	if err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	if got, _ := sum.In(Meter); !almostEqual(got, 1500) {
+		t.Fatalf("1 km + 500 m = %v m; want 1500", got)
+	}
+	if _, err := distance.Add(New(1, Second)); err == nil {
+		t.Fatalf("Add with different dimensions should return error")
+	}
+
+	speed, err := New(36, Kilometer).Divide(New(1, Hour))
+	if err != nil {
+		t.Fatalf("Divide returned error: %v", err)
+	}
+	if !almostEqual(speed.Value(), 10) {
+		t.Fatalf("36 km / 1 h = %v m/s; want 10", speed.Value())
+	}
+	if _, err := speed.Divide(New(0, Second)); !errors.Is(err, fraction.ErrDivisionByZero) {
+		t.Fatalf("Divide by zero error = %v; want %v", err, fraction.ErrDivisionByZero)
+	}
+	if s := speed.Dimension().String(); s != "m s^-1" {
+		t.Fatalf("Dimension = %q; want \"m s^-1\"", s)
+	}
+	if _, err := speed.In(Meter); err == nil {
+		t.Fatalf("In with a different dimension should return error")
+	}
+
+	force := New(2, Kilogram).Multiply(NewFromDimension(9.81, Dimension{1, 0, -2}))
+	if got, err := force.In(Newton); err != nil || !almostEqual(got, 19.62) {
+		t.Fatalf("2 kg * 9.81 m/s^2 = %v N (%v); want 19.62", got, err)
+	}
+	if got := New(3, Meter).Pow(2).Dimension(); got != Length.Pow(2) {
+		t.Fatalf("Pow(2) dimension = %v; want m^2", got)
+	}
+}
@@ -0,0 +1,77 @@
+package vector
+
+import (
+	"math"
+
+	"github.com/bogersw/wbmath"
+)
+
+// RoundingMode determines how ToInt converts elements with a fractional part.
+type RoundingMode int
+
+const (
+	// Truncate rounds towards zero, like a Go conversion (and CloneAsInt).
+	Truncate RoundingMode = iota
+	// HalfAwayFromZero rounds to the nearest integer, ties away from zero
+	// (1.5 -> 2, -1.5 -> -2), like math.Round.
+	HalfAwayFromZero
+	// HalfEven rounds to the nearest integer, ties to the even neighbour
+	// (1.5 -> 2, 2.5 -> 2), like math.RoundToEven.
+	HalfEven
+	// Floor rounds towards negative infinity.
+	Floor
+	// Ceiling rounds towards positive infinity.
+	Ceiling
+)
+
+// ============================================================================
+// Type conversion
+// ============================================================================
+
+// ToFloat64 returns a new Vector of type float64 with the elements of the
+// current Vector. It is identical to CloneAsFloat64.
+func (v Vector[T]) ToFloat64() Vector[float64] {
+	return v.CloneAsFloat64()
+}
+
+// ToInt returns a new Vector of type int with the elements of the current
+// Vector, rounded with the specified RoundingMode. Integer elements are
+// converted unchanged. As with any Go conversion, the result for NaN,
+// infinities and elements outside the range of int is unspecified.
+func (v Vector[T]) ToInt(mode RoundingMode) Vector[int] {
+	var round func(float64) float64
+	switch mode {
+	case HalfAwayFromZero:
+		round = math.Round
+	case HalfEven:
+		round = math.RoundToEven
+	case Floor:
+		round = math.Floor
+	case Ceiling:
+		round = math.Ceil
+	default:
+		round = math.Trunc
+	}
+	result := make(Vector[int], len(v), len(v)*2)
+	for i, element := range v {
+		if float64(element) == math.Trunc(float64(element)) {
+			// Integers (and integral floats) convert exactly, also beyond 2^53.
+			result[i] = int(element)
+		} else {
+			result[i] = int(round(float64(element)))
+		}
+	}
+	return result
+}
+
+// Convert returns a new Vector with the elements of v converted to the element
+// type U, e.g. Convert[float32](v). Conversions follow the Go rules, so
+// floats are truncated when converted to an integer type (use ToInt for other
+// rounding modes).
+func Convert[U, T wbmath.SignedNumber](v Vector[T]) Vector[U] {
+	result := make(Vector[U], len(v), len(v)*2)
+	for i, element := range v {
+		result[i] = U(element)
+	}
+	return result
+}
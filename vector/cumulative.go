@@ -0,0 +1,74 @@
+package vector
+
+import "errors"
+
+// ============================================================================
+// Cumulative and rolling operations
+// ============================================================================
+
+// CumSum replaces every element by the sum of the elements up to and
+// including it (in-place, unless a Clone is made beforehand): [1 2 3] gives
+// [1 3 6].
+func (v Vector[T]) CumSum() Vector[T] {
+	for i := 1; i < len(v); i++ {
+		v[i] += v[i-1]
+	}
+	return v
+}
+
+// CumProd replaces every element by the product of the elements up to and
+// including it (in-place, unless a Clone is made beforehand): [1 2 3] gives
+// [1 2 6].
+func (v Vector[T]) CumProd() Vector[T] {
+	for i := 1; i < len(v); i++ {
+		v[i] *= v[i-1]
+	}
+	return v
+}
+
+// Diff returns a new Vector with the first differences v[i+1] - v[i], which
+// has one element less than the current Vector (none if it is empty). It is
+// the inverse of CumSum, apart from the first element.
+func (v Vector[T]) Diff() Vector[T] {
+	if len(v) < 2 {
+		return New[T]()
+	}
+	result := make(Vector[T], len(v)-1, (len(v)-1)*2)
+	for i := range result {
+		result[i] = v[i+1] - v[i]
+	}
+	return result
+}
+
+// RollingSum returns a new Vector with the sums of all windows of `window`
+// consecutive elements: element i is the sum of v[i] ... v[i+window-1], so
+// the result has len(v) - window + 1 elements. Returns an error if the window
+// is smaller than 1 or larger than the Vector.
+func (v Vector[T]) RollingSum(window int) (Vector[T], error) {
+	if window < 1 || window > len(v) {
+		return nil, errors.New("window must be between 1 and the length of the vector")
+	}
+	result := make(Vector[T], len(v)-window+1, (len(v)-window+1)*2)
+	var sum T
+	for i := 0; i < window; i++ {
+		sum += v[i]
+	}
+	result[0] = sum
+	for i := 1; i < len(result); i++ {
+		// Slide the window: add the new element and drop the oldest one.
+		sum += v[i+window-1] - v[i-1]
+		result[i] = sum
+	}
+	return result, nil
+}
+
+// RollingMean returns a new Vector of type float64 with the means of all
+// windows of `window` consecutive elements (see RollingSum). Returns an error
+// if the window is smaller than 1 or larger than the Vector.
+func (v Vector[T]) RollingMean(window int) (Vector[float64], error) {
+	sums, err := v.CloneAsFloat64().RollingSum(window)
+	if err != nil {
+		return nil, err
+	}
+	return sums.Scale(1 / float64(window)), nil
+}
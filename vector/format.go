@@ -0,0 +1,76 @@
+package vector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+)
+
+// FormatOptions controls how Format prints the elements of a Vector.
+type FormatOptions struct {
+	// Precision is the number of decimal places of float elements; a negative
+	// value prints the shortest representation that round-trips, like fmt's
+	// %v. Integer elements ignore the precision.
+	Precision int
+	// Separator is written between the elements.
+	Separator string
+	// MaxElements is the number of elements printed before a long Vector is
+	// truncated, followed by the total number of elements, e.g.
+	// "1, 2, 3, … 1000 elements". Zero or a negative value disables
+	// truncation.
+	MaxElements int
+}
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+// String implements the fmt.Stringer interface: the elements between square
+// brackets, separated by commas, e.g. "[1, 2.5, -3]". Float elements are
+// printed with the shortest representation that round-trips, and only the
+// first 10 elements of a longer Vector are printed, followed by the total
+// number of elements: "[1, 2, 3, … 1000 elements]". Use Format for other
+// options.
+func (v Vector[T]) String() string {
+	return "[" + Format(v, FormatOptions{Precision: -1, Separator: ", ", MaxElements: 10}) + "]"
+}
+
+// Format returns the elements of v formatted with the specified options,
+// without brackets, e.g. "1.00;2.50" for
+// Format(New(1, 2.5), FormatOptions{Precision: 2, Separator: ";"}).
+func Format[T wbmath.SignedNumber](v Vector[T], options FormatOptions) string {
+	truncated := options.MaxElements > 0 && len(v) > options.MaxElements
+	elements := v
+	if truncated {
+		elements = v[:options.MaxElements]
+	}
+	var builder strings.Builder
+	for i, element := range elements {
+		if i > 0 {
+			builder.WriteString(options.Separator)
+		}
+		builder.WriteString(formatElement(element, options.Precision))
+	}
+	if truncated {
+		fmt.Fprintf(&builder, "%s… %d elements", options.Separator, len(v))
+	}
+	return builder.String()
+}
+
+// formatElement formats a single element with the specified precision (see
+// FormatOptions).
+func formatElement[T wbmath.SignedNumber](element T, precision int) string {
+	format := byte('f')
+	if precision < 0 {
+		format = 'g'
+	}
+	switch value := any(element).(type) {
+	case float32:
+		return strconv.FormatFloat(float64(value), format, precision, 32)
+	case float64:
+		return strconv.FormatFloat(value, format, precision, 64)
+	}
+	return strconv.FormatInt(int64(element), 10)
+}
@@ -0,0 +1,81 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ============================================================================
+// Histograms and binning
+// ============================================================================
+
+// Histogram divides the range from the smallest to the largest finite element
+// into `bins` bins of equal width and counts the elements in every bin, like
+// NumPy's histogram. It returns the bins+1 bin edges and the bins counts. Bin
+// i contains the elements x with edges[i] <= x < edges[i+1]; the last bin
+// also contains the largest element. If all elements are equal, the range is
+// that value ± 0.5; without finite elements it is [0, 1]. NaN and ±Inf
+// elements are not counted. Returns an error if bins is smaller than 1 or if
+// the range can't be divided into bins (e.g. because its width overflows).
+func (v Vector[T]) Histogram(bins int) (edges Vector[float64], counts Vector[int], err error) {
+	if bins < 1 {
+		return nil, nil, errors.New("at least one bin is required")
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, element := range v {
+		if x := float64(element); !math.IsNaN(x) && !math.IsInf(x, 0) {
+			low, high = math.Min(low, x), math.Max(high, x)
+		}
+	}
+	switch {
+	case low > high:
+		low, high = 0, 1
+	case low == high:
+		low, high = low-0.5, high+0.5
+	}
+	edges = Linspace(low, high, bins+1)
+	indices, err := v.Bin(edges)
+	if err != nil {
+		return nil, nil, fmt.Errorf("histogram of [%g, %g]: %w", low, high, err)
+	}
+	counts = NewFromValue(0, bins)
+	for _, index := range indices {
+		if index >= 0 {
+			counts[index]++
+		}
+	}
+	return edges, counts, nil
+}
+
+// Bin returns, for every element, the index of the bin it falls into: index i
+// if edges[i] <= x < edges[i+1], with the last bin also containing the last
+// edge (as in Histogram). Elements outside the edges and NaN elements get
+// index -1. Returns an error if there are fewer than two edges or if they are
+// not strictly increasing.
+func (v Vector[T]) Bin(edges Vector[float64]) (Vector[int], error) {
+	if len(edges) < 2 {
+		return nil, errors.New("at least two bin edges are required")
+	}
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i] > edges[i-1]) {
+			return nil, errors.New("bin edges must be strictly increasing")
+		}
+	}
+	last := len(edges) - 1
+	indices := make(Vector[int], len(v), len(v)*2)
+	for i, element := range v {
+		x := float64(element)
+		switch {
+		case math.IsNaN(x) || x < edges[0] || x > edges[last]:
+			indices[i] = -1
+		case x == edges[last]:
+			indices[i] = last - 1
+		default:
+			// The first edge larger than x closes the bin.
+			indices[i] = sort.Search(len(edges), func(j int) bool { return edges[j] > x }) - 1
+		}
+	}
+	return indices, nil
+}
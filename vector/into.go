@@ -0,0 +1,96 @@
+package vector
+
+import (
+	"errors"
+
+	"github.com/bogersw/wbmath"
+)
+
+// ============================================================================
+// Copy variants
+// ============================================================================
+
+// AddC is the copying variant of Add: it returns a new Vector with the sum and
+// leaves the current Vector unchanged.
+func (v Vector[T]) AddC(other Vector[T], offset int) Vector[T] {
+	return v.Clone().Add(other, offset)
+}
+
+// SubtractC is the copying variant of Subtract: it returns a new Vector with
+// the difference and leaves the current Vector unchanged.
+func (v Vector[T]) SubtractC(other Vector[T], offset int) Vector[T] {
+	return v.Clone().Subtract(other, offset)
+}
+
+// MultiplyC is the copying variant of Multiply: it returns a new Vector with
+// the product and leaves the current Vector unchanged.
+func (v Vector[T]) MultiplyC(other Vector[T], offset int) Vector[T] {
+	return v.Clone().Multiply(other, offset)
+}
+
+// DivideC is the copying variant of Divide: it returns a new Vector with the
+// quotient and leaves the current Vector unchanged.
+func (v Vector[T]) DivideC(other Vector[T], offset int) Vector[T] {
+	return v.Clone().Divide(other, offset)
+}
+
+// ScaleC is the copying variant of Scale: it returns a new scaled Vector and
+// leaves the current Vector unchanged.
+func (v Vector[T]) ScaleC(factor T) Vector[T] {
+	return v.Clone().Scale(factor)
+}
+
+// ============================================================================
+// Destination variants
+// ============================================================================
+
+// AddInto stores the element-wise sum a + b in dst without allocating. dst
+// may be a or b itself. Returns an error, without modifying dst, if the three
+// vectors don't have the same length.
+func AddInto[T wbmath.SignedNumber](dst, a, b Vector[T]) error {
+	return into(dst, a, b, add)
+}
+
+// SubtractInto stores the element-wise difference a - b in dst without
+// allocating. dst may be a or b itself. Returns an error, without modifying
+// dst, if the three vectors don't have the same length.
+func SubtractInto[T wbmath.SignedNumber](dst, a, b Vector[T]) error {
+	return into(dst, a, b, subtract)
+}
+
+// MultiplyInto stores the element-wise product a * b in dst without
+// allocating. dst may be a or b itself. Returns an error, without modifying
+// dst, if the three vectors don't have the same length.
+func MultiplyInto[T wbmath.SignedNumber](dst, a, b Vector[T]) error {
+	return into(dst, a, b, multiply)
+}
+
+// DivideInto stores the element-wise quotient a / b in dst without
+// allocating. dst may be a or b itself. As with Divide, an integer division
+// by zero panics. Returns an error, without modifying dst, if the three
+// vectors don't have the same length.
+func DivideInto[T wbmath.SignedNumber](dst, a, b Vector[T]) error {
+	return into(dst, a, b, divide)
+}
+
+// ScaleInto stores a multiplied by `factor` in dst without allocating. dst
+// may be a itself. Returns an error, without modifying dst, if the vectors
+// don't have the same length.
+func ScaleInto[T wbmath.SignedNumber](dst, a Vector[T], factor T) error {
+	if len(dst) != len(a) {
+		return errors.New("vectors must have the same length")
+	}
+	for i, element := range a {
+		dst[i] = element * factor
+	}
+	return nil
+}
+
+// into checks the lengths and applies the element-wise operation (see apply).
+func into[T wbmath.SignedNumber](dst, a, b Vector[T], op arithmetic) error {
+	if len(dst) != len(a) || len(a) != len(b) {
+		return errors.New("vectors must have the same length")
+	}
+	apply(dst, a, b, op)
+	return nil
+}
@@ -0,0 +1,140 @@
+package vector
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bogersw/wbmath"
+)
+
+// ============================================================================
+// JSON
+// ============================================================================
+
+// MarshalJSON implements the json.Marshaler interface: the Vector is encoded
+// as a JSON array of numbers, e.g. [1,2.5,-3], and a nil Vector as null.
+// Returns an error if an element is NaN or infinite, which JSON can't
+// represent.
+func (v Vector[T]) MarshalJSON() ([]byte, error) {
+	if v == nil {
+		return []byte("null"), nil
+	}
+	var buffer bytes.Buffer
+	buffer.WriteByte('[')
+	for i, element := range v {
+		if math.IsNaN(float64(element)) || math.IsInf(float64(element), 0) {
+			return nil, fmt.Errorf("unsupported value %v at index %d", element, i)
+		}
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteString(formatElement(element, -1))
+	}
+	buffer.WriteByte(']')
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface: it accepts a JSON
+// array of numbers (null gives a nil Vector) and replaces the contents of the
+// Vector. Returns an error if an element is not a number or doesn't fit in the
+// element type, e.g. 2.5 for a Vector of type int.
+func (v *Vector[T]) UnmarshalJSON(data []byte) error {
+	var numbers []json.Number
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return err
+	}
+	if numbers == nil {
+		*v = nil
+		return nil
+	}
+	result := make(Vector[T], len(numbers), len(numbers)*2)
+	for i, number := range numbers {
+		element, err := parseElement[T](number.String())
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		result[i] = element
+	}
+	*v = result
+	return nil
+}
+
+// ============================================================================
+// CSV
+// ============================================================================
+
+// ToCSV writes the Vector to w as CSV with one element per line (a single
+// column), in the shortest representation that round-trips.
+func (v Vector[T]) ToCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	for _, element := range v {
+		if err := writer.Write([]string{formatElement(element, -1)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// FromCSV is a constructor function that reads a Vector from CSV data: the
+// fields of all records in order, so both a single column and a single row
+// are accepted. Spaces around the fields are ignored. Returns an error if the
+// data is not valid CSV or if a field is not a number of the element type T.
+func FromCSV[T wbmath.SignedNumber](r io.Reader) (Vector[T], error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	result := New[T]()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		for column, field := range record {
+			element, err := parseElement[T](strings.TrimSpace(field))
+			if err != nil {
+				return nil, fmt.Errorf("line %d, field %d: %w", line, column+1, err)
+			}
+			result = result.Append(element)
+		}
+	}
+}
+
+// parseElement parses a number of the element type T.
+func parseElement[T wbmath.SignedNumber](s string) (T, error) {
+	var zero T
+	switch any(zero).(type) {
+	case float32:
+		value, err := strconv.ParseFloat(s, 32)
+		return T(value), err
+	case float64:
+		value, err := strconv.ParseFloat(s, 64)
+		return T(value), err
+	case int8:
+		value, err := strconv.ParseInt(s, 10, 8)
+		return T(value), err
+	case int16:
+		value, err := strconv.ParseInt(s, 10, 16)
+		return T(value), err
+	case int32:
+		value, err := strconv.ParseInt(s, 10, 32)
+		return T(value), err
+	case int64:
+		value, err := strconv.ParseInt(s, 10, 64)
+		return T(value), err
+	default:
+		value, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		return T(value), err
+	}
+}
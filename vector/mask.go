@@ -0,0 +1,192 @@
+package vector
+
+import "errors"
+
+// Mask is the result of an element-wise comparison of a Vector: element i is
+// true if the comparison holds for element i of the Vector. A Mask selects
+// elements with Select and Where, e.g. v.Select(v.Gt(0)) returns the positive
+// elements of v.
+type Mask []bool
+
+// ============================================================================
+// Comparisons
+// ============================================================================
+
+// Gt returns a Mask that is true where the element is greater than `value`.
+func (v Vector[T]) Gt(value T) Mask {
+	return v.compare(func(x T) bool { return x > value })
+}
+
+// Ge returns a Mask that is true where the element is greater than or equal
+// to `value`.
+func (v Vector[T]) Ge(value T) Mask {
+	return v.compare(func(x T) bool { return x >= value })
+}
+
+// Lt returns a Mask that is true where the element is less than `value`.
+func (v Vector[T]) Lt(value T) Mask {
+	return v.compare(func(x T) bool { return x < value })
+}
+
+// Le returns a Mask that is true where the element is less than or equal to
+// `value`.
+func (v Vector[T]) Le(value T) Mask {
+	return v.compare(func(x T) bool { return x <= value })
+}
+
+// Eq returns a Mask that is true where the element is equal to `value`.
+func (v Vector[T]) Eq(value T) Mask {
+	return v.compare(func(x T) bool { return x == value })
+}
+
+// GtVector returns a Mask that is true where the element is greater than the
+// corresponding element of the specified Vector. Returns an error if the
+// vectors don't have the same length.
+func (v Vector[T]) GtVector(other Vector[T]) (Mask, error) {
+	return v.compareVector(other, func(x, y T) bool { return x > y })
+}
+
+// GeVector returns a Mask that is true where the element is greater than or
+// equal to the corresponding element of the specified Vector. Returns an
+// error if the vectors don't have the same length.
+func (v Vector[T]) GeVector(other Vector[T]) (Mask, error) {
+	return v.compareVector(other, func(x, y T) bool { return x >= y })
+}
+
+// LtVector returns a Mask that is true where the element is less than the
+// corresponding element of the specified Vector. Returns an error if the
+// vectors don't have the same length.
+func (v Vector[T]) LtVector(other Vector[T]) (Mask, error) {
+	return v.compareVector(other, func(x, y T) bool { return x < y })
+}
+
+// LeVector returns a Mask that is true where the element is less than or
+// equal to the corresponding element of the specified Vector. Returns an
+// error if the vectors don't have the same length.
+func (v Vector[T]) LeVector(other Vector[T]) (Mask, error) {
+	return v.compareVector(other, func(x, y T) bool { return x <= y })
+}
+
+// EqVector returns a Mask that is true where the element is equal to the
+// corresponding element of the specified Vector. Returns an error if the
+// vectors don't have the same length.
+func (v Vector[T]) EqVector(other Vector[T]) (Mask, error) {
+	return v.compareVector(other, func(x, y T) bool { return x == y })
+}
+
+// ============================================================================
+// Selection
+// ============================================================================
+
+// Select returns a new Vector with the elements where the Mask is true, in
+// their original order. Returns an error if the Mask and the Vector don't have
+// the same length.
+func (v Vector[T]) Select(mask Mask) (Vector[T], error) {
+	if len(mask) != len(v) {
+		return nil, errors.New("mask and vector must have the same length")
+	}
+	result := make(Vector[T], 0, mask.CountTrue())
+	for i, keep := range mask {
+		if keep {
+			result = append(result, v[i])
+		}
+	}
+	return result, nil
+}
+
+// Where returns a new Vector with the elements where the Mask is true and
+// `fill` elsewhere, e.g. v.Where(v.Ge(0), 0) clips negative elements to zero.
+// Returns an error if the Mask and the Vector don't have the same length.
+func (v Vector[T]) Where(mask Mask, fill T) (Vector[T], error) {
+	if len(mask) != len(v) {
+		return nil, errors.New("mask and vector must have the same length")
+	}
+	result := v.Clone()
+	for i, keep := range mask {
+		if !keep {
+			result[i] = fill
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Mask methods
+// ============================================================================
+
+// CountTrue returns the number of true elements of the Mask.
+func (m Mask) CountTrue() int {
+	count := 0
+	for _, element := range m {
+		if element {
+			count++
+		}
+	}
+	return count
+}
+
+// Any returns true if at least one element of the Mask is true.
+func (m Mask) Any() bool {
+	return m.CountTrue() > 0
+}
+
+// All returns true if all elements of the Mask are true (also for an empty
+// Mask).
+func (m Mask) All() bool {
+	return m.CountTrue() == len(m)
+}
+
+// Not inverts every element of the Mask (in-place) and returns it.
+func (m Mask) Not() Mask {
+	for i := range m {
+		m[i] = !m[i]
+	}
+	return m
+}
+
+// And combines the Mask with the specified Mask with a logical AND (in-place)
+// and returns it. Returns an error if the masks don't have the same length.
+func (m Mask) And(other Mask) (Mask, error) {
+	if len(m) != len(other) {
+		return m, errors.New("masks must have the same length")
+	}
+	for i := range m {
+		m[i] = m[i] && other[i]
+	}
+	return m, nil
+}
+
+// Or combines the Mask with the specified Mask with a logical OR (in-place)
+// and returns it. Returns an error if the masks don't have the same length.
+func (m Mask) Or(other Mask) (Mask, error) {
+	if len(m) != len(other) {
+		return m, errors.New("masks must have the same length")
+	}
+	for i := range m {
+		m[i] = m[i] || other[i]
+	}
+	return m, nil
+}
+
+// ============================================================================
+// Private methods
+// ============================================================================
+
+func (v Vector[T]) compare(holds func(T) bool) Mask {
+	mask := make(Mask, len(v))
+	for i, element := range v {
+		mask[i] = holds(element)
+	}
+	return mask
+}
+
+func (v Vector[T]) compareVector(other Vector[T], holds func(T, T) bool) (Mask, error) {
+	if len(v) != len(other) {
+		return nil, errors.New("vectors must have the same length")
+	}
+	mask := make(Mask, len(v))
+	for i, element := range v {
+		mask[i] = holds(element, other[i])
+	}
+	return mask, nil
+}
@@ -0,0 +1,83 @@
+package vector
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/bogersw/wbmath"
+)
+
+// minChunk is the smallest number of elements a worker processes: for
+// shorter vectors the goroutine overhead outweighs the gain.
+const minChunk = 1 << 14
+
+// ParallelVector runs reductions of a Vector concurrently, see Parallel.
+type ParallelVector[T wbmath.SignedNumber] struct {
+	vector  Vector[T]
+	workers int
+}
+
+// Parallel returns a ParallelVector that computes the reductions Sum,
+// DotProduct and Magnitude with up to `workers` goroutines, each reducing a
+// contiguous chunk of the Vector: v.Parallel(8).Sum(). If workers is smaller
+// than 1, runtime.GOMAXPROCS(0) workers are used. Vectors too small to
+// benefit are reduced on the calling goroutine. For floats the result can
+// differ from the sequential one in the last bits, because the partial sums
+// are added in a different order; it only depends on the number of workers.
+func (v Vector[T]) Parallel(workers int) ParallelVector[T] {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return ParallelVector[T]{vector: v, workers: workers}
+}
+
+// Sum returns the sum of the elements, see Vector.Sum.
+func (p ParallelVector[T]) Sum() T {
+	return reduce(p, func(start, end int) T {
+		return p.vector[start:end].Sum()
+	})
+}
+
+// DotProduct returns the dot product with the specified Vector, see
+// Vector.DotProduct. Returns an error if the vectors don't have the same
+// length.
+func (p ParallelVector[T]) DotProduct(other Vector[T]) (T, error) {
+	if len(p.vector) != len(other) {
+		return 0, errors.New("vectors must have the same length")
+	}
+	return reduce(p, func(start, end int) T {
+		return dot(p.vector[start:end], other[start:end])
+	}), nil
+}
+
+// Magnitude returns the Euclidean length, see Vector.Magnitude.
+func (p ParallelVector[T]) Magnitude() float64 {
+	product, _ := p.DotProduct(p.vector)
+	return math.Sqrt(float64(product))
+}
+
+// reduce splits the Vector into one contiguous chunk per worker, reduces the
+// chunks concurrently with `partial` and adds the partial results in order.
+func reduce[T wbmath.SignedNumber](p ParallelVector[T], partial func(start, end int) T) T {
+	n := len(p.vector)
+	workers := min(p.workers, max(n/minChunk, 1))
+	if workers == 1 {
+		return partial(0, n)
+	}
+	results := make([]T, workers)
+	var group sync.WaitGroup
+	for w := range workers {
+		start, end := w*n/workers, (w+1)*n/workers
+		group.Go(func() {
+			results[w] = partial(start, end)
+		})
+	}
+	group.Wait()
+	var total T
+	for _, result := range results {
+		total += result
+	}
+	return total
+}
@@ -0,0 +1,56 @@
+package vector
+
+import (
+	"errors"
+
+	"github.com/bogersw/wbmath/internal/qr"
+)
+
+// ============================================================================
+// Polynomials
+// ============================================================================
+
+// PolyFit fits the polynomial y ≈ c0 + c1·x + ... + cd·x^d of the specified
+// degree to the points (x[i], y[i]) by least squares and returns the
+// coefficients in increasing order of the power (coefficients[i] belongs to
+// x^i, as in PolyVal and the fit package). The least-squares problem is solved
+// with a QR decomposition, which is more accurate than the normal equations.
+// Returns an error if the lengths of x and y differ, if degree is negative or
+// if there are fewer than degree + 1 distinct x values.
+func PolyFit(x, y Vector[float64], degree int) (Vector[float64], error) {
+	if len(x) != len(y) {
+		return nil, errors.New("x and y must have the same length")
+	}
+	if degree < 0 {
+		return nil, errors.New("degree must not be negative")
+	}
+	if len(x) < degree+1 {
+		return nil, errors.New("fewer points than coefficients")
+	}
+	// The columns of the Vandermonde matrix: columns[j][i] = x[i]^j.
+	columns := make([][]float64, degree+1)
+	columns[0] = NewFromValue(1.0, len(x))
+	for j := 1; j <= degree; j++ {
+		columns[j] = Vector[float64](columns[j-1]).Clone().Multiply(x, 0)
+	}
+	// qty becomes Q^T y.
+	qty := y.Clone()
+	r, err := qr.Householder(columns, qty)
+	if err != nil {
+		return nil, errors.New("too few distinct x values for the degree")
+	}
+	return qr.Substitute(r, qty), nil
+}
+
+// PolyVal returns the value of the polynomial with the specified coefficients
+// (coefficients[i] belongs to x^i) at every element of x, as a new Vector,
+// using Horner's method. An empty list of coefficients is the zero polynomial.
+func PolyVal(coefficients, x Vector[float64]) Vector[float64] {
+	return x.Clone().Map(func(value float64) float64 {
+		result := 0.0
+		for i := len(coefficients) - 1; i >= 0; i-- {
+			result = result*value + coefficients[i]
+		}
+		return result
+	})
+}
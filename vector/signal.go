@@ -0,0 +1,166 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ConvolutionMode determines the length of the result of Convolve, like the
+// mode of NumPy's convolve.
+type ConvolutionMode int
+
+const (
+	// Full returns the convolution at every point where the signal and the
+	// kernel overlap: len(v) + len(kernel) - 1 elements.
+	Full ConvolutionMode = iota
+	// Same returns max(len(v), len(kernel)) elements, centered on the Full
+	// result.
+	Same
+	// Valid returns only the points where the signal and the kernel overlap
+	// completely: max(len(v), len(kernel)) - min(len(v), len(kernel)) + 1
+	// elements.
+	Valid
+)
+
+// ============================================================================
+// Convolution and correlation
+// ============================================================================
+
+// Convolve returns the discrete linear convolution of the Vector with the
+// kernel as a new Vector of type float64: element k of the Full result is the
+// sum of v[i]·kernel[k-i] over all valid i. The mode selects the part of the
+// result that is returned (see ConvolutionMode). For example
+// v.Convolve(New(1/3.0, 1/3.0, 1/3.0), Valid) gives the moving average over
+// 3 elements. Returns an empty Vector if either Vector is empty.
+func (v Vector[T]) Convolve(kernel Vector[float64], mode ConvolutionMode) Vector[float64] {
+	if len(v) == 0 || len(kernel) == 0 {
+		return New[float64]()
+	}
+	full := NewFromValue(0.0, len(v)+len(kernel)-1)
+	for i, element := range v {
+		x := float64(element)
+		row := full[i : i+len(kernel)]
+		for j, weight := range kernel {
+			row[j] += x * weight
+		}
+	}
+	shorter, longer := min(len(v), len(kernel)), max(len(v), len(kernel))
+	switch mode {
+	case Same:
+		start := (shorter - 1) / 2
+		return New(full[start : start+longer]...)
+	case Valid:
+		return New(full[shorter-1 : longer]...)
+	}
+	return full
+}
+
+// CrossCorrelate returns the full cross-correlation of the Vector with the
+// specified Vector as a new Vector of type float64, like NumPy's correlate
+// with mode "full": element k is the sum of v[i+k-(len(other)-1)]·other[i]
+// over all valid i, so the element at index len(other) - 1 is the
+// correlation at lag zero (the dot product if both have the same length).
+// Returns an empty Vector if either Vector is empty.
+func (v Vector[T]) CrossCorrelate(other Vector[T]) Vector[float64] {
+	return v.Convolve(other.CloneAsFloat64().Reverse(), Full)
+}
+
+// ============================================================================
+// Smoothing
+// ============================================================================
+
+// EdgeMode determines how the moving averages SMA and WMA handle the first
+// elements, for which the window is not yet full.
+type EdgeMode int
+
+const (
+	// Shorter drops the incomplete windows: the result has len(v) - window + 1
+	// elements (like RollingMean).
+	Shorter EdgeMode = iota
+	// PadNaN keeps the length of the Vector and sets the elements of the
+	// incomplete windows to NaN.
+	PadNaN
+	// Partial keeps the length of the Vector and averages the incomplete
+	// windows over the elements that are available.
+	Partial
+)
+
+// SMA returns the simple moving average over `window` elements as a new Vector
+// of type float64: element i is the mean of the window that ends at element
+// i. The edge mode determines the result for the first window - 1 elements.
+// Returns an error if the window is smaller than 1 or larger than the Vector.
+func (v Vector[T]) SMA(window int, edge EdgeMode) (Vector[float64], error) {
+	if window < 1 || window > len(v) {
+		return nil, errors.New("window must be between 1 and the length of the vector")
+	}
+	return v.movingAverage(NewFromValue(1.0, window), edge)
+}
+
+// WMA returns the weighted moving average with the specified weights as a new
+// Vector of type float64: the window has len(weights) elements, and the last
+// weight applies to the newest element, so New(1.0, 2, 3) gives the newest
+// element three times the weight of the oldest one. Every window is divided by
+// the sum of its weights. The edge mode determines the result for the first
+// len(weights) - 1 elements. Returns an error if there are no weights or more
+// weights than elements, or if the weights sum to zero; with Partial also if
+// the last weights used for an incomplete window sum to zero, e.g. New(1.0, 0).
+func (v Vector[T]) WMA(weights Vector[float64], edge EdgeMode) (Vector[float64], error) {
+	if len(weights) < 1 || len(weights) > len(v) {
+		return nil, errors.New("number of weights must be between 1 and the length of the vector")
+	}
+	if weights.Sum() == 0 {
+		return nil, errors.New("weights must not sum to zero")
+	}
+	return v.movingAverage(weights, edge)
+}
+
+// EMA returns the exponential moving average with smoothing factor alpha as a
+// new Vector of type float64 with the same length: the first element is
+// copied and every next element is alpha·x + (1 - alpha)·previous. A larger
+// alpha follows the data more closely. Returns an error if alpha is not in
+// (0, 1].
+func (v Vector[T]) EMA(alpha float64) (Vector[float64], error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, fmt.Errorf("alpha %v must be in (0, 1]", alpha)
+	}
+	result := v.CloneAsFloat64()
+	for i := 1; i < len(result); i++ {
+		result[i] = alpha*result[i] + (1-alpha)*result[i-1]
+	}
+	return result, nil
+}
+
+// movingAverage returns the weighted average of every window of len(weights)
+// elements, handling the incomplete windows according to the edge mode.
+func (v Vector[T]) movingAverage(weights Vector[float64], edge EdgeMode) (Vector[float64], error) {
+	if edge != Shorter && edge != PadNaN && edge != Partial {
+		return nil, fmt.Errorf("unknown edge mode %d", edge)
+	}
+	window := len(weights)
+	result := make(Vector[float64], 0, len(v)*2)
+	for end := 1; end <= len(v); end++ {
+		if end < window {
+			switch edge {
+			case Shorter:
+				continue
+			case PadNaN:
+				result = append(result, math.NaN())
+				continue
+			}
+		}
+		// The window v[start:end] gets the last weights if it is incomplete.
+		start := max(end-window, 0)
+		used := weights[window-(end-start):]
+		sum, total := 0.0, 0.0
+		for i, weight := range used {
+			sum += weight * float64(v[start+i])
+			total += weight
+		}
+		if total == 0 {
+			return nil, fmt.Errorf("last %d weights must not sum to zero", len(used))
+		}
+		result = append(result, sum/total)
+	}
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package vector
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// ============================================================================
+// Slicing, appending and concatenation
+// ============================================================================
+
+// Slice returns a new Vector with the elements from index `start` up to (but
+// not including) index `end`. Unlike a slice expression, the result is a copy
+// that doesn't share memory with the current Vector. Returns an error if the
+// indices are out of range or if start > end.
+func (v Vector[T]) Slice(start, end int) (Vector[T], error) {
+	if start < 0 || end > len(v) || start > end {
+		return nil, fmt.Errorf("invalid slice [%d:%d] of vector with length %d", start, end, len(v))
+	}
+	return New(v[start:end]...), nil
+}
+
+// Append adds the specified values at the end of the Vector. Like the built-in
+// append, the result may or may not share memory with the current Vector, so
+// always use the returned Vector. When the capacity is exceeded, the new
+// Vector gets twice the capacity it needs.
+func (v Vector[T]) Append(values ...T) Vector[T] {
+	result := v.grow(len(values))
+	return append(result, values...)
+}
+
+// Insert inserts the specified values before the element at `index` (at the
+// end if index equals the length). Like slices.Insert, the elements are moved
+// in-place when the capacity suffices, so always use the returned Vector.
+// Returns an error if the index is out of range.
+func (v Vector[T]) Insert(index int, values ...T) (Vector[T], error) {
+	if index < 0 || index > len(v) {
+		return v, fmt.Errorf("index %d out of range for vector with length %d", index, len(v))
+	}
+	result := v.grow(len(values))[:len(v)+len(values)]
+	copy(result[index+len(values):], v[index:])
+	copy(result[index:], values)
+	return result, nil
+}
+
+// Remove removes the element at `index` (in-place) and returns the shortened
+// Vector. Returns an error if the index is out of range.
+func (v Vector[T]) Remove(index int) (Vector[T], error) {
+	if index < 0 || index >= len(v) {
+		return v, fmt.Errorf("index %d out of range for vector with length %d", index, len(v))
+	}
+	copy(v[index:], v[index+1:])
+	return v[:len(v)-1], nil
+}
+
+// Concat returns a new Vector with the elements of the current Vector
+// followed by the elements of the specified Vector. Neither Vector is
+// modified.
+func (v Vector[T]) Concat(other Vector[T]) Vector[T] {
+	result := make(Vector[T], len(v)+len(other), (len(v)+len(other))*2)
+	copy(result, v)
+	copy(result[len(v):], other)
+	return result
+}
+
+// grow returns the Vector with room for n more elements: the Vector itself if
+// its capacity suffices, otherwise a copy with twice the needed capacity.
+func (v Vector[T]) grow(n int) Vector[T] {
+	if len(v)+n <= cap(v) {
+		return v
+	}
+	result := make(Vector[T], len(v), (len(v)+n)*2)
+	copy(result, v)
+	return result
+}
+
+// ============================================================================
+// Rearranging
+// ============================================================================
+
+// Reverse reverses the order of the elements (in-place, unless a Clone is made
+// beforehand).
+func (v Vector[T]) Reverse() Vector[T] {
+	slices.Reverse(v)
+	return v
+}
+
+// Shuffle randomly permutes the elements with the specified generator
+// (in-place, unless a Clone is made beforehand). The result is deterministic
+// for a seeded generator, e.g. rand.New(rand.NewPCG(1, 2)).
+func (v Vector[T]) Shuffle(rng *rand.Rand) Vector[T] {
+	rng.Shuffle(len(v), func(i, j int) { v[i], v[j] = v[j], v[i] })
+	return v
+}
+
+// Shift moves the elements n positions to the right (to the left for negative
+// n) and fills the vacated positions with `fill` (in-place, unless a Clone is
+// made beforehand). Elements shifted past the end are dropped: shifting
+// [1 2 3 4] by 1 with fill 0 gives [0 1 2 3].
+func (v Vector[T]) Shift(n int, fill T) Vector[T] {
+	if n >= len(v) || n <= -len(v) {
+		return v.Map(func(T) T { return fill })
+	}
+	if n > 0 {
+		copy(v[n:], v)
+		for i := 0; i < n; i++ {
+			v[i] = fill
+		}
+	} else if n < 0 {
+		copy(v, v[-n:])
+		for i := len(v) + n; i < len(v); i++ {
+			v[i] = fill
+		}
+	}
+	return v
+}
+
+// Rotate moves the elements n positions to the right (to the left for
+// negative n), wrapping the elements shifted past the end around to the start
+// (in-place, unless a Clone is made beforehand): rotating [1 2 3 4] by 1
+// gives [4 1 2 3].
+func (v Vector[T]) Rotate(n int) Vector[T] {
+	if len(v) == 0 {
+		return v
+	}
+	n = ((n % len(v)) + len(v)) % len(v)
+	// A right rotation by n is three reversals.
+	slices.Reverse(v)
+	slices.Reverse(v[:n])
+	slices.Reverse(v[n:])
+	return v
+}
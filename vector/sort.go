@@ -0,0 +1,63 @@
+package vector
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ============================================================================
+// Sorting and ranking
+// ============================================================================
+
+// Sort sorts the elements of the Vector in ascending or descending order
+// (in-place, unless a Clone is made beforehand). NaN elements are placed
+// first in ascending order and last in descending order.
+func (v Vector[T]) Sort(ascending bool) Vector[T] {
+	if ascending {
+		slices.Sort(v)
+	} else {
+		slices.SortFunc(v, func(a, b T) int { return cmp.Compare(b, a) })
+	}
+	return v
+}
+
+// SortedCopy returns a new Vector with the elements sorted in ascending order.
+// The current Vector is not modified.
+func (v Vector[T]) SortedCopy() Vector[T] {
+	return v.Clone().Sort(true)
+}
+
+// ArgSort returns the indices that sort the Vector in ascending order: v[i]
+// for i in ArgSort() gives the sorted elements. Equal elements keep their
+// original order.
+func (v Vector[T]) ArgSort() []int {
+	order := make([]int, len(v))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(v[a], v[b])
+	})
+	return order
+}
+
+// Rank returns the 1-based ranks of the elements in ascending order as a
+// Vector of type float64, averaging the ranks of ties: [10 30 20 20] gives
+// [1 4 2.5 2.5].
+func (v Vector[T]) Rank() Vector[float64] {
+	order := v.ArgSort()
+	ranks := NewFromValue(0.0, len(v))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && v[order[end]] == v[order[start]] {
+			end++
+		}
+		// Positions start..end-1 share the average rank.
+		average := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			ranks[i] = average
+		}
+		start = end
+	}
+	return ranks
+}
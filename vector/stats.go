@@ -0,0 +1,425 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/bogersw/wbmath"
+)
+
+// ============================================================================
+// Descriptive statistics
+// ============================================================================
+
+// Mean returns the arithmetic mean of the elements of a Vector. Returns NaN
+// if the Vector is empty.
+func (v Vector[T]) Mean() float64 {
+	if len(v) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, element := range v {
+		sum += float64(element)
+	}
+	return sum / float64(len(v))
+}
+
+// Median returns the middle element of the sorted elements of a Vector, or
+// the mean of the two middle elements if the number of elements is even. The
+// Vector itself is not modified. Returns NaN if the Vector is empty.
+func (v Vector[T]) Median() float64 {
+	if len(v) == 0 {
+		return math.NaN()
+	}
+	sorted := v.CloneAsFloat64()
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+// Variance returns the sample variance of the elements of a Vector: the sum
+// of the squared deviations from the mean divided by n - 1 (like the stats
+// package). Returns NaN if the Vector has fewer than 2 elements.
+func (v Vector[T]) Variance() float64 {
+	if len(v) < 2 {
+		return math.NaN()
+	}
+	mean := v.Mean()
+	sum := 0.0
+	for _, element := range v {
+		deviation := float64(element) - mean
+		sum += deviation * deviation
+	}
+	return sum / float64(len(v)-1)
+}
+
+// StdDev returns the sample standard deviation of the elements of a Vector
+// (the square root of Variance). Returns NaN if the Vector has fewer than 2
+// elements.
+func (v Vector[T]) StdDev() float64 {
+	return math.Sqrt(v.Variance())
+}
+
+// Min returns the smallest element of a Vector. NaN elements are ignored,
+// unless all elements are NaN. Returns an error if the Vector is empty.
+func (v Vector[T]) Min() (T, error) {
+	index := v.ArgMin()
+	if index < 0 {
+		return 0, errors.New("vector must not be empty")
+	}
+	return v[index], nil
+}
+
+// Max returns the largest element of a Vector. NaN elements are ignored,
+// unless all elements are NaN. Returns an error if the Vector is empty.
+func (v Vector[T]) Max() (T, error) {
+	index := v.ArgMax()
+	if index < 0 {
+		return 0, errors.New("vector must not be empty")
+	}
+	return v[index], nil
+}
+
+// ArgMin returns the index of the (first) smallest element of a Vector. NaN
+// elements are ignored, unless all elements are NaN (then 0 is returned).
+// Returns -1 if the Vector is empty.
+func (v Vector[T]) ArgMin() int {
+	return v.extreme(-1)
+}
+
+// ArgMax returns the index of the (first) largest element of a Vector. NaN
+// elements are ignored, unless all elements are NaN (then 0 is returned).
+// Returns -1 if the Vector is empty.
+func (v Vector[T]) ArgMax() int {
+	return v.extreme(1)
+}
+
+// extreme returns the index of the first smallest (direction -1) or largest
+// (direction 1) element, skipping NaNs (the only values not equal to
+// themselves).
+func (v Vector[T]) extreme(direction int) int {
+	if len(v) == 0 {
+		return -1
+	}
+	best := -1
+	for i, element := range v {
+		if element != element {
+			continue
+		}
+		if best < 0 || (direction < 0 && element < v[best]) || (direction > 0 && element > v[best]) {
+			best = i
+		}
+	}
+	return max(best, 0)
+}
+
+// ============================================================================
+// Weighted statistics
+// ============================================================================
+
+// WeightedSum returns the sum of the elements multiplied by the corresponding
+// weights. Returns an error if the vectors don't have the same length.
+func (v Vector[T]) WeightedSum(weights Vector[T]) (T, error) {
+	return v.DotProduct(weights)
+}
+
+// WeightedMean returns the weighted arithmetic mean of the elements: the
+// weighted sum divided by the sum of the weights. Returns an error if the
+// vectors don't have the same length, if a weight is negative or if the
+// weights sum to zero.
+func (v Vector[T]) WeightedMean(weights Vector[T]) (float64, error) {
+	total, err := v.checkWeights(weights)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if total == 0 {
+		return math.NaN(), errors.New("weights must not sum to zero")
+	}
+	sum := 0.0
+	for i, element := range v {
+		sum += float64(weights[i]) * float64(element)
+	}
+	return sum / total, nil
+}
+
+// WeightedVariance returns the weighted sample variance of the elements,
+// treating the weights as frequencies: the weighted sum of the squared
+// deviations from the weighted mean divided by (sum of the weights) - 1. With
+// integer weights the result equals the Variance of the Vector in which every
+// element is repeated `weight` times. Returns an error if the vectors don't
+// have the same length, if a weight is negative or if the weights sum to 1 or
+// less.
+func (v Vector[T]) WeightedVariance(weights Vector[T]) (float64, error) {
+	total, err := v.checkWeights(weights)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if total <= 1 {
+		return math.NaN(), errors.New("weights must sum to more than 1")
+	}
+	mean, _ := v.WeightedMean(weights)
+	sum := 0.0
+	for i, element := range v {
+		deviation := float64(element) - mean
+		sum += float64(weights[i]) * deviation * deviation
+	}
+	return sum / (total - 1), nil
+}
+
+// checkWeights validates weights for the current Vector and returns their
+// sum.
+func (v Vector[T]) checkWeights(weights Vector[T]) (float64, error) {
+	if len(v) != len(weights) {
+		return 0, errors.New("data and weights must have the same length")
+	}
+	total := 0.0
+	for i, weight := range weights {
+		if weight < 0 {
+			return 0, fmt.Errorf("weight %d must not be negative", i)
+		}
+		total += float64(weight)
+	}
+	return total, nil
+}
+
+// ============================================================================
+// Quantiles
+// ============================================================================
+
+// InterpolationMethod determines how Quantile computes a quantile that lies
+// between two elements. With the sorted elements x and the position
+// h = (n - 1)·q, the quantile lies between x[floor(h)] and x[ceil(h)]. The
+// methods match those of NumPy's quantile.
+type InterpolationMethod int
+
+const (
+	// Linear interpolates linearly between the two elements (the default of
+	// NumPy and most spreadsheets).
+	Linear InterpolationMethod = iota
+	// Lower takes the lower element.
+	Lower
+	// Higher takes the higher element.
+	Higher
+	// Nearest takes the nearest element (the even index for ties).
+	Nearest
+	// Midpoint takes the mean of the two elements.
+	Midpoint
+)
+
+// Quantile returns the q-quantile of the elements (0 <= q <= 1), e.g. the
+// median for q = 0.5, using the specified InterpolationMethod. The Vector
+// itself is not modified. NaN elements have no place in the order, so the
+// result is NaN (without an error) if an element is NaN, like NumPy's
+// quantile. Returns an error if the Vector is empty, if q is outside [0, 1]
+// or if the method is unknown.
+func (v Vector[T]) Quantile(q float64, method InterpolationMethod) (float64, error) {
+	if len(v) == 0 {
+		return math.NaN(), errors.New("vector must not be empty")
+	}
+	if !(q >= 0 && q <= 1) {
+		return math.NaN(), fmt.Errorf("quantile %v must be in [0, 1]", q)
+	}
+	if method < Linear || method > Midpoint {
+		return math.NaN(), fmt.Errorf("unknown interpolation method %d", method)
+	}
+	sorted := v.CloneAsFloat64().Sort(true)
+	if math.IsNaN(sorted[0]) {
+		// Sort places NaN elements first.
+		return math.NaN(), nil
+	}
+	position := float64(len(sorted)-1) * q
+	lower, higher := sorted[int(math.Floor(position))], sorted[int(math.Ceil(position))]
+	switch method {
+	case Lower:
+		return lower, nil
+	case Higher:
+		return higher, nil
+	case Nearest:
+		return sorted[int(math.RoundToEven(position))], nil
+	case Midpoint:
+		return (lower + higher) / 2, nil
+	}
+	return lower + (higher-lower)*(position-math.Floor(position)), nil
+}
+
+// Percentile returns the p-th percentile of the elements (0 <= p <= 100) with
+// linear interpolation: Quantile(p / 100, Linear), so the result is NaN if an
+// element is NaN. Returns an error if the Vector is empty or if p is outside
+// [0, 100].
+func (v Vector[T]) Percentile(p float64) (float64, error) {
+	if !(p >= 0 && p <= 100) {
+		return math.NaN(), fmt.Errorf("percentile %v must be in [0, 100]", p)
+	}
+	return v.Quantile(p/100, Linear)
+}
+
+// IQR returns the interquartile range of the elements: the difference between
+// the third and the first quartile (with linear interpolation), the height of
+// the box in a box plot. Returns NaN if the Vector is empty or if an element
+// is NaN.
+func (v Vector[T]) IQR() float64 {
+	q1, err := v.Quantile(0.25, Linear)
+	if err != nil {
+		return math.NaN()
+	}
+	q3, _ := v.Quantile(0.75, Linear)
+	return q3 - q1
+}
+
+// ============================================================================
+// Scaling and outliers
+// ============================================================================
+
+// OutlierRule determines how OutlierMask detects outliers.
+type OutlierRule int
+
+const (
+	// ZScoreRule marks the elements whose z-score is larger than the
+	// threshold in absolute value (typically 3).
+	ZScoreRule OutlierRule = iota
+	// IQRRule marks the elements more than threshold·IQR below the first or
+	// above the third quartile (typically 1.5, Tukey's fences).
+	IQRRule
+)
+
+// ZScores returns a new Vector of type float64 with the z-score of every
+// element: its distance from the mean in sample standard deviations. If the
+// standard deviation is zero or undefined (fewer than 2 elements), all
+// z-scores are zero. The current Vector is not modified.
+func (v Vector[T]) ZScores() Vector[float64] {
+	mean, deviation := v.Mean(), v.StdDev()
+	if deviation == 0 || math.IsNaN(deviation) {
+		return NewFromValue(0.0, len(v))
+	}
+	return v.CloneAsFloat64().Map(func(x float64) float64 {
+		return (x - mean) / deviation
+	})
+}
+
+// Standardize replaces every element by its z-score (in-place, unless a Clone
+// is made beforehand), see ZScores. It is intended for float vectors: the
+// z-scores of an integer Vector are truncated, so use ZScores instead.
+func (v Vector[T]) Standardize() Vector[T] {
+	for i, z := range v.ZScores() {
+		v[i] = T(z)
+	}
+	return v
+}
+
+// MinMaxScale returns a new Vector of type float64 with the elements scaled
+// linearly to the range [lo, hi]: the smallest element becomes lo and the
+// largest hi. If all elements are equal, they all become lo. The current
+// Vector is not modified.
+func (v Vector[T]) MinMaxScale(lo, hi float64) Vector[float64] {
+	result := v.CloneAsFloat64()
+	minimum, err := result.Min()
+	if err != nil {
+		return result
+	}
+	maximum, _ := result.Max()
+	if maximum == minimum {
+		return result.Map(func(float64) float64 { return lo })
+	}
+	scale := (hi - lo) / (maximum - minimum)
+	return result.Map(func(x float64) float64 {
+		return lo + (x-minimum)*scale
+	})
+}
+
+// OutlierMask returns a Mask that is true for the outliers according to the
+// specified rule and threshold, e.g. v.OutlierMask(1.5, IQRRule). Use
+// Select(mask.Not()) to remove them. NaN elements are never outliers.
+func (v Vector[T]) OutlierMask(threshold float64, rule OutlierRule) Mask {
+	switch rule {
+	case IQRRule:
+		q1, err := v.Quantile(0.25, Linear)
+		if err != nil {
+			return Mask{}
+		}
+		q3, _ := v.Quantile(0.75, Linear)
+		low, high := q1-threshold*(q3-q1), q3+threshold*(q3-q1)
+		return v.compare(func(x T) bool { return float64(x) < low || float64(x) > high })
+	default:
+		return v.ZScores().compare(func(z float64) bool { return math.Abs(z) > threshold })
+	}
+}
+
+// ============================================================================
+// Covariance, correlation and regression
+// ============================================================================
+
+// Covariance returns the sample covariance of x and y: the sum of the
+// products of the deviations from the means divided by n - 1. Returns an
+// error if the vectors don't have the same length or have fewer than 2
+// elements.
+func Covariance[T wbmath.SignedNumber](x, y Vector[T]) (float64, error) {
+	if len(x) != len(y) {
+		return math.NaN(), errors.New("x and y must have the same length")
+	}
+	if len(x) < 2 {
+		return math.NaN(), errors.New("at least two observations are required")
+	}
+	meanX, meanY := x.Mean(), y.Mean()
+	sum := 0.0
+	for i := range x {
+		sum += (float64(x[i]) - meanX) * (float64(y[i]) - meanY)
+	}
+	return sum / float64(len(x)-1), nil
+}
+
+// PearsonCorrelation returns the Pearson correlation coefficient of x and y,
+// a value in [-1, 1] that measures how well they fit a straight line. Returns
+// an error if the vectors don't have the same length, have fewer than 2
+// elements or if either has zero variance.
+func PearsonCorrelation[T wbmath.SignedNumber](x, y Vector[T]) (float64, error) {
+	covariance, err := Covariance(x, y)
+	if err != nil {
+		return math.NaN(), err
+	}
+	deviationX, deviationY := x.StdDev(), y.StdDev()
+	if deviationX == 0 || deviationY == 0 {
+		return math.NaN(), errors.New("correlation is undefined for zero variance")
+	}
+	// Clamp the rounding error, so perfectly correlated data gives exactly ±1.
+	return math.Max(-1, math.Min(1, covariance/(deviationX*deviationY))), nil
+}
+
+// SpearmanCorrelation returns the Spearman rank correlation coefficient of x
+// and y: the Pearson correlation of their ranks (ties get the average of
+// their ranks). It measures how well the relation is monotonic. Returns an
+// error if the vectors don't have the same length, have fewer than 2 elements
+// or if either has zero variance.
+func SpearmanCorrelation[T wbmath.SignedNumber](x, y Vector[T]) (float64, error) {
+	if len(x) != len(y) {
+		return math.NaN(), errors.New("x and y must have the same length")
+	}
+	return PearsonCorrelation(x.Rank(), y.Rank())
+}
+
+// LinearRegression fits the straight line y ≈ intercept + slope·x by
+// ordinary least squares and returns its slope, intercept and coefficient of
+// determination R² (the fraction of the variance of y explained by the line;
+// 1 if y is constant). Returns an error if the vectors don't have the same
+// length, have fewer than 2 elements or if x has zero variance.
+func LinearRegression[T wbmath.SignedNumber](x, y Vector[T]) (slope, intercept, rSquared float64, err error) {
+	covariance, err := Covariance(x, y)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN(), err
+	}
+	varianceX, varianceY := x.Variance(), y.Variance()
+	if varianceX == 0 {
+		return math.NaN(), math.NaN(), math.NaN(), errors.New("x must not have zero variance")
+	}
+	slope = covariance / varianceX
+	intercept = y.Mean() - slope*x.Mean()
+	rSquared = 1.0
+	if varianceY > 0 {
+		rSquared = math.Min(1, covariance*covariance/(varianceX*varianceY))
+	}
+	return slope, intercept, rSquared, nil
+}
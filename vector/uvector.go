@@ -0,0 +1,175 @@
+package vector
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bogersw/wbmath"
+)
+
+// UVector is the unsigned counterpart of Vector, for counters, histogram bins
+// and other data that is naturally stored in unsigned integers. It offers the
+// operations that make sense for unsigned elements; Subtract saturates at
+// zero instead of wrapping around. Like Vector, the mutating methods operate
+// in-place and return the modified UVector to allow chaining.
+type UVector[T wbmath.Unsigned] []T
+
+// ============================================================================
+// Constructor functions
+// ============================================================================
+
+// NewUnsigned is a constructor function that accepts an arbitrary number of
+// elements of type `Unsigned` and returns a UVector.
+func NewUnsigned[T wbmath.Unsigned](elements ...T) UVector[T] {
+	vec := make(UVector[T], len(elements), len(elements)*2)
+	copy(vec, elements)
+	return vec
+}
+
+// NewUnsignedFromValue is a constructor function that returns a UVector with
+// `count` elements that are all equal to `value`, e.g. zeroed histogram bins.
+func NewUnsignedFromValue[T wbmath.Unsigned](value T, count int) UVector[T] {
+	vec := make(UVector[T], count, count*2)
+	for i := 0; i < count; i++ {
+		vec[i] = value
+	}
+	return vec
+}
+
+// ============================================================================
+// Private methods
+// ============================================================================
+
+// operation applies an element-wise operation to the elements the two vectors
+// have in common, with one bounds-check free loop per operation (like
+// Vector.operation).
+func (v UVector[T]) operation(other UVector[T], offset int, op arithmetic) UVector[T] {
+	if offset < 0 || offset >= len(v) {
+		return v
+	}
+	dst := v[offset:]
+	src := other[:min(len(other), len(dst))]
+	dst = dst[:len(src)]
+	switch op {
+	case add:
+		for i := range src {
+			dst[i] += src[i]
+		}
+	case subtract:
+		for i := range src {
+			if src[i] > dst[i] {
+				dst[i] = 0
+			} else {
+				dst[i] -= src[i]
+			}
+		}
+	case multiply:
+		for i := range src {
+			dst[i] *= src[i]
+		}
+	}
+	return v
+}
+
+// ============================================================================
+// Public methods
+// ============================================================================
+
+// Clone returns a new UVector which is a copy of the original UVector.
+func (v UVector[T]) Clone() UVector[T] {
+	clone := make(UVector[T], len(v), len(v)*2)
+	copy(clone, v)
+	return clone
+}
+
+// CloneAsFloat64 returns a new Vector of type float64 with the elements of
+// the UVector, so that the signed Vector methods can be used.
+func (v UVector[T]) CloneAsFloat64() Vector[float64] {
+	vector := make(Vector[float64], len(v), len(v)*2)
+	for i := 0; i < len(v); i++ {
+		vector[i] = float64(v[i])
+	}
+	return vector
+}
+
+// Add adds the specified UVector to the current UVector (in-place, unless a
+// Clone is made beforehand), with an optional offset like Vector.Add. Like
+// all unsigned arithmetic in Go, elements wrap around on overflow.
+func (v UVector[T]) Add(other UVector[T], offset int) UVector[T] {
+	return v.operation(other, offset, add)
+}
+
+// Subtract subtracts the specified UVector from the current UVector
+// (in-place, unless a Clone is made beforehand), with an optional offset like
+// Vector.Subtract. Results that would be negative saturate at zero: 3 - 5
+// gives 0.
+func (v UVector[T]) Subtract(other UVector[T], offset int) UVector[T] {
+	return v.operation(other, offset, subtract)
+}
+
+// Multiply multiplies the specified UVector with the current UVector
+// (in-place, unless a Clone is made beforehand), with an optional offset like
+// Vector.Multiply.
+func (v UVector[T]) Multiply(other UVector[T], offset int) UVector[T] {
+	return v.operation(other, offset, multiply)
+}
+
+// DotProduct calculates the dot product of two vectors: the sum of the
+// products of the corresponding elements.
+func (v UVector[T]) DotProduct(other UVector[T]) (T, error) {
+	if len(v) != len(other) {
+		return 0, errors.New("vectors must have the same length")
+	}
+	return v.Clone().Multiply(other, 0).Sum(), nil
+}
+
+// Scale multiplies every element by `factor` (in-place, unless a Clone is
+// made beforehand).
+func (v UVector[T]) Scale(factor T) UVector[T] {
+	for index := range v {
+		v[index] *= factor
+	}
+	return v
+}
+
+// Map replaces every element by the result of `transform` (in-place, unless a
+// Clone is made beforehand).
+func (v UVector[T]) Map(transform func(T) T) UVector[T] {
+	for index := range v {
+		v[index] = transform(v[index])
+	}
+	return v
+}
+
+// Sum returns the sum of the elements of a UVector. The sum has the element
+// type and wraps around on overflow; use CloneAsFloat64().Sum() for narrow
+// element types.
+func (v UVector[T]) Sum() T {
+	var sum T = 0
+	for i := 0; i < len(v); i++ {
+		sum += v[i]
+	}
+	return sum
+}
+
+// Magnitude returns the Euclidean length of a UVector. The squares are
+// summed as float64, so large counts don't overflow.
+func (v UVector[T]) Magnitude() float64 {
+	sum := 0.0
+	for _, element := range v {
+		sum += float64(element) * float64(element)
+	}
+	return math.Sqrt(sum)
+}
+
+// Frequencies returns the elements divided by their sum as a Vector of type
+// float64, e.g. the relative frequencies of histogram counts. A UVector with
+// sum zero gives all zeros.
+func (v UVector[T]) Frequencies() Vector[float64] {
+	result := v.CloneAsFloat64()
+	total := result.Sum()
+	if total == 0 {
+		return result
+	}
+	return result.Scale(1 / total)
+}
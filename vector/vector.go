@@ -5,28 +5,59 @@
 // Elements of a Vector are constrained by `wbmath.SignedNumber`.
 //
 // Available functionality includes constructors (New, NewFromValue,
-// NewFromRange), cloning (Clone, CloneAsFloat64, CloneAsInt), element-wise
-// arithmetic with optional offsets (Add, Subtract, Multiply, Divide), scalar
-// multiplication (Scale), reductions (Sum, Product, Magnitude), normalizing
-// (Normalize) and rounding (Round).
+// NewFromRange, Linspace, Arange, Logspace), cloning (Clone, CloneAsFloat64,
+// CloneAsInt), type conversion (ToFloat64, ToInt, Convert), comparison (Equal,
+// AlmostEqual), slicing and concatenation (Slice, Append, Insert, Remove,
+// Concat), rearranging (Reverse, Shuffle, Shift, Rotate), sorting and ranking
+// (Sort, SortedCopy, ArgSort, Rank), element-wise arithmetic with optional
+// offsets (Add, Subtract, Multiply, Divide) or with length checks (AddStrict,
+// SubtractStrict, MultiplyStrict, DivideStrict), scalar multiplication (Scale),
+// copying variants of the arithmetic (AddC, SubtractC, MultiplyC, DivideC,
+// ScaleC) and allocation-free destination variants (AddInto, SubtractInto,
+// MultiplyInto, DivideInto, ScaleInto), higher-order functions (Map,
+// MapIndexed, Filter, Reduce), products (DotProduct, Cross, PerpDot),
+// reductions (Sum, SumKahan, Product, Magnitude) with concurrent variants for
+// large vectors (Parallel), cumulative and rolling operations (CumSum, CumProd,
+// Diff, RollingSum, RollingMean), descriptive statistics (Mean, Median,
+// Variance, StdDev, Min, Max, ArgMin, ArgMax), weighted statistics
+// (WeightedSum, WeightedMean, WeightedVariance), quantiles (Quantile,
+// Percentile, IQR), histograms (Histogram, Bin), scaling and outlier detection
+// (ZScores, Standardize, MinMaxScale, OutlierMask), signal processing
+// (Convolve, CrossCorrelate), smoothing (SMA, WMA, EMA), polynomial fitting
+// (PolyFit, PolyVal), covariance, correlation and regression between vectors
+// (Covariance, PearsonCorrelation, SpearmanCorrelation, LinearRegression),
+// element-wise math returning a float64 Vector (ApplyFloat, Sqrt, Exp, Log,
+// Sin, Cos, Pow), comparisons returning a boolean Mask (Gt, Ge, Lt, Le, Eq and
+// their Vector variants) with selection (Select, Where), normalizing
+// (Normalize), rounding (Round), formatting (String, Format) and serialization
+// (MarshalJSON, UnmarshalJSON, ToCSV, FromCSV).
 //
 // Important details:
 //
 // (*) Most mutating methods operate in-place and also return the modified Vector
-// to allow chaining. Call Clone() first when an independent copy is needed.
+// to allow chaining. Call Clone() first when an independent copy is needed, or
+// use the C variants of the arithmetic (AddC, ...), which return a new Vector,
+// or the Into functions (AddInto, ...), which write to a destination Vector
+// without allocating.
 //
 // (*) NewFromRange always returns a Vector[float64] regardless of the input type.
+// Its `steps` argument is the number of elements in between min and max; the
+// NumPy-style Linspace takes the total number of elements instead.
 //
 // (*) Arithmetic with offsets only processes indices common to both vectors;
-// out-of-range elements are ignored.
+// out-of-range elements are ignored. The Strict variants reject vectors of
+// different lengths and division by zero elements with an error instead.
 //
 // (*) The type parameter T must satisfy wbmath.SignedNumber, so both integer and
-// floating-point element types are supported.
+// floating-point element types are supported. UVector is the counterpart for
+// unsigned integers (wbmath.Unsigned), with a Subtract that saturates at zero.
 package vector
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"slices"
 
 	"github.com/bogersw/wbmath"
 )
@@ -75,32 +106,152 @@ func NewFromRange[T wbmath.SignedNumber](min T, max T, steps uint) Vector[float6
 	return vec
 }
 
+// Linspace is a constructor function that returns a Vector with `n` evenly
+// spaced elements from `min` to `max` (both included), like NumPy's linspace:
+// Linspace(0, 1, 5) gives [0 0.25 0.5 0.75 1]. Returns an empty Vector if n
+// is smaller than 1 and [min] if n is 1.
+func Linspace(min, max float64, n int) Vector[float64] {
+	if n < 1 {
+		return New[float64]()
+	}
+	vec := make(Vector[float64], n, n*2)
+	vec[0] = min
+	if n == 1 {
+		return vec
+	}
+	step := (max - min) / float64(n-1)
+	for i := 1; i < n-1; i++ {
+		vec[i] = min + float64(i)*step
+	}
+	vec[n-1] = max
+	return vec
+}
+
+// Arange is a constructor function that returns a Vector with the elements
+// start, start + step, start + 2·step, ... up to (but not including) `stop`,
+// like NumPy's arange: Arange(0, 1, 0.25) gives [0 0.25 0.5 0.75]. A negative
+// step counts down; the Vector is empty if stop can't be reached. Returns an
+// error if step is zero or if an argument is NaN or infinite.
+func Arange(start, stop, step float64) (Vector[float64], error) {
+	if step == 0 {
+		return nil, errors.New("step must not be zero")
+	}
+	count := math.Ceil((stop - start) / step)
+	if math.IsNaN(count) || math.IsInf(count, 0) {
+		return nil, errors.New("start, stop and step must be finite")
+	}
+	n := max(int(count), 0)
+	vec := make(Vector[float64], n, n*2)
+	for i := range vec {
+		vec[i] = start + float64(i)*step
+	}
+	return vec, nil
+}
+
+// Logspace is a constructor function that returns a Vector with `n` elements
+// evenly spaced on a logarithmic scale from base^start to base^stop (both
+// included), like NumPy's logspace: Logspace(0, 3, 4, 10) gives
+// [1 10 100 1000].
+func Logspace(start, stop float64, n int, base float64) Vector[float64] {
+	return Linspace(start, stop, n).Map(func(exponent float64) float64 {
+		return math.Pow(base, exponent)
+	})
+}
+
 // ============================================================================
 // Private methods
 // ============================================================================
 
-func (v Vector[T]) operation(other Vector[T], offset int, operation string) Vector[T] {
-	if offset < len(v) && offset >= 0 {
-		index := offset
-		for index < len(v) {
-			if index-offset >= len(other) {
-				break
-			}
-			if operation == "add" {
-				v[index] += other[index-offset]
-			} else if operation == "subtract" {
-				v[index] -= other[index-offset]
-			} else if operation == "multiply" {
-				v[index] *= other[index-offset]
-			} else if operation == "divide" {
-				v[index] /= other[index-offset]
-			}
-			index += 1
-		}
+// arithmetic selects the element-wise operation (addition, subtraction,
+// multiplication or division) that operation, strict and into apply.
+type arithmetic int
+
+const (
+	add arithmetic = iota
+	subtract
+	multiply
+	divide
+)
+
+// operation applies an element-wise operation to the elements the two vectors
+// have in common (see Add).
+func (v Vector[T]) operation(other Vector[T], offset int, op arithmetic) Vector[T] {
+	if offset < 0 || offset >= len(v) {
+		return v
 	}
+	dst := v[offset:]
+	src := other[:min(len(other), len(dst))]
+	apply(dst[:len(src)], dst, src, op)
 	return v
 }
 
+// apply stores the element-wise result of a op b in dst; dst may be a or b
+// itself. The switch is outside the loops, and the operands are resliced to
+// the length of dst, so the compiler can drop the bounds checks and every
+// loop is a simple kernel, unlike the original dispatcher that compared the
+// operation for every element.
+func apply[T wbmath.SignedNumber](dst, a, b []T, op arithmetic) {
+	a, b = a[:len(dst)], b[:len(dst)]
+	switch op {
+	case add:
+		for i := range dst {
+			dst[i] = a[i] + b[i]
+		}
+	case subtract:
+		for i := range dst {
+			dst[i] = a[i] - b[i]
+		}
+	case multiply:
+		for i := range dst {
+			dst[i] = a[i] * b[i]
+		}
+	case divide:
+		for i := range dst {
+			dst[i] = a[i] / b[i]
+		}
+	}
+}
+
+func (v Vector[T]) strict(other Vector[T], op arithmetic) (Vector[T], error) {
+	if len(v) != len(other) {
+		return v, fmt.Errorf("vectors must have the same length: %d and %d", len(v), len(other))
+	}
+	if op == divide {
+		if index := slices.Index(other, 0); index >= 0 {
+			return v, fmt.Errorf("division by zero at index %d", index)
+		}
+	}
+	return v.operation(other, 0, op), nil
+}
+
+// dot returns the sum of the products of the corresponding elements of two
+// vectors of the same length, without allocating.
+func dot[T wbmath.SignedNumber](a, b Vector[T]) T {
+	b = b[:len(a)]
+	var sum T
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// pairwiseBlock is the length below which pairwiseSum adds with a simple
+// loop; larger blocks are split in halves.
+const pairwiseBlock = 128
+
+// pairwiseSum adds the elements by recursively splitting the Vector in halves.
+func pairwiseSum[T wbmath.SignedNumber](v Vector[T]) T {
+	if len(v) <= pairwiseBlock {
+		var sum T
+		for _, element := range v {
+			sum += element
+		}
+		return sum
+	}
+	middle := len(v) / 2
+	return pairwiseSum(v[:middle]) + pairwiseSum(v[middle:])
+}
+
 // ============================================================================
 // Public methods
 // ============================================================================
@@ -133,6 +284,33 @@ func (v Vector[T]) CloneAsInt() Vector[int] {
 	return vector
 }
 
+// Equal returns true if both vectors have the same length and equal elements.
+// As with ==, NaN elements are never equal.
+func (v Vector[T]) Equal(other Vector[T]) bool {
+	return slices.Equal(v, other)
+}
+
+// AlmostEqual returns true if both vectors have the same length and all
+// corresponding elements are equal within the specified tolerance:
+// |a - b| <= epsilon·max(1, |a|, |b|), like wbmath.AlmostEqualC. The
+// tolerance is absolute for small elements and relative for large ones.
+func (v Vector[T]) AlmostEqual(other Vector[T], epsilon float64) bool {
+	if len(v) != len(other) {
+		return false
+	}
+	for i := range v {
+		a, b := float64(v[i]), float64(other[i])
+		if a == b {
+			continue
+		}
+		scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+		if !(math.Abs(a-b) <= epsilon*scale) {
+			return false
+		}
+	}
+	return true
+}
+
 // Normalize scales each element of a Vector in such a way that the magnitude
 // of the Vector becomes 1: basically the Vector is converted to a unit Vector.
 // The returned Vector is of type float64.
@@ -150,16 +328,17 @@ func (v Vector[T]) Normalize() Vector[float64] {
 // When the specified Vector is shorter than the current Vector, only matching
 // elements are added: when it's longer, extra elements are ignored.
 func (v Vector[T]) Add(other Vector[T], offset int) Vector[T] {
-	return v.operation(other, offset, "add")
+	return v.operation(other, offset, add)
 }
 
 // Subtract subtracts the specified Vector from the current Vector (in-place,
 // unless a Clone is made beforehand). Subtraction is element-wise, based on the
 // index, but when an offset is specified the vectors are shifted by that amount.
 // When the specified Vector is shorter than the current Vector, only matching
-// elements are subtracted: when it's longer, extra elements are ignored.
+// elements are subtracted: when it's longer, extra elements are ignored. The
+// specified Vector is not modified.
 func (v Vector[T]) Subtract(other Vector[T], offset int) Vector[T] {
-	return v.Add(other.Scale(T(-1)), offset)
+	return v.operation(other, offset, subtract)
 }
 
 // Multiply multiplies the specified Vector with the current Vector (in-place,
@@ -168,7 +347,7 @@ func (v Vector[T]) Subtract(other Vector[T], offset int) Vector[T] {
 // amount. When the specified Vector is shorter than the current Vector, only
 // matching elements are multiplied: when it's longer, extra elements are ignored.
 func (v Vector[T]) Multiply(other Vector[T], offset int) Vector[T] {
-	return v.operation(other, offset, "multiply")
+	return v.operation(other, offset, multiply)
 }
 
 // Divide divides the current Vector by the specified Vector (in-place,
@@ -177,7 +356,37 @@ func (v Vector[T]) Multiply(other Vector[T], offset int) Vector[T] {
 // amount. When the specified Vector is shorter than the current Vector, only
 // matching elements are divided: when it's longer, extra elements are ignored.
 func (v Vector[T]) Divide(other Vector[T], offset int) Vector[T] {
-	return v.operation(other, offset, "divide")
+	return v.operation(other, offset, divide)
+}
+
+// AddStrict is the strict variant of Add: it adds the specified Vector
+// element-wise (in-place, unless a Clone is made beforehand) and returns an
+// error, without modifying the current Vector, if the vectors don't have the
+// same length.
+func (v Vector[T]) AddStrict(other Vector[T]) (Vector[T], error) {
+	return v.strict(other, add)
+}
+
+// SubtractStrict is the strict variant of Subtract: it returns an error,
+// without modifying the current Vector, if the vectors don't have the same
+// length.
+func (v Vector[T]) SubtractStrict(other Vector[T]) (Vector[T], error) {
+	return v.strict(other, subtract)
+}
+
+// MultiplyStrict is the strict variant of Multiply: it returns an error,
+// without modifying the current Vector, if the vectors don't have the same
+// length.
+func (v Vector[T]) MultiplyStrict(other Vector[T]) (Vector[T], error) {
+	return v.strict(other, multiply)
+}
+
+// DivideStrict is the strict variant of Divide: it returns an error, without
+// modifying the current Vector, if the vectors don't have the same length or
+// if an element of the specified Vector is zero (instead of panicking for
+// integers or producing an infinity for floats).
+func (v Vector[T]) DivideStrict(other Vector[T]) (Vector[T], error) {
+	return v.strict(other, divide)
 }
 
 // DotProduct calculates the dot product of two vectors: the sum of the products
@@ -187,7 +396,34 @@ func (v Vector[T]) DotProduct(other Vector[T]) (T, error) {
 	if len(v) != len(other) {
 		return 0, errors.New("vectors must have the same length")
 	}
-	return v.Clone().Multiply(other, 0).Sum(), nil
+	return dot(v, other), nil
+}
+
+// Cross calculates the cross product of two vectors with 3 elements: a new
+// Vector that is perpendicular to both, with a magnitude equal to the area of
+// the parallelogram they span. Returns an error if either Vector doesn't have
+// exactly 3 elements.
+func (v Vector[T]) Cross(other Vector[T]) (Vector[T], error) {
+	if len(v) != 3 || len(other) != 3 {
+		return nil, errors.New("cross product requires vectors with 3 elements")
+	}
+	return New(
+		v[1]*other[2]-v[2]*other[1],
+		v[2]*other[0]-v[0]*other[2],
+		v[0]*other[1]-v[1]*other[0],
+	), nil
+}
+
+// PerpDot calculates the perp dot product (the 2D cross product) of two
+// vectors with 2 elements: v[0]*other[1] - v[1]*other[0]. It is positive if
+// `other` is counterclockwise from the current Vector, negative if it is
+// clockwise and zero if the vectors are parallel. Returns an error if either
+// Vector doesn't have exactly 2 elements.
+func (v Vector[T]) PerpDot(other Vector[T]) (T, error) {
+	if len(v) != 2 || len(other) != 2 {
+		return 0, errors.New("perp dot product requires vectors with 2 elements")
+	}
+	return v[0]*other[1] - v[1]*other[0], nil
 }
 
 // Scale implements scalar multiplication: every element in the current Vector
@@ -200,6 +436,8 @@ func (v Vector[T]) Scale(factor T) Vector[T] {
 	return v
 }
 
+// Map replaces every element by the result of `transform` (in-place, unless a
+// Clone is made beforehand).
 func (v Vector[T]) Map(transform func(T) T) Vector[T] {
 	for index := range v {
 		v[index] = transform(v[index])
@@ -207,8 +445,47 @@ func (v Vector[T]) Map(transform func(T) T) Vector[T] {
 	return v
 }
 
-// Sum returns the sum of the elements of a Vector.
+// MapIndexed is identical to Map, but `transform` also receives the index of
+// the element.
+func (v Vector[T]) MapIndexed(transform func(int, T) T) Vector[T] {
+	for index := range v {
+		v[index] = transform(index, v[index])
+	}
+	return v
+}
+
+// Filter returns a new Vector with the elements for which `keep` returns
+// true, in their original order. The current Vector is not modified.
+func (v Vector[T]) Filter(keep func(T) bool) Vector[T] {
+	result := make(Vector[T], 0, len(v))
+	for _, element := range v {
+		if keep(element) {
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// Reduce combines the elements of a Vector into a single value: starting from
+// `init`, `combine` is called with the accumulated value and every element in
+// turn, e.g. v.Reduce(0, func(acc, x int) int { return max(acc, x) }).
+func (v Vector[T]) Reduce(init T, combine func(acc, element T) T) T {
+	result := init
+	for _, element := range v {
+		result = combine(result, element)
+	}
+	return result
+}
+
+// Sum returns the sum of the elements of a Vector. Float elements are added
+// with pairwise summation, which keeps the rounding error at O(log n) instead
+// of O(n) for a simple loop at practically the same speed; integer elements
+// are added exactly (wrapping around on overflow).
 func (v Vector[T]) Sum() T {
+	switch any(T(0)).(type) {
+	case float32, float64:
+		return pairwiseSum(v)
+	}
 	var sum T = 0
 	for i := 0; i < len(v); i++ {
 		sum += v[i]
@@ -216,11 +493,28 @@ func (v Vector[T]) Sum() T {
 	return sum
 }
 
+// SumKahan returns the sum of the elements of a Vector with compensated
+// (Kahan-Babuška-Neumaier) summation: a running correction term captures the
+// low-order bits lost in every addition, so the error doesn't grow with the
+// number of elements. It is more accurate than Sum, but slower.
+func (v Vector[T]) SumKahan() T {
+	var sum, compensation T
+	for _, element := range v {
+		total := sum + element
+		if wbmath.Abs(sum) >= wbmath.Abs(element) {
+			compensation += (sum - total) + element
+		} else {
+			compensation += (element - total) + sum
+		}
+		sum = total
+	}
+	return sum + compensation
+}
+
 // Magnitude returns the size / length of a Vector. It is equal to the square
 // root of the sum of the squared elements.
 func (v Vector[T]) Magnitude() float64 {
-	var sumProducts = v.Clone().Multiply(v, 0).Sum()
-	return math.Sqrt(float64(sumProducts))
+	return math.Sqrt(float64(dot(v, v)))
 }
 
 // Product returns the product of the elements of a Vector.
@@ -249,3 +543,51 @@ func (v Vector[T]) Round(precision uint) Vector[T] {
 	}
 	return v
 }
+
+// ============================================================================
+// Element-wise math
+// ============================================================================
+
+// ApplyFloat returns a new Vector of type float64 with the result of
+// `function` for every element (converted to float64), e.g.
+// v.ApplyFloat(math.Log1p). The current Vector is not modified, so the
+// function can also be used on integer vectors without truncating the results.
+func (v Vector[T]) ApplyFloat(function func(float64) float64) Vector[float64] {
+	return v.CloneAsFloat64().Map(function)
+}
+
+// Sqrt returns a new Vector of type float64 with the square roots of the
+// elements (NaN for negative elements).
+func (v Vector[T]) Sqrt() Vector[float64] {
+	return v.ApplyFloat(math.Sqrt)
+}
+
+// Exp returns a new Vector of type float64 with e raised to the power of the
+// elements.
+func (v Vector[T]) Exp() Vector[float64] {
+	return v.ApplyFloat(math.Exp)
+}
+
+// Log returns a new Vector of type float64 with the natural logarithms of the
+// elements (-Inf for zero and NaN for negative elements).
+func (v Vector[T]) Log() Vector[float64] {
+	return v.ApplyFloat(math.Log)
+}
+
+// Sin returns a new Vector of type float64 with the sines of the elements (in
+// radians).
+func (v Vector[T]) Sin() Vector[float64] {
+	return v.ApplyFloat(math.Sin)
+}
+
+// Cos returns a new Vector of type float64 with the cosines of the elements
+// (in radians).
+func (v Vector[T]) Cos() Vector[float64] {
+	return v.ApplyFloat(math.Cos)
+}
+
+// Pow returns a new Vector of type float64 with the elements raised to the
+// power `exponent`.
+func (v Vector[T]) Pow(exponent float64) Vector[float64] {
+	return v.ApplyFloat(func(x float64) float64 { return math.Pow(x, exponent) })
+}
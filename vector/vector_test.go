@@ -0,0 +1,1203 @@
+package vector
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bogersw/wbmath"
+)
+
+func TestSubtract(t *testing.T) {
+	v := New(5, 7, 9)
+	other := New(1, 2, 3)
+	if got := v.Subtract(other, 0); !reflect.DeepEqual(got, New(4, 5, 6)) {
+		t.Fatalf("Subtract = %v; want [4 5 6]", got)
+	}
+	// Subtract used to negate `other` in-place as a side effect.
+	if !reflect.DeepEqual(other, New(1, 2, 3)) {
+		t.Fatalf("Subtract modified its argument: %v; want [1 2 3]", other)
+	}
+	if got := New(5, 7, 9).Subtract(New(1, 2), 1); !reflect.DeepEqual(got, New(5, 6, 7)) {
+		t.Fatalf("Subtract with offset 1 = %v; want [5 6 7]", got)
+	}
+}
+
+func TestUVector(t *testing.T) {
+	v := NewUnsigned[uint8](1, 2, 3)
+	if !reflect.DeepEqual(NewUnsignedFromValue[uint](0, 3), NewUnsigned[uint](0, 0, 0)) {
+		t.Fatalf("NewUnsignedFromValue(0, 3) = %v; want [0 0 0]", NewUnsignedFromValue[uint](0, 3))
+	}
+	if got := v.Clone().Add(NewUnsigned[uint8](10, 20), 1); !reflect.DeepEqual(got, NewUnsigned[uint8](1, 12, 23)) {
+		t.Fatalf("Add with offset 1 = %v; want [1 12 23]", got)
+	}
+	// Subtract saturates at zero instead of wrapping around to 255.
+	if got := v.Clone().Subtract(NewUnsigned[uint8](5, 2, 1), 0); !reflect.DeepEqual(got, NewUnsigned[uint8](0, 0, 2)) {
+		t.Fatalf("Subtract = %v; want [0 0 2]", got)
+	}
+	if got := v.Clone().Multiply(NewUnsigned[uint8](2, 2, 2), 0).Scale(2); !reflect.DeepEqual(got, NewUnsigned[uint8](4, 8, 12)) {
+		t.Fatalf("Multiply and Scale = %v; want [4 8 12]", got)
+	}
+	// Like all unsigned arithmetic in Go, Add wraps around on overflow.
+	if got := NewUnsigned[uint8](250).Add(NewUnsigned[uint8](10), 0); got[0] != 4 {
+		t.Fatalf("250 + 10 in uint8 = %d; want 4", got[0])
+	}
+	if dot, err := v.DotProduct(NewUnsigned[uint8](4, 5, 6)); err != nil || dot != 32 {
+		t.Fatalf("DotProduct = %d, %v; want 32", dot, err)
+	}
+	if _, err := v.DotProduct(NewUnsigned[uint8](1)); err == nil {
+		t.Fatalf("DotProduct with different lengths should return error")
+	}
+	// Magnitude sums in float64: 200² + 200² overflows uint8 but not the result.
+	if got := NewUnsigned[uint8](200, 200).Magnitude(); got != 200*math.Sqrt2 {
+		t.Fatalf("Magnitude = %v; want %v", got, 200*math.Sqrt2)
+	}
+	if got := NewUnsigned[uint](1, 3).Frequencies(); !reflect.DeepEqual(got, New(0.25, 0.75)) {
+		t.Fatalf("Frequencies = %v; want [0.25 0.75]", got)
+	}
+	if got := NewUnsigned[uint](0, 0).Frequencies(); !reflect.DeepEqual(got, New(0.0, 0)) {
+		t.Fatalf("Frequencies of zeros = %v; want [0 0]", got)
+	}
+	if got := v.Clone().Map(func(x uint8) uint8 { return x * x }); !reflect.DeepEqual(got, NewUnsigned[uint8](1, 4, 9)) {
+		t.Fatalf("Map = %v; want [1 4 9]", got)
+	}
+}
+
+func TestCrossAndPerpDot(t *testing.T) {
+	x, y := New(1, 0, 0), New(0, 1, 0)
+	if got, err := x.Cross(y); err != nil || !reflect.DeepEqual(got, New(0, 0, 1)) {
+		t.Fatalf("Cross(x, y) = %v, %v; want [0 0 1]", got, err)
+	}
+	if got, _ := New(2.0, 3, 4).Cross(New(5.0, 6, 7)); !reflect.DeepEqual(got, New(-3.0, 6, -3)) {
+		t.Fatalf("Cross = %v; want [-3 6 -3]", got)
+	}
+	if _, err := New(1, 2).Cross(New(3, 4)); err == nil {
+		t.Fatalf("Cross of 2-element vectors should return error")
+	}
+	if got, err := New(1, 0).PerpDot(New(0, 1)); err != nil || got != 1 {
+		t.Fatalf("PerpDot counterclockwise = %v, %v; want 1", got, err)
+	}
+	if got, _ := New(0, 1).PerpDot(New(1, 0)); got != -1 {
+		t.Fatalf("PerpDot clockwise = %v; want -1", got)
+	}
+	if got, _ := New(2, 4).PerpDot(New(1, 2)); got != 0 {
+		t.Fatalf("PerpDot of parallel vectors = %v; want 0", got)
+	}
+	if _, err := New(1, 2, 3).PerpDot(New(1, 2)); err == nil {
+		t.Fatalf("PerpDot of a 3-element vector should return error")
+	}
+}
+
+func TestDescriptiveStatistics(t *testing.T) {
+	v := New(2, 4, 4, 4, 5, 5, 7, 9)
+	if got := v.Mean(); got != 5 {
+		t.Fatalf("Mean = %v; want 5", got)
+	}
+	if got := v.Median(); got != 4.5 {
+		t.Fatalf("Median = %v; want 4.5", got)
+	}
+	if got := New(3, 1, 2).Median(); got != 2 {
+		t.Fatalf("Median of odd length = %v; want 2", got)
+	}
+	// Sum of squared deviations is 32: the sample variance divides by n - 1.
+	if got := v.Variance(); math.Abs(got-32.0/7) > 1e-12 {
+		t.Fatalf("Variance = %v; want %v", got, 32.0/7)
+	}
+	if got := v.StdDev(); math.Abs(got-math.Sqrt(32.0/7)) > 1e-12 {
+		t.Fatalf("StdDev = %v; want %v", got, math.Sqrt(32.0/7))
+	}
+	if !math.IsNaN(New[int]().Mean()) || !math.IsNaN(New(1).Variance()) || !math.IsNaN(New[int]().Median()) {
+		t.Fatalf("Mean, Median and Variance of too short vectors should be NaN")
+	}
+	if !reflect.DeepEqual(v, New(2, 4, 4, 4, 5, 5, 7, 9)) {
+		t.Fatalf("Median modified the vector: %v", v)
+	}
+	w := New(3.0, math.NaN(), -1, 8, -1, 8)
+	if got, err := w.Min(); err != nil || got != -1 {
+		t.Fatalf("Min = %v, %v; want -1", got, err)
+	}
+	if got, err := w.Max(); err != nil || got != 8 {
+		t.Fatalf("Max = %v, %v; want 8", got, err)
+	}
+	// The first of equal extremes is returned; NaN is skipped.
+	if w.ArgMin() != 2 || w.ArgMax() != 3 {
+		t.Fatalf("ArgMin, ArgMax = %d, %d; want 2, 3", w.ArgMin(), w.ArgMax())
+	}
+	if New(math.NaN()).ArgMin() != 0 || New[int]().ArgMax() != -1 {
+		t.Fatalf("ArgMin of all NaN should be 0 and ArgMax of empty -1")
+	}
+	if _, err := New[int]().Min(); err == nil {
+		t.Fatalf("Min of empty vector should return error")
+	}
+}
+
+func TestHigherOrderFunctions(t *testing.T) {
+	v := New(1, 2, 3, 4)
+	if got := v.Clone().Map(func(x int) int { return x * x }); !reflect.DeepEqual(got, New(1, 4, 9, 16)) {
+		t.Fatalf("Map = %v; want [1 4 9 16]", got)
+	}
+	if got := v.Clone().MapIndexed(func(i, x int) int { return i * x }); !reflect.DeepEqual(got, New(0, 2, 6, 12)) {
+		t.Fatalf("MapIndexed = %v; want [0 2 6 12]", got)
+	}
+	even := v.Filter(func(x int) bool { return x%2 == 0 })
+	if !reflect.DeepEqual(even, New(2, 4)) || !reflect.DeepEqual(v, New(1, 2, 3, 4)) {
+		t.Fatalf("Filter = %v (vector %v); want [2 4] and an unmodified vector", even, v)
+	}
+	if got := v.Filter(func(int) bool { return false }); len(got) != 0 {
+		t.Fatalf("Filter keeping nothing = %v; want []", got)
+	}
+	if got := v.Reduce(0, func(acc, x int) int { return max(acc, x) }); got != 4 {
+		t.Fatalf("Reduce(max) = %d; want 4", got)
+	}
+	if got := v.Reduce(1, func(acc, x int) int { return acc * x }); got != 24 {
+		t.Fatalf("Reduce(product) = %d; want 24", got)
+	}
+	if got := New[int]().Reduce(7, func(acc, x int) int { return acc + x }); got != 7 {
+		t.Fatalf("Reduce of empty vector = %d; want the initial value 7", got)
+	}
+}
+
+func TestElementWiseMath(t *testing.T) {
+	v := New(0, 1, 4, 9)
+	if got := v.Sqrt(); !reflect.DeepEqual(got, New(0.0, 1, 2, 3)) {
+		t.Fatalf("Sqrt = %v; want [0 1 2 3]", got)
+	}
+	if !reflect.DeepEqual(v, New(0, 1, 4, 9)) {
+		t.Fatalf("Sqrt modified the vector: %v", v)
+	}
+	// Integer vectors are not truncated: the results are float64.
+	if got := New(1, 2).ApplyFloat(func(x float64) float64 { return x / 4 }); !reflect.DeepEqual(got, New(0.25, 0.5)) {
+		t.Fatalf("ApplyFloat = %v; want [0.25 0.5]", got)
+	}
+	if got := New(0, 1).Exp(); got[0] != 1 || math.Abs(got[1]-math.E) > 1e-15 {
+		t.Fatalf("Exp = %v; want [1 e]", got)
+	}
+	if got := New(1.0, 0, -1).Log(); got[0] != 0 || !math.IsInf(got[1], -1) || !math.IsNaN(got[2]) {
+		t.Fatalf("Log = %v; want [0 -Inf NaN]", got)
+	}
+	if got := New(0.0, math.Pi/2).Sin(); got[0] != 0 || math.Abs(got[1]-1) > 1e-15 {
+		t.Fatalf("Sin = %v; want [0 1]", got)
+	}
+	if got := New(0.0, math.Pi).Cos(); got[0] != 1 || math.Abs(got[1]+1) > 1e-15 {
+		t.Fatalf("Cos = %v; want [1 -1]", got)
+	}
+	if got := New(2, 3).Pow(2); !reflect.DeepEqual(got, New(4.0, 9)) {
+		t.Fatalf("Pow(2) = %v; want [4 9]", got)
+	}
+	if got := New(-4.0).Sqrt(); !math.IsNaN(got[0]) {
+		t.Fatalf("Sqrt of negative element = %v; want [NaN]", got)
+	}
+}
+
+func TestStrictArithmetic(t *testing.T) {
+	v := New(6, 8, 10)
+	if got, err := v.Clone().AddStrict(New(1, 2, 3)); err != nil || !reflect.DeepEqual(got, New(7, 10, 13)) {
+		t.Fatalf("AddStrict = %v, %v; want [7 10 13]", got, err)
+	}
+	if got, err := v.Clone().SubtractStrict(New(1, 2, 3)); err != nil || !reflect.DeepEqual(got, New(5, 6, 7)) {
+		t.Fatalf("SubtractStrict = %v, %v; want [5 6 7]", got, err)
+	}
+	if got, err := v.Clone().MultiplyStrict(New(1, 2, 3)); err != nil || !reflect.DeepEqual(got, New(6, 16, 30)) {
+		t.Fatalf("MultiplyStrict = %v, %v; want [6 16 30]", got, err)
+	}
+	if got, err := v.Clone().DivideStrict(New(2, 4, 5)); err != nil || !reflect.DeepEqual(got, New(3, 2, 2)) {
+		t.Fatalf("DivideStrict = %v, %v; want [3 2 2]", got, err)
+	}
+	// On error the vector is left unmodified.
+	if _, err := v.AddStrict(New(1, 2)); err == nil {
+		t.Fatalf("AddStrict with vectors of different length should return error")
+	}
+	if _, err := v.SubtractStrict(New(1, 2, 3, 4)); err == nil {
+		t.Fatalf("SubtractStrict with vectors of different length should return error")
+	}
+	if _, err := v.MultiplyStrict(New[int]()); err == nil {
+		t.Fatalf("MultiplyStrict with vectors of different length should return error")
+	}
+	if _, err := v.DivideStrict(New(1, 0, 2)); err == nil {
+		t.Fatalf("DivideStrict by zero should return error")
+	}
+	if !reflect.DeepEqual(v, New(6, 8, 10)) {
+		t.Fatalf("failed strict operations modified the vector: %v", v)
+	}
+}
+
+func TestMask(t *testing.T) {
+	v := New(-2, 0, 3, 5)
+	comparisons := []struct {
+		name string
+		got  Mask
+		want Mask
+	}{
+		{"Gt(0)", v.Gt(0), Mask{false, false, true, true}},
+		{"Ge(0)", v.Ge(0), Mask{false, true, true, true}},
+		{"Lt(3)", v.Lt(3), Mask{true, true, false, false}},
+		{"Le(3)", v.Le(3), Mask{true, true, true, false}},
+		{"Eq(5)", v.Eq(5), Mask{false, false, false, true}},
+	}
+	for _, c := range comparisons {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Fatalf("%s = %v; want %v", c.name, c.got, c.want)
+		}
+	}
+	other := New(-2, 1, 2, 5)
+	vectorComparisons := []struct {
+		name    string
+		compare func(Vector[int]) (Mask, error)
+		want    Mask
+	}{
+		{"GtVector", v.GtVector, Mask{false, false, true, false}},
+		{"GeVector", v.GeVector, Mask{true, false, true, true}},
+		{"LtVector", v.LtVector, Mask{false, true, false, false}},
+		{"LeVector", v.LeVector, Mask{true, true, false, true}},
+		{"EqVector", v.EqVector, Mask{true, false, false, true}},
+	}
+	for _, c := range vectorComparisons {
+		if got, err := c.compare(other); err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("%s = %v, %v; want %v", c.name, got, err, c.want)
+		}
+		if _, err := c.compare(New(1)); err == nil {
+			t.Fatalf("%s with vectors of different length should return error", c.name)
+		}
+	}
+	if got, err := v.Select(v.Gt(0)); err != nil || !reflect.DeepEqual(got, New(3, 5)) {
+		t.Fatalf("Select = %v, %v; want [3 5]", got, err)
+	}
+	if got, err := v.Where(v.Ge(0), 0); err != nil || !reflect.DeepEqual(got, New(0, 0, 3, 5)) {
+		t.Fatalf("Where = %v, %v; want [0 0 3 5]", got, err)
+	}
+	if !reflect.DeepEqual(v, New(-2, 0, 3, 5)) {
+		t.Fatalf("Select and Where modified the vector: %v", v)
+	}
+	if _, err := v.Select(Mask{true}); err == nil {
+		t.Fatalf("Select with mask of different length should return error")
+	}
+	if _, err := v.Where(Mask{true}, 0); err == nil {
+		t.Fatalf("Where with mask of different length should return error")
+	}
+
+	m := Mask{true, false, true}
+	if m.CountTrue() != 2 || !m.Any() || m.All() {
+		t.Fatalf("CountTrue, Any, All = %d, %v, %v; want 2, true, false", m.CountTrue(), m.Any(), m.All())
+	}
+	if !(Mask{}).All() || (Mask{}).Any() {
+		t.Fatalf("empty Mask should satisfy All and not Any")
+	}
+	if got, err := (Mask{true, false, true}).And(Mask{true, true, false}); err != nil || !reflect.DeepEqual(got, Mask{true, false, false}) {
+		t.Fatalf("And = %v, %v; want [true false false]", got, err)
+	}
+	if got, err := (Mask{true, false, true}).Or(Mask{false, true, false}); err != nil || !reflect.DeepEqual(got, Mask{true, true, true}) {
+		t.Fatalf("Or = %v, %v; want [true true true]", got, err)
+	}
+	if _, err := m.And(Mask{true}); err == nil {
+		t.Fatalf("And with masks of different length should return error")
+	}
+	if _, err := m.Or(Mask{true}); err == nil {
+		t.Fatalf("Or with masks of different length should return error")
+	}
+	if got := m.Not(); !reflect.DeepEqual(got, Mask{false, true, false}) {
+		t.Fatalf("Not = %v; want [false true false]", got)
+	}
+}
+
+func TestSlicing(t *testing.T) {
+	v := New(1, 2, 3, 4, 5)
+	s, err := v.Slice(1, 3)
+	if err != nil || !reflect.DeepEqual(s, New(2, 3)) {
+		t.Fatalf("Slice(1, 3) = %v, %v; want [2 3]", s, err)
+	}
+	// The slice is a copy that doesn't share memory with the vector.
+	s[0] = 99
+	if v[1] != 2 {
+		t.Fatalf("modifying the result of Slice modified the vector: %v", v)
+	}
+	for _, bounds := range [][2]int{{-1, 2}, {3, 2}, {0, 6}} {
+		if _, err := v.Slice(bounds[0], bounds[1]); err == nil {
+			t.Fatalf("Slice(%d, %d) should return error", bounds[0], bounds[1])
+		}
+	}
+	if got := New(1, 2).Append(3, 4); !reflect.DeepEqual(got, New(1, 2, 3, 4)) {
+		t.Fatalf("Append = %v; want [1 2 3 4]", got)
+	}
+	if got, err := New(1, 4).Insert(1, 2, 3); err != nil || !reflect.DeepEqual(got, New(1, 2, 3, 4)) {
+		t.Fatalf("Insert(1, 2, 3) = %v, %v; want [1 2 3 4]", got, err)
+	}
+	if got, err := New(1, 2).Insert(2, 3); err != nil || !reflect.DeepEqual(got, New(1, 2, 3)) {
+		t.Fatalf("Insert at the end = %v, %v; want [1 2 3]", got, err)
+	}
+	if _, err := New(1, 2).Insert(3, 3); err == nil {
+		t.Fatalf("Insert with index out of range should return error")
+	}
+	if got, err := New(1, 2, 3).Remove(1); err != nil || !reflect.DeepEqual(got, New(1, 3)) {
+		t.Fatalf("Remove(1) = %v, %v; want [1 3]", got, err)
+	}
+	if _, err := New(1, 2, 3).Remove(3); err == nil {
+		t.Fatalf("Remove with index out of range should return error")
+	}
+	a, b := New(1, 2), New(3)
+	if got := a.Concat(b); !reflect.DeepEqual(got, New(1, 2, 3)) {
+		t.Fatalf("Concat = %v; want [1 2 3]", got)
+	}
+	if !reflect.DeepEqual(a, New(1, 2)) || !reflect.DeepEqual(b, New(3)) {
+		t.Fatalf("Concat modified the vectors: %v, %v", a, b)
+	}
+}
+
+func TestSorting(t *testing.T) {
+	v := New(3, 1, 2)
+	if got := v.SortedCopy(); !reflect.DeepEqual(got, New(1, 2, 3)) || !reflect.DeepEqual(v, New(3, 1, 2)) {
+		t.Fatalf("SortedCopy = %v (vector %v); want [1 2 3] and an unmodified vector", got, v)
+	}
+	if got := v.Clone().Sort(false); !reflect.DeepEqual(got, New(3, 2, 1)) {
+		t.Fatalf("Sort(false) = %v; want [3 2 1]", got)
+	}
+	// NaN goes first in ascending order and last in descending order.
+	w := New(2.0, math.NaN(), 1)
+	if got := w.Clone().Sort(true); !math.IsNaN(got[0]) || got[1] != 1 || got[2] != 2 {
+		t.Fatalf("Sort(true) with NaN = %v; want [NaN 1 2]", got)
+	}
+	if got := w.Clone().Sort(false); got[0] != 2 || got[1] != 1 || !math.IsNaN(got[2]) {
+		t.Fatalf("Sort(false) with NaN = %v; want [2 1 NaN]", got)
+	}
+	// Equal elements keep their original order.
+	if got := New(20, 10, 20, 5).ArgSort(); !reflect.DeepEqual(got, []int{3, 1, 0, 2}) {
+		t.Fatalf("ArgSort = %v; want [3 1 0 2]", got)
+	}
+	if got := New(10, 30, 20, 20).Rank(); !reflect.DeepEqual(got, New(1, 4, 2.5, 2.5)) {
+		t.Fatalf("Rank = %v; want [1 4 2.5 2.5]", got)
+	}
+	if got := New[int]().Rank(); len(got) != 0 {
+		t.Fatalf("Rank of empty vector = %v; want []", got)
+	}
+}
+
+func TestCumulative(t *testing.T) {
+	if got := New(1, 2, 3, 4).CumSum(); !reflect.DeepEqual(got, New(1, 3, 6, 10)) {
+		t.Fatalf("CumSum = %v; want [1 3 6 10]", got)
+	}
+	if got := New(1, 2, 3, 4).CumProd(); !reflect.DeepEqual(got, New(1, 2, 6, 24)) {
+		t.Fatalf("CumProd = %v; want [1 2 6 24]", got)
+	}
+	if got := New(1, 3, 6, 10).Diff(); !reflect.DeepEqual(got, New(2, 3, 4)) {
+		t.Fatalf("Diff = %v; want [2 3 4]", got)
+	}
+	if got := New(1).Diff(); len(got) != 0 {
+		t.Fatalf("Diff of single element = %v; want []", got)
+	}
+	v := New(1, 2, 3, 4, 5)
+	if got, err := v.RollingSum(3); err != nil || !reflect.DeepEqual(got, New(6, 9, 12)) {
+		t.Fatalf("RollingSum(3) = %v, %v; want [6 9 12]", got, err)
+	}
+	if got, err := v.RollingSum(5); err != nil || !reflect.DeepEqual(got, New(15)) {
+		t.Fatalf("RollingSum(5) = %v, %v; want [15]", got, err)
+	}
+	// The mean of integer windows is not truncated.
+	if got, err := v.RollingMean(2); err != nil || !reflect.DeepEqual(got, New(1.5, 2.5, 3.5, 4.5)) {
+		t.Fatalf("RollingMean(2) = %v, %v; want [1.5 2.5 3.5 4.5]", got, err)
+	}
+	if !reflect.DeepEqual(v, New(1, 2, 3, 4, 5)) {
+		t.Fatalf("rolling operations modified the vector: %v", v)
+	}
+	for _, window := range []int{0, 6} {
+		if _, err := v.RollingSum(window); err == nil {
+			t.Fatalf("RollingSum(%d) should return error", window)
+		}
+		if _, err := v.RollingMean(window); err == nil {
+			t.Fatalf("RollingMean(%d) should return error", window)
+		}
+	}
+}
+
+func TestRearranging(t *testing.T) {
+	if got := New(1, 2, 3, 4).Reverse(); !reflect.DeepEqual(got, New(4, 3, 2, 1)) {
+		t.Fatalf("Reverse = %v; want [4 3 2 1]", got)
+	}
+	// A seeded generator gives the same permutation every time.
+	a := NewFromRange(1, 10, 8).Shuffle(rand.New(rand.NewPCG(1, 2)))
+	b := NewFromRange(1, 10, 8).Shuffle(rand.New(rand.NewPCG(1, 2)))
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("Shuffle with equally seeded generators = %v and %v; want equal", a, b)
+	}
+	if got := a.SortedCopy(); !reflect.DeepEqual(got, NewFromRange(1, 10, 8)) {
+		t.Fatalf("Shuffle = %v; want a permutation of 1 ... 10", a)
+	}
+	shifts := []struct {
+		n    int
+		want Vector[int]
+	}{
+		{1, New(0, 1, 2, 3)},
+		{-2, New(3, 4, 0, 0)},
+		{0, New(1, 2, 3, 4)},
+		{4, New(0, 0, 0, 0)},
+		{-5, New(0, 0, 0, 0)},
+		{math.MinInt, New(0, 0, 0, 0)},
+		{math.MaxInt, New(0, 0, 0, 0)},
+	}
+	for _, s := range shifts {
+		if got := New(1, 2, 3, 4).Shift(s.n, 0); !reflect.DeepEqual(got, s.want) {
+			t.Fatalf("Shift(%d) = %v; want %v", s.n, got, s.want)
+		}
+	}
+	rotations := []struct {
+		n    int
+		want Vector[int]
+	}{
+		{1, New(4, 1, 2, 3)},
+		{-1, New(2, 3, 4, 1)},
+		{4, New(1, 2, 3, 4)},
+		{6, New(3, 4, 1, 2)},
+	}
+	for _, r := range rotations {
+		if got := New(1, 2, 3, 4).Rotate(r.n); !reflect.DeepEqual(got, r.want) {
+			t.Fatalf("Rotate(%d) = %v; want %v", r.n, got, r.want)
+		}
+	}
+	if got := New[int]().Rotate(3); len(got) != 0 {
+		t.Fatalf("Rotate of empty vector = %v; want []", got)
+	}
+}
+
+func TestConversion(t *testing.T) {
+	v := New(1.5, 2.5, -1.5, 2.7)
+	modes := []struct {
+		mode RoundingMode
+		want Vector[int]
+	}{
+		{Truncate, New(1, 2, -1, 2)},
+		{HalfAwayFromZero, New(2, 3, -2, 3)},
+		{HalfEven, New(2, 2, -2, 3)},
+		{Floor, New(1, 2, -2, 2)},
+		{Ceiling, New(2, 3, -1, 3)},
+	}
+	for _, m := range modes {
+		if got := v.ToInt(m.mode); !reflect.DeepEqual(got, m.want) {
+			t.Fatalf("ToInt(%d) = %v; want %v", m.mode, got, m.want)
+		}
+	}
+	// Integers beyond 2^53 are converted exactly.
+	if got := New(int64(1<<53 + 1)).ToInt(HalfEven); got[0] != 1<<53+1 {
+		t.Fatalf("ToInt(2^53 + 1) = %v; want %d", got, 1<<53+1)
+	}
+	if got := New(1, 2).ToFloat64(); !reflect.DeepEqual(got, New(1.0, 2)) {
+		t.Fatalf("ToFloat64 = %v; want [1 2]", got)
+	}
+	if got := Convert[float32](New(1, 2)); !reflect.DeepEqual(got, New[float32](1, 2)) {
+		t.Fatalf("Convert[float32] = %v; want [1 2]", got)
+	}
+	if got := Convert[int8](New(1.9, -1.9)); !reflect.DeepEqual(got, New[int8](1, -1)) {
+		t.Fatalf("Convert[int8] = %v; want [1 -1]", got)
+	}
+}
+
+func TestEquality(t *testing.T) {
+	if !New(1, 2, 3).Equal(New(1, 2, 3)) || New(1, 2, 3).Equal(New(1, 2)) || New(1, 2).Equal(New(1, 3)) {
+		t.Fatalf("Equal should compare length and elements")
+	}
+	if New(math.NaN()).Equal(New(math.NaN())) {
+		t.Fatalf("Equal with NaN elements should be false")
+	}
+	// The tolerance is absolute for small and relative for large elements.
+	if !New(0.1+0.2, 1e10).AlmostEqual(New(0.3, 1e10+1), 1e-9) {
+		t.Fatalf("AlmostEqual within tolerance should be true")
+	}
+	if New(1.0).AlmostEqual(New(1.001), 1e-9) {
+		t.Fatalf("AlmostEqual outside tolerance should be false")
+	}
+	if New(1.0).AlmostEqual(New(1.0, 2), 1) {
+		t.Fatalf("AlmostEqual with vectors of different length should be false")
+	}
+	if New(math.NaN()).AlmostEqual(New(math.NaN()), 1) {
+		t.Fatalf("AlmostEqual with NaN elements should be false")
+	}
+	if !New(math.Inf(1)).AlmostEqual(New(math.Inf(1)), 0) {
+		t.Fatalf("AlmostEqual with equal infinities should be true")
+	}
+}
+
+func TestSpacedConstructors(t *testing.T) {
+	if got := Linspace(0, 1, 5); !reflect.DeepEqual(got, New(0, 0.25, 0.5, 0.75, 1)) {
+		t.Fatalf("Linspace(0, 1, 5) = %v; want [0 0.25 0.5 0.75 1]", got)
+	}
+	// The last element is exactly max, without rounding errors.
+	if got := Linspace(0, 0.3, 4); got[3] != 0.3 {
+		t.Fatalf("Linspace(0, 0.3, 4) = %v; want last element 0.3", got)
+	}
+	if got := Linspace(2, 5, 1); !reflect.DeepEqual(got, New(2.0)) {
+		t.Fatalf("Linspace(2, 5, 1) = %v; want [2]", got)
+	}
+	if got := Linspace(0, 1, 0); len(got) != 0 {
+		t.Fatalf("Linspace(0, 1, 0) = %v; want []", got)
+	}
+	if got, err := Arange(0, 1, 0.25); err != nil || !reflect.DeepEqual(got, New(0, 0.25, 0.5, 0.75)) {
+		t.Fatalf("Arange(0, 1, 0.25) = %v, %v; want [0 0.25 0.5 0.75]", got, err)
+	}
+	if got, err := Arange(3, 0, -1); err != nil || !reflect.DeepEqual(got, New(3.0, 2, 1)) {
+		t.Fatalf("Arange(3, 0, -1) = %v, %v; want [3 2 1]", got, err)
+	}
+	if got, err := Arange(0, 3, -1); err != nil || len(got) != 0 {
+		t.Fatalf("Arange(0, 3, -1) = %v, %v; want []", got, err)
+	}
+	if _, err := Arange(0, 1, 0); err == nil {
+		t.Fatalf("Arange with step 0 should return error")
+	}
+	if _, err := Arange(0, math.Inf(1), 1); err == nil {
+		t.Fatalf("Arange with infinite stop should return error")
+	}
+	if got := Logspace(0, 3, 4, 10); !reflect.DeepEqual(got, New(1.0, 10, 100, 1000)) {
+		t.Fatalf("Logspace(0, 3, 4, 10) = %v; want [1 10 100 1000]", got)
+	}
+	if got := Logspace(0, 2, 3, 2); !reflect.DeepEqual(got, New(1.0, 2, 4)) {
+		t.Fatalf("Logspace(0, 2, 3, 2) = %v; want [1 2 4]", got)
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	if got := fmt.Sprint(New(1, 2.5, -3)); got != "[1, 2.5, -3]" {
+		t.Fatalf("fmt.Sprint = %q; want %q", got, "[1, 2.5, -3]")
+	}
+	if got := New[int]().String(); got != "[]" {
+		t.Fatalf("String of empty vector = %q; want %q", got, "[]")
+	}
+	if got := New[float32](0.1).String(); got != "[0.1]" {
+		t.Fatalf("String of float32 vector = %q; want %q", got, "[0.1]")
+	}
+	long := Linspace(1, 12, 12)
+	if got := long.String(); got != "[1, 2, 3, 4, 5, 6, 7, 8, 9, 10, … 12 elements]" {
+		t.Fatalf("String of 12 elements = %q; want %q", got, "[1, 2, 3, 4, 5, 6, 7, 8, 9, 10, … 12 elements]")
+	}
+	options := FormatOptions{Precision: 2, Separator: ", ", MaxElements: 3}
+	if got := Format(New(1, 2.5, 3, 4), options); got != "1.00, 2.50, 3.00, … 4 elements" {
+		t.Fatalf("Format with precision 2 and 3 elements = %q; want %q", got, "1.00, 2.50, 3.00, … 4 elements")
+	}
+	if got := Format(New(1, 2, 3, 4), FormatOptions{Precision: -1, Separator: ", "}); got != "1, 2, 3, 4" {
+		t.Fatalf("Format without truncation = %q; want %q", got, "1, 2, 3, 4")
+	}
+	if got := Format(New(1, 2.5), FormatOptions{Precision: 2, Separator: ";"}); got != "1.00;2.50" {
+		t.Fatalf("Format = %q; want %q", got, "1.00;2.50")
+	}
+	if got := Format(New(-1, 20), FormatOptions{Precision: 3, Separator: " "}); got != "-1 20" {
+		t.Fatalf("Format of integer vector = %q; want %q", got, "-1 20")
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	data, err := json.Marshal(New(1, 2.5, -3))
+	if err != nil || string(data) != "[1,2.5,-3]" {
+		t.Fatalf("json.Marshal = %s, %v; want [1,2.5,-3]", data, err)
+	}
+	if data, err := json.Marshal(Vector[int](nil)); err != nil || string(data) != "null" {
+		t.Fatalf("json.Marshal of nil vector = %s, %v; want null", data, err)
+	}
+	if _, err := json.Marshal(New(1, math.NaN())); err == nil {
+		t.Fatalf("json.Marshal with NaN element should return error")
+	}
+	var v Vector[float64]
+	if err := json.Unmarshal(data, &v); err != nil || !reflect.DeepEqual(v, New(1, 2.5, -3)) {
+		t.Fatalf("json.Unmarshal = %v, %v; want [1 2.5 -3]", v, err)
+	}
+	if err := json.Unmarshal([]byte("null"), &v); err != nil || v != nil {
+		t.Fatalf("json.Unmarshal of null = %v, %v; want nil vector", v, err)
+	}
+	var w Vector[int]
+	if err := json.Unmarshal([]byte("[1,2.5]"), &w); err == nil {
+		t.Fatalf("json.Unmarshal of 2.5 into int vector should return error")
+	}
+	if err := json.Unmarshal([]byte(`[1,"a"]`), &w); err == nil {
+		t.Fatalf("json.Unmarshal of string element should return error")
+	}
+
+	var builder strings.Builder
+	if err := New(1, 0.1, -3).ToCSV(&builder); err != nil || builder.String() != "1\n0.1\n-3\n" {
+		t.Fatalf("ToCSV = %q, %v; want %q", builder.String(), err, "1\n0.1\n-3\n")
+	}
+	if got, err := FromCSV[float64](strings.NewReader(builder.String())); err != nil || !reflect.DeepEqual(got, New(1, 0.1, -3)) {
+		t.Fatalf("FromCSV of a column = %v, %v; want [1 0.1 -3]", got, err)
+	}
+	if got, err := FromCSV[int](strings.NewReader("1, 2,3\n4")); err != nil || !reflect.DeepEqual(got, New(1, 2, 3, 4)) {
+		t.Fatalf("FromCSV of rows = %v, %v; want [1 2 3 4]", got, err)
+	}
+	if _, err := FromCSV[int8](strings.NewReader("1\n300")); err == nil {
+		t.Fatalf("FromCSV with value out of range should return error")
+	}
+	if _, err := FromCSV[int](strings.NewReader("1\nx")); err == nil {
+		t.Fatalf("FromCSV with invalid number should return error")
+	}
+}
+
+func TestCopyAndDestinationVariants(t *testing.T) {
+	v := New(6, 8, 10)
+	copies := []struct {
+		name string
+		got  Vector[int]
+		want Vector[int]
+	}{
+		{"AddC", v.AddC(New(1, 2), 1), New(6, 9, 12)},
+		{"SubtractC", v.SubtractC(New(1, 2, 3), 0), New(5, 6, 7)},
+		{"MultiplyC", v.MultiplyC(New(2), 2), New(6, 8, 20)},
+		{"DivideC", v.DivideC(New(2, 4, 5), 0), New(3, 2, 2)},
+		{"ScaleC", v.ScaleC(3), New(18, 24, 30)},
+	}
+	for _, c := range copies {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Fatalf("%s = %v; want %v", c.name, c.got, c.want)
+		}
+	}
+	if !reflect.DeepEqual(v, New(6, 8, 10)) {
+		t.Fatalf("copy variants modified the vector: %v", v)
+	}
+
+	a, b := New(6.0, 8, 10), New(2.0, 4, 5)
+	dst := NewFromValue(0.0, 3)
+	destinations := []struct {
+		name string
+		into func(dst, a, b Vector[float64]) error
+		want Vector[float64]
+	}{
+		{"AddInto", AddInto[float64], New(8.0, 12, 15)},
+		{"SubtractInto", SubtractInto[float64], New(4.0, 4, 5)},
+		{"MultiplyInto", MultiplyInto[float64], New(12.0, 32, 50)},
+		{"DivideInto", DivideInto[float64], New(3.0, 2, 2)},
+	}
+	for _, d := range destinations {
+		if err := d.into(dst, a, b); err != nil || !reflect.DeepEqual(dst, d.want) {
+			t.Fatalf("%s = %v, %v; want %v", d.name, dst, err, d.want)
+		}
+		if err := d.into(dst, a, New(1.0)); err == nil || !reflect.DeepEqual(dst, d.want) {
+			t.Fatalf("%s with vectors of different length should return error without modifying dst", d.name)
+		}
+	}
+	if err := ScaleInto(dst, a, 0.5); err != nil || !reflect.DeepEqual(dst, New(3.0, 4, 5)) {
+		t.Fatalf("ScaleInto = %v, %v; want [3 4 5]", dst, err)
+	}
+	if err := ScaleInto(dst, New(1.0), 2); err == nil {
+		t.Fatalf("ScaleInto with vectors of different length should return error")
+	}
+	// The destination may be one of the operands.
+	if err := AddInto(a, a, b); err != nil || !reflect.DeepEqual(a, New(8.0, 12, 15)) {
+		t.Fatalf("AddInto(a, a, b) = %v, %v; want [8 12 15]", a, err)
+	}
+	if !reflect.DeepEqual(b, New(2.0, 4, 5)) {
+		t.Fatalf("destination variants modified an operand: %v", b)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	// Large enough to be split over several workers.
+	n := 4*minChunk + 3
+	v := NewFromValue(0, n).MapIndexed(func(i, _ int) int { return i%7 - 3 })
+	for _, workers := range []int{0, 1, 3, 8} {
+		p := v.Parallel(workers)
+		if got, want := p.Sum(), v.Sum(); got != want {
+			t.Fatalf("Parallel(%d).Sum = %d; want %d", workers, got, want)
+		}
+		if got, err := p.DotProduct(v); err != nil || got != dot(v, v) {
+			t.Fatalf("Parallel(%d).DotProduct = %d, %v; want %d", workers, got, err, dot(v, v))
+		}
+		if got, want := p.Magnitude(), v.Magnitude(); got != want {
+			t.Fatalf("Parallel(%d).Magnitude = %v; want %v", workers, got, want)
+		}
+	}
+	// Floats may differ from the sequential result in the last bits only.
+	f := v.CloneAsFloat64().Scale(0.1)
+	if got, want := f.Parallel(4).Sum(), f.Sum(); math.Abs(got-want) > 1e-9*math.Abs(want) {
+		t.Fatalf("Parallel(4).Sum = %v; want %v", got, want)
+	}
+	if _, err := v.Parallel(2).DotProduct(New(1)); err == nil {
+		t.Fatalf("Parallel DotProduct with vectors of different length should return error")
+	}
+	if got := New(1, 2, 3).Parallel(4).Sum(); got != 6 {
+		t.Fatalf("Parallel Sum of short vector = %d; want 6", got)
+	}
+}
+
+func TestKernels(t *testing.T) {
+	// Every operation, for float and integer element types.
+	if got := New(1.5, 2).Add(New(0.5, 1), 0).Multiply(New(2.0, 3), 0); !reflect.DeepEqual(got, New(4.0, 9)) {
+		t.Fatalf("float64 Add, Multiply = %v; want [4 9]", got)
+	}
+	dst := New[float32](0, 0)
+	if err := SubtractInto(dst, New[float32](1.5, 2), New[float32](0.5, 4)); err != nil || !reflect.DeepEqual(dst, New[float32](1, -2)) {
+		t.Fatalf("float32 SubtractInto = %v, %v; want [1 -2]", dst, err)
+	}
+	if got := New[float32](3, 8).Divide(New[float32](4), 1); !reflect.DeepEqual(got, New[float32](3, 2)) {
+		t.Fatalf("float32 Divide with offset = %v; want [3 2]", got)
+	}
+	if got := New[int16](7, 9).Divide(New[int16](2, 4), 0); !reflect.DeepEqual(got, New[int16](3, 2)) {
+		t.Fatalf("int16 Divide = %v; want [3 2]", got)
+	}
+}
+
+func TestSum(t *testing.T) {
+	if got := New(1, 2, 3, 4).Sum(); got != 10 {
+		t.Fatalf("Sum = %d; want 10", got)
+	}
+	if got := New[float64]().Sum(); got != 0 {
+		t.Fatalf("Sum of empty vector = %v; want 0", got)
+	}
+	// A simple loop is off by about 1e-6 here; pairwise summation is not.
+	v := NewFromValue(0.1, 1_000_000)
+	if got := v.Sum(); math.Abs(got-100_000) > 1e-9 {
+		t.Fatalf("Sum of 10^6 times 0.1 = %v; want 100000 within 1e-9", got)
+	}
+	if got := NewFromValue[float32](0.1, 1_000_000).Sum(); math.Abs(float64(got)-100_000) > 1 {
+		t.Fatalf("float32 Sum of 10^6 times 0.1 = %v; want 100000 within 1", got)
+	}
+	if got := v.SumKahan(); math.Abs(got-100_000) > 1e-9 {
+		t.Fatalf("SumKahan of 10^6 times 0.1 = %v; want 100000 within 1e-9", got)
+	}
+	// The correction term also recovers elements smaller than the rounding
+	// error of the running sum.
+	if got := New(1e100, 1, -1e100).SumKahan(); got != 1 {
+		t.Fatalf("SumKahan of [1e100 1 -1e100] = %v; want 1", got)
+	}
+	if got := New(1, 2, 3).SumKahan(); got != 6 {
+		t.Fatalf("SumKahan of integers = %d; want 6", got)
+	}
+}
+
+func TestWeightedStatistics(t *testing.T) {
+	v, weights := New(1, 2, 4), New(3, 0, 1)
+	if got, err := v.WeightedSum(weights); err != nil || got != 7 {
+		t.Fatalf("WeightedSum = %d, %v; want 7", got, err)
+	}
+	if got, err := v.WeightedMean(weights); err != nil || got != 1.75 {
+		t.Fatalf("WeightedMean = %v, %v; want 1.75", got, err)
+	}
+	// Integer weights act as frequencies: the variance of [1 1 1 4].
+	want := New(1, 1, 1, 4).Variance()
+	if got, err := v.WeightedVariance(weights); err != nil || math.Abs(got-want) > 1e-12 {
+		t.Fatalf("WeightedVariance = %v, %v; want %v", got, err, want)
+	}
+	if _, err := v.WeightedSum(New(1, 2)); err == nil {
+		t.Fatalf("WeightedSum with vectors of different length should return error")
+	}
+	errorCases := []struct {
+		name    string
+		weights Vector[int]
+	}{
+		{"different length", New(1, 2)},
+		{"negative weight", New(2, -1, 1)},
+		{"zero sum", New(0, 0, 0)},
+	}
+	for _, c := range errorCases {
+		if got, err := v.WeightedMean(c.weights); err == nil || !math.IsNaN(got) {
+			t.Fatalf("WeightedMean with %s = %v, %v; want NaN and error", c.name, got, err)
+		}
+		if got, err := v.WeightedVariance(c.weights); err == nil || !math.IsNaN(got) {
+			t.Fatalf("WeightedVariance with %s = %v, %v; want NaN and error", c.name, got, err)
+		}
+	}
+	if _, err := v.WeightedVariance(New(0, 1, 0)); err == nil {
+		t.Fatalf("WeightedVariance with weights summing to 1 should return error")
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	v := New(4, 1, 3, 2)
+	// The position between the sorted elements [1 2 3 4] is 3·0.5 = 1.5.
+	methods := []struct {
+		method InterpolationMethod
+		want   float64
+	}{
+		{Linear, 2.5},
+		{Lower, 2},
+		{Higher, 3},
+		{Nearest, 3},
+		{Midpoint, 2.5},
+	}
+	for _, m := range methods {
+		if got, err := v.Quantile(0.5, m.method); err != nil || got != m.want {
+			t.Fatalf("Quantile(0.5, %d) = %v, %v; want %v", m.method, got, err, m.want)
+		}
+	}
+	if got, err := v.Quantile(0.1, Linear); err != nil || math.Abs(got-1.3) > 1e-12 {
+		t.Fatalf("Quantile(0.1, Linear) = %v, %v; want 1.3", got, err)
+	}
+	if got, err := v.Quantile(1, Linear); err != nil || got != 4 {
+		t.Fatalf("Quantile(1, Linear) = %v, %v; want 4", got, err)
+	}
+	if !reflect.DeepEqual(v, New(4, 1, 3, 2)) {
+		t.Fatalf("Quantile modified the vector: %v", v)
+	}
+	if got, err := v.Percentile(25); err != nil || got != 1.75 {
+		t.Fatalf("Percentile(25) = %v, %v; want 1.75", got, err)
+	}
+	if got := v.IQR(); got != 1.5 {
+		t.Fatalf("IQR = %v; want 1.5", got)
+	}
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := v.Quantile(q, Linear); err == nil {
+			t.Fatalf("Quantile(%v) should return error", q)
+		}
+	}
+	if _, err := v.Quantile(0.5, Midpoint+1); err == nil {
+		t.Fatalf("Quantile with unknown method should return error")
+	}
+	if _, err := v.Percentile(101); err == nil {
+		t.Fatalf("Percentile(101) should return error")
+	}
+	if _, err := New[int]().Quantile(0.5, Linear); err == nil || !math.IsNaN(New[int]().IQR()) {
+		t.Fatalf("Quantile of empty vector should return error and IQR NaN")
+	}
+	// A NaN element gives NaN, wherever it is.
+	for _, w := range []Vector[float64]{New(3, math.NaN(), 1), New(math.NaN(), 1, 3), New(1, 3, math.NaN())} {
+		if got, err := w.Quantile(0.5, Linear); err != nil || !math.IsNaN(got) {
+			t.Fatalf("Quantile(0.5) of %v = %v, %v; want NaN", w, got, err)
+		}
+		if got, err := w.Quantile(0, Lower); err != nil || !math.IsNaN(got) {
+			t.Fatalf("Quantile(0, Lower) of %v = %v, %v; want NaN", w, got, err)
+		}
+		if got := w.IQR(); !math.IsNaN(got) {
+			t.Fatalf("IQR of %v = %v; want NaN", w, got)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	edges, counts, err := New(1, 2, 2, 3, 5).Histogram(4)
+	if err != nil || !reflect.DeepEqual(edges, New(1, 2, 3, 4, 5.0)) || !reflect.DeepEqual(counts, New(1, 2, 1, 1)) {
+		t.Fatalf("Histogram(4) = %v, %v, %v; want [1 2 3 4 5], [1 2 1 1]", edges, counts, err)
+	}
+	// NaN elements are not counted.
+	if _, counts, _ := New(0.0, math.NaN(), 1).Histogram(2); !reflect.DeepEqual(counts, New(1, 1)) {
+		t.Fatalf("Histogram(2) with NaN = %v; want [1 1]", counts)
+	}
+	// Neither are infinite elements: the range covers the finite elements.
+	withInf := New(math.Inf(-1), 0.0, 1, 2, math.Inf(1))
+	if edges, counts, err := withInf.Histogram(2); err != nil || !reflect.DeepEqual(edges, New(0, 1, 2.0)) || !reflect.DeepEqual(counts, New(1, 2)) {
+		t.Fatalf("Histogram(2) with ±Inf = %v, %v, %v; want [0 1 2], [1 2]", edges, counts, err)
+	}
+	if edges, counts, err := New(math.Inf(1)).Histogram(2); err != nil || !reflect.DeepEqual(edges, New(0, 0.5, 1)) || !reflect.DeepEqual(counts, New(0, 0)) {
+		t.Fatalf("Histogram(2) of [+Inf] = %v, %v, %v; want [0 0.5 1], [0 0]", edges, counts, err)
+	}
+	if edges, counts, _ := New(3, 3).Histogram(2); !reflect.DeepEqual(edges, New(2.5, 3, 3.5)) || !reflect.DeepEqual(counts, New(0, 2)) {
+		t.Fatalf("Histogram(2) of equal elements = %v, %v; want [2.5 3 3.5], [0 2]", edges, counts)
+	}
+	if edges, counts, _ := New[int]().Histogram(2); !reflect.DeepEqual(edges, New(0, 0.5, 1)) || !reflect.DeepEqual(counts, New(0, 0)) {
+		t.Fatalf("Histogram(2) of empty vector = %v, %v; want [0 0.5 1], [0 0]", edges, counts)
+	}
+	if _, _, err := New(1, 2).Histogram(0); err == nil {
+		t.Fatalf("Histogram(0) should return error")
+	}
+	// The width of the range overflows, so it can't be divided into bins.
+	if _, _, err := New(-math.MaxFloat64, math.MaxFloat64).Histogram(2); err == nil {
+		t.Fatalf("Histogram(2) of [-MaxFloat64, MaxFloat64] should return error")
+	}
+	v := New(-1, 0, 0.5, 1, 2, 3, math.NaN())
+	if got, err := v.Bin(New(0, 1, 2.0)); err != nil || !reflect.DeepEqual(got, New(-1, 0, 0, 1, 1, -1, -1)) {
+		t.Fatalf("Bin = %v, %v; want [-1 0 0 1 1 -1 -1]", got, err)
+	}
+	if _, err := v.Bin(New(1.0)); err == nil {
+		t.Fatalf("Bin with a single edge should return error")
+	}
+	if _, err := v.Bin(New(0, 1, 1.0)); err == nil {
+		t.Fatalf("Bin with edges that are not strictly increasing should return error")
+	}
+}
+
+func TestScalingAndOutliers(t *testing.T) {
+	v := New(1, 2, 3)
+	if got := v.ZScores(); !reflect.DeepEqual(got, New(-1, 0, 1.0)) {
+		t.Fatalf("ZScores = %v; want [-1 0 1]", got)
+	}
+	if got := New(4, 4).ZScores(); !reflect.DeepEqual(got, New(0, 0.0)) {
+		t.Fatalf("ZScores of equal elements = %v; want [0 0]", got)
+	}
+	if got := New(4).ZScores(); !reflect.DeepEqual(got, New(0.0)) {
+		t.Fatalf("ZScores of single element = %v; want [0]", got)
+	}
+	if got := New(1.0, 2, 3).Standardize(); !reflect.DeepEqual(got, New(-1, 0, 1.0)) {
+		t.Fatalf("Standardize = %v; want [-1 0 1]", got)
+	}
+	if got := New(1, 2, 5).MinMaxScale(0, 1); !reflect.DeepEqual(got, New(0, 0.25, 1)) {
+		t.Fatalf("MinMaxScale(0, 1) = %v; want [0 0.25 1]", got)
+	}
+	if got := New(1, 2, 5).MinMaxScale(-1, 1); !reflect.DeepEqual(got, New(-1, -0.5, 1)) {
+		t.Fatalf("MinMaxScale(-1, 1) = %v; want [-1 -0.5 1]", got)
+	}
+	if got := New(3, 3).MinMaxScale(2, 4); !reflect.DeepEqual(got, New(2, 2.0)) {
+		t.Fatalf("MinMaxScale of equal elements = %v; want [2 2]", got)
+	}
+	if !reflect.DeepEqual(v, New(1, 2, 3)) {
+		t.Fatalf("ZScores and MinMaxScale modified the vector: %v", v)
+	}
+	// Quartiles 2 and 4 give the fences -1 and 7.
+	w := New(1, 2, 3, 4, 100)
+	if got := w.OutlierMask(1.5, IQRRule); !reflect.DeepEqual(got, Mask{false, false, false, false, true}) {
+		t.Fatalf("OutlierMask(1.5, IQRRule) = %v; want only the last element", got)
+	}
+	if got := w.OutlierMask(1.5, ZScoreRule); !reflect.DeepEqual(got, Mask{false, false, false, false, true}) {
+		t.Fatalf("OutlierMask(1.5, ZScoreRule) = %v; want only the last element", got)
+	}
+	if got := New(1.0, math.NaN(), 100).OutlierMask(0, IQRRule); got.Any() {
+		t.Fatalf("OutlierMask with NaN element = %v; want no outliers", got)
+	}
+	if got := New[int]().OutlierMask(1.5, IQRRule); len(got) != 0 {
+		t.Fatalf("OutlierMask of empty vector = %v; want []", got)
+	}
+}
+
+func TestConvolution(t *testing.T) {
+	v, kernel := New(1, 2, 3), New(0, 1, 0.5)
+	modes := []struct {
+		mode ConvolutionMode
+		want Vector[float64]
+	}{
+		{Full, New(0, 1, 2.5, 4, 1.5)},
+		{Same, New(1, 2.5, 4)},
+		{Valid, New(2.5)},
+	}
+	for _, m := range modes {
+		if got := v.Convolve(kernel, m.mode); !reflect.DeepEqual(got, m.want) {
+			t.Fatalf("Convolve(%d) = %v; want %v", m.mode, got, m.want)
+		}
+	}
+	// The modes also apply when the kernel is longer than the signal.
+	if got := New(2.0).Convolve(New(1, 2, 3.0), Same); !reflect.DeepEqual(got, New(2, 4, 6.0)) {
+		t.Fatalf("Convolve(Same) with longer kernel = %v; want [2 4 6]", got)
+	}
+	third := 1 / 3.0
+	if got := New(3, 6, 9, 12).Convolve(New(third, third, third), Valid); !got.AlmostEqual(New(6, 9.0), 1e-12) {
+		t.Fatalf("Convolve as moving average = %v; want [6 9]", got)
+	}
+	if got := New[int]().Convolve(kernel, Full); len(got) != 0 {
+		t.Fatalf("Convolve of empty vector = %v; want []", got)
+	}
+	// Index len(other) - 1 is lag zero: the dot product.
+	if got := v.CrossCorrelate(New(0, 1, 2)); !reflect.DeepEqual(got, New(2, 5, 8, 3, 0.0)) {
+		t.Fatalf("CrossCorrelate = %v; want [2 5 8 3 0]", got)
+	}
+	if got := v.CrossCorrelate(New[int]()); len(got) != 0 {
+		t.Fatalf("CrossCorrelate with empty vector = %v; want []", got)
+	}
+}
+
+func TestSmoothing(t *testing.T) {
+	v := New(1, 2, 3, 4, 5)
+	edges := []struct {
+		edge EdgeMode
+		want Vector[float64]
+	}{
+		{Shorter, New(2, 3, 4.0)},
+		{PadNaN, New(math.NaN(), math.NaN(), 2, 3, 4)},
+		{Partial, New(1, 1.5, 2, 3, 4)},
+	}
+	for _, e := range edges {
+		got, err := v.SMA(3, e.edge)
+		if err != nil || len(got) != len(e.want) {
+			t.Fatalf("SMA(3, %d) = %v, %v; want %v", e.edge, got, err, e.want)
+		}
+		for i := range got {
+			if got[i] != e.want[i] && !(math.IsNaN(got[i]) && math.IsNaN(e.want[i])) {
+				t.Fatalf("SMA(3, %d) = %v; want %v", e.edge, got, e.want)
+			}
+		}
+	}
+	// The last weight applies to the newest element.
+	if got, err := v.WMA(New(1.0, 3), Shorter); err != nil || !reflect.DeepEqual(got, New(1.75, 2.75, 3.75, 4.75)) {
+		t.Fatalf("WMA([1 3], Shorter) = %v, %v; want [1.75 2.75 3.75 4.75]", got, err)
+	}
+	if got, err := v.WMA(New(1.0, 3), Partial); err != nil || !reflect.DeepEqual(got, New(1, 1.75, 2.75, 3.75, 4.75)) {
+		t.Fatalf("WMA([1 3], Partial) = %v, %v; want [1 1.75 2.75 3.75 4.75]", got, err)
+	}
+	// The incomplete first window only has the last weight, which is zero.
+	if got, err := v.WMA(New(1.0, 0), Partial); err == nil {
+		t.Fatalf("WMA([1 0], Partial) = %v; want error", got)
+	}
+	if got, err := v.WMA(New(1.0, 0), Shorter); err != nil || !reflect.DeepEqual(got, New(1.0, 2, 3, 4)) {
+		t.Fatalf("WMA([1 0], Shorter) = %v, %v; want [1 2 3 4]", got, err)
+	}
+	errorCases := []struct {
+		name string
+		err  error
+	}{
+		{"SMA with window 0", errorOf(v.SMA(0, Shorter))},
+		{"SMA with window larger than vector", errorOf(v.SMA(6, Shorter))},
+		{"SMA with unknown edge mode", errorOf(v.SMA(2, Partial+1))},
+		{"WMA without weights", errorOf(v.WMA(New[float64](), Shorter))},
+		{"WMA with weights summing to zero", errorOf(v.WMA(New(1.0, -1), PadNaN))},
+		{"EMA with alpha 0", errorOf(v.EMA(0))},
+		{"EMA with alpha larger than 1", errorOf(v.EMA(1.5))},
+	}
+	for _, c := range errorCases {
+		if c.err == nil {
+			t.Fatalf("%s should return error", c.name)
+		}
+	}
+	if got, err := New(2, 4, 8).EMA(0.5); err != nil || !reflect.DeepEqual(got, New(2, 3, 5.5)) {
+		t.Fatalf("EMA(0.5) = %v, %v; want [2 3 5.5]", got, err)
+	}
+	if got, err := New(2, 4, 8).EMA(1); err != nil || !reflect.DeepEqual(got, New(2, 4, 8.0)) {
+		t.Fatalf("EMA(1) = %v, %v; want [2 4 8]", got, err)
+	}
+}
+
+// errorOf returns the error of a function call with two results.
+func errorOf[T any](_ T, err error) error {
+	return err
+}
+
+func TestPolynomials(t *testing.T) {
+	x := New(0, 1, 2, 3, 4.0)
+	y := PolyVal(New(1, 2, 3.0), x)
+	if !reflect.DeepEqual(y, New(1, 6, 17, 34, 57.0)) {
+		t.Fatalf("PolyVal = %v; want [1 6 17 34 57]", y)
+	}
+	if got, err := PolyFit(x, y, 2); err != nil || !got.AlmostEqual(New(1, 2, 3.0), 1e-12) {
+		t.Fatalf("PolyFit of exact quadratic = %v, %v; want [1 2 3]", got, err)
+	}
+	if !reflect.DeepEqual(x, New(0, 1, 2, 3, 4.0)) || !reflect.DeepEqual(y, New(1, 6, 17, 34, 57.0)) {
+		t.Fatalf("PolyFit modified x or y: %v, %v", x, y)
+	}
+	// The least-squares line through (0, 0), (1, 1), (2, 1) and (3, 2).
+	if got, err := PolyFit(New(0, 1, 2, 3.0), New(0, 1, 1, 2.0), 1); err != nil || !got.AlmostEqual(New(0.1, 0.6), 1e-12) {
+		t.Fatalf("PolyFit of line = %v, %v; want [0.1 0.6]", got, err)
+	}
+	if got, err := PolyFit(New(0, 1, 2.0), New(1, 2, 6.0), 0); err != nil || !got.AlmostEqual(New(3.0), 1e-12) {
+		t.Fatalf("PolyFit of degree 0 = %v, %v; want the mean [3]", got, err)
+	}
+	if got := PolyVal(New[float64](), x); !reflect.DeepEqual(got, NewFromValue(0.0, 5)) {
+		t.Fatalf("PolyVal without coefficients = %v; want zeros", got)
+	}
+	errorCases := []struct {
+		name   string
+		x, y   Vector[float64]
+		degree int
+	}{
+		{"different lengths", New(0, 1.0), New(1.0), 1},
+		{"negative degree", New(0, 1.0), New(1, 2.0), -1},
+		{"fewer points than coefficients", New(0, 1.0), New(1, 2.0), 2},
+		{"too few distinct x values", New(1, 1, 1.0), New(1, 2, 3.0), 1},
+	}
+	for _, c := range errorCases {
+		if _, err := PolyFit(c.x, c.y, c.degree); err == nil {
+			t.Fatalf("PolyFit with %s should return error", c.name)
+		}
+	}
+}
+
+func TestCorrelationAndRegression(t *testing.T) {
+	x, y := New(0, 1, 2, 3), New(0, 1, 1, 2)
+	if got, err := Covariance(x, y); err != nil || math.Abs(got-1) > 1e-12 {
+		t.Fatalf("Covariance = %v, %v; want 1", got, err)
+	}
+	// Covariance 1 and variances 5/3 and 2/3.
+	if got, err := PearsonCorrelation(x, y); err != nil || math.Abs(got-3/math.Sqrt(10)) > 1e-12 {
+		t.Fatalf("PearsonCorrelation = %v, %v; want %v", got, err, 3/math.Sqrt(10))
+	}
+	if got, err := PearsonCorrelation(x, x.Clone().Scale(-2)); err != nil || got != -1 {
+		t.Fatalf("PearsonCorrelation of perfectly anticorrelated data = %v, %v; want -1", got, err)
+	}
+	// A monotonic relation has Spearman correlation 1, but not Pearson.
+	squares := New(1, 4, 9, 100)
+	if got, err := SpearmanCorrelation(New(1, 2, 3, 4), squares); err != nil || got != 1 {
+		t.Fatalf("SpearmanCorrelation of monotonic data = %v, %v; want 1", got, err)
+	}
+	if got, _ := PearsonCorrelation(New(1, 2, 3, 4), squares); !(got < 0.9) {
+		t.Fatalf("PearsonCorrelation of monotonic data = %v; want < 0.9", got)
+	}
+	slope, intercept, rSquared, err := LinearRegression(x, y)
+	if err != nil || math.Abs(slope-0.6) > 1e-12 || math.Abs(intercept-0.1) > 1e-12 || math.Abs(rSquared-0.9) > 1e-12 {
+		t.Fatalf("LinearRegression = %v, %v, %v, %v; want 0.6, 0.1, 0.9", slope, intercept, rSquared, err)
+	}
+	if slope, intercept, rSquared, err := LinearRegression(x, New(5, 5, 5, 5)); err != nil || slope != 0 || intercept != 5 || rSquared != 1 {
+		t.Fatalf("LinearRegression of constant y = %v, %v, %v, %v; want 0, 5, 1", slope, intercept, rSquared, err)
+	}
+	constant := New(1, 1, 1, 1)
+	errorCases := []struct {
+		name string
+		err  error
+	}{
+		{"Covariance with different lengths", errorOf(Covariance(x, New(1, 2)))},
+		{"Covariance with one observation", errorOf(Covariance(New(1), New(2)))},
+		{"PearsonCorrelation with zero variance", errorOf(PearsonCorrelation(x, constant))},
+		{"SpearmanCorrelation with different lengths", errorOf(SpearmanCorrelation(x, New(1, 2)))},
+		{"SpearmanCorrelation with zero variance", errorOf(SpearmanCorrelation(constant, x))},
+	}
+	for _, c := range errorCases {
+		if c.err == nil {
+			t.Fatalf("%s should return error", c.name)
+		}
+	}
+	if _, _, _, err := LinearRegression(constant, x); err == nil {
+		t.Fatalf("LinearRegression with zero variance of x should return error")
+	}
+}
+
+// benchmarkLength is the length of the vectors in the benchmarks: large
+// enough for Parallel to use several workers.
+const benchmarkLength = 1 << 22
+
+func BenchmarkSum(b *testing.B) {
+	v := NewFromValue(1.5, benchmarkLength)
+	b.SetBytes(benchmarkLength * 8)
+	for i := 0; i < b.N; i++ {
+		v.Sum()
+	}
+}
+
+func BenchmarkParallelSum(b *testing.B) {
+	v := NewFromValue(1.5, benchmarkLength)
+	b.SetBytes(benchmarkLength * 8)
+	for i := 0; i < b.N; i++ {
+		v.Parallel(0).Sum()
+	}
+}
+
+// kernelLength is the length of the vectors in the arithmetic benchmarks:
+// small enough to stay in cache, so the loops are measured instead of the
+// memory bandwidth.
+const kernelLength = 1 << 12
+
+func BenchmarkAddInto(b *testing.B) {
+	x, y := NewFromValue(1.5, kernelLength), NewFromValue(2.5, kernelLength)
+	dst := NewFromValue(0.0, kernelLength)
+	b.SetBytes(kernelLength * 8)
+	for i := 0; i < b.N; i++ {
+		_ = AddInto(dst, x, y)
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	x, y := NewFromValue(1.5, kernelLength), NewFromValue(2.5, kernelLength)
+	b.SetBytes(kernelLength * 8)
+	for i := 0; i < b.N; i++ {
+		x.Add(y, 0)
+	}
+}
+
+// BenchmarkAddBaseline is the baseline for BenchmarkAdd: the original
+// dispatcher, which compared the operation name for every element.
+func BenchmarkAddBaseline(b *testing.B) {
+	x, y := NewFromValue(1.5, kernelLength), NewFromValue(2.5, kernelLength)
+	b.SetBytes(kernelLength * 8)
+	for i := 0; i < b.N; i++ {
+		baselineOperation(x, y, 0, "add")
+	}
+}
+
+// baselineOperation is a copy of the original Vector.operation.
+func baselineOperation[T wbmath.SignedNumber](v, other Vector[T], offset int, operation string) Vector[T] {
+	if offset < len(v) && offset >= 0 {
+		index := offset
+		for index < len(v) {
+			if index-offset >= len(other) {
+				break
+			}
+			if operation == "add" {
+				v[index] += other[index-offset]
+			} else if operation == "subtract" {
+				v[index] -= other[index-offset]
+			} else if operation == "multiply" {
+				v[index] *= other[index-offset]
+			} else if operation == "divide" {
+				v[index] /= other[index-offset]
+			}
+			index += 1
+		}
+	}
+	return v
+}
@@ -2,9 +2,16 @@
 package wbmath
 
 import (
+	"errors"
 	"math"
+	"math/cmplx"
 )
 
+// ErrSingular is returned when a matrix is singular, so it has no inverse and
+// a linear system with it has no unique solution. The matrix, linsolve and
+// realnum packages all return this error.
+var ErrSingular = errors.New("matrix is singular")
+
 // Number is a custom constraint that allows integers and floats.
 type Number interface {
 	int | int8 | int16 | int32 | int64 | uint | uint8 |
@@ -17,6 +24,31 @@ type SignedNumber interface {
 	int | int8 | int16 | int32 | int64 | float32 | float64
 }
 
+// Unsigned is a custom constraint that allows unsigned integers.
+type Unsigned interface {
+	uint | uint8 | uint16 | uint32 | uint64
+}
+
+// Complex is a custom constraint that allows complex numbers.
+type Complex interface {
+	complex64 | complex128
+}
+
+// Real is implemented by number types that support field arithmetic, so
+// algorithms can be written once and run with float64 speed or with exact
+// rational precision, depending on the type the caller chooses. T is the
+// implementing type itself, e.g. `func Sum[T Real[T]](values ...T) T`.
+// Implementations must not modify their receiver or argument.
+type Real[T any] interface {
+	Add(other T) T
+	Mul(other T) T
+	Neg() T
+	Inverse() (T, error)
+	// Cmp returns -1, 0 or 1 if the receiver is smaller than, equal to or
+	// larger than other.
+	Cmp(other T) int
+}
+
 // Abs returns the absolute value of the specified number.
 func Abs[T SignedNumber](value T) T {
 	if value < 0 {
@@ -41,6 +73,18 @@ func Gcd(a int, b int) int {
 	return a
 }
 
+// Lcm determines the least common multiple (lcm) of two integers: the
+// smallest positive integer that is a multiple of both numbers. The lcm is 0
+// if either number is 0. Like Gcd, the function is associative, so the lcm
+// of more numbers follows by repetition: lcm(a, b, c) = lcm(a, lcm(b, c)).
+// Note that the result can overflow for large inputs.
+func Lcm(a int, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return Abs(a / Gcd(a, b) * b)
+}
+
 // IsNthRootInt checks if the specified integer value can be expressed
 // in terms of a root of the specified degree.
 func IsNthRootInt(value int, degree uint) bool {
@@ -94,3 +138,37 @@ func IsInteger[T float32 | float64](num T) bool {
 		return false
 	}
 }
+
+// AbsC returns the absolute value (modulus) |z| of the specified complex
+// number.
+func AbsC[T Complex](z T) float64 {
+	return cmplx.Abs(complex128(z))
+}
+
+// ArgC returns the argument (phase) of the specified complex number, in the
+// range [-π, π].
+func ArgC[T Complex](z T) float64 {
+	return cmplx.Phase(complex128(z))
+}
+
+// PolarToComplex returns the complex number with modulus r and argument
+// theta (in radians): r·(cos θ + i·sin θ).
+func PolarToComplex[T Complex](r, theta float64) T {
+	sin, cos := math.Sincos(theta)
+	return T(complex(r*cos, r*sin))
+}
+
+// RoundC rounds the real and the imaginary part of the specified complex
+// number to the specified number of decimal places, like Round.
+func RoundC[T Complex](z T, decimalPlaces uint) T {
+	c := complex128(z)
+	return T(complex(Round(real(c), decimalPlaces), Round(imag(c), decimalPlaces)))
+}
+
+// AlmostEqualC checks if two complex numbers are equal within the specified
+// tolerance: |a - b| <= tolerance·max(1, |a|, |b|). The tolerance is
+// absolute for small numbers and relative for large ones.
+func AlmostEqualC[T Complex](a, b T, tolerance float64) bool {
+	scale := math.Max(1, math.Max(AbsC(a), AbsC(b)))
+	return AbsC(a-b) <= tolerance*scale
+}
@@ -34,6 +34,24 @@ func TestGcd(t *testing.T) {
 	}
 }
 
+func TestLcm(t *testing.T) {
+	cases := []struct {
+		a, b int
+		want int
+	}{
+		{4, 6, 12},
+		{21, 6, 42},
+		{-4, 6, 12},
+		{7, 1, 7},
+		{0, 5, 0},
+	}
+	for _, c := range cases {
+		if got := Lcm(c.a, c.b); got != c.want {
+			t.Fatalf("Lcm(%d, %d) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
 func TestIsNthRootInt(t *testing.T) {
 	if !IsNthRootInt(27, 3) {
 		t.Fatalf("IsNthRootInt(27,3) = false, want true")
@@ -78,3 +96,25 @@ func TestIsInteger(t *testing.T) {
 		t.Fatalf("IsInteger(Inf) = true, want false")
 	}
 }
+
+func TestComplexHelpers(t *testing.T) {
+	z := complex(3.0, 4.0)
+	if got := AbsC(z); got != 5 {
+		t.Fatalf("AbsC(3+4i) = %v, want 5", got)
+	}
+	if got := ArgC(complex64(-1)); got != math.Pi {
+		t.Fatalf("ArgC(-1) = %v, want π", got)
+	}
+	if got := PolarToComplex[complex128](2, math.Pi/2); !AlmostEqualC(got, 2i, 1e-15) {
+		t.Fatalf("PolarToComplex(2, π/2) = %v, want 2i", got)
+	}
+	if got := RoundC(complex(1.23456, -2.71828), 2); got != complex(1.23, -2.72) {
+		t.Fatalf("RoundC(1.23456-2.71828i, 2) = %v, want (1.23-2.72i)", got)
+	}
+	if AlmostEqualC(complex(1e6, 0), complex(1e6+2, 0), 1e-9) {
+		t.Fatalf("AlmostEqualC(1e6, 1e6+2, 1e-9) = true, want false")
+	}
+	if !AlmostEqualC(complex(1e6, 0), complex(1e6+2, 0), 1e-5) {
+		t.Fatalf("AlmostEqualC(1e6, 1e6+2, 1e-5) = false, want true")
+	}
+}